@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/initializers"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/middleware"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	swaggerFiles "github.com/swaggo/files"
@@ -130,6 +132,15 @@ func main() {
 
 	database.StartJanitor(ctx, appDB, 10*time.Minute)
 
+	rotationDays, _ := strconv.Atoi(os.Getenv("SIGNING_KEY_ROTATION_DAYS"))
+	service.StartKeyRotation(
+		ctx,
+		s.KeyStore,
+		time.Duration(rotationDays)*24*time.Hour,
+	)
+
+	service.StartBackchannelLogout(ctx, s.LogoutService, 30*time.Second)
+
 	r := gin.Default()
 	r.Use(middleware.SecurityHeadersMiddleware())
 	r.Use(h.CORS)
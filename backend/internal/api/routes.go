@@ -1,12 +1,13 @@
 package api
 
 import (
-	"crypto/rsa"
 	"net/http"
 
 	v1 "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/middleware"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,15 +26,38 @@ type Handlers struct {
 	MetricsHandler      *v1.MetricsHandler
 	BackupHandler       *v1.BackupHandler
 	ReportHandler       *v1.ReportHandler
+	KeyHandler          *v1.KeyHandler
+	ConsentHandler      *v1.ConsentHandler
+	DeviceHandler       *v1.DeviceHandler
+	LockoutHandler      *v1.LockoutHandler
+	MagicLinkHandler    *v1.MagicLinkHandler
 	UserRepo            repository.UserRepository
 
-	RoleRepo   repository.RoleRepository
-	PubKey     *rsa.PublicKey
-	CORS       gin.HandlerFunc
-	ClientCORS gin.HandlerFunc
+	RoleRepo      repository.RoleRepository
+	Keys          service.KeyStore
+	TokenDenylist service.TokenDenylist
+	RateLimiter   cache.RateLimiter
+	RateLimits    middleware.RateLimitPolicies
+	CORS          gin.HandlerFunc
+	ClientCORS    gin.HandlerFunc
 }
 
 func SetupRoutes(r *gin.Engine, h Handlers) {
+	// Bearer token validation, including the access token revocation list
+	authMW := middleware.AuthMiddleware(
+		h.Keys,
+		h.LogHandler.LogService,
+		h.TokenDenylist,
+	)
+
+	rateLimit := func(route string) gin.HandlerFunc {
+		return middleware.RateLimitMiddleware(
+			h.RateLimiter,
+			h.RateLimits,
+			route,
+		)
+	}
+
 	// Open health check endpoints
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
@@ -42,6 +66,10 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 	wellKnown := r.Group("/.well-known")
 	{
 		wellKnown.GET("/jwks.json", h.AuthHandler.GetJWKS)
+		wellKnown.GET(
+			"/openid-configuration",
+			h.AuthHandler.GetOpenIDConfiguration,
+		)
 	}
 
 	v1Group := r.Group("api/v1")
@@ -49,16 +77,37 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 	auth := v1Group.Group("/auth")
-	auth.Use(middleware.RateLimitMiddleware())
+	auth.Use(rateLimit("auth"))
 	{
 		auth.GET("/authorize", h.AuthHandler.Authorize)
-		auth.POST("/login", h.ClientCORS, h.AuthHandler.LoginAndAuthorize)
-		auth.POST("/token", h.AuthHandler.PostTokenExchange)
+		auth.POST("/login", h.ClientCORS, rateLimit("login"),
+			h.AuthHandler.LoginAndAuthorize)
+		auth.POST("/passkey/login/begin", h.ClientCORS,
+			rateLimit("login"), h.PasskeyHandler.BeginPasskeyLogin)
+		auth.POST("/passkey/login/finish", h.ClientCORS,
+			rateLimit("login"), h.PasskeyHandler.FinishPasskeyLogin)
+		auth.POST("/magic-link", h.ClientCORS, rateLimit("magic_link"),
+			h.MagicLinkHandler.PostMagicLink)
+		auth.POST("/magic-link/verify", h.ClientCORS, rateLimit("login"),
+			h.MagicLinkHandler.PostVerifyMagicLink)
+		auth.POST("/token", rateLimit("token"),
+			h.AuthHandler.PostTokenExchange)
+		auth.POST("/par", h.AuthHandler.PostPushedAuthorizationRequest)
 		auth.POST("/refresh", h.AuthHandler.PostTokenRotate)
+		auth.POST("/introspect", h.AuthHandler.PostIntrospect)
+		auth.POST("/revoke", h.AuthHandler.PostRevoke)
 		auth.POST("/logout",
-			middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService),
+			authMW,
 			h.AuthHandler.Logout)
 		auth.GET("/session", h.AuthHandler.CheckSession)
+		auth.GET("/end-session", h.AuthHandler.EndSession)
+		auth.POST("/end-session", h.AuthHandler.EndSession)
+		auth.GET("/consent", h.ClientCORS, h.ConsentHandler.GetConsent)
+		auth.POST("/consent", h.ClientCORS, h.ConsentHandler.PostConsent)
+		auth.POST("/device_authorization",
+			h.DeviceHandler.PostDeviceAuthorization)
+		auth.GET("/device", h.ClientCORS, h.DeviceHandler.GetDevice)
+		auth.POST("/device", h.ClientCORS, h.DeviceHandler.PostDevice)
 	}
 
 	v1Group.POST("/activate", h.RegistrationHandler.ActivateAccount)
@@ -70,13 +119,23 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 
 	// Endpoint for getting user information
 	me := v1Group.Group("/me")
-	me.Use(middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService))
+	me.Use(authMW)
 	me.GET("", h.UserHandler.GetMe)
+	me.GET("/consents", h.ConsentHandler.GetMyConsents)
+	me.DELETE("/consents/:client_id", h.ConsentHandler.DeleteMyConsent)
+
+	// OpenID Connect UserInfo endpoint
+	userInfo := v1Group.Group("/userinfo")
+	userInfo.Use(authMW)
+	{
+		userInfo.GET("", h.UserHandler.GetUserInfo)
+		userInfo.POST("", h.UserHandler.GetUserInfo)
+	}
 
 	otp := v1Group.Group("/otp")
-	otp.Use(middleware.RateLimitMiddleware())
+	otp.Use(rateLimit("otp"))
 	{
-		otp.POST("/send", h.OTPHandler.SendOTP)
+		otp.POST("/send", rateLimit("otp_send"), h.OTPHandler.SendOTP)
 		otp.POST("/verify", h.OTPHandler.VerifyOTP)
 	}
 
@@ -84,12 +143,13 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 	mfaVerify := v1Group.Group("/mfa")
 	mfaVerify.Use(
 		h.ClientCORS,
-		middleware.RateLimitMiddleware(),
+		rateLimit("mfa"),
 	)
 	{
 		// TOTP verification (mid-login, no JWT available)
 		mfaVerify.POST(
 			"/totp/verify",
+			rateLimit("mfa_verify"),
 			h.MFAHandler.PostVerifyMFA,
 		)
 
@@ -108,6 +168,7 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 			)
 			passkeyVerify.POST(
 				"/finish",
+				rateLimit("mfa_verify"),
 				h.PasskeyHandler.FinishVerification,
 			)
 		}
@@ -147,8 +208,8 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 	mfaManage := v1Group.Group("/mfa")
 	mfaManage.Use(
 		h.ClientCORS,
-		middleware.RateLimitMiddleware(),
-		middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService),
+		rateLimit("mfa"),
+		authMW,
 		middleware.APIKeyMiddleware(),
 	)
 	{
@@ -173,7 +234,7 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 		user.PATCH("/:id/name", h.UserHandler.PatchUserName)
 		user.PATCH("/password/forgot", h.UserHandler.PatchUserPasswordByEmail)
 		user.PATCH("/password/change",
-			middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService),
+			authMW,
 			h.UserHandler.PatchChangePassword)
 	}
 
@@ -182,7 +243,7 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 	{
 		internalUser.POST("", h.UserHandler.PostUser)
 		internalUser.PATCH("/:id/name",
-			middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService),
+			authMW,
 			h.UserHandler.PatchUserName)
 		internalUser.PATCH("/:id/password", h.UserHandler.PatchUserPassword)
 		internalUser.PATCH(
@@ -191,20 +252,20 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 		)
 		internalUser.PATCH(
 			"/password/change",
-			middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService),
+			authMW,
 			h.UserHandler.PatchChangePassword,
 		)
 	}
 
 	v1Group.GET("/users/access",
-		middleware.AuthMiddleware(h.PubKey, h.LogHandler.LogService),
+		authMW,
 		middleware.APIKeyMiddleware(),
 		h.UserHandler.GetUserDetailedAccess)
 
 	// Protected Admin Endpoints
 	admin := v1Group.Group("/admin")
-	admin.Use(middleware.AuthorizeRBAC(h.PubKey, h.UserRepo,
-		h.RoleRepo, h.LogHandler.LogService))
+	admin.Use(middleware.AuthorizeRBAC(h.Keys, h.UserRepo,
+		h.RoleRepo, h.LogHandler.LogService, h.TokenDenylist))
 	{
 		admin.GET("/status", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"status": "IdP is operational"})
@@ -253,6 +314,7 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 			users.PUT("/:id/managed-clients", h.UserHandler.PutAdminAccess)
 			users.DELETE("/:id", h.UserHandler.DeleteUser)
 			users.POST("/:id/restore", h.UserHandler.PostRestoreUser)
+			users.POST("/:id/unlock", h.LockoutHandler.PostUnlockUser)
 			users.GET("/metrics", h.MetricsHandler.GetUserMetrics)
 		}
 
@@ -274,10 +336,7 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 			)
 
 			protectedPerms := permissions.Group("")
-			protectedPerms.Use(middleware.AuthMiddleware(
-				h.PubKey,
-				h.LogHandler.LogService,
-			))
+			protectedPerms.Use(authMW)
 			{
 				protectedPerms.GET("", h.PermissionHandler.GetUserPermissions)
 			}
@@ -327,5 +386,20 @@ func SetupRoutes(r *gin.Engine, h Handlers) {
 			backup.POST("/run", h.BackupHandler.PostRunBackup)
 			backup.POST("/restore", h.BackupHandler.PostRestoreBackup)
 		}
+
+		// Token Signing Key Management
+		keys := admin.Group("/keys")
+		{
+			keys.GET("", h.KeyHandler.GetSigningKeys)
+			keys.POST("/rotate", h.KeyHandler.PostRotateSigningKey)
+			keys.POST("/rewrap", h.KeyHandler.PostRewrapKeys)
+		}
+
+		// Account Lockout Policy
+		security := admin.Group("/security")
+		{
+			security.GET("/lockout", h.LockoutHandler.GetLockoutPolicy)
+			security.PUT("/lockout", h.LockoutHandler.PutLockoutPolicy)
+		}
 	}
 }
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// sessionActor names the user behind a session cookie for the audit and
+// security logs: their email, else their user ID. A session that does not
+// resolve is logged by client IP; the cookie itself is a credential and
+// never ends up in a log.
+func sessionActor(
+	c *gin.Context,
+	authService service.AuthService,
+	logService service.LogService,
+	sessionToken string,
+) string {
+	session, err := authService.ValidateSession(
+		c.Request.Context(),
+		sessionToken,
+	)
+	if err != nil || session == nil {
+		return c.ClientIP()
+	}
+
+	email, _ := logService.GetUserEmail(c.Request.Context(), session.UserId)
+	if email != "" {
+		return email
+	}
+	uID, err := uuid.FromBytes(session.UserId)
+	if err != nil {
+		return c.ClientIP()
+	}
+	return uID.String()
+}
@@ -1,11 +1,16 @@
 package v1
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html/template"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +18,7 @@ import (
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -22,17 +28,28 @@ const (
 	actionAuthorize     = "authorize"
 	actionLogin         = "login"
 	actionLogout        = "logout"
+	actionEndSession    = "end_session"
 	actionSessionCheck  = "session_check"
 	actionJWKS          = "jwks"
+	actionDiscovery     = "openid_configuration"
 	actionTokenExchange = "token_exchange"
+	actionClientToken   = "client_credentials"
+	actionDeviceToken   = "device_token"
+	actionPushRequest   = "pushed_authorization_request"
+	actionIntrospect    = "token_introspect"
+	actionRevoke        = "token_revoke"
 	actionTokenRotate   = "token_rotate"
+	actionTokenReuse    = "refresh_token_reuse"
 )
 
 // AuthHandler handles authentication HTTP requests.
 type AuthHandler struct {
-	AuthService   service.AuthService
-	ClientService service.ClientService
-	LogService    service.LogService
+	AuthService    service.AuthService
+	ClientService  service.ClientService
+	LogService     service.LogService
+	LogoutService  service.LogoutService
+	DeviceService  service.DeviceService
+	LockoutService service.LockoutService
 }
 
 // GetAuthorize initiates the authorization flow for the user.
@@ -40,19 +57,82 @@ type AuthHandler struct {
 // @Description Validates the authorization request for the user.
 // @Tags Authentication
 // @Param client_id query string true "Client ID"
+// @Param redirect_uri query string false "Redirect URI"
+// @Param code_challenge query string false "PKCE code challenge"
+// @Param code_challenge_method query string false "S256 or plain"
+// @Param scope query string false "Space-delimited scopes (openid profile email)"
+// @Param nonce query string false "Value echoed in the ID token"
+// @Param state query string false "Opaque value echoed on the redirect"
+// @Param response_type query string false "Must be code when present"
+// @Param prompt query string false "none, login, consent or select_account"
+// @Param max_age query int false "Maximum session age in seconds"
+// @Param login_hint query string false "Email to pre-fill on the login page"
+// @Param acr_values query string false "Requested authentication context classes (1fa, 2fa)"
+// @Param request_uri query string false "request_uri returned by /auth/par"
 // @Success 302
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/authorize [get]
 func (h *AuthHandler) Authorize(c *gin.Context) {
+	restoreAuthorizeRequest(c)
+
+	var req dto.AuthorizeRequest
+	_ = c.ShouldBindQuery(&req)
+
 	loginUI := os.Getenv("CLIENT_BASE_URL")
-	clientID := c.Query("client_id")
-	redirectURI := c.Query("redirect_uri")
+
+	// Parameters pushed to /auth/par replace those of the URL. Without a
+	// valid request there is no trusted redirect URI to report errors to.
+	if req.RequestURI != "" {
+		resolved, err := h.AuthService.ResolveAuthorizeRequest(
+			c.Request.Context(),
+			req,
+			pushedRequestBinding(c),
+		)
+		if err != nil {
+			log.Printf("[Authorize] Request URI: %v", err)
+			_ = h.LogService.PostAuditLogWithActorString(
+				c.Request.Context(),
+				"",
+				&dto.PostAuditLogRequest{
+					Action: actionAuthorize,
+					Target: loginUI,
+					Status: models.StatusFail,
+					Metadata: buildMetadata(map[string]interface{}{
+						"client_id":  req.ClientID,
+						"ip":         c.ClientIP(),
+						"user_agent": c.Request.UserAgent(),
+						"error":      err.Error(),
+					}),
+				},
+			)
+			clearAuthorizeRequest(c)
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"The authorization request has expired. Please try again.",
+				err,
+			)
+			return
+		}
+		req = resolved
+	}
+
+	clientID := req.ClientID
+	redirectURI := req.RedirectURI
 	loginLink := loginUI + "/login?client_id=" + clientID
 	if redirectURI != "" {
 		loginLink += "&redirect_uri=" + url.QueryEscape(redirectURI)
 	}
+	// The login UI pre-fills the email and offers switching accounts.
+	if req.LoginHint != "" {
+		loginLink += "&login_hint=" + url.QueryEscape(req.LoginHint)
+	}
+	if req.Prompt != "" && req.Prompt != models.PromptNone {
+		loginLink += "&prompt=" + url.QueryEscape(req.Prompt)
+	}
 
 	// Resolve client name for audit logging
 	clientName := h.LogService.ResolveClientName(
@@ -92,6 +172,91 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 		return
 	}
 
+	// Never redirect anywhere the client has not registered
+	validRedirect, err := h.AuthService.ValidateRedirectURI(
+		c.Request.Context(),
+		clientID,
+		redirectURI,
+	)
+	if err != nil {
+		log.Printf("[Authorize] Redirect Validation: %v", err)
+		_ = h.LogService.PostAuditLogWithActorString(
+			c.Request.Context(),
+			"",
+			&dto.PostAuditLogRequest{
+				Action: actionAuthorize,
+				Target: loginUI,
+				Status: models.StatusFail,
+				Metadata: buildMetadata(map[string]interface{}{
+					"client_id":    clientID,
+					"client_name":  clientName,
+					"redirect_uri": redirectURI,
+					"ip":           c.ClientIP(),
+					"user_agent":   c.Request.UserAgent(),
+					"error":        err.Error(),
+				}),
+			},
+		)
+		clearAuthorizeRequest(c)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"The redirect URI is not registered for this client.",
+			err,
+		)
+		return
+	}
+
+	if req.ResponseType != "" && req.ResponseType != "code" {
+		log.Printf("[Authorize] Response Type: %q", req.ResponseType)
+		_ = h.LogService.PostAuditLogWithActorString(
+			c.Request.Context(),
+			"",
+			&dto.PostAuditLogRequest{
+				Action:   actionAuthorize,
+				Target:   validRedirect,
+				Status:   models.StatusFail,
+				Metadata: metadata,
+			},
+		)
+		clearAuthorizeRequest(c)
+		errors.RedirectOAuth(
+			c,
+			validRedirect,
+			errors.OAuthUnsupportedResponseType,
+			"only the code response type is supported",
+			req.State,
+		)
+		return
+	}
+
+	prompts, err := service.ParsePrompt(req.Prompt)
+	if err != nil {
+		log.Printf("[Authorize] Prompt: %v", err)
+		_ = h.LogService.PostAuditLogWithActorString(
+			c.Request.Context(),
+			"",
+			&dto.PostAuditLogRequest{
+				Action:   actionAuthorize,
+				Target:   validRedirect,
+				Status:   models.StatusFail,
+				Metadata: metadata,
+			},
+		)
+		clearAuthorizeRequest(c)
+		errors.RedirectOAuth(
+			c,
+			validRedirect,
+			errors.OAuthInvalidRequest,
+			"prompt=none cannot be combined with other values",
+			req.State,
+		)
+		return
+	}
+	// prompt=none never shows a page; the client gets an error instead.
+	promptNone := slices.Contains(prompts, models.PromptNone)
+
 	// Extract session from cookie
 	sessionToken, err := c.Cookie(service.SESSION_COOKIE_NAME)
 	if err != nil {
@@ -107,15 +272,28 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 				Metadata: metadata,
 			},
 		)
+		if promptNone {
+			clearAuthorizeRequest(c)
+			errors.RedirectOAuth(
+				c,
+				validRedirect,
+				errors.OAuthLoginRequired,
+				"the user is not signed in",
+				req.State,
+			)
+			return
+		}
+		rememberAuthorizeRequest(c)
 		c.Redirect(http.StatusFound, loginLink)
 		return
 	}
 
 	redirectURL, err := h.AuthService.Authorize(
 		c.Request.Context(),
-		clientID,
+		req,
 		sessionToken,
 	)
+	actor := sessionActor(c, h.AuthService, h.LogService, sessionToken)
 	if err != nil {
 		log.Printf("[Authorize] %v", err)
 
@@ -130,7 +308,7 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 		})
 		_ = h.LogService.PostAuditLogWithActorString(
 			c.Request.Context(),
-			sessionToken,
+			actor,
 			&dto.PostAuditLogRequest{
 				Action:   actionAuthorize,
 				Target:   loginLink,
@@ -139,15 +317,76 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 			},
 		)
 
+		if promptNone {
+			code, description := promptNoneError(err)
+			clearAuthorizeRequest(c)
+			errors.RedirectOAuth(
+				c,
+				validRedirect,
+				code,
+				description,
+				req.State,
+			)
+			return
+		}
+
+		// Clients requiring consent send the user to the consent screen
+		// with the request remembered for the way back.
+		if strings.Contains(err.Error(), "consent required") {
+			rememberAuthorizeRequest(c)
+			c.Redirect(http.StatusFound, loginUI+
+				"/consent?client_id="+url.QueryEscape(clientID)+
+				"&scope="+url.QueryEscape(req.Scope))
+			return
+		}
+
+		// Sessions below the required authentication context verify a
+		// second factor again before the request is resumed.
+		if strings.Contains(err.Error(), "step-up required") {
+			rememberAuthorizeRequest(c)
+			c.Redirect(http.StatusFound, loginUI+
+				"/step-up?client_id="+url.QueryEscape(clientID)+
+				"&acr_values="+url.QueryEscape(req.ACRValues))
+			return
+		}
+
+		// Session problems send the user back through the login UI; every
+		// other failure is reported to the client as an OAuth error.
+		if code, description := oauthAuthorizeError(err); code != "" {
+			if code == errors.OAuthAccessDenied {
+				_ = h.LogService.PostSecurityLogWithActorString(
+					c.Request.Context(),
+					actor,
+					&dto.PostAuditLogRequest{
+						Action:   actionAuthorize,
+						Target:   clientID,
+						Status:   models.StatusFail,
+						Metadata: metadataWithErr,
+					},
+				)
+			}
+			clearAuthorizeRequest(c)
+			errors.RedirectOAuth(
+				c,
+				validRedirect,
+				code,
+				description,
+				req.State,
+			)
+			return
+		}
+
 		h.AuthService.RevokeCookies(c)
+		rememberAuthorizeRequest(c)
 		c.Redirect(http.StatusFound, loginLink)
 		return
 	}
+	clearAuthorizeRequest(c)
 
 	// Log success
 	_ = h.LogService.PostAuditLogWithActorString(
 		c.Request.Context(),
-		sessionToken,
+		actor,
 		&dto.PostAuditLogRequest{
 			Action:   actionAuthorize,
 			Target:   loginUI,
@@ -161,7 +400,10 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 
 // LoginAndAuthorize verifies credentials and issues an authorization code
 // @Summary Login and Authorize
-// @Description Authenticate user and return a redirect URL with auth code
+// @Description Authenticate user and return a redirect URL with auth code.
+// @Description Repeated failures delay further attempts (429, code 1032)
+// @Description and then lock the account (423, code 1031); both carry a
+// @Description Retry-After header.
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -170,6 +412,8 @@ func (h *AuthHandler) Authorize(c *gin.Context) {
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Failure 403 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) LoginAndAuthorize(c *gin.Context) {
@@ -234,12 +478,39 @@ func (h *AuthHandler) LoginAndAuthorize(c *gin.Context) {
 		"user_agent":  c.Request.UserAgent(),
 	})
 
-	redirectLink, sessionID, err := h.AuthService.LoginAndAuthorize(
+	// Locked accounts and attempts within the progressive delay are
+	// refused before the password is checked. A lockout store failure
+	// does not block logins.
+	lockout, lockErr := h.LockoutService.CheckLogin(
 		c.Request.Context(),
-		req,
-		c.ClientIP(),
-		c.Request.UserAgent(),
+		req.Email,
 	)
+	if lockErr != nil {
+		log.Printf("[LoginAndAuthorize] Lockout: %v", lockErr)
+	}
+
+	var redirectLink, sessionID string
+	if lockout != nil {
+		err = fmt.Errorf("login lockout: retry in %ds", lockout.RetryAfter)
+	} else {
+		redirectLink, sessionID, err = h.AuthService.LoginAndAuthorize(
+			c.Request.Context(),
+			req,
+			c.ClientIP(),
+			c.Request.UserAgent(),
+		)
+		wrongPassword := err != nil &&
+			strings.Contains(err.Error(), "secret verification")
+		if wrongPassword {
+			lockout, lockErr = h.LockoutService.RecordFailure(
+				c.Request.Context(),
+				req.Email,
+			)
+			if lockErr != nil {
+				log.Printf("[LoginAndAuthorize] Lockout: %v", lockErr)
+			}
+		}
+	}
 	if err != nil {
 		log.Printf("[LoginAndAuthorize] %v", err)
 
@@ -287,10 +558,33 @@ func (h *AuthHandler) LoginAndAuthorize(c *gin.Context) {
 			msg = "The redirect URI is not authorized for this client."
 		}
 
+		// A wrong password keeps its message; the header still tells the
+		// UI how long to wait before the next attempt.
+		if lockout != nil {
+			c.Header("Retry-After", strconv.Itoa(lockout.RetryAfter))
+			if lockout.Locked {
+				status = http.StatusLocked
+				code = errors.CodeAccountLocked
+				msg = "Your account is temporarily locked after too many " +
+					"failed sign-ins."
+			} else if strings.Contains(err.Error(), "login lockout") {
+				status = http.StatusTooManyRequests
+				code = errors.CodeLoginDelayed
+				msg = "Too many failed sign-ins. Please wait and try again."
+			}
+		}
+
 		errors.Send(c, status, code, msg, err)
 		return
 	}
 
+	if err := h.LockoutService.RecordSuccess(
+		c.Request.Context(),
+		req.Email,
+	); err != nil {
+		log.Printf("[LoginAndAuthorize] Lockout: %v", err)
+	}
+
 	// Log success with the email that just logged in
 	logReq := &dto.PostAuditLogRequest{
 		Action:   actionLogin,
@@ -398,6 +692,12 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		log.Printf("[Logout] Token Revocation: %v", err)
 	}
 
+	// 3. End the user's sessions and notify the clients they signed in to
+	err = h.LogoutService.LogoutUser(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("[Logout] Session Logout: %v", err)
+	}
+
 	// metadata for logging
 	metadata := buildMetadata(map[string]interface{}{
 		"client_id": req.ClientID,
@@ -418,6 +718,96 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	c.Redirect(http.StatusFound, logoutURL)
 }
 
+// EndSession logs the user out at the request of a client
+// @Summary RP-Initiated Logout
+// @Description Ends the browser session (OIDC RP-Initiated Logout), sends
+// @Description back-channel logout tokens to the clients it signed in to
+// @Description and returns to the post_logout_redirect_uri. Clients with
+// @Description a front-channel logout URI are loaded in hidden frames on
+// @Description the way. Without an id_token_hint the user confirms the
+// @Description logout on a page first.
+// @Tags Authentication
+// @Produce html
+// @Param id_token_hint query string false "ID token issued to the client"
+// @Param client_id query string false "Client ID"
+// @Param post_logout_redirect_uri query string false "Registered URI to return to"
+// @Param state query string false "Opaque value returned to the client"
+// @Success 200 {string} string "Confirmation or front-channel logout page"
+// @Success 302
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/end-session [get]
+func (h *AuthHandler) EndSession(c *gin.Context) {
+	var req dto.EndSessionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		log.Printf("[EndSession] Bind: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid logout request.",
+			err,
+		)
+		return
+	}
+
+	// Nothing but an id_token_hint shows that a client the user signed in
+	// to asked for the logout, so any other request is confirmed first.
+	if req.IDTokenHint == "" {
+		req.Confirmed = logoutConfirmed(c)
+		if !req.Confirmed {
+			renderLogoutConfirmation(c, req)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	sessionID, _ := c.Cookie(service.SESSION_COOKIE_NAME)
+	result, err := h.LogoutService.EndSession(ctx, req, sessionID)
+	if err != nil {
+		log.Printf("[EndSession] %v", err)
+		if strings.Contains(err.Error(), "invalid request") {
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"The logout request is invalid.",
+				err,
+			)
+			return
+		}
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Failed to sign out. Please try again.",
+			err,
+		)
+		return
+	}
+	h.AuthService.RevokeCookies(c)
+
+	if result.UserID != nil {
+		_ = h.LogService.PostAuditLog(ctx, result.UserID,
+			&dto.PostAuditLogRequest{
+				Action: actionEndSession,
+				Target: "session",
+				Status: models.StatusSuccess,
+				Metadata: buildMetadata(map[string]interface{}{
+					"client_id":  req.ClientID,
+					"ip":         c.ClientIP(),
+					"user_agent": c.Request.UserAgent(),
+				}),
+			})
+	}
+
+	if len(result.FrontchannelURIs) == 0 {
+		c.Redirect(http.StatusFound, result.RedirectURL)
+		return
+	}
+	renderFrontchannelLogout(c, result)
+}
+
 // InternalLogout handles server-side logout for specific users.
 func (h *AuthHandler) InternalLogout(c *gin.Context) {
 	var req dto.InternalLogoutRequest
@@ -486,6 +876,10 @@ func (h *AuthHandler) InternalLogout(c *gin.Context) {
 	if err != nil {
 		log.Printf("[InternalLogout] Token Revocation: %v", err)
 	}
+	err = h.LogoutService.LogoutUser(c.Request.Context(), uID)
+	if err != nil {
+		log.Printf("[InternalLogout] Session Logout: %v", err)
+	}
 
 	// 3. Clear session and access token cookies
 	h.AuthService.RevokeCookies(c)
@@ -508,7 +902,8 @@ func (h *AuthHandler) InternalLogout(c *gin.Context) {
 
 // CheckSession verifies if the current session cookie is valid
 // @Summary Check Session
-// @Description Validate the idp_session cookie against the database
+// @Description Validate the idp_session cookie against the database and
+// @Description return the signed-in user's ID and email
 // @Tags Authentication
 // @Produce json
 // @Success 200 {object} dto.SuccessResponse
@@ -571,6 +966,7 @@ func (h *AuthHandler) CheckSession(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"authenticated": true,
 		"user_id":       uID.String(),
+		"email":         userEmail,
 	})
 }
 
@@ -625,30 +1021,119 @@ func (h *AuthHandler) GetJWKS(c *gin.Context) {
 	c.JSON(http.StatusOK, jwks)
 }
 
+// GetOpenIDConfiguration serves the OpenID Connect discovery document
+// @Summary Get OpenID configuration
+// @Description Retrieve the OIDC discovery metadata for relying parties
+// @Tags JSON Web Key Set
+// @Produce json
+// @Success 200 {object} dto.OpenIDConfiguration
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /.well-known/openid-configuration [get]
+func (h *AuthHandler) GetOpenIDConfiguration(c *gin.Context) {
+	config, err := h.AuthService.GetOpenIDConfiguration(c.Request.Context())
+	if err != nil {
+		log.Printf("[GetOpenIDConfiguration] Build: %v", err)
+		_ = h.LogService.PostAuditLogWithActorString(c.Request.Context(), "",
+			&dto.PostAuditLogRequest{
+				Action: actionDiscovery,
+				Target: "openid_configuration",
+				Status: models.StatusFail,
+				Metadata: buildMetadata(map[string]interface{}{
+					"ip":         c.ClientIP(),
+					"user_agent": c.Request.UserAgent(),
+					"error":      err.Error(),
+				}),
+			})
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Failed to build OpenID configuration.",
+			err,
+		)
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
+
 // PostTokenExchange handles the exchange of an auth code for access tokens
 // @Summary Exchange Auth Code
-// @Description Validates the code and client secret to issue JWT and Refresh
+// @Description Validates the code and client secret (or PKCE code_verifier
+// @Description for public clients) to issue JWT and Refresh. With
+// @Description grant_type=client_credentials a secret-authenticated client
+// @Description receives a token for itself, limited to its allowed scopes.
+// @Description With the device_code grant a device polls with its
+// @Description device_code until the user approves the request. Client
+// @Description credentials go in the body or an HTTP Basic header.
 // @Tags Authentication
 // @Security
 // @Accept json
 // @Produce json
 // @Param req body dto.TokenExchangeRequest true "Exchange Request"
 // @Success 200 {object} dto.TokenResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 401 {object} dto.ErrorResponse
-// @Failure 403 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
 // @Router /auth/token [post]
 func (h *AuthHandler) PostTokenExchange(c *gin.Context) {
 	var req dto.TokenExchangeRequest
-	if err := c.ShouldBind(&req); err != nil {
-		log.Printf("[PostTokenExchange] Bind: %v", err)
-		errors.Send(
+	_ = c.ShouldBind(&req)
+	err := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+	if err != nil {
+		errors.SendOAuth(
 			c,
 			http.StatusBadRequest,
-			errors.CodeInvalidInput,
-			"Invalid token exchange request.",
-			err,
+			errors.OAuthInvalidRequest,
+			err.Error(),
+		)
+		return
+	}
+	if req.ClientID == "" {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			"client_id is required",
+		)
+		return
+	}
+
+	// grant_type is optional for backwards compatibility with clients
+	// that only ever exchanged authorization codes.
+	grantType := models.ClientGrantType(req.GrantType)
+	if grantType == "" {
+		grantType = models.GrantAuthCode
+	}
+	switch grantType {
+	case models.GrantAuthCode:
+		if req.Code == "" {
+			errors.SendOAuth(
+				c,
+				http.StatusBadRequest,
+				errors.OAuthInvalidRequest,
+				"code is required",
+			)
+			return
+		}
+	case models.GrantClientCredentials:
+	case models.GrantDeviceCode:
+		if req.DeviceCode == "" {
+			errors.SendOAuth(
+				c,
+				http.StatusBadRequest,
+				errors.OAuthInvalidRequest,
+				"device_code is required",
+			)
+			return
+		}
+	default:
+		log.Printf("[PostTokenExchange] Grant Type: %q", req.GrantType)
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthUnsupportedGrantType,
+			"grant_type "+req.GrantType+" is not supported",
 		)
 		return
 	}
@@ -657,12 +1142,11 @@ func (h *AuthHandler) PostTokenExchange(c *gin.Context) {
 	cID, err := uuid.Parse(req.ClientID)
 	if err != nil {
 		log.Printf("[PostTokenExchange] UUID Parse: %v", err)
-		errors.Send(
+		errors.SendOAuth(
 			c,
-			http.StatusBadRequest,
-			errors.CodeClientError,
-			"The Client ID format is invalid.",
-			err,
+			http.StatusUnauthorized,
+			errors.OAuthInvalidClient,
+			"client_id is malformed",
 		)
 		return
 	}
@@ -676,14 +1160,22 @@ func (h *AuthHandler) PostTokenExchange(c *gin.Context) {
 		uID,
 		perms,
 	)
-	if err != nil || (!slices.Contains(client.Grants, "client_credentials") &&
-		!slices.Contains(client.Grants, "authorization_code")) {
-		errors.SendString(
+	if err != nil {
+		log.Printf("[PostTokenExchange] Client Lookup: %v", err)
+		errors.SendOAuth(
 			c,
-			http.StatusForbidden,
-			errors.CodeForbidden,
-			"The client is missing the required grant type.",
-			"missing grant type",
+			http.StatusUnauthorized,
+			errors.OAuthInvalidClient,
+			"client authentication failed",
+		)
+		return
+	}
+	if !slices.Contains(client.Grants, string(grantType)) {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthUnauthorizedClient,
+			"client is not allowed to use the "+string(grantType)+" grant",
 		)
 		return
 	}
@@ -701,6 +1193,15 @@ func (h *AuthHandler) PostTokenExchange(c *gin.Context) {
 		"user_agent":  c.Request.UserAgent(),
 	})
 
+	if grantType == models.GrantClientCredentials {
+		h.postClientCredentials(c, req, clientName, metadata)
+		return
+	}
+	if grantType == models.GrantDeviceCode {
+		h.postDeviceCode(c, req, clientName, metadata)
+		return
+	}
+
 	resp, err := h.AuthService.ExchangeCodeForToken(
 		c.Request.Context(),
 		req,
@@ -733,22 +1234,8 @@ func (h *AuthHandler) PostTokenExchange(c *gin.Context) {
 			logReq,
 		)
 
-		status := http.StatusInternalServerError
-		code := errors.CodeInternalError
-		msg := "An unexpected error occurred. Please try again."
-
-		if strings.Contains(err.Error(), "verification") {
-			status, code = http.StatusUnauthorized, errors.CodeUnauthorized
-			msg = "The request is unauthorized."
-		} else if strings.Contains(err.Error(), "Code Exchange") {
-			status, code = http.StatusBadRequest, errors.CodeInvalidInput
-			msg = "The authorization code is invalid or has expired."
-		} else if strings.Contains(err.Error(), "UUID Parse") {
-			status, code = http.StatusBadRequest, errors.CodeClientError
-			msg = "The Client ID is invalid."
-		}
-
-		errors.Send(c, status, code, msg, err)
+		status, code, description := oauthTokenError(err)
+		errors.SendOAuth(c, status, code, description)
 		return
 	}
 
@@ -790,6 +1277,404 @@ func (h *AuthHandler) PostTokenExchange(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// postClientCredentials issues a machine token for the client_credentials
+// grant. The session cookie is left untouched since no user is involved.
+func (h *AuthHandler) postClientCredentials(
+	c *gin.Context,
+	req dto.TokenExchangeRequest,
+	clientName string,
+	metadata json.RawMessage,
+) {
+	resp, err := h.AuthService.IssueClientCredentialsToken(
+		c.Request.Context(),
+		req,
+	)
+	if err != nil {
+		log.Printf("[PostTokenExchange] Client Credentials: %v", err)
+
+		logReq := &dto.PostAuditLogRequest{
+			Action: actionClientToken,
+			Target: req.ClientID,
+			Status: models.StatusFail,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":   req.ClientID,
+				"client_name": clientName,
+				"scope":       req.Scope,
+				"ip":          c.ClientIP(),
+				"user_agent":  c.Request.UserAgent(),
+				"error":       err.Error(),
+			}),
+		}
+		_ = h.LogService.PostAuditLogWithActorString(
+			c.Request.Context(),
+			clientName,
+			logReq,
+		)
+		_ = h.LogService.PostSecurityLogWithActorString(
+			c.Request.Context(),
+			clientName,
+			logReq,
+		)
+
+		status, code, description := oauthTokenError(err)
+		errors.SendOAuth(c, status, code, description)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(
+		c.Request.Context(),
+		clientName,
+		&dto.PostAuditLogRequest{
+			Action:   actionClientToken,
+			Target:   req.ClientID,
+			Status:   models.StatusSuccess,
+			Metadata: metadata,
+		},
+	)
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, resp)
+}
+
+// postDeviceCode answers a device polling for its tokens (RFC 8628). The
+// pending and slow_down answers are expected while the user decides, so
+// only the final outcome is logged.
+func (h *AuthHandler) postDeviceCode(
+	c *gin.Context,
+	req dto.TokenExchangeRequest,
+	clientName string,
+	metadata json.RawMessage,
+) {
+	ctx := c.Request.Context()
+	code, err := h.DeviceService.PollDeviceCode(ctx, req)
+	if err == nil {
+		// The code is bound to the device code in place of a PKCE
+		// verifier, so public clients need no secret.
+		req.Code = code
+		req.CodeVerifier = req.DeviceCode
+		var resp *dto.TokenResponse
+		resp, err = h.AuthService.ExchangeCodeForToken(ctx, req)
+		if err == nil {
+			_ = h.LogService.PostAuditLogWithActorString(ctx, clientName,
+				&dto.PostAuditLogRequest{
+					Action:   actionDeviceToken,
+					Target:   req.ClientID,
+					Status:   models.StatusSuccess,
+					Metadata: metadata,
+				})
+			c.Header("Cache-Control", "no-store")
+			c.Header("Pragma", "no-cache")
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+	}
+
+	status, code, description := oauthTokenError(err)
+	if code != errors.OAuthAuthorizationPending &&
+		code != errors.OAuthSlowDown {
+		log.Printf("[PostTokenExchange] Device Code: %v", err)
+		logReq := &dto.PostAuditLogRequest{
+			Action: actionDeviceToken,
+			Target: req.ClientID,
+			Status: models.StatusFail,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":   req.ClientID,
+				"client_name": clientName,
+				"ip":          c.ClientIP(),
+				"user_agent":  c.Request.UserAgent(),
+				"error":       err.Error(),
+			}),
+		}
+		_ = h.LogService.PostAuditLogWithActorString(ctx, clientName, logReq)
+		_ = h.LogService.PostSecurityLogWithActorString(
+			ctx,
+			clientName,
+			logReq,
+		)
+	}
+	errors.SendOAuth(c, status, code, description)
+}
+
+// PostPushedAuthorizationRequest stores the parameters of an
+// authorization request ahead of the redirect (RFC 9126)
+// @Summary Push Authorization Request
+// @Description Authenticated clients push the parameters they would send
+// @Description to /auth/authorize and receive a request_uri to send there
+// @Description with their client_id instead. Public clients authenticate
+// @Description with PKCE in place of a secret.
+// @Tags Authentication
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param redirect_uri formData string false "Redirect URI"
+// @Param code_challenge formData string false "PKCE code challenge"
+// @Param code_challenge_method formData string false "S256 or plain"
+// @Param scope formData string false "Space-delimited scopes"
+// @Param nonce formData string false "Value echoed in the ID token"
+// @Param state formData string false "Opaque value echoed on the redirect"
+// @Param response_type formData string false "Must be code when present"
+// @Param prompt formData string false "none, login, consent or select_account"
+// @Param max_age formData int false "Maximum session age in seconds"
+// @Param login_hint formData string false "Email to pre-fill on the login page"
+// @Param acr_values formData string false "Requested authentication context classes"
+// @Success 201 {object} dto.PushedAuthorizationResponse
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /auth/par [post]
+func (h *AuthHandler) PostPushedAuthorizationRequest(c *gin.Context) {
+	var req dto.PushedAuthorizationRequest
+	_ = c.ShouldBind(&req)
+	err := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+	if err != nil {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			err.Error(),
+		)
+		return
+	}
+	if req.ClientID == "" {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			"client_id is required",
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	clientName := h.LogService.ResolveClientName(ctx, req.ClientID)
+
+	resp, err := h.AuthService.PushAuthorizationRequest(ctx, req)
+	if err != nil {
+		log.Printf("[PostPushedAuthorizationRequest] %v", err)
+		logReq := &dto.PostAuditLogRequest{
+			Action: actionPushRequest,
+			Target: req.ClientID,
+			Status: models.StatusFail,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":    req.ClientID,
+				"client_name":  clientName,
+				"redirect_uri": req.RedirectURI,
+				"ip":           c.ClientIP(),
+				"user_agent":   c.Request.UserAgent(),
+				"error":        err.Error(),
+			}),
+		}
+		_ = h.LogService.PostSecurityLogWithActorString(ctx, clientName,
+			logReq)
+
+		status, code, description := oauthPARError(err)
+		errors.SendOAuth(c, status, code, description)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(
+		ctx,
+		clientName,
+		&dto.PostAuditLogRequest{
+			Action: actionPushRequest,
+			Target: req.ClientID,
+			Status: models.StatusSuccess,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":    req.ClientID,
+				"client_name":  clientName,
+				"redirect_uri": req.RedirectURI,
+				"ip":           c.ClientIP(),
+				"user_agent":   c.Request.UserAgent(),
+			}),
+		},
+	)
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, resp)
+}
+
+// PostIntrospect reports whether a token is active (RFC 7662)
+// @Summary Introspect Token
+// @Description Authenticated clients may check access and refresh tokens.
+// @Description Client credentials are read from the body or Basic auth.
+// @Tags Authentication
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to introspect"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200 {object} dto.IntrospectionResponse
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /auth/introspect [post]
+func (h *AuthHandler) PostIntrospect(c *gin.Context) {
+	var req dto.IntrospectionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		log.Printf("[PostIntrospect] Bind: %v", err)
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			"token is required",
+		)
+		return
+	}
+	err := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+	if err != nil {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			err.Error(),
+		)
+		return
+	}
+
+	clientName := h.LogService.ResolveClientName(
+		c.Request.Context(),
+		req.ClientID,
+	)
+
+	resp, err := h.AuthService.IntrospectToken(c.Request.Context(), req)
+	if err != nil {
+		log.Printf("[PostIntrospect] %v", err)
+		logReq := &dto.PostAuditLogRequest{
+			Action: actionIntrospect,
+			Target: req.ClientID,
+			Status: models.StatusFail,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":   req.ClientID,
+				"client_name": clientName,
+				"ip":          c.ClientIP(),
+				"user_agent":  c.Request.UserAgent(),
+				"error":       err.Error(),
+			}),
+		}
+		_ = h.LogService.PostSecurityLogWithActorString(
+			c.Request.Context(),
+			clientName,
+			logReq,
+		)
+
+		status, code, description := oauthTokenError(err)
+		errors.SendOAuth(c, status, code, description)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(
+		c.Request.Context(),
+		clientName,
+		&dto.PostAuditLogRequest{
+			Action: actionIntrospect,
+			Target: req.ClientID,
+			Status: models.StatusSuccess,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":   req.ClientID,
+				"client_name": clientName,
+				"active":      resp.Active,
+				"ip":          c.ClientIP(),
+				"user_agent":  c.Request.UserAgent(),
+			}),
+		},
+	)
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, resp)
+}
+
+// PostRevoke revokes a single refresh or access token (RFC 7009)
+// @Summary Revoke Token
+// @Description Revokes one token issued to the calling client without
+// @Description ending the user's other sessions. Unknown tokens are ignored.
+// @Tags Authentication
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to revoke"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Param revoke_family formData bool false "Also revoke the token family"
+// @Success 200
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /auth/revoke [post]
+func (h *AuthHandler) PostRevoke(c *gin.Context) {
+	var req dto.RevocationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		log.Printf("[PostRevoke] Bind: %v", err)
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			"token is required",
+		)
+		return
+	}
+	err := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+	if err != nil {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			err.Error(),
+		)
+		return
+	}
+
+	clientName := h.LogService.ResolveClientName(
+		c.Request.Context(),
+		req.ClientID,
+	)
+	metadata := map[string]interface{}{
+		"client_id":       req.ClientID,
+		"client_name":     clientName,
+		"token_type_hint": req.TokenTypeHint,
+		"revoke_family":   req.RevokeFamily,
+		"ip":              c.ClientIP(),
+		"user_agent":      c.Request.UserAgent(),
+	}
+
+	if err := h.AuthService.RevokeToken(c.Request.Context(), req); err != nil {
+		log.Printf("[PostRevoke] %v", err)
+		metadata["error"] = err.Error()
+		logReq := &dto.PostAuditLogRequest{
+			Action:   actionRevoke,
+			Target:   req.ClientID,
+			Status:   models.StatusFail,
+			Metadata: buildMetadata(metadata),
+		}
+		_ = h.LogService.PostAuditLogWithActorString(
+			c.Request.Context(),
+			clientName,
+			logReq,
+		)
+		_ = h.LogService.PostSecurityLogWithActorString(
+			c.Request.Context(),
+			clientName,
+			logReq,
+		)
+
+		status, code, description := oauthTokenError(err)
+		errors.SendOAuth(c, status, code, description)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(
+		c.Request.Context(),
+		clientName,
+		&dto.PostAuditLogRequest{
+			Action:   actionRevoke,
+			Target:   req.ClientID,
+			Status:   models.StatusSuccess,
+			Metadata: buildMetadata(metadata),
+		},
+	)
+
+	c.Status(http.StatusOK)
+}
+
 // PostTokenRotate handles refreshing an access token using a refresh token
 // @Summary Rotate Refresh Token
 // @Description Invalidates old refresh token and issues a new pair
@@ -863,7 +1748,21 @@ func (h *AuthHandler) PostTokenRotate(c *gin.Context) {
 		code := errors.CodeInternalError
 		msg := "An unexpected error occurred. Please try again."
 
-		if strings.Contains(err.Error(), "TokenLookup") {
+		if strings.Contains(err.Error(), "token reuse") {
+			_ = h.LogService.PostSecurityLogWithActorString(
+				c.Request.Context(),
+				"",
+				&dto.PostAuditLogRequest{
+					Action:   actionTokenReuse,
+					Target:   "refresh_token",
+					Status:   models.StatusFail,
+					Metadata: metadataWithErr,
+				},
+			)
+			status, code = http.StatusUnauthorized, errors.CodeTokenExpired
+			msg = "The refresh token was already used. Please sign in again."
+		} else if strings.Contains(err.Error(), "TokenLookup") ||
+			strings.Contains(err.Error(), "token validation") {
 			status, code = http.StatusUnauthorized, errors.CodeTokenExpired
 			msg = "The provided refresh token is invalid or has expired."
 		} else if strings.Contains(err.Error(), "missing refresh_token grant") {
@@ -979,3 +1878,387 @@ func (h *AuthHandler) PostInternalRefresh(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// authAfterParam carries the authentication time a remembered request
+// still requires. It is set by the IdP, never by the client.
+const authAfterParam = "auth_after"
+
+// rememberAuthorizeRequest keeps the authorize query string in a short-lived
+// cookie so parameters the login UI does not forward (e.g. PKCE) survive
+// the trip through the login page. prompt and max_age would ask the user
+// again on the way back, so they are replaced by the session creation time
+// they require.
+func rememberAuthorizeRequest(c *gin.Context) {
+	query := c.Request.URL.Query()
+	if after := requiredAuthTime(query, time.Now()); after > 0 {
+		query.Set(authAfterParam, strconv.FormatInt(after, 10))
+	}
+	query.Del("prompt")
+	query.Del("max_age")
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		service.AUTHORIZE_REQUEST_COOKIE_NAME,
+		query.Encode(),
+		service.AUTHORIZE_REQUEST_TTL,
+		"/",
+		"",
+		true,
+		true,
+	)
+}
+
+// requiredAuthTime returns the earliest session creation time, in Unix
+// seconds, that satisfies the query's prompt, max_age and any remembered
+// requirement, or 0 when the request has none.
+func requiredAuthTime(query url.Values, now time.Time) int64 {
+	after, _ := strconv.ParseInt(query.Get(authAfterParam), 10, 64)
+
+	prompts := strings.Fields(query.Get("prompt"))
+	if slices.Contains(prompts, models.PromptLogin) ||
+		slices.Contains(prompts, models.PromptSelectAccount) {
+		after = max(after, now.Unix())
+	}
+
+	seconds, err := strconv.Atoi(query.Get("max_age"))
+	if err == nil && seconds >= 0 {
+		after = max(after, now.Unix()-int64(seconds))
+	}
+	return after
+}
+
+// frontchannelLogoutPage loads each front-channel logout URI in a hidden
+// frame and then continues to the post-logout redirect.
+var frontchannelLogoutPage = template.Must(template.New("logout").Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="2;url={{.RedirectURL}}">
+<title>Signing out</title>
+</head>
+<body>
+<p>Signing out&hellip;</p>
+{{range .FrontchannelURIs}}<iframe src="{{.}}" hidden></iframe>
+{{end}}</body>
+</html>
+`))
+
+// renderFrontchannelLogout writes the front-channel logout page. The page
+// frames other origins, which the default same-origin CSP forbids.
+func renderFrontchannelLogout(c *gin.Context, result *dto.EndSessionResult) {
+	c.Header("Content-Security-Policy",
+		"default-src 'none'; frame-src https: http:")
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	err := frontchannelLogoutPage.Execute(c.Writer, result)
+	if err != nil {
+		log.Printf("[EndSession] Render: %v", err)
+	}
+}
+
+// logoutConfirmPage asks the user to confirm a logout that arrived
+// without an id_token_hint and posts the request back with the token.
+var logoutConfirmPage = template.Must(template.New("confirm").Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Sign out</title>
+</head>
+<body>
+<form method="post" action="{{.Action}}">
+<p>Do you want to sign out?</p>
+<input type="hidden" name="confirm" value="{{.Confirm}}">
+{{with .Request.ClientID}}<input type="hidden" name="client_id" value="{{.}}">
+{{end}}{{with .Request.PostLogoutRedirectURI}}<input type="hidden" name="post_logout_redirect_uri" value="{{.}}">
+{{end}}{{with .Request.State}}<input type="hidden" name="state" value="{{.}}">
+{{end}}<button type="submit">Sign out</button>
+<a href="{{.CancelURL}}">Stay signed in</a>
+</form>
+</body>
+</html>
+`))
+
+// renderLogoutConfirmation writes the logout confirmation page and keeps
+// its token in a strict same-site cookie, so a form posted from another
+// site cannot carry it.
+func renderLogoutConfirmation(c *gin.Context, req dto.EndSessionRequest) {
+	confirm, err := utils.GenerateRandomString(service.SECRET_ENTROPY)
+	if err != nil {
+		log.Printf("[EndSession] Confirm Token: %v", err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Failed to sign out. Please try again.",
+			err,
+		)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(
+		service.LOGOUT_CONFIRM_COOKIE_NAME,
+		confirm,
+		service.LOGOUT_CONFIRM_TTL,
+		"/",
+		"",
+		true,
+		true,
+	)
+
+	c.Header("Content-Security-Policy", "default-src 'none'")
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	err = logoutConfirmPage.Execute(c.Writer, map[string]interface{}{
+		"Action":    c.Request.URL.Path,
+		"Confirm":   confirm,
+		"Request":   req,
+		"CancelURL": os.Getenv("CLIENT_BASE_URL") + "/",
+	})
+	if err != nil {
+		log.Printf("[EndSession] Render: %v", err)
+	}
+}
+
+// logoutConfirmed reports whether the request is the user's answer to the
+// confirmation page, and consumes the confirmation.
+func logoutConfirmed(c *gin.Context) bool {
+	if c.Request.Method != http.MethodPost {
+		return false
+	}
+	confirm := c.PostForm("confirm")
+	expected, err := c.Cookie(service.LOGOUT_CONFIRM_COOKIE_NAME)
+	if err != nil || confirm == "" || expected == "" {
+		return false
+	}
+
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(
+		service.LOGOUT_CONFIRM_COOKIE_NAME,
+		"",
+		-1,
+		"/",
+		"",
+		true,
+		true,
+	)
+	return subtle.ConstantTimeCompare([]byte(confirm), []byte(expected)) == 1
+}
+
+// clientCredentials merges the credentials of an HTTP Basic header, which
+// are form-urlencoded (RFC 6749 section 2.3.1), into those of the body. A
+// client authenticates with one method per request, and a body client_id
+// must name the client of the header.
+func clientCredentials(c *gin.Context, clientID, clientSecret *string) error {
+	id, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		return nil
+	}
+	id, errID := url.QueryUnescape(id)
+	secret, errSecret := url.QueryUnescape(secret)
+	if errID != nil || errSecret != nil {
+		return fmt.Errorf("malformed basic credentials")
+	}
+	if *clientSecret != "" {
+		return fmt.Errorf("multiple client authentication methods")
+	}
+	if *clientID != "" && *clientID != id {
+		return fmt.Errorf("client_id does not match the basic credentials")
+	}
+	*clientID, *clientSecret = id, secret
+	return nil
+}
+
+// pushedRequestBinding returns the random value that ties a request_uri to
+// this browser, minting the cookie on its first authorize request. A
+// request_uri that leaked from the browser cannot be resolved elsewhere.
+func pushedRequestBinding(c *gin.Context) string {
+	binding, err := c.Cookie(service.PAR_BINDING_COOKIE_NAME)
+	if err == nil && binding != "" {
+		return binding
+	}
+
+	binding, err = utils.GenerateRandomString(service.SECRET_ENTROPY)
+	if err != nil {
+		log.Printf("[Authorize] PAR Binding: %v", err)
+		return ""
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		service.PAR_BINDING_COOKIE_NAME,
+		binding,
+		service.PAR_REQUEST_TTL,
+		"/",
+		"",
+		true,
+		true,
+	)
+	return binding
+}
+
+// restoreAuthorizeRequest merges a remembered authorize query into the
+// current request for the same client without overriding explicit values.
+// The remembered auth_after always wins so it cannot be relaxed by the URL.
+func restoreAuthorizeRequest(c *gin.Context) {
+	saved, err := c.Cookie(service.AUTHORIZE_REQUEST_COOKIE_NAME)
+	if err != nil || saved == "" {
+		return
+	}
+
+	savedQuery, err := url.ParseQuery(saved)
+	if err != nil {
+		return
+	}
+
+	query := c.Request.URL.Query()
+	if savedQuery.Get("client_id") != query.Get("client_id") {
+		return
+	}
+
+	for key, values := range savedQuery {
+		if !query.Has(key) || key == authAfterParam {
+			query[key] = values
+		}
+	}
+	c.Request.URL.RawQuery = query.Encode()
+}
+
+// clearAuthorizeRequest removes the remembered authorize query.
+func clearAuthorizeRequest(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(
+		service.AUTHORIZE_REQUEST_COOKIE_NAME,
+		"",
+		-1,
+		"/",
+		"",
+		true,
+		true,
+	)
+}
+
+// oauthAuthorizeError maps an Authorize service error to an RFC 6749
+// error code and description. Session errors map to an empty code because
+// the user is sent through the login UI instead.
+func oauthAuthorizeError(err error) (string, string) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "pkce"):
+		return errors.OAuthInvalidRequest,
+			"code_challenge is missing or invalid"
+	case strings.Contains(msg, "unauthorized client"):
+		return errors.OAuthUnauthorizedClient,
+			"client is not allowed to use the authorization_code grant"
+	case strings.Contains(msg, "access denied"):
+		return errors.OAuthAccessDenied,
+			"the user is not allowed to access this client"
+	case strings.Contains(msg, "pushed authorization request required"):
+		return errors.OAuthInvalidRequest,
+			"the client must push the request to the PAR endpoint"
+	case strings.Contains(msg, "invalid request"):
+		return errors.OAuthInvalidRequest,
+			"max_age must be a non-negative integer"
+	case strings.Contains(strings.ToLower(msg), "session"),
+		strings.Contains(msg, "login required"):
+		return "", ""
+	default:
+		return errors.OAuthServerError,
+			"the authorization request could not be completed"
+	}
+}
+
+// promptNoneError maps an Authorize service error for a prompt=none
+// request, where the user cannot be shown the login or consent screen.
+func promptNoneError(err error) (string, string) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "consent required"):
+		return errors.OAuthConsentRequired,
+			"the user has not consented to the requested scopes"
+	case strings.Contains(msg, "step-up required"):
+		return errors.OAuthInteractionRequired,
+			"the user must verify a second factor"
+	case strings.Contains(strings.ToLower(msg), "session"),
+		strings.Contains(msg, "login required"):
+		return errors.OAuthLoginRequired,
+			"the user must sign in again"
+	}
+	return oauthAuthorizeError(err)
+}
+
+// oauthPARError maps a PAR endpoint service error to the HTTP status,
+// error code and description returned to the client.
+func oauthPARError(err error) (int, string, string) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "client verification"),
+		strings.Contains(msg, "uuid parse"):
+		return http.StatusUnauthorized, errors.OAuthInvalidClient,
+			"client authentication failed"
+	case strings.Contains(msg, "unauthorized client"):
+		return http.StatusBadRequest, errors.OAuthUnauthorizedClient,
+			"client is not allowed to use the authorization_code grant"
+	case strings.Contains(msg, "redirect validation"):
+		return http.StatusBadRequest, errors.OAuthInvalidRequest,
+			"redirect_uri is not registered for this client"
+	case strings.Contains(msg, "pkce"):
+		return http.StatusBadRequest, errors.OAuthInvalidRequest,
+			"code_challenge is missing or invalid"
+	case strings.Contains(msg, "invalid request: "):
+		_, description, _ := strings.Cut(msg, "invalid request: ")
+		return http.StatusBadRequest, errors.OAuthInvalidRequest, description
+	default:
+		return http.StatusInternalServerError, errors.OAuthServerError,
+			"the authorization request could not be stored"
+	}
+}
+
+// oauthTokenError maps a token endpoint service error to the HTTP status,
+// RFC 6749 error code and description returned to the client.
+func oauthTokenError(err error) (int, string, string) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "authorization pending"):
+		return http.StatusBadRequest, errors.OAuthAuthorizationPending,
+			"the user has not yet approved the request"
+	case strings.Contains(msg, "slow down"):
+		return http.StatusBadRequest, errors.OAuthSlowDown,
+			"polling too frequently, increase the interval by 5 seconds"
+	case strings.Contains(msg, "expired token"):
+		return http.StatusBadRequest, errors.OAuthExpiredToken,
+			"the device code has expired"
+	case strings.Contains(msg, "device denied"):
+		return http.StatusBadRequest, errors.OAuthAccessDenied,
+			"the user denied the request"
+	case strings.Contains(msg, "pkce"):
+		return http.StatusBadRequest, errors.OAuthInvalidGrant,
+			"code_verifier does not match the code_challenge"
+	case strings.Contains(msg, "redirect validation"):
+		return http.StatusBadRequest, errors.OAuthInvalidGrant,
+			"redirect_uri does not match the authorization request"
+	case strings.Contains(msg, "code exchange"),
+		strings.Contains(msg, "grant validation"):
+		return http.StatusBadRequest, errors.OAuthInvalidGrant,
+			"authorization code is invalid, expired or already used"
+	case strings.Contains(msg, "access denied"):
+		return http.StatusBadRequest, errors.OAuthInvalidGrant,
+			"the user is not allowed to access this client"
+	case strings.Contains(msg, "scope validation"):
+		return http.StatusBadRequest, errors.OAuthInvalidScope,
+			"requested scope is not allowed for this client"
+	case strings.Contains(msg, "unauthorized client"):
+		return http.StatusBadRequest, errors.OAuthUnauthorizedClient,
+			"client is not allowed to use this grant"
+	case strings.Contains(msg, "client verification"),
+		strings.Contains(msg, "uuid parse"):
+		return http.StatusUnauthorized, errors.OAuthInvalidClient,
+			"client authentication failed"
+	default:
+		return http.StatusInternalServerError, errors.OAuthServerError,
+			"the token request could not be completed"
+	}
+}
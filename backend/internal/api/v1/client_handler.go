@@ -4,7 +4,10 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
@@ -24,10 +27,10 @@ const (
 	actionDeleteClient = "delete_client"
 )
 
-// Token TTL guard limits
+// Token TTL guard limits. The longest access token TTL is
+// service.MaxAccessTokenTTL, which also bounds signing key retention.
 const (
 	MinAccessTokenTTL  = 1
-	MaxAccessTokenTTL  = 1440
 	MinRefreshTokenTTL = 1
 	MaxRefreshTokenTTL = 8760
 )
@@ -51,7 +54,7 @@ func validateTokenTTL(accStr, refStr string) (int, int, string) {
 		return 0, 0, "refresh_token_ttl must be an integer"
 	}
 
-	if accTTL < MinAccessTokenTTL || accTTL > MaxAccessTokenTTL {
+	if accTTL < MinAccessTokenTTL || accTTL > service.MaxAccessTokenTTL {
 		return 0, 0, "access_token_ttl must be between 1 and 1440"
 	}
 
@@ -62,6 +65,41 @@ func validateTokenTTL(accStr, refStr string) (int, int, string) {
 	return accTTL, refTTL, ""
 }
 
+// validateRedirectURIs merges the primary redirect URI with the additional
+// registered ones, dropping blanks and duplicates. Every URI must be
+// absolute and must not carry a fragment (RFC 6749 section 3.1.2).
+func validateRedirectURIs(primary string, extra []string) ([]string, string) {
+	var uris []string
+	for _, raw := range append([]string{primary}, extra...) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || slices.Contains(uris, raw) {
+			continue
+		}
+
+		parsed, err := url.Parse(raw)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return nil, "redirect_uris must be absolute URIs without fragments"
+		}
+		uris = append(uris, raw)
+	}
+
+	return uris, ""
+}
+
+// validateSigningAlg checks the requested token signing algorithm. Clients
+// that do not choose one keep RS256.
+func validateSigningAlg(alg string) (string, string) {
+	alg = strings.TrimSpace(alg)
+	if alg == "" {
+		return models.SigningAlgRS256, ""
+	}
+	if !slices.Contains(models.SigningAlgorithms, alg) {
+		return "", "token_signing_alg must be one of " +
+			strings.Join(models.SigningAlgorithms, ", ")
+	}
+	return alg, ""
+}
+
 // ClientHandler handles client management HTTP requests.
 type ClientHandler struct {
 	Service    service.ClientService
@@ -79,8 +117,18 @@ type ClientHandler struct {
 // @Param description formData string false "Description"
 // @Param base_url formData string true "Base URL"
 // @Param redirect_uri formData string true "Redirect URI"
+// @Param redirect_uris formData []string false "Additional Redirect URIs"
 // @Param logout_uri formData string true "Logout URI"
 // @Param grants formData []string true "Grants (e.g. authorization_code)"
+// @Param require_pkce formData bool false "Require PKCE on authorization"
+// @Param allowed_scopes formData string false "Client credentials scopes"
+// @Param token_signing_alg formData string false "Token signing algorithm"
+// @Param require_consent formData bool false "Require user consent"
+// @Param min_acr formData string false "Minimum ACR (1fa or 2fa)"
+// @Param require_par formData bool false "Require pushed authorization requests"
+// @Param allow_magic_link formData bool false "Allow email magic-link login"
+// @Param public_client formData bool false "Public client without a secret"
+// @Param frontchannel_logout_uri formData string false "Front-channel logout URI"
 // @Param roles formData []string false "Initial Roles"
 // @Param image formData file true "Client Icon"
 // @Success 201 {object} dto.SuccessResponse
@@ -127,16 +175,73 @@ func (h *ClientHandler) PostClient(c *gin.Context) {
 		return
 	}
 
+	redirectURIs, valErr := validateRedirectURIs(
+		c.PostForm("redirect_uri"),
+		c.PostFormArray("redirect_uris"),
+	)
+	if valErr != "" {
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			valErr,
+			valErr,
+		)
+		return
+	}
+
+	signingAlg, valErr := validateSigningAlg(c.PostForm("token_signing_alg"))
+	if valErr != "" {
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			valErr,
+			valErr,
+		)
+		return
+	}
+
+	minACR := strings.TrimSpace(c.PostForm("min_acr"))
+	if minACR != "" && !slices.Contains(models.ACRValues, minACR) {
+		valErr := "min_acr must be one of " +
+			strings.Join(models.ACRValues, ", ")
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			valErr,
+			valErr,
+		)
+		return
+	}
+
+	requirePKCE, _ := strconv.ParseBool(c.PostForm("require_pkce"))
+	requireConsent, _ := strconv.ParseBool(c.PostForm("require_consent"))
+	requirePAR, _ := strconv.ParseBool(c.PostForm("require_par"))
+	allowMagicLink, _ := strconv.ParseBool(c.PostForm("allow_magic_link"))
+	publicClient, _ := strconv.ParseBool(c.PostForm("public_client"))
+
 	req := dto.CreateClientRequest{
-		Name:            c.PostForm("name"),
-		BaseURL:         c.PostForm("base_url"),
-		RedirectURI:     c.PostForm("redirect_uri"),
-		LogoutURI:       c.PostForm("logout_uri"),
-		Description:     c.PostForm("description"),
-		Grants:          c.PostFormArray("grants"),
-		OnePortalLink:   c.PostForm("one_portal_link"),
-		AccessTokenTTL:  accTTL,
-		RefreshTokenTTL: refTTL,
+		Name:                  c.PostForm("name"),
+		BaseURL:               c.PostForm("base_url"),
+		RedirectURI:           c.PostForm("redirect_uri"),
+		RedirectURIs:          redirectURIs,
+		LogoutURI:             c.PostForm("logout_uri"),
+		FrontchannelLogoutURI: c.PostForm("frontchannel_logout_uri"),
+		Description:           c.PostForm("description"),
+		Grants:                c.PostFormArray("grants"),
+		OnePortalLink:         c.PostForm("one_portal_link"),
+		AccessTokenTTL:        accTTL,
+		RefreshTokenTTL:       refTTL,
+		RequirePKCE:           requirePKCE,
+		AllowedScopes:         c.PostForm("allowed_scopes"),
+		TokenSigningAlg:       signingAlg,
+		RequireConsent:        requireConsent,
+		MinACR:                minACR,
+		RequirePAR:            requirePAR,
+		AllowMagicLink:        allowMagicLink,
+		PublicClient:          publicClient,
 	}
 
 	userID := c.GetString("user_id")
@@ -397,6 +502,8 @@ func (h *ClientHandler) GetClient(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Client UUID"
+// @Param redirect_uri formData string true "Redirect URI"
+// @Param redirect_uris formData []string false "Additional Redirect URIs; omit to keep the registered ones"
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -460,16 +567,79 @@ func (h *ClientHandler) PutClient(c *gin.Context) {
 		return
 	}
 
+	// A form without redirect_uris keeps the registered list; the service
+	// merges the primary URI into it.
+	extraURIs, hasExtraURIs := c.GetPostFormArray("redirect_uris")
+	redirectURIs, valErr := validateRedirectURIs(
+		c.PostForm("redirect_uri"),
+		extraURIs,
+	)
+	if !hasExtraURIs {
+		redirectURIs = nil
+	}
+	if valErr != "" {
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			valErr,
+			valErr,
+		)
+		return
+	}
+
+	signingAlg, valErr := validateSigningAlg(c.PostForm("token_signing_alg"))
+	if valErr != "" {
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			valErr,
+			valErr,
+		)
+		return
+	}
+
+	minACR := strings.TrimSpace(c.PostForm("min_acr"))
+	if minACR != "" && !slices.Contains(models.ACRValues, minACR) {
+		valErr := "min_acr must be one of " +
+			strings.Join(models.ACRValues, ", ")
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			valErr,
+			valErr,
+		)
+		return
+	}
+
+	requirePKCE, _ := strconv.ParseBool(c.PostForm("require_pkce"))
+	requireConsent, _ := strconv.ParseBool(c.PostForm("require_consent"))
+	requirePAR, _ := strconv.ParseBool(c.PostForm("require_par"))
+	allowMagicLink, _ := strconv.ParseBool(c.PostForm("allow_magic_link"))
+	publicClient, _ := strconv.ParseBool(c.PostForm("public_client"))
+
 	req := dto.CreateClientRequest{
-		Name:            c.PostForm("name"),
-		BaseURL:         c.PostForm("base_url"),
-		RedirectURI:     c.PostForm("redirect_uri"),
-		LogoutURI:       c.PostForm("logout_uri"),
-		Description:     c.PostForm("description"),
-		Grants:          c.PostFormArray("grants"),
-		OnePortalLink:   c.PostForm("one_portal_link"),
-		AccessTokenTTL:  accTTL,
-		RefreshTokenTTL: refTTL,
+		Name:                  c.PostForm("name"),
+		BaseURL:               c.PostForm("base_url"),
+		RedirectURI:           c.PostForm("redirect_uri"),
+		RedirectURIs:          redirectURIs,
+		LogoutURI:             c.PostForm("logout_uri"),
+		FrontchannelLogoutURI: c.PostForm("frontchannel_logout_uri"),
+		Description:           c.PostForm("description"),
+		Grants:                c.PostFormArray("grants"),
+		OnePortalLink:         c.PostForm("one_portal_link"),
+		AccessTokenTTL:        accTTL,
+		RefreshTokenTTL:       refTTL,
+		RequirePKCE:           requirePKCE,
+		AllowedScopes:         c.PostForm("allowed_scopes"),
+		TokenSigningAlg:       signingAlg,
+		RequireConsent:        requireConsent,
+		MinACR:                minACR,
+		RequirePAR:            requirePAR,
+		AllowMagicLink:        allowMagicLink,
+		PublicClient:          publicClient,
 	}
 
 	metadata := buildMetadata(map[string]interface{}{
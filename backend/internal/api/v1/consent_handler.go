@@ -0,0 +1,356 @@
+package v1
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Action constants for audit logging
+const (
+	actionConsentGrant  = "consent_grant"
+	actionConsentDeny   = "consent_deny"
+	actionConsentRevoke = "consent_revoke"
+)
+
+// ConsentHandler handles the consent screen and the user's stored consents.
+type ConsentHandler struct {
+	ConsentService service.ConsentService
+	AuthService    service.AuthService
+	LogService     service.LogService
+}
+
+// GetConsent describes the pending consent for the signed-in user
+// @Summary Get consent prompt
+// @Description Returns the client and the scopes the consent screen asks
+// @Description the user to approve, along with the scopes already granted.
+// @Tags Authentication
+// @Produce json
+// @Param client_id query string true "Client ID"
+// @Param scope query string false "Space-delimited requested scopes"
+// @Success 200 {object} dto.ConsentPromptResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/consent [get]
+func (h *ConsentHandler) GetConsent(c *gin.Context) {
+	sessionToken, err := c.Cookie(service.SESSION_COOKIE_NAME)
+	if err != nil {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeSessionExpired,
+			"Please sign in again.",
+			"no session found",
+		)
+		return
+	}
+
+	resp, err := h.ConsentService.GetConsentPrompt(
+		c.Request.Context(),
+		sessionToken,
+		c.Query("client_id"),
+		c.Query("scope"),
+	)
+	if err != nil {
+		log.Printf("[GetConsent] %v", err)
+		sendConsentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PostConsent records the user's answer on the consent screen
+// @Summary Answer consent prompt
+// @Description Grants or denies the scopes a client requested. Either way
+// @Description the response carries the URL the browser continues to: the
+// @Description authorize endpoint when approved, or the client's redirect
+// @Description URI with an access_denied error when denied.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param consent body dto.ConsentRequest true "Consent answer"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/consent [post]
+func (h *ConsentHandler) PostConsent(c *gin.Context) {
+	var req dto.ConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[PostConsent] Bind JSON: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid request format.",
+			err,
+		)
+		return
+	}
+
+	sessionToken, err := c.Cookie(service.SESSION_COOKIE_NAME)
+	if err != nil {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeSessionExpired,
+			"Please sign in again.",
+			"no session found",
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	actor := sessionActor(c, h.AuthService, h.LogService, sessionToken)
+	metadata := buildMetadata(map[string]interface{}{
+		"client_id":  req.ClientID,
+		"scope":      req.Scope,
+		"ip":         c.ClientIP(),
+		"user_agent": c.Request.UserAgent(),
+	})
+
+	if !req.Approve {
+		redirectURL, err := h.denyConsent(c, req.ClientID)
+		if err != nil {
+			log.Printf("[PostConsent] %v", err)
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"The authorization request could not be found.",
+				err,
+			)
+			return
+		}
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actor,
+			&dto.PostAuditLogRequest{
+				Action:   actionConsentDeny,
+				Target:   req.ClientID,
+				Status:   models.StatusSuccess,
+				Metadata: metadata,
+			})
+		c.JSON(http.StatusOK, gin.H{"redirect_url": redirectURL})
+		return
+	}
+
+	err = h.ConsentService.GrantConsent(
+		ctx,
+		sessionToken,
+		req.ClientID,
+		req.Scope,
+	)
+	if err != nil {
+		log.Printf("[PostConsent] %v", err)
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actor,
+			&dto.PostAuditLogRequest{
+				Action:   actionConsentGrant,
+				Target:   req.ClientID,
+				Status:   models.StatusFail,
+				Metadata: metadata,
+			})
+		sendConsentError(c, err)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actor,
+		&dto.PostAuditLogRequest{
+			Action:   actionConsentGrant,
+			Target:   req.ClientID,
+			Status:   models.StatusSuccess,
+			Metadata: metadata,
+		})
+
+	// The remembered authorize request supplies the remaining parameters.
+	c.JSON(http.StatusOK, gin.H{
+		"redirect_url": utils.AppendQuery(
+			service.BackendBaseURL()+"/api/v1/auth/authorize",
+			map[string]string{"client_id": req.ClientID},
+		),
+	})
+}
+
+// GetMyConsents lists the clients the user has granted consent to
+// @Summary List my consents
+// @Description Returns every client the authenticated user has consented
+// @Description to, with the granted scopes.
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Success 200 {array} dto.ConsentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /me/consents [get]
+func (h *ConsentHandler) GetMyConsents(c *gin.Context) {
+	uID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		log.Printf("[GetMyConsents] UUID Parse: %v", err)
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid identification format.",
+			"invalid identification format",
+		)
+		return
+	}
+
+	resp, err := h.ConsentService.ListConsents(c.Request.Context(), uID)
+	if err != nil {
+		log.Printf("[GetMyConsents] %v", err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeDatabaseError,
+			"Failed to retrieve consents.",
+			err,
+		)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteMyConsent revokes the user's consent for a client
+// @Summary Revoke my consent
+// @Description Withdraws the consent given to a client and revokes the
+// @Description refresh tokens it holds for the user. The next sign-in to
+// @Description the client asks for consent again.
+// @Tags Users
+// @Security Bearer
+// @Produce json
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /me/consents/{client_id} [delete]
+func (h *ConsentHandler) DeleteMyConsent(c *gin.Context) {
+	userIDStr := c.GetString("user_id")
+	uID, uErr := uuid.Parse(userIDStr)
+	cID, cErr := uuid.Parse(c.Param("client_id"))
+	if uErr != nil || cErr != nil {
+		log.Print("[DeleteMyConsent] UUID Parse: invalid uuid format")
+		errors.SendString(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid identification format.",
+			"invalid identification format",
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	actor, _ := h.LogService.GetUserEmail(ctx, uID[:])
+	if actor == "" {
+		actor = userIDStr
+	}
+	logReq := &dto.PostAuditLogRequest{
+		Action: actionConsentRevoke,
+		Target: cID.String(),
+		Status: models.StatusSuccess,
+		Metadata: buildMetadata(map[string]interface{}{
+			"client_id":  cID.String(),
+			"ip":         c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+		}),
+	}
+
+	if err := h.ConsentService.RevokeConsent(ctx, uID, cID); err != nil {
+		log.Printf("[DeleteMyConsent] %v", err)
+		logReq.Status = models.StatusFail
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actor, logReq)
+		if strings.Contains(err.Error(), "not found") {
+			errors.Send(
+				c,
+				http.StatusNotFound,
+				errors.CodeNotFound,
+				"No consent found for this client.",
+				err,
+			)
+			return
+		}
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeDatabaseError,
+			"Failed to revoke consent.",
+			err,
+		)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actor, logReq)
+	c.JSON(http.StatusOK, gin.H{"message": "Consent revoked."})
+}
+
+// denyConsent builds the access_denied redirect for the remembered
+// authorize request of the client and forgets the request.
+func (h *ConsentHandler) denyConsent(
+	c *gin.Context,
+	clientID string,
+) (string, error) {
+	saved, _ := c.Cookie(service.AUTHORIZE_REQUEST_COOKIE_NAME)
+	query, err := url.ParseQuery(saved)
+	if err != nil || query.Get("client_id") != clientID {
+		return "", fmt.Errorf("authorize request not found")
+	}
+
+	validRedirect, err := h.AuthService.ValidateRedirectURI(
+		c.Request.Context(),
+		clientID,
+		query.Get("redirect_uri"),
+	)
+	if err != nil {
+		return "", err
+	}
+	clearAuthorizeRequest(c)
+
+	return utils.AppendQuery(validRedirect, map[string]string{
+		"error":             errors.OAuthAccessDenied,
+		"error_description": "the user denied consent",
+		"state":             query.Get("state"),
+	}), nil
+}
+
+// sendConsentError maps a consent service error to an HTTP response.
+func sendConsentError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "session"):
+		errors.Send(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeSessionExpired,
+			"Please sign in again.",
+			err,
+		)
+	case strings.Contains(msg, "uuid parse"),
+		strings.Contains(msg, "GetClient"):
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeClientError,
+			"The client could not be found.",
+			err,
+		)
+	default:
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"An unexpected error occurred. Please try again.",
+			err,
+		)
+	}
+}
@@ -0,0 +1,256 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Action constants for audit logging
+const (
+	actionDeviceAuthorization = "device_authorization"
+	actionDeviceApprove       = "device_approve"
+	actionDeviceDeny          = "device_deny"
+)
+
+// DeviceHandler handles the device authorization grant (RFC 8628): the
+// device authorization endpoint and the verification page on which the
+// user approves a device.
+type DeviceHandler struct {
+	DeviceService service.DeviceService
+	AuthService   service.AuthService
+	LogService    service.LogService
+}
+
+// PostDeviceAuthorization starts the device flow for a device
+// @Summary Device authorization
+// @Description Issues a device_code and a user_code. The device shows the
+// @Description user code and verification URI, then polls /auth/token with
+// @Description grant_type=urn:ietf:params:oauth:grant-type:device_code.
+// @Tags Authentication
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param client_id formData string true "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Param scope formData string false "Space-delimited scopes"
+// @Success 200 {object} dto.DeviceAuthorizationResponse
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /auth/device_authorization [post]
+func (h *DeviceHandler) PostDeviceAuthorization(c *gin.Context) {
+	var req dto.DeviceAuthorizationRequest
+	_ = c.ShouldBind(&req)
+	err := clientCredentials(c, &req.ClientID, &req.ClientSecret)
+	if err != nil {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			err.Error(),
+		)
+		return
+	}
+	if req.ClientID == "" {
+		errors.SendOAuth(
+			c,
+			http.StatusBadRequest,
+			errors.OAuthInvalidRequest,
+			"client_id is required",
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	clientName := h.LogService.ResolveClientName(ctx, req.ClientID)
+	logReq := &dto.PostAuditLogRequest{
+		Action: actionDeviceAuthorization,
+		Target: req.ClientID,
+		Status: models.StatusSuccess,
+		Metadata: buildMetadata(map[string]interface{}{
+			"client_id":   req.ClientID,
+			"client_name": clientName,
+			"scope":       req.Scope,
+			"ip":          c.ClientIP(),
+			"user_agent":  c.Request.UserAgent(),
+		}),
+	}
+
+	resp, err := h.DeviceService.StartDeviceAuthorization(ctx, req)
+	if err != nil {
+		log.Printf("[PostDeviceAuthorization] %v", err)
+		logReq.Status = models.StatusFail
+		_ = h.LogService.PostAuditLogWithActorString(ctx, clientName, logReq)
+		status, code, description := oauthTokenError(err)
+		errors.SendOAuth(c, status, code, description)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, clientName, logReq)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetDevice describes the device request a user code belongs to
+// @Summary Get device request
+// @Description Returns the client and scopes of the device request the
+// @Description signed-in user entered the code of.
+// @Tags Authentication
+// @Produce json
+// @Param user_code query string true "User code shown on the device"
+// @Success 200 {object} dto.DeviceVerificationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/device [get]
+func (h *DeviceHandler) GetDevice(c *gin.Context) {
+	sessionToken, err := c.Cookie(service.SESSION_COOKIE_NAME)
+	if err != nil {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeSessionExpired,
+			"Please sign in again.",
+			"no session found",
+		)
+		return
+	}
+
+	resp, err := h.DeviceService.GetDeviceVerification(
+		c.Request.Context(),
+		sessionToken,
+		c.Query("user_code"),
+	)
+	if err != nil {
+		log.Printf("[GetDevice] %v", err)
+		sendDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PostDevice records the user's answer for a device request
+// @Summary Answer device request
+// @Description Approves or denies the device request of a user code. The
+// @Description polling device receives its tokens or access_denied.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param device body dto.DeviceVerificationRequest true "Device answer"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /auth/device [post]
+func (h *DeviceHandler) PostDevice(c *gin.Context) {
+	var req dto.DeviceVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[PostDevice] Bind JSON: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid request format.",
+			err,
+		)
+		return
+	}
+
+	sessionToken, err := c.Cookie(service.SESSION_COOKIE_NAME)
+	if err != nil {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeSessionExpired,
+			"Please sign in again.",
+			"no session found",
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	actor := sessionActor(c, h.AuthService, h.LogService, sessionToken)
+	action := actionDeviceDeny
+	if req.Approve {
+		action = actionDeviceApprove
+	}
+	logReq := &dto.PostAuditLogRequest{
+		Action: action,
+		Target: req.UserCode,
+		Status: models.StatusSuccess,
+		Metadata: buildMetadata(map[string]interface{}{
+			"user_code":  req.UserCode,
+			"ip":         c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+		}),
+	}
+
+	err = h.DeviceService.AnswerDeviceVerification(ctx, sessionToken, req)
+	if err != nil {
+		log.Printf("[PostDevice] %v", err)
+		logReq.Status = models.StatusFail
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actor, logReq)
+		sendDeviceError(c, err)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actor, logReq)
+	if req.Approve {
+		c.JSON(http.StatusOK, gin.H{"message": "Device approved."})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Device denied."})
+}
+
+// sendDeviceError maps a device service error to an HTTP response.
+func sendDeviceError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "session"):
+		errors.Send(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeSessionExpired,
+			"Please sign in again.",
+			err,
+		)
+	case strings.Contains(msg, "invalid user code"):
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"The code is invalid or has expired.",
+			err,
+		)
+	case strings.Contains(msg, "access denied"):
+		errors.Send(
+			c,
+			http.StatusForbidden,
+			errors.CodeForbidden,
+			"You are not allowed to use this application.",
+			err,
+		)
+	case strings.Contains(msg, "step-up required"):
+		errors.Send(
+			c,
+			http.StatusForbidden,
+			errors.CodeMFAFailed,
+			"Verify a second factor to approve this device.",
+			err,
+		)
+	default:
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"An unexpected error occurred. Please try again.",
+			err,
+		)
+	}
+}
@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/middleware"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Action constants for audit logging
+const (
+	actionRotateSigningKey = "rotate_signing_key"
+	actionRewrapKeys       = "rewrap_encryption_keys"
+)
+
+// KeyHandler handles token signing key maintenance.
+type KeyHandler struct {
+	KeyStore   service.KeyStore
+	MFAService service.MFAService
+	LogService service.LogService
+}
+
+// GetSigningKeys handles GET /v1/admin/keys
+// @Summary List token signing keys
+// @Description Lists the active signing key of every algorithm and the
+// @Description retired keys that are still published in JWKS. Key material
+// @Description is never returned.
+// @Tags Keys
+// @Produce json
+// @Success 200 {array} dto.SigningKeyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Security CookieAuth
+// @Router /admin/keys [get]
+func (h *KeyHandler) GetSigningKeys(c *gin.Context) {
+	if !middleware.HasPermission(c, "Manage Signing Keys") {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeUnauthorized,
+			"Unauthorized access.",
+			"Unauthorized",
+		)
+		return
+	}
+
+	keys := h.KeyStore.Keys()
+	resp := make([]dto.SigningKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		item := dto.SigningKeyResponse{
+			KeyID:     key.ID,
+			Algorithm: key.Algorithm,
+			Active:    key.ID == h.KeyStore.ActiveKey(key.Algorithm).ID,
+			CreatedAt: key.CreatedAt,
+		}
+		if !key.ExpiresAt.IsZero() {
+			expiresAt := key.ExpiresAt
+			item.ExpiresAt = &expiresAt
+		}
+		resp = append(resp, item)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PostRotateSigningKey handles POST /v1/admin/keys/rotate
+// @Summary Rotate the token signing keys
+// @Description Generates a new active signing key for the given algorithm,
+// @Description or for every algorithm when none is given. Previous keys
+// @Description keep verifying tokens until they have expired. Each
+// @Description algorithm rotates on its own; the response reports every
+// @Description result and is 207 when only some of them succeeded.
+// @Tags Keys
+// @Produce json
+// @Param alg query string false "RS256, PS256, ES256 or EdDSA"
+// @Success 201 {array} dto.SigningKeyRotationResponse
+// @Success 207 {array} dto.SigningKeyRotationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {array} dto.SigningKeyRotationResponse
+// @Security CookieAuth
+// @Router /admin/keys/rotate [post]
+func (h *KeyHandler) PostRotateSigningKey(c *gin.Context) {
+	if !middleware.HasPermission(c, "Manage Signing Keys") {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeUnauthorized,
+			"Unauthorized access.",
+			"Unauthorized",
+		)
+		return
+	}
+
+	algorithms := models.SigningAlgorithms
+	if alg := c.Query("alg"); alg != "" {
+		if !slices.Contains(models.SigningAlgorithms, alg) {
+			errors.SendString(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"Unsupported signing algorithm.",
+				"alg must be one of "+
+					strings.Join(models.SigningAlgorithms, ", "),
+			)
+			return
+		}
+		algorithms = []string{alg}
+	}
+
+	userIDStr := c.GetString("user_id")
+	userID, _ := uuid.Parse(userIDStr)
+	ctx := c.Request.Context()
+	actorName, _ := h.LogService.GetUserEmail(ctx, userID[:])
+	if actorName == "" {
+		actorName = userIDStr
+	}
+
+	// A failed algorithm does not stop the others; the caller gets the
+	// result of each one and can retry just the failed ones with ?alg.
+	resp := make([]dto.SigningKeyRotationResponse, 0, len(algorithms))
+	failed := 0
+	for _, alg := range algorithms {
+		var previousID string
+		if previous := h.KeyStore.ActiveKey(alg); previous != nil {
+			previousID = previous.ID
+		}
+		key, err := h.KeyStore.Rotate(ctx, alg)
+		if err != nil {
+			log.Printf("[PostRotateSigningKey] %s: %v", alg, err)
+			_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+				&dto.PostAuditLogRequest{
+					Action: actionRotateSigningKey,
+					Target: previousID,
+					Status: models.StatusFail,
+					Metadata: buildMetadata(map[string]interface{}{
+						"alg":        alg,
+						"ip":         c.ClientIP(),
+						"user_agent": c.Request.UserAgent(),
+						"error":      err.Error(),
+					}),
+				})
+			failed++
+			message := "Failed to rotate the signing key."
+			if err == service.ErrKeyRotationConflict {
+				message = "The signing key was just rotated by " +
+					"another instance."
+			}
+			resp = append(resp, dto.SigningKeyRotationResponse{
+				Algorithm:     alg,
+				PreviousKeyID: previousID,
+				Error:         message,
+			})
+			continue
+		}
+
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+			&dto.PostAuditLogRequest{
+				Action: actionRotateSigningKey,
+				Target: key.ID,
+				Status: models.StatusSuccess,
+				Metadata: buildMetadata(map[string]interface{}{
+					"alg":          alg,
+					"previous_kid": previousID,
+					"ip":           c.ClientIP(),
+					"user_agent":   c.Request.UserAgent(),
+				}),
+			})
+
+		createdAt := key.CreatedAt
+		resp = append(resp, dto.SigningKeyRotationResponse{
+			Algorithm:     key.Algorithm,
+			Rotated:       true,
+			KeyID:         key.ID,
+			PreviousKeyID: previousID,
+			CreatedAt:     &createdAt,
+		})
+	}
+
+	status := http.StatusCreated
+	switch {
+	case failed == len(algorithms):
+		status = http.StatusInternalServerError
+	case failed > 0:
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
+}
+
+// PostRewrapKeys handles POST /v1/admin/keys/rewrap
+// @Summary Re-encrypt stored secrets under the active encryption key
+// @Description After a new MFA_ENCRYPTION_KEY_VERSION is deployed, moves
+// @Description the signing keys kept in the database and every TOTP secret
+// @Description onto it. Old key versions can be removed afterwards.
+// @Tags Keys
+// @Produce json
+// @Success 200 {object} dto.RewrapKeysResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security CookieAuth
+// @Router /admin/keys/rewrap [post]
+func (h *KeyHandler) PostRewrapKeys(c *gin.Context) {
+	if !middleware.HasPermission(c, "Manage Signing Keys") {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeUnauthorized,
+			"Unauthorized access.",
+			"Unauthorized",
+		)
+		return
+	}
+
+	userIDStr := c.GetString("user_id")
+	userID, _ := uuid.Parse(userIDStr)
+	ctx := c.Request.Context()
+	actorName, _ := h.LogService.GetUserEmail(ctx, userID[:])
+	if actorName == "" {
+		actorName = userIDStr
+	}
+
+	var resp dto.RewrapKeysResponse
+	var err error
+	resp.SigningKeys, err = h.KeyStore.Rewrap(ctx)
+	if err == nil {
+		resp.MFASecrets, err = h.MFAService.RewrapSecrets(ctx)
+	}
+
+	metadata := map[string]interface{}{
+		"signing_keys": resp.SigningKeys,
+		"mfa_secrets":  resp.MFASecrets,
+		"ip":           c.ClientIP(),
+		"user_agent":   c.Request.UserAgent(),
+	}
+	if err != nil {
+		log.Printf("[PostRewrapKeys] %v", err)
+		metadata["error"] = err.Error()
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+			&dto.PostAuditLogRequest{
+				Action:   actionRewrapKeys,
+				Target:   "encryption_keys",
+				Status:   models.StatusFail,
+				Metadata: buildMetadata(metadata),
+			})
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Failed to re-encrypt the stored secrets.",
+			err,
+		)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+		&dto.PostAuditLogRequest{
+			Action:   actionRewrapKeys,
+			Target:   "encryption_keys",
+			Status:   models.StatusSuccess,
+			Metadata: buildMetadata(metadata),
+		})
+
+	c.JSON(http.StatusOK, resp)
+}
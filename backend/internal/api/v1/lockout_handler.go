@@ -0,0 +1,245 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/middleware"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Action constants for audit logging
+const (
+	actionUpdateLockoutPolicy = "update_lockout_policy"
+	actionUnlockAccount       = "unlock_account"
+)
+
+// LockoutHandler handles the account lockout policy and the unlocking of
+// accounts by administrators.
+type LockoutHandler struct {
+	LockoutService service.LockoutService
+	LogService     service.LogService
+}
+
+// GetLockoutPolicy handles GET /v1/admin/security/lockout
+// @Summary Get the account lockout policy
+// @Description Returns the progressive delay and lockout applied to
+// @Description accounts after failed sign-ins.
+// @Tags Security
+// @Produce json
+// @Success 200 {object} dto.LockoutPolicyResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security CookieAuth
+// @Router /admin/security/lockout [get]
+func (h *LockoutHandler) GetLockoutPolicy(c *gin.Context) {
+	if !middleware.HasPermission(c, "Manage Account Lockout") {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeUnauthorized,
+			"Unauthorized access.",
+			"Unauthorized",
+		)
+		return
+	}
+
+	resp, err := h.LockoutService.GetPolicy(c.Request.Context())
+	if err != nil {
+		log.Printf("[GetLockoutPolicy] %v", err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeDatabaseError,
+			"Failed to retrieve the lockout policy.",
+			err,
+		)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PutLockoutPolicy handles PUT /v1/admin/security/lockout
+// @Summary Update the account lockout policy
+// @Description Replaces the progressive delay and lockout applied to
+// @Description accounts after failed sign-ins.
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param policy body dto.LockoutPolicyRequest true "Lockout policy"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security CookieAuth
+// @Router /admin/security/lockout [put]
+func (h *LockoutHandler) PutLockoutPolicy(c *gin.Context) {
+	if !middleware.HasPermission(c, "Manage Account Lockout") {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeUnauthorized,
+			"Unauthorized access.",
+			"Unauthorized",
+		)
+		return
+	}
+
+	var req dto.LockoutPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[PutLockoutPolicy] Bind JSON: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid lockout policy.",
+			err,
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	actorName := h.actorName(c)
+	logReq := &dto.PostAuditLogRequest{
+		Action: actionUpdateLockoutPolicy,
+		Target: "lockout_policy",
+		Status: models.StatusSuccess,
+		Metadata: buildMetadata(map[string]interface{}{
+			"max_failures":           req.MaxFailures,
+			"lockout_minutes":        req.LockoutMinutes,
+			"backoff_base_seconds":   req.BackoffBaseSeconds,
+			"backoff_max_seconds":    req.BackoffMaxSeconds,
+			"failure_window_minutes": req.FailureWindowMinutes,
+			"ip":                     c.ClientIP(),
+			"user_agent":             c.Request.UserAgent(),
+		}),
+	}
+
+	if err := h.LockoutService.UpdatePolicy(ctx, req); err != nil {
+		log.Printf("[PutLockoutPolicy] %v", err)
+		logReq.Status = models.StatusFail
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName, logReq)
+		if strings.Contains(err.Error(), "invalid policy") {
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"The maximum delay cannot be below the base delay.",
+				err,
+			)
+			return
+		}
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeDatabaseError,
+			"Failed to update the lockout policy.",
+			err,
+		)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actorName, logReq)
+	_ = h.LogService.PostSecurityLogWithActorString(ctx, actorName, logReq)
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Lockout policy updated.",
+	})
+}
+
+// PostUnlockUser handles POST /v1/admin/users/:id/unlock
+// @Summary Unlock a user account
+// @Description Clears the failed sign-in count and any lock of a user.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security CookieAuth
+// @Router /admin/users/{id}/unlock [post]
+func (h *LockoutHandler) PostUnlockUser(c *gin.Context) {
+	if !middleware.HasPermission(c, "Edit user") {
+		errors.SendString(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeUnauthorized,
+			"Unauthorized access.",
+			"Unauthorized",
+		)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		log.Printf("[PostUnlockUser] UUID Parse: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid ID Format.",
+			err,
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	actorName := h.actorName(c)
+	email, err := h.LockoutService.UnlockUser(ctx, userID)
+	logReq := &dto.PostAuditLogRequest{
+		Action: actionUnlockAccount,
+		Target: userID.String(),
+		Status: models.StatusSuccess,
+		Metadata: buildMetadata(map[string]interface{}{
+			"target_email": email,
+			"ip":           c.ClientIP(),
+			"user_agent":   c.Request.UserAgent(),
+		}),
+	}
+	if err != nil {
+		log.Printf("[PostUnlockUser] %v", err)
+		logReq.Status = models.StatusFail
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName, logReq)
+		if strings.Contains(err.Error(), "not found") {
+			errors.Send(
+				c,
+				http.StatusNotFound,
+				errors.CodeNotFound,
+				"User not found.",
+				err,
+			)
+			return
+		}
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeDatabaseError,
+			"Failed to unlock the account.",
+			err,
+		)
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actorName, logReq)
+	_ = h.LogService.PostSecurityLogWithActorString(ctx, actorName, logReq)
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Account unlocked."})
+}
+
+// actorName resolves the email of the administrator making the request.
+func (h *LockoutHandler) actorName(c *gin.Context) string {
+	userIDStr := c.GetString("user_id")
+	userID, _ := uuid.Parse(userIDStr)
+	actorName, _ := h.LogService.GetUserEmail(c.Request.Context(), userID[:])
+	if actorName == "" {
+		actorName = userIDStr
+	}
+	return actorName
+}
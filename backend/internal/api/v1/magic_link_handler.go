@@ -0,0 +1,292 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const actionRequestMagicLink = "request_magic_link"
+
+// MagicLinkHandler handles passwordless login through emailed links.
+type MagicLinkHandler struct {
+	MagicLinkService service.MagicLinkService
+	AuthService      service.AuthService
+	LogService       service.LogService
+}
+
+// PostMagicLink handles POST /v1/auth/magic-link
+// @Summary Request a magic sign-in link
+// @Description Emails a single-use sign-in link to the account of the
+// @Description email, if there is one, for a client that allows
+// @Description magic-link login. The answer is the same for unknown
+// @Description emails. The pending authorization request of the browser
+// @Description travels with the link.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.MagicLinkRequest true "Link request"
+// @Success 200 {object} dto.MagicLinkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/magic-link [post]
+func (h *MagicLinkHandler) PostMagicLink(c *gin.Context) {
+	var req dto.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[PostMagicLink] Bind JSON: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid request format.",
+			err,
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	authorizeQuery, _ := c.Cookie(service.AUTHORIZE_REQUEST_COOKIE_NAME)
+	err := h.MagicLinkService.RequestLink(
+		ctx,
+		req.Email,
+		req.ClientID,
+		req.RedirectURI,
+		authorizeQuery,
+	)
+
+	metadata := map[string]interface{}{
+		"client_id":  req.ClientID,
+		"ip":         c.ClientIP(),
+		"user_agent": c.Request.UserAgent(),
+	}
+	logReq := &dto.PostAuditLogRequest{
+		Action: actionRequestMagicLink,
+		Target: req.Email,
+		Status: models.StatusSuccess,
+	}
+
+	if err != nil {
+		log.Printf("[PostMagicLink] Service: %v", err)
+		metadata["error"] = err.Error()
+		logReq.Status = models.StatusFail
+		logReq.Metadata = buildMetadata(metadata)
+		_ = h.LogService.PostAuditLogWithActorString(ctx, req.Email, logReq)
+		_ = h.LogService.PostSecurityLogWithActorString(
+			ctx,
+			req.Email,
+			logReq,
+		)
+
+		switch {
+		case strings.Contains(err.Error(), "invalid client"):
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeClientError,
+				"The client is invalid.",
+				err,
+			)
+		case strings.Contains(err.Error(), "not allowed"):
+			errors.Send(
+				c,
+				http.StatusForbidden,
+				errors.CodeForbidden,
+				"Magic-link sign-in is not enabled for this app.",
+				err,
+			)
+		case strings.Contains(err.Error(), "redirect validation"):
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"The redirect URI is not registered for this app.",
+				err,
+			)
+		default:
+			errors.Send(
+				c,
+				http.StatusInternalServerError,
+				errors.CodeInternalError,
+				"Failed to send the sign-in link. Please try again.",
+				err,
+			)
+		}
+		return
+	}
+
+	logReq.Metadata = buildMetadata(metadata)
+	_ = h.LogService.PostAuditLogWithActorString(ctx, req.Email, logReq)
+	_ = h.LogService.PostSecurityLogWithActorString(ctx, req.Email, logReq)
+
+	c.JSON(http.StatusOK, dto.MagicLinkResponse{
+		Message: "If the email belongs to an account, " +
+			"a sign-in link is on its way.",
+		ExpiresIn: service.MAGIC_LINK_TTL,
+	})
+}
+
+// PostVerifyMagicLink handles POST /v1/auth/magic-link/verify
+// @Summary Sign in with a magic link
+// @Description Redeems the token of an emailed sign-in link as the first
+// @Description factor. Like a password login it answers with an MFA
+// @Description pending token, and the session is created once a second
+// @Description factor is verified. An emailed code proves the same inbox
+// @Description as the link and does not count. Returns the authorize URL
+// @Description that continues the pending authorization request.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.MagicLinkVerifyRequest true "Link token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/magic-link/verify [post]
+func (h *MagicLinkHandler) PostVerifyMagicLink(c *gin.Context) {
+	var req dto.MagicLinkVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("[PostVerifyMagicLink] Bind JSON: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeInvalidInput,
+			"Invalid request format.",
+			err,
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	metadata := map[string]interface{}{
+		"method":     models.AMREmailLink,
+		"ip":         c.ClientIP(),
+		"user_agent": c.Request.UserAgent(),
+	}
+
+	login, err := h.MagicLinkService.ConsumeLink(ctx, req.Token)
+	if err != nil {
+		log.Printf("[PostVerifyMagicLink] Service: %v", err)
+		// Failed sign-ins are logged against the client IP.
+		metadata["error"] = err.Error()
+		_ = h.LogService.PostSecurityLogWithActorString(
+			ctx,
+			c.ClientIP(),
+			&dto.PostAuditLogRequest{
+				Action:   actionLogin,
+				Target:   "magic_link",
+				Status:   models.StatusFail,
+				Metadata: buildMetadata(metadata),
+			},
+		)
+
+		switch {
+		case strings.Contains(err.Error(), "suspended"):
+			errors.Send(
+				c,
+				http.StatusForbidden,
+				errors.CodeSuspended,
+				"Your account has been suspended.",
+				err,
+			)
+		case strings.Contains(err.Error(), "not allowed"):
+			errors.Send(
+				c,
+				http.StatusForbidden,
+				errors.CodeForbidden,
+				"Magic-link sign-in is not enabled for this app.",
+				err,
+			)
+		case strings.Contains(err.Error(), "database query"):
+			errors.Send(
+				c,
+				http.StatusInternalServerError,
+				errors.CodeDatabaseError,
+				"Failed to verify the sign-in link.",
+				err,
+			)
+		default:
+			errors.Send(
+				c,
+				http.StatusUnauthorized,
+				errors.CodeInvalidCredentials,
+				"This sign-in link is invalid or has expired.",
+				err,
+			)
+		}
+		return
+	}
+
+	pendingToken, err := h.AuthService.GenerateMFAPendingToken(
+		login.UserID.String(),
+		login.Email,
+		c.ClientIP(),
+		c.Request.UserAgent(),
+		models.AMREmailLink,
+	)
+	if err != nil {
+		log.Printf("[PostVerifyMagicLink] MFA Pending Token: %v", err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Failed to continue the sign-in.",
+			err,
+		)
+		return
+	}
+
+	metadata["client_id"] = login.ClientID
+	logReq := &dto.PostAuditLogRequest{
+		Action:   actionLogin,
+		Target:   login.ClientID,
+		Status:   models.StatusSuccess,
+		Metadata: buildMetadata(metadata),
+	}
+	_ = h.LogService.PostAuditLogWithActorString(ctx, login.Email, logReq)
+	_ = h.LogService.PostSecurityLogWithActorString(ctx, login.Email, logReq)
+
+	redirectURL := service.AuthorizeURL(login.ClientID, login.RedirectURI)
+	if login.AuthorizeQuery != "" {
+		redirectURL = service.AuthorizeQueryURL(login.AuthorizeQuery)
+	}
+
+	// Set temporary MFA pending cookie
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(
+		"idp_mfa_pending",
+		pendingToken,
+		300,
+		"/",
+		"",
+		true,
+		true,
+	)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"redirect_url":      redirectURL,
+		"mfa_pending_token": pendingToken,
+		"email":             login.Email,
+	})
+}
+
+// NewMagicLinkHandler constructs a MagicLinkHandler.
+func NewMagicLinkHandler(
+	ms service.MagicLinkService,
+	as service.AuthService,
+	ls service.LogService,
+) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		MagicLinkService: ms,
+		AuthService:      as,
+		LogService:       ls,
+	}
+}
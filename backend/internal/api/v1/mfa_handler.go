@@ -8,6 +8,7 @@ import (
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -145,7 +146,11 @@ func (h *MFAHandler) PostAuthenticator(c *gin.Context) {
 	}
 
 	if isPending {
-		err := h.AuthService.CreateSessionAndSetCookie(c, uID)
+		err := h.AuthService.CreateSessionAndSetCookie(
+			c,
+			uID,
+			models.AMRTOTP,
+		)
 		if err != nil {
 			log.Printf("[PostAuthenticator] CreateSession: %v", err)
 			errors.Send(
@@ -256,7 +261,11 @@ func (h *MFAHandler) PostVerifyMFA(c *gin.Context) {
 	}
 
 	if isPending {
-		err := h.AuthService.CreateSessionAndSetCookie(c, uID)
+		err := h.AuthService.CreateSessionAndSetCookie(
+			c,
+			uID,
+			models.AMRTOTP,
+		)
 		if err != nil {
 			log.Printf("[PostVerifyMFA] CreateSession: %v", err)
 			errors.Send(
@@ -269,6 +278,11 @@ func (h *MFAHandler) PostVerifyMFA(c *gin.Context) {
 			return
 		}
 		clearCookie()
+	} else if err := h.AuthService.RecordSecondFactor(
+		c, models.AMRTOTP,
+	); err != nil {
+		// A failed step-up leaves the session at its current level.
+		log.Printf("[PostVerifyMFA] RecordSecondFactor: %v", err)
 	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
@@ -352,7 +366,10 @@ func (h *MFAHandler) DeleteAuthenticator(c *gin.Context) {
 		return
 	}
 
-	user, err := h.UserService.GetUserByEmail(c.Request.Context(), req.Email)
+	user, err := h.UserService.GetUserByEmail(
+		c.Request.Context(),
+		req.Email,
+	)
 	if err != nil {
 		log.Printf("[DeleteAuthenticator] User Lookup: %v", err)
 		errors.Send(
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"bytes"
+	"fmt"
 	"log"
 	"net/http"
 
@@ -18,10 +20,11 @@ const (
 )
 
 type OTPHandler struct {
-	OTPService  service.OTPService
-	LogService  service.LogService
-	UserService service.UserService
-	AuthService service.AuthService
+	OTPService     service.OTPService
+	LogService     service.LogService
+	UserService    service.UserService
+	AuthService    service.AuthService
+	LockoutService service.LockoutService
 }
 
 // SendOTP is a handler to generate and send an OTP code to a user's email.
@@ -101,12 +104,17 @@ func (h *OTPHandler) SendOTP(c *gin.Context) {
 
 // VerifyOTP is a handler to verify an OTP code.
 // @Summary Verify OTP Code
-// @Description Verifies a 6-digit numeric OTP for a user.
+// @Description Verifies a 6-digit numeric OTP for a user. With the
+// @Description password_reset purpose the response carries a single-use
+// @Description reset_token for /user/password/forgot; with account_unlock
+// @Description it lifts a lock placed after failed sign-ins. Without a
+// @Description purpose, a code sent to the signed-in user steps the
+// @Description session up to 2fa.
 // @Tags otp
 // @Accept json
 // @Produce json
 // @Param request body dto.VerifyOTPRequest true "Verify OTP Request"
-// @Success 200 {object} dto.SuccessResponse
+// @Success 200 {object} dto.VerifyOTPResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
 // @Router /otp/verify [post]
@@ -142,6 +150,18 @@ func (h *OTPHandler) VerifyOTP(c *gin.Context) {
 	if tokenStr != "" {
 		claims, errVal := h.AuthService.
 			ValidateMFAPendingToken(tokenStr)
+		if errVal == nil && claims.FirstFactor == models.AMREmailLink {
+			// The emailed code proves the inbox the link was sent to.
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"Verify with an authenticator or a passkey "+
+					"after signing in with an email link.",
+				fmt.Errorf("email otp after an email link"),
+			)
+			return
+		}
 		if errVal == nil {
 			parsedID, errParse := uuid.Parse(claims.UserID)
 			if errParse == nil {
@@ -209,7 +229,11 @@ func (h *OTPHandler) VerifyOTP(c *gin.Context) {
 	}
 
 	if isPending {
-		err := h.AuthService.CreateSessionAndSetCookie(c, uID)
+		err := h.AuthService.CreateSessionAndSetCookie(
+			c,
+			uID,
+			models.AMROTP,
+		)
 		if err != nil {
 			log.Printf("[VerifyOTP] CreateSession: %v", err)
 			errors.Send(
@@ -222,13 +246,74 @@ func (h *OTPHandler) VerifyOTP(c *gin.Context) {
 			return
 		}
 		clearCookie()
+	} else if req.Purpose == "" && h.sessionOwnsEmail(c, req.Email) {
+		// On a signed-in session the code is a step-up. A failure only
+		// leaves the session at its current level.
+		err := h.AuthService.RecordSecondFactor(c, models.AMROTP)
+		if err != nil {
+			log.Printf("[VerifyOTP] RecordSecondFactor: %v", err)
+		}
+	}
+
+	resp := dto.VerifyOTPResponse{Message: "OTP verified successfully"}
+	if req.Purpose == models.PurposePasswordReset {
+		token, err := h.OTPService.IssueResetToken(reqCtx, req.Email)
+		if err != nil {
+			log.Printf("[VerifyOTP] IssueResetToken: %v", err)
+			errors.Send(
+				c,
+				http.StatusInternalServerError,
+				errors.CodeInternalError,
+				"Failed to start the password reset. Please try again.",
+				err,
+			)
+			return
+		}
+		resp.ResetToken = token
+		resp.ExpiresIn = service.RESET_TOKEN_TTL
+	}
+	if req.Purpose == models.PurposeAccountUnlock {
+		err := h.LockoutService.Unlock(reqCtx, req.Email)
+		if err != nil {
+			log.Printf("[VerifyOTP] Unlock: %v", err)
+			errors.Send(
+				c,
+				http.StatusInternalServerError,
+				errors.CodeInternalError,
+				"Failed to unlock the account. Please try again.",
+				err,
+			)
+			return
+		}
+		resp.Message = "Account unlocked successfully"
 	}
 
 	_ = h.LogService.PostAuditLogWithActorString(reqCtx, req.Email, logReq)
 	_ = h.LogService.PostSecurityLogWithActorString(reqCtx, req.Email, logReq)
 
-	c.JSON(http.StatusOK,
-		dto.SuccessResponse{Message: "OTP verified successfully"})
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// sessionOwnsEmail reports whether the session cookie belongs to the user
+// the code was sent to, so a code for another account cannot step it up.
+func (h *OTPHandler) sessionOwnsEmail(c *gin.Context, email string) bool {
+	sessionToken, err := c.Cookie(service.SESSION_COOKIE_NAME)
+	if err != nil || sessionToken == "" {
+		return false
+	}
+
+	ctx := c.Request.Context()
+	session, err := h.AuthService.ValidateSession(ctx, sessionToken)
+	if err != nil || session == nil {
+		return false
+	}
+	user, err := h.UserService.GetUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		return false
+	}
+	userID, err := uuid.Parse(user.ID)
+	return err == nil && bytes.Equal(userID[:], session.UserId)
 }
 
 func NewOTPHandler(
@@ -236,11 +321,13 @@ func NewOTPHandler(
 	ls service.LogService,
 	us service.UserService,
 	as service.AuthService,
+	los service.LockoutService,
 ) *OTPHandler {
 	return &OTPHandler{
-		OTPService:  os,
-		LogService:  ls,
-		UserService: us,
-		AuthService: as,
+		OTPService:     os,
+		LogService:     ls,
+		UserService:    us,
+		AuthService:    as,
+		LockoutService: los,
 	}
 }
@@ -3,9 +3,11 @@ package v1
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,6 +18,7 @@ type PasskeyHandler struct {
 	PasskeyService service.PasskeyService
 	UserService    service.UserService
 	AuthService    service.AuthService
+	LogService     service.LogService
 }
 
 /**
@@ -180,9 +183,14 @@ func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
 	}
 
 	if isPending {
-		err := h.AuthService.CreateSessionAndSetCookie(c, uID)
+		err := h.AuthService.CreateSessionAndSetCookie(
+			c,
+			uID,
+			models.AMRHardwareKey,
+		)
 		if err != nil {
-			log.Printf("[FinishRegistration] CreateSession: %v", err)
+			log.Printf("[FinishRegistration] CreateSession: %v",
+				err)
 			errors.Send(
 				c,
 				http.StatusInternalServerError,
@@ -363,9 +371,14 @@ func (h *PasskeyHandler) FinishVerification(c *gin.Context) {
 	}
 
 	if isPending {
-		err := h.AuthService.CreateSessionAndSetCookie(c, uID)
+		err := h.AuthService.CreateSessionAndSetCookie(
+			c,
+			uID,
+			models.AMRHardwareKey,
+		)
 		if err != nil {
-			log.Printf("[FinishVerification] CreateSession: %v", err)
+			log.Printf("[FinishVerification] CreateSession: %v",
+				err)
 			errors.Send(
 				c,
 				http.StatusInternalServerError,
@@ -376,6 +389,11 @@ func (h *PasskeyHandler) FinishVerification(c *gin.Context) {
 			return
 		}
 		clearCookie()
+	} else if err := h.AuthService.RecordSecondFactor(
+		c, models.AMRHardwareKey,
+	); err != nil {
+		// A failed step-up leaves the session at its current level.
+		log.Printf("[FinishVerification] RecordSecondFactor: %v", err)
 	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
@@ -462,15 +480,169 @@ func (h *PasskeyHandler) GetHasPasskey(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"has_passkey": has})
 }
 
+// BeginPasskeyLogin starts a passwordless login with a discoverable passkey
+// @Summary Begin a passkey login
+// @Description Returns WebAuthn request options without allowed
+// @Description credentials, so the browser offers any passkey of the user.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param req body dto.PasskeyLoginBeginRequest false "Login options"
+// @Success 200 {object} object
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/passkey/login/begin [post]
+func (h *PasskeyHandler) BeginPasskeyLogin(c *gin.Context) {
+	var req dto.PasskeyLoginBeginRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Printf("[BeginPasskeyLogin] Bind JSON: %v", err)
+			errors.Send(
+				c,
+				http.StatusBadRequest,
+				errors.CodeInvalidInput,
+				"Invalid request format.",
+				err,
+			)
+			return
+		}
+	}
+
+	challenge, err := h.PasskeyService.BeginLogin(
+		c.Request.Context(),
+		req.Conditional,
+		c.Request,
+	)
+	if err != nil {
+		log.Printf("[BeginPasskeyLogin] Service: %v", err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeMFAFailed,
+			"Failed to begin passkey sign-in.",
+			err,
+		)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json", challenge)
+}
+
+// FinishPasskeyLogin signs the user in with a discoverable passkey
+// @Summary Finish a passkey login
+// @Description Verifies the assertion, resolves the user from the user
+// @Description handle and creates the session without a password
+// @Description (amr=hwk). Returns the authorize URL to continue with.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string false "Redirect URI"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/passkey/login/finish [post]
+func (h *PasskeyHandler) FinishPasskeyLogin(c *gin.Context) {
+	clientID := c.Query("client_id")
+	if _, err := uuid.Parse(clientID); err != nil {
+		log.Printf("[FinishPasskeyLogin] UUID Parse: %v", err)
+		errors.Send(
+			c,
+			http.StatusBadRequest,
+			errors.CodeClientError,
+			"The Client ID format is invalid.",
+			err,
+		)
+		return
+	}
+
+	ctx := c.Request.Context()
+	metadata := map[string]interface{}{
+		"client_id":  clientID,
+		"method":     models.AMRHardwareKey,
+		"ip":         c.ClientIP(),
+		"user_agent": c.Request.UserAgent(),
+	}
+
+	user, err := h.PasskeyService.FinishLogin(ctx, c.Request)
+	if err != nil {
+		log.Printf("[FinishPasskeyLogin] Service: %v", err)
+		// Failed sign-ins are logged against the client IP.
+		metadata["error"] = err.Error()
+		_ = h.LogService.PostSecurityLogWithActorString(
+			ctx,
+			c.ClientIP(),
+			&dto.PostAuditLogRequest{
+				Action:   actionLogin,
+				Target:   clientID,
+				Status:   models.StatusFail,
+				Metadata: buildMetadata(metadata),
+			},
+		)
+
+		if strings.Contains(err.Error(), "suspended") {
+			errors.Send(
+				c,
+				http.StatusForbidden,
+				errors.CodeSuspended,
+				"Your account has been suspended.",
+				err,
+			)
+			return
+		}
+		errors.Send(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeInvalidCredentials,
+			"Passkey sign-in failed.",
+			err,
+		)
+		return
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+	err = h.AuthService.CreatePasskeySessionAndSetCookie(c, userID)
+	if err != nil {
+		log.Printf("[FinishPasskeyLogin] CreateSession: %v", err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Failed to establish session.",
+			err,
+		)
+		return
+	}
+
+	logReq := &dto.PostAuditLogRequest{
+		Action:   actionLogin,
+		Target:   clientID,
+		Status:   models.StatusSuccess,
+		Metadata: buildMetadata(metadata),
+	}
+	_ = h.LogService.PostAuditLogWithActorString(ctx, user.Email, logReq)
+	_ = h.LogService.PostSecurityLogWithActorString(ctx, user.Email, logReq)
+
+	c.JSON(http.StatusOK, gin.H{
+		"redirect_url": service.AuthorizeURL(
+			clientID,
+			c.Query("redirect_uri"),
+		),
+	})
+}
+
 // NewPasskeyHandler constructs a PasskeyHandler.
 func NewPasskeyHandler(
 	ps service.PasskeyService,
 	us service.UserService,
 	as service.AuthService,
+	ls service.LogService,
 ) *PasskeyHandler {
 	return &PasskeyHandler{
 		PasskeyService: ps,
 		UserService:    us,
 		AuthService:    as,
+		LogService:     ls,
 	}
 }
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -22,6 +23,7 @@ const (
 	actionSyncAdminAccess = "sync_admin_access"
 	actionGetUser         = "get_user"
 	actionGetMe           = "get_me"
+	actionUserInfo        = "userinfo"
 	actionUpdatePass      = "update_password"
 	actionUpdateStatus    = "update_status"
 	actionUpdateUserRole  = "update_user_role"
@@ -34,11 +36,15 @@ const (
 
 // UserHandler handles user management HTTP requests.
 type UserHandler struct {
-	Service       service.UserService
-	LogService    service.LogService
-	ClientService service.ClientService
-	AccessService service.ClientAllowedUserService
-	MFAService    service.MFAService
+	Service        service.UserService
+	LogService     service.LogService
+	ClientService  service.ClientService
+	AccessService  service.ClientAllowedUserService
+	MFAService     service.MFAService
+	OTPService     service.OTPService
+	AuthService    service.AuthService
+	LogoutService  service.LogoutService
+	LockoutService service.LockoutService
 }
 
 // PostUser creates a new user in the system
@@ -529,6 +535,95 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetUserInfo serves the OpenID Connect UserInfo endpoint
+// @Summary      Get OIDC user info
+// @Description  Returns the standard OIDC claims of the token subject,
+// @Description  filtered by the scopes granted to the access token
+// @Tags         Users
+// @Security     Bearer
+// @Produce      json
+// @Success      200  {object}  dto.OIDCUserInfoResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Router       /userinfo [get]
+// @Router       /userinfo [post]
+func (h *UserHandler) GetUserInfo(c *gin.Context) {
+	uID, uErr := uuid.Parse(c.GetString("user_id"))
+	clientID := c.GetString("client_id")
+	scope := c.GetString("scope")
+
+	if uErr != nil {
+		log.Print("[GetUserInfo] Context Extraction: invalid subject")
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized,
+			dto.ErrorResponse{Error: "invalid_token"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	actorName, _ := h.LogService.GetUserEmail(ctx, uID[:])
+	if actorName == "" {
+		actorName = uID.String()
+	}
+
+	// The UserInfo endpoint is only available to OpenID Connect tokens
+	if !service.HasScope(scope, models.ScopeOpenID) {
+		log.Print("[GetUserInfo] Scope Check: missing openid scope")
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+			&dto.PostAuditLogRequest{
+				Action: actionUserInfo,
+				Target: "self",
+				Status: models.StatusFail,
+				Metadata: buildMetadata(map[string]interface{}{
+					"client_id":  clientID,
+					"ip":         c.ClientIP(),
+					"user_agent": c.Request.UserAgent(),
+					"error":      "missing openid scope",
+				}),
+			})
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		c.AbortWithStatusJSON(http.StatusForbidden,
+			dto.ErrorResponse{Error: "insufficient_scope"})
+		return
+	}
+
+	resp, err := h.Service.GetUserInfo(ctx, uID, scope)
+	if err != nil {
+		log.Printf("[GetUserInfo] %v", err)
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+			&dto.PostAuditLogRequest{
+				Action: actionUserInfo,
+				Target: "self",
+				Status: models.StatusFail,
+				Metadata: buildMetadata(map[string]interface{}{
+					"client_id":  clientID,
+					"ip":         c.ClientIP(),
+					"user_agent": c.Request.UserAgent(),
+					"error":      err.Error(),
+				}),
+			})
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized,
+			dto.ErrorResponse{Error: "invalid_token"})
+		return
+	}
+
+	_ = h.LogService.PostAuditLogWithActorString(ctx, actorName,
+		&dto.PostAuditLogRequest{
+			Action: actionUserInfo,
+			Target: "self",
+			Status: models.StatusSuccess,
+			Metadata: buildMetadata(map[string]interface{}{
+				"client_id":  clientID,
+				"scope":      scope,
+				"ip":         c.ClientIP(),
+				"user_agent": c.Request.UserAgent(),
+			}),
+		})
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // PatchUserPassword updates a user's password.
 // @Summary Update user password
 // @Description Updates the password for a specific user identified by ID.
@@ -752,7 +847,10 @@ func (h *UserHandler) PatchChangePassword(c *gin.Context) {
 
 // PatchUserPasswordByEmail updates a user's password using their email.
 // @Summary Update user password by email
-// @Description Updates the password for a user identified by email.
+// @Description Resets the password for a user identified by email. The
+// @Description reset_token is the single-use token returned by /otp/verify
+// @Description for that email; every session and refresh token of the
+// @Description user is revoked afterwards.
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -760,6 +858,7 @@ func (h *UserHandler) PatchChangePassword(c *gin.Context) {
 // @Param request body dto.UpdatePasswordByEmailRequest true "Update Data"
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /internal/user/{email}/password [patch]
 func (h *UserHandler) PatchUserPasswordByEmail(c *gin.Context) {
@@ -795,8 +894,39 @@ func (h *UserHandler) PatchUserPasswordByEmail(c *gin.Context) {
 		"ip":           c.ClientIP(),
 		"user_agent":   c.Request.UserAgent(),
 	})
+	logFailure := func(err error) {
+		logReq := &dto.PostAuditLogRequest{
+			Action: actionUpdatePass,
+			Target: email,
+			Status: models.StatusFail,
+			Metadata: buildMetadata(map[string]interface{}{
+				"target_email": email,
+				"ip":           c.ClientIP(),
+				"user_agent":   c.Request.UserAgent(),
+				"error":        err.Error(),
+			}),
+		}
+		_ = h.LogService.PostAuditLogWithActorString(ctx, actorName, logReq)
+		_ = h.LogService.PostSecurityLogWithActorString(ctx, email, logReq)
+	}
 
-	err := h.Service.UpdateUserPasswordByEmail(
+	// 1. The token proves the OTP sent to this email was verified.
+	err := h.OTPService.ConsumeResetToken(ctx, email, req.ResetToken)
+	if err != nil {
+		log.Printf("[PatchUserPasswordByEmail] Reset Token: %v", err)
+		logFailure(err)
+		errors.Send(
+			c,
+			http.StatusUnauthorized,
+			errors.CodeOTPFailed,
+			"The password reset has expired. Verify your email again.",
+			err,
+		)
+		return
+	}
+
+	// 2. Password Update
+	err = h.Service.UpdateUserPasswordByEmail(
 		ctx,
 		email,
 		req.NewPassword,
@@ -828,6 +958,24 @@ func (h *UserHandler) PatchUserPasswordByEmail(c *gin.Context) {
 		return
 	}
 
+	// 3. Whoever knew the old password is signed out everywhere, and the
+	// failed attempts made with it no longer count against the owner.
+	if err := h.LockoutService.Unlock(ctx, email); err != nil {
+		log.Printf("[PatchUserPasswordByEmail] Unlock: %v", err)
+	}
+	if err := h.endAllSessions(ctx, email); err != nil {
+		log.Printf("[PatchUserPasswordByEmail] End Sessions: %v", err)
+		logFailure(err)
+		errors.Send(
+			c,
+			http.StatusInternalServerError,
+			errors.CodeInternalError,
+			"Password updated, but other sessions could not be signed out.",
+			err,
+		)
+		return
+	}
+
 	logReq := &dto.PostAuditLogRequest{
 		Action:   actionUpdatePass,
 		Target:   email,
@@ -844,6 +992,24 @@ func (h *UserHandler) PatchUserPasswordByEmail(c *gin.Context) {
 	})
 }
 
+// endAllSessions revokes the refresh tokens and ends the sessions of the
+// user with the given email.
+func (h *UserHandler) endAllSessions(ctx context.Context, email string) error {
+	user, err := h.Service.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.AuthService.RevokeAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
+	return h.LogoutService.LogoutUser(ctx, userID)
+}
+
 // PatchUserStatus updates the operational status of a user.
 // @Summary Update user status
 // @Description Modifies the status (e.g., active, disabled) of a user by ID.
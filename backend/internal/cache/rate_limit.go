@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// memorySweepInterval is how often the in-memory limiter drops the windows
+// of keys that saw no request for a whole window.
+const memorySweepInterval = time.Minute
+
+// RateLimitResult is the outcome of counting a request against a window.
+type RateLimitResult struct {
+	// Allowed reports whether the request fits within the limit.
+	Allowed bool
+
+	// Remaining is the number of requests still allowed in the window.
+	Remaining int
+
+	// Reset is the time until the oldest counted request leaves the
+	// window and frees up room for another.
+	Reset time.Duration
+}
+
+// RateLimiter counts requests per key within a sliding window.
+type RateLimiter interface {
+	// Allow counts a request under key unless limit requests were already
+	// counted within the last window.
+	Allow(
+		ctx context.Context,
+		key string,
+		limit int,
+		window time.Duration,
+	) (RateLimitResult, error)
+
+	// Peek reports whether a request under key would be allowed, without
+	// counting it.
+	Peek(
+		ctx context.Context,
+		key string,
+		limit int,
+		window time.Duration,
+	) (RateLimitResult, error)
+}
+
+// slidingWindowScript keeps one sorted set entry per counted request, scored
+// by the Redis server time in microseconds, so that every replica shares
+// the same window and clock.
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, now .. '-' .. ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+
+local reset = 0
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// peekWindowScript reads a window the way slidingWindowScript does without
+// adding an entry.
+var peekWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	allowed = 1
+end
+
+local reset = 0
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+type redisRateLimiter struct {
+	client   *redis.Client
+	fallback RateLimiter
+}
+
+// NewRedisRateLimiter creates a RateLimiter that shares its windows between
+// replicas through Redis. The fallback counts requests while Redis cannot
+// be reached.
+func NewRedisRateLimiter(
+	client *redis.Client,
+	fallback RateLimiter,
+) RateLimiter {
+	return &redisRateLimiter{client: client, fallback: fallback}
+}
+
+// Allow counts a request in the Redis sorted set of key.
+func (r *redisRateLimiter) Allow(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (RateLimitResult, error) {
+	nonce := make([]byte, 8)
+	_, _ = rand.Read(nonce)
+
+	res, err := slidingWindowScript.Run(
+		ctx,
+		r.client,
+		[]string{key},
+		window.Microseconds(),
+		limit,
+		hex.EncodeToString(nonce),
+	).Int64Slice()
+	if err != nil || len(res) != 3 {
+		log.Printf("[RateLimiter] Redis unavailable, using fallback: %v", err)
+		return r.fallback.Allow(ctx, key, limit, window)
+	}
+
+	return RateLimitResult{
+		Allowed:   res[0] == 1,
+		Remaining: max(0, limit-int(res[1])),
+		Reset:     time.Duration(res[2]) * time.Microsecond,
+	}, nil
+}
+
+// Peek reads the Redis sorted set of key without counting a request.
+func (r *redisRateLimiter) Peek(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (RateLimitResult, error) {
+	res, err := peekWindowScript.Run(
+		ctx,
+		r.client,
+		[]string{key},
+		window.Microseconds(),
+		limit,
+	).Int64Slice()
+	if err != nil || len(res) != 3 {
+		log.Printf("[RateLimiter] Redis unavailable, using fallback: %v", err)
+		return r.fallback.Peek(ctx, key, limit, window)
+	}
+
+	return RateLimitResult{
+		Allowed:   res[0] == 1,
+		Remaining: max(0, limit-int(res[1])),
+		Reset:     time.Duration(res[2]) * time.Microsecond,
+	}, nil
+}
+
+type memoryWindow struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+type memoryRateLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimiter creates a RateLimiter that keeps its windows in
+// process memory. Each replica counts its own requests.
+func NewMemoryRateLimiter() RateLimiter {
+	return &memoryRateLimiter{
+		windows:   make(map[string]*memoryWindow),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow counts a request in the in-memory window of key.
+func (m *memoryRateLimiter) Allow(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (RateLimitResult, error) {
+	return m.count(key, limit, window, true), nil
+}
+
+// Peek reads the in-memory window of key without counting a request.
+func (m *memoryRateLimiter) Peek(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (RateLimitResult, error) {
+	return m.count(key, limit, window, false), nil
+}
+
+// count drops the requests that left the window of key and, when record is
+// set, adds the current one if it fits.
+func (m *memoryRateLimiter) count(
+	key string,
+	limit int,
+	window time.Duration,
+	record bool,
+) RateLimitResult {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	w, ok := m.windows[key]
+	if !ok {
+		w = &memoryWindow{}
+		m.windows[key] = w
+	}
+	w.window = window
+
+	start := now.Add(-window)
+	i := 0
+	for i < len(w.hits) && !w.hits[i].After(start) {
+		i++
+	}
+	w.hits = w.hits[i:]
+
+	allowed := len(w.hits) < limit
+	if allowed && record {
+		w.hits = append(w.hits, now)
+	}
+
+	var reset time.Duration
+	if len(w.hits) > 0 {
+		reset = w.hits[0].Add(window).Sub(now)
+	}
+	return RateLimitResult{
+		Allowed:   allowed,
+		Remaining: max(0, limit-len(w.hits)),
+		Reset:     reset,
+	}
+}
+
+// sweep drops the windows whose last request has left the window, so that
+// keys seen once do not stay in memory. The caller must hold m.mu.
+func (m *memoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, w := range m.windows {
+		if len(w.hits) == 0 ||
+			now.Sub(w.hits[len(w.hits)-1]) >= w.window {
+			delete(m.windows, key)
+		}
+	}
+}
@@ -23,6 +23,16 @@ func StartJanitor(ctx context.Context, db *sqlx.DB, interval time.Duration) {
 				cleanExpiredRecords(db, "authorization_codes")
 				cleanExpiredRecords(db, "refresh_tokens")
 				cleanExpiredRecords(db, "idp_sessions")
+				cleanExpiredRecords(db, "idp_session_clients")
+				cleanExpiredRecords(db, "logout_notifications")
+				cleanExpiredRecords(db, "device_codes")
+				cleanExpiredRecords(db, "pushed_authorization_requests")
+				cleanExpiredRecords(db, "password_reset_tokens")
+				cleanExpiredRecords(db, "magic_link_tokens")
+				cleanExpiredRecords(db, "login_failures")
+				cleanExpiredRecords(db, "revoked_access_tokens")
+				cleanExpiredRecords(db, "access_token_watermarks")
+				cleanExpiredRecords(db, "signing_keys")
 			case <-ctx.Done():
 				log.Printf("[Janitor] %s: Shutting down", "Signal Received")
 				return
@@ -54,12 +54,18 @@ func RunAllMigrations(db *sqlx.DB) {
 		tables.SecurityLogsMigration,
 		tables.PermissionsMigration,
 		tables.AccountTypesMigration,
+		tables.SigningKeysMigration,
+		tables.LoginFailuresMigration,
+		tables.LockoutPolicyMigration,
+		tables.RevokedAccessTokensMigration,
+		tables.AccessTokenWatermarksMigration,
 	}
 
 	childTables := []migrations.TableMigration{
 		tables.UsersMigration,
 		tables.UserRolesMigration,
 		tables.ClientGrantTypesMigration,
+		tables.ClientRedirectURIsMigration,
 		tables.IdpSessionsMigration,
 		tables.RefreshTokensMigration,
 		tables.AuthorizationCodesMigration,
@@ -71,6 +77,13 @@ func RunAllMigrations(db *sqlx.DB) {
 		tables.ClientAllowedUsersMigration,
 		tables.PreapprovedClientsMigration,
 		tables.UserAuthenticatorsMigration,
+		tables.UserConsentsMigration,
+		tables.IdpSessionClientsMigration,
+		tables.LogoutNotificationsMigration,
+		tables.DeviceCodesMigration,
+		tables.PasswordResetTokensMigration,
+		tables.PushedAuthorizationRequestsMigration,
+		tables.MagicLinkTokensMigration,
 	}
 
 	procedurePlan := []migrations.MigrationPart{
@@ -16,6 +16,9 @@ var RotateRefreshTokenProcedure = migrations.MigrationPart{
             DECLARE v_clientId BINARY(16);
             DECLARE v_revokedAt TIMESTAMP;
             DECLARE v_expiresAt TIMESTAMP;
+            DECLARE v_scope VARCHAR(255);
+            DECLARE v_familyId BINARY(16);
+            DECLARE v_sid VARCHAR(64);
 
             -- Exit handler for unexpected system errors
             DECLARE EXIT HANDLER FOR SQLEXCEPTION
@@ -28,8 +31,10 @@ var RotateRefreshTokenProcedure = migrations.MigrationPart{
 
             -- 1. Look up the old token and lock the row
             -- If not found, MySQL will throw an error or we handle v_userId being NULL
-            SELECT user_id, client_id, revoked_at, expires_at 
-            INTO v_userId, v_clientId, v_revokedAt, v_expiresAt
+            SELECT user_id, client_id, revoked_at, expires_at, scope, family_id,
+                sid
+            INTO v_userId, v_clientId, v_revokedAt, v_expiresAt, v_scope,
+                v_familyId, v_sid
             FROM refresh_tokens 
             WHERE token = p_oldToken FOR UPDATE;
 
@@ -40,10 +45,10 @@ var RotateRefreshTokenProcedure = migrations.MigrationPart{
 
             -- 2. Security Check: Detection of Replay Attack
             IF v_revokedAt IS NOT NULL THEN
-                -- REPLAY ATTACK: Revoke everything for this user/client pair
+                -- REPLAY ATTACK: Revoke every token rotated from this grant
                 UPDATE refresh_tokens 
                 SET revoked_at = NOW() 
-                WHERE user_id = v_userId AND client_id = v_clientId AND revoked_at IS NULL;
+                WHERE family_id = v_familyId AND revoked_at IS NULL;
                 
                 COMMIT; 
                 
@@ -63,9 +68,11 @@ var RotateRefreshTokenProcedure = migrations.MigrationPart{
             SET revoked_at = NOW(), replaced_by = p_newToken 
             WHERE token = p_oldToken;
 
-            -- Insert the new token
-            INSERT INTO refresh_tokens (token, client_id, user_id, expires_at)
-            VALUES (p_newToken, v_clientId, v_userId, p_newExpiresAt);
+            -- Insert the new token, carrying over the scope, family and sid
+            INSERT INTO refresh_tokens
+                (token, client_id, user_id, expires_at, scope, family_id, sid)
+            VALUES (p_newToken, v_clientId, v_userId, p_newExpiresAt, v_scope,
+                v_familyId, v_sid);
 
             COMMIT;
         END;`,
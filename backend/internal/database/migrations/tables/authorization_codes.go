@@ -20,5 +20,31 @@ var AuthorizationCodesMigration = migrations.TableMigration{
 				INDEX idx_code_expiry (expires_at)
 			);`,
 		},
+		{
+			ID: "add-pkce-columns",
+			SQL: `
+				ALTER TABLE authorization_codes
+				ADD COLUMN code_challenge VARCHAR(128) NOT NULL DEFAULT '',
+				ADD COLUMN code_challenge_method VARCHAR(10) NOT NULL DEFAULT '';
+			`,
+		},
+		{
+			ID: "add-oidc-columns",
+			SQL: `
+				ALTER TABLE authorization_codes
+				ADD COLUMN scope VARCHAR(255) NOT NULL DEFAULT '',
+				ADD COLUMN nonce VARCHAR(255) NOT NULL DEFAULT '',
+				ADD COLUMN auth_time TIMESTAMP NULL,
+				ADD COLUMN amr VARCHAR(64) NOT NULL DEFAULT '',
+				ADD COLUMN acr VARCHAR(64) NOT NULL DEFAULT '';
+			`,
+		},
+		{
+			ID: "add-sid-column",
+			SQL: `
+				ALTER TABLE authorization_codes
+				ADD COLUMN sid VARCHAR(64) NOT NULL DEFAULT '';
+			`,
+		},
 	},
 }
@@ -19,5 +19,15 @@ var ClientGrantTypesMigration = migrations.TableMigration{
 				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
 			);`,
 		},
+		{
+			ID: "add-device-code-grant",
+			SQL: `ALTER TABLE client_grant_types
+				MODIFY COLUMN grant_type ENUM(
+					'authorization_code',
+					'refresh_token',
+					'client_credentials',
+					'urn:ietf:params:oauth:grant-type:device_code'
+				) NOT NULL;`,
+		},
 	},
 }
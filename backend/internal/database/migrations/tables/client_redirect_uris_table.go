@@ -0,0 +1,28 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+var ClientRedirectURIsMigration = migrations.TableMigration{
+	TableName: "client_redirect_uris",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-client-redirect-uris-table",
+			SQL: `
+			CREATE TABLE IF NOT EXISTS client_redirect_uris (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				client_id BINARY(16) NOT NULL,
+				redirect_uri VARCHAR(2048) NOT NULL,
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				INDEX idx_redirect_client (client_id)
+			);`,
+		},
+		{
+			ID: "seed-client-redirect-uris",
+			SQL: `
+				INSERT INTO client_redirect_uris (client_id, redirect_uri)
+				SELECT id, redirect_uri FROM clients
+				WHERE redirect_uri IS NOT NULL AND redirect_uri <> '';
+			`,
+		},
+	},
+}
@@ -64,5 +64,70 @@ var ClientsMigration = migrations.TableMigration{
 				ADD COLUMN refresh_token_ttl INT NOT NULL DEFAULT 168;
 			`,
 		},
+		{
+			ID: "add-require-pkce-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN require_pkce BOOLEAN NOT NULL DEFAULT FALSE;
+			`,
+		},
+		{
+			ID: "add-allowed-scopes-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN allowed_scopes VARCHAR(1024) NOT NULL DEFAULT '';
+			`,
+		},
+		{
+			ID: "add-token-signing-alg-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN token_signing_alg VARCHAR(16) NOT NULL
+				DEFAULT 'RS256';
+			`,
+		},
+		{
+			ID: "add-require-consent-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN require_consent BOOLEAN NOT NULL DEFAULT FALSE;
+			`,
+		},
+		{
+			ID: "add-min-acr-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN min_acr VARCHAR(16) NOT NULL DEFAULT '';
+			`,
+		},
+		{
+			ID: "add-frontchannel-logout-uri-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN frontchannel_logout_uri VARCHAR(255) NOT NULL
+				DEFAULT '';
+			`,
+		},
+		{
+			ID: "add-require-par-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN require_par BOOLEAN NOT NULL DEFAULT FALSE;
+			`,
+		},
+		{
+			ID: "add-allow-magic-link-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN allow_magic_link BOOLEAN NOT NULL DEFAULT FALSE;
+			`,
+		},
+		{
+			ID: "add-public-client-column",
+			SQL: `
+				ALTER TABLE clients
+				ADD COLUMN public_client BOOLEAN NOT NULL DEFAULT FALSE;
+			`,
+		},
 	},
 }
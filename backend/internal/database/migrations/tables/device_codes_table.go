@@ -0,0 +1,39 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * DeviceCodesMigration holds the pending device authorization requests
+ * (RFC 8628). A row is created with the pending status, answered by the
+ * user on the verification page and removed once the device redeems it;
+ * the janitor drops those left unanswered past expires_at.
+ */
+var DeviceCodesMigration = migrations.TableMigration{
+	TableName: "device_codes",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-device-codes-table",
+			SQL: `CREATE TABLE IF NOT EXISTS device_codes (
+				device_code VARCHAR(255) PRIMARY KEY,
+				user_code VARCHAR(16) NOT NULL,
+				client_id BINARY(16) NOT NULL,
+				scope VARCHAR(1024) NOT NULL DEFAULT '',
+				status ENUM('pending', 'approved', 'denied')
+					NOT NULL DEFAULT 'pending',
+				user_id BINARY(16) NULL,
+				auth_time TIMESTAMP NULL,
+				amr VARCHAR(255) NOT NULL DEFAULT '',
+				acr VARCHAR(32) NOT NULL DEFAULT '',
+				sid VARCHAR(64) NOT NULL DEFAULT '',
+				poll_interval INT NOT NULL,
+				last_polled_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL,
+				UNIQUE KEY uq_device_user_code (user_code),
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				INDEX idx_device_expiry (expires_at)
+			);`,
+		},
+	},
+}
@@ -0,0 +1,29 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * IdpSessionClientsMigration records the clients a session signed in to,
+ * keyed by the session's public sid, so logout can notify each of them.
+ * Rows expire with the session.
+ */
+var IdpSessionClientsMigration = migrations.TableMigration{
+	TableName: "idp_session_clients",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-idp-session-clients-table",
+			SQL: `CREATE TABLE IF NOT EXISTS idp_session_clients (
+				sid VARCHAR(64) NOT NULL,
+				client_id BINARY(16) NOT NULL,
+				user_id BINARY(16) NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (sid, client_id),
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				INDEX idx_session_client_user (user_id),
+				INDEX idx_session_client_expiry (expires_at)
+			);`,
+		},
+	},
+}
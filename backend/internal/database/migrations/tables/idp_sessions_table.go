@@ -19,5 +19,14 @@ var IdpSessionsMigration = migrations.TableMigration{
 				INDEX idx_session_expiry (expires_at)
 			);`,
 		},
+		{
+			ID: "add-session-auth-context-columns",
+			SQL: `
+				ALTER TABLE idp_sessions
+				ADD COLUMN amr VARCHAR(64) NOT NULL DEFAULT '',
+				ADD COLUMN auth_time TIMESTAMP NULL,
+				ADD COLUMN mfa_time TIMESTAMP NULL;
+			`,
+		},
 	},
 }
@@ -0,0 +1,55 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * LoginFailuresMigration counts the failed password attempts of each
+ * account. A row is removed by a successful login or an unlock, and by the
+ * janitor once neither the failure window nor a lock is running.
+ */
+var LoginFailuresMigration = migrations.TableMigration{
+	TableName: "login_failures",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-login-failures-table",
+			SQL: `CREATE TABLE IF NOT EXISTS login_failures (
+				email VARCHAR(255) PRIMARY KEY,
+				failed_count INT NOT NULL DEFAULT 0,
+				last_failed_at TIMESTAMP NOT NULL,
+				locked_until TIMESTAMP NULL,
+				expires_at TIMESTAMP NOT NULL,
+				INDEX idx_login_failures_expiry (expires_at)
+			);`,
+		},
+	},
+}
+
+/**
+ * LockoutPolicyMigration holds the single row of the account lockout
+ * policy administrators edit.
+ */
+var LockoutPolicyMigration = migrations.TableMigration{
+	TableName: "lockout_policy",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-lockout-policy-table",
+			SQL: `CREATE TABLE IF NOT EXISTS lockout_policy (
+				id TINYINT PRIMARY KEY,
+				max_failures INT NOT NULL,
+				lockout_minutes INT NOT NULL,
+				backoff_base_seconds INT NOT NULL,
+				backoff_max_seconds INT NOT NULL,
+				failure_window_minutes INT NOT NULL,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+					ON UPDATE CURRENT_TIMESTAMP
+			);`,
+		},
+		{
+			ID: "add-default-lockout-policy",
+			SQL: `INSERT IGNORE INTO lockout_policy (id, max_failures,
+				lockout_minutes, backoff_base_seconds, backoff_max_seconds,
+				failure_window_minutes)
+				VALUES (1, 5, 15, 1, 30, 15);`,
+		},
+	},
+}
@@ -0,0 +1,30 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * LogoutNotificationsMigration queues the back-channel logout requests
+ * still to be delivered to clients. Failed deliveries are retried until
+ * expires_at, after which the janitor drops them.
+ */
+var LogoutNotificationsMigration = migrations.TableMigration{
+	TableName: "logout_notifications",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-logout-notifications-table",
+			SQL: `CREATE TABLE IF NOT EXISTS logout_notifications (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				client_id BINARY(16) NOT NULL,
+				user_id BINARY(16) NOT NULL,
+				sid VARCHAR(64) NOT NULL DEFAULT '',
+				attempts INT NOT NULL DEFAULT 0,
+				next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL,
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				INDEX idx_logout_next_attempt (next_attempt_at),
+				INDEX idx_logout_expiry (expires_at)
+			);`,
+		},
+	},
+}
@@ -0,0 +1,35 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * MagicLinkTokensMigration holds the single-use tokens emailed for a
+ * magic-link login, together with the full authorization request they
+ * continue, since the link may be opened in another browser. Only a hash of the token is kept; a row is removed when the
+ * link is used and the janitor drops unused ones past expires_at.
+ */
+var MagicLinkTokensMigration = migrations.TableMigration{
+	TableName: "magic_link_tokens",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-magic-link-tokens-table",
+			SQL: `CREATE TABLE IF NOT EXISTS magic_link_tokens (
+				token_hash CHAR(64) PRIMARY KEY,
+				email VARCHAR(255) NOT NULL,
+				client_id BINARY(16) NOT NULL,
+				redirect_uri VARCHAR(2048) NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL,
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				INDEX idx_magic_link_expiry (expires_at)
+			);`,
+		},
+		{
+			ID: "add-magic-link-authorize-query",
+			SQL: `
+				ALTER TABLE magic_link_tokens
+				ADD COLUMN authorize_query VARCHAR(4096) NOT NULL DEFAULT '';
+			`,
+		},
+	},
+}
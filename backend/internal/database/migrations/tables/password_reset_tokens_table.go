@@ -0,0 +1,26 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * PasswordResetTokensMigration holds the tokens a verified OTP mints for
+ * a password reset. Only a hash of the token is kept; a row is removed
+ * when the token is redeemed and the janitor drops unused ones past
+ * expires_at.
+ */
+var PasswordResetTokensMigration = migrations.TableMigration{
+	TableName: "password_reset_tokens",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-password-reset-tokens-table",
+			SQL: `CREATE TABLE IF NOT EXISTS password_reset_tokens (
+				token_hash CHAR(64) PRIMARY KEY,
+				email VARCHAR(255) NOT NULL,
+				purpose VARCHAR(32) NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL,
+				INDEX idx_password_reset_expiry (expires_at)
+			);`,
+		},
+	},
+}
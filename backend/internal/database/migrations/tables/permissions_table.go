@@ -80,5 +80,17 @@ var PermissionsMigration = migrations.TableMigration{
 				('Manage Backup and Restore')
 			;`,
 		},
+		{
+			ID: "add-signing-key-permission",
+			SQL: `INSERT IGNORE INTO permissions (permission) VALUES 
+				('Manage Signing Keys')
+			;`,
+		},
+		{
+			ID: "add-account-lockout-permission",
+			SQL: `INSERT IGNORE INTO permissions (permission) VALUES 
+				('Manage Account Lockout')
+			;`,
+		},
 	},
 }
@@ -0,0 +1,34 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * PushedAuthorizationRequestsMigration stores the authorization request
+ * parameters clients push ahead of redirecting the user (RFC 9126). The
+ * first browser to resolve a row claims it in used_by. A row is removed
+ * once a code is issued for it, or by the janitor after expires_at.
+ */
+var PushedAuthorizationRequestsMigration = migrations.TableMigration{
+	TableName: "pushed_authorization_requests",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-pushed-authorization-requests-table",
+			SQL: `CREATE TABLE IF NOT EXISTS pushed_authorization_requests (
+				request_id VARCHAR(64) PRIMARY KEY,
+				client_id BINARY(16) NOT NULL,
+				params TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL,
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				INDEX idx_par_expiry (expires_at)
+			);`,
+		},
+		{
+			ID: "add-pushed-request-used-by",
+			SQL: `
+				ALTER TABLE pushed_authorization_requests
+				ADD COLUMN used_by VARCHAR(64) NULL;
+			`,
+		},
+	},
+}
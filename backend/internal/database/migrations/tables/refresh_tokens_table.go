@@ -28,5 +28,44 @@ var RefreshTokensMigration = migrations.TableMigration{
 				ADD COLUMN replaced_by VARCHAR(255) NULL;
 			`,
 		},
+		{
+			ID: "add-refresh-token-scope",
+			SQL: `
+				ALTER TABLE refresh_tokens
+				ADD COLUMN scope VARCHAR(255) NOT NULL DEFAULT '';
+			`,
+		},
+		{
+			ID: "add-refresh-token-created-at",
+			SQL: `
+				ALTER TABLE refresh_tokens
+				ADD COLUMN created_at TIMESTAMP NOT NULL
+					DEFAULT CURRENT_TIMESTAMP;
+			`,
+		},
+		{
+			ID: "add-refresh-token-family",
+			SQL: `
+				ALTER TABLE refresh_tokens
+				ADD COLUMN family_id BINARY(16) NULL,
+				ADD INDEX idx_family_lookup (family_id);
+			`,
+		},
+		{
+			ID: "backfill-refresh-token-family",
+			SQL: `
+				UPDATE refresh_tokens
+				SET family_id = UNHEX(REPLACE(UUID(), '-', ''))
+				WHERE family_id IS NULL;
+			`,
+		},
+		{
+			ID: "add-refresh-token-sid",
+			SQL: `
+				ALTER TABLE refresh_tokens
+				ADD COLUMN sid VARCHAR(64) NOT NULL DEFAULT '',
+				ADD INDEX idx_sid_lookup (sid);
+			`,
+		},
 	},
 }
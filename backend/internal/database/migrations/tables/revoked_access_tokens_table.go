@@ -0,0 +1,42 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * RevokedAccessTokensMigration keeps the jti of each access token revoked
+ * before it expired. It backs the revocation list when Redis is not
+ * configured, and a row is removed by the janitor once the token expires.
+ */
+var RevokedAccessTokensMigration = migrations.TableMigration{
+	TableName: "revoked_access_tokens",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-revoked-access-tokens-table",
+			SQL: `CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+				jti VARCHAR(64) PRIMARY KEY,
+				expires_at TIMESTAMP NOT NULL,
+				INDEX idx_revoked_access_tokens_expiry (expires_at)
+			);`,
+		},
+	},
+}
+
+/**
+ * AccessTokenWatermarksMigration keeps, per user, the unix time before
+ * which every issued access token is rejected. A row outlives the longest
+ * access token lifetime and is then removed by the janitor.
+ */
+var AccessTokenWatermarksMigration = migrations.TableMigration{
+	TableName: "access_token_watermarks",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-access-token-watermarks-table",
+			SQL: `CREATE TABLE IF NOT EXISTS access_token_watermarks (
+				user_id VARCHAR(36) PRIMARY KEY,
+				not_before BIGINT NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				INDEX idx_access_token_watermarks_expiry (expires_at)
+			);`,
+		},
+	},
+}
@@ -0,0 +1,27 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * SigningKeysMigration defines the token signing keys. Exactly one key is
+ * active; retired keys stay published for verification until expires_at.
+ */
+var SigningKeysMigration = migrations.TableMigration{
+	TableName: "signing_keys",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-signing-keys-table",
+			SQL: `CREATE TABLE IF NOT EXISTS signing_keys (
+				kid VARCHAR(64) PRIMARY KEY,
+				algorithm VARCHAR(16) NOT NULL DEFAULT 'RS256',
+				private_key TEXT NOT NULL,
+				public_key TEXT NOT NULL,
+				active BOOLEAN NOT NULL DEFAULT FALSE,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				retired_at TIMESTAMP NULL,
+				expires_at TIMESTAMP NULL,
+				INDEX idx_signing_keys_active (active)
+			);`,
+		},
+	},
+}
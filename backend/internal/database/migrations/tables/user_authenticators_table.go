@@ -41,5 +41,11 @@ var UserAuthenticatorsMigration = migrations.TableMigration{
 				ADD COLUMN backup_eligible BOOLEAN DEFAULT FALSE,
 				ADD COLUMN backup_state BOOLEAN DEFAULT FALSE;`,
 		},
+		{
+			// NULL marks secrets encrypted before envelope encryption.
+			ID: "user-authenticators-add-key-version",
+			SQL: `ALTER TABLE user_authenticators
+				ADD COLUMN key_version INT NULL;`,
+		},
 	},
 }
@@ -0,0 +1,27 @@
+package tables
+
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/database/migrations"
+
+/**
+ * UserConsentsMigration records the scopes a user granted to clients that
+ * require consent. One row per user and client holds the union of every
+ * scope granted so far.
+ */
+var UserConsentsMigration = migrations.TableMigration{
+	TableName: "user_consents",
+	Steps: []migrations.MigrationStep{
+		{
+			ID: "create-user-consents-table",
+			SQL: `CREATE TABLE IF NOT EXISTS user_consents (
+				user_id BINARY(16) NOT NULL,
+				client_id BINARY(16) NOT NULL,
+				scope VARCHAR(1024) NOT NULL DEFAULT '',
+				granted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (user_id, client_id),
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE,
+				INDEX idx_consent_client (client_id)
+			);`,
+		},
+	},
+}
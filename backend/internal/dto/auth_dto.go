@@ -1,23 +1,143 @@
 package dto
 
+import "time"
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
 	ClientID string `json:"client_id" binding:"required"`
+	// RedirectURI is optional; when omitted the pending authorize request
+	// or the client's primary redirect URI is used.
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// AuthorizeRequest carries the query parameters of /auth/authorize.
+type AuthorizeRequest struct {
+	ClientID            string `form:"client_id"`
+	RedirectURI         string `form:"redirect_uri"`
+	CodeChallenge       string `form:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method"`
+	Scope               string `form:"scope"`
+	Nonce               string `form:"nonce"`
+	State               string `form:"state"`
+	ResponseType        string `form:"response_type"`
+	Prompt              string `form:"prompt"`
+	MaxAge              string `form:"max_age"`
+	LoginHint           string `form:"login_hint"`
+	ACRValues           string `form:"acr_values"`
+	RequestURI          string `form:"request_uri"`
+	// AuthAfter is the earliest session creation time, in Unix seconds,
+	// that satisfies a remembered prompt=login or max_age. The IdP sets it
+	// when it sends the user through the login page.
+	AuthAfter string `form:"auth_after"`
+}
+
+// PushedAuthorizationRequest carries the authorization request parameters
+// a client pushes to /auth/par (RFC 9126) together with its credentials.
+type PushedAuthorizationRequest struct {
+	AuthorizeRequest
+	ClientSecret string `form:"client_secret"`
 }
 
+// PushedAuthorizationResponse hands back the request_uri that replaces
+// the pushed parameters on /auth/authorize.
+type PushedAuthorizationResponse struct {
+	RequestURI string `json:"request_uri"`
+	ExpiresIn  int    `json:"expires_in"`
+}
+
+// TokenExchangeRequest omits client_secret for public clients using PKCE.
+// Confidential clients may send their credentials with HTTP Basic instead.
 type TokenExchangeRequest struct {
-	Code         string `json:"code" form:"code" binding:"required"`
-	ClientID     string `json:"client_id" form:"client_id" binding:"required"`
-	ClientSecret string `json:"client_secret" form:"client_secret" binding:"required"`
+	GrantType    string `json:"grant_type" form:"grant_type"`
+	Code         string `json:"code" form:"code"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	CodeVerifier string `json:"code_verifier" form:"code_verifier"`
+	RedirectURI  string `json:"redirect_uri" form:"redirect_uri"`
+	Scope        string `json:"scope" form:"scope"`
+	DeviceCode   string `json:"device_code" form:"device_code"`
+}
+
+// DeviceAuthorizationRequest starts the device authorization grant
+// (RFC 8628). Public clients omit client_secret.
+type DeviceAuthorizationRequest struct {
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"`
+}
+
+// DeviceAuthorizationResponse tells the device which code to show the
+// user, where to send them and how often to poll the token endpoint.
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceVerificationRequest is the user's answer on the device
+// verification page.
+type DeviceVerificationRequest struct {
+	UserCode string `json:"user_code" binding:"required"`
+	Approve  bool   `json:"approve"`
+}
+
+// DeviceVerificationResponse describes the device request a user code
+// belongs to, for the user to confirm.
+type DeviceVerificationResponse struct {
+	UserCode   string   `json:"user_code"`
+	ClientID   string   `json:"client_id"`
+	ClientName string   `json:"client_name"`
+	Scopes     []string `json:"scopes"`
 }
 
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresIn    int    `json:"expires_in"`
 	TokenType    string `json:"token_type"`
 	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// IntrospectionRequest is the RFC 7662 introspection request. The caller
+// authenticates with its client credentials.
+type IntrospectionRequest struct {
+	Token         string `json:"token" form:"token" binding:"required"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint"`
+	ClientID      string `json:"client_id" form:"client_id"`
+	ClientSecret  string `json:"client_secret" form:"client_secret"`
+}
+
+// RevocationRequest is the RFC 7009 revocation request. RevokeFamily also
+// revokes every refresh token rotated from the same authorization grant.
+type RevocationRequest struct {
+	Token         string `json:"token" form:"token" binding:"required"`
+	TokenTypeHint string `json:"token_type_hint" form:"token_type_hint"`
+	ClientID      string `json:"client_id" form:"client_id"`
+	ClientSecret  string `json:"client_secret" form:"client_secret"`
+	RevokeFamily  bool   `json:"revoke_family" form:"revoke_family"`
+}
+
+// IntrospectionResponse is the RFC 7662 introspection response. Inactive
+// tokens only report active=false.
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Subject   string `json:"sub,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+}
+
+// OAuthErrorResponse is the RFC 6749 section 5.2 error body.
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
 }
 
 type RefreshRequest struct {
@@ -32,7 +152,69 @@ type LogoutRequest struct {
 	ClientID string `json:"client_id" binding:"required"`
 }
 
+// EndSessionRequest is an RP-initiated logout request. The client is
+// named by client_id or by the audience of the id_token_hint. Confirmed
+// is set by the handler once the user confirmed a logout without a hint.
+type EndSessionRequest struct {
+	IDTokenHint           string `form:"id_token_hint"`
+	ClientID              string `form:"client_id"`
+	PostLogoutRedirectURI string `form:"post_logout_redirect_uri"`
+	State                 string `form:"state"`
+	Confirmed             bool   `form:"-"`
+}
+
+// EndSessionResult tells the handler where to send the browser and which
+// front-channel logout URIs to load on the way. UserID is empty when no
+// session was ended.
+type EndSessionResult struct {
+	RedirectURL      string
+	FrontchannelURIs []string
+	UserID           []byte
+}
+
 type InternalLogoutRequest struct {
 	ClientID string `json:"client_id" binding:"required"`
 	UserID   string `json:"user_id" binding:"required"`
 }
+
+// LockoutPolicyRequest replaces the account lockout policy. After the
+// second failed login each attempt waits backoff_base_seconds, doubling up
+// to backoff_max_seconds; max_failures failures within the window lock the
+// account for lockout_minutes.
+type LockoutPolicyRequest struct {
+	MaxFailures          int `json:"max_failures" binding:"required,min=1,max=100"`
+	LockoutMinutes       int `json:"lockout_minutes" binding:"required,min=1,max=1440"`
+	BackoffBaseSeconds   int `json:"backoff_base_seconds" binding:"min=0,max=60"`
+	BackoffMaxSeconds    int `json:"backoff_max_seconds" binding:"min=0,max=3600"`
+	FailureWindowMinutes int `json:"failure_window_minutes" binding:"required,min=1,max=1440"`
+}
+
+// LockoutPolicyResponse is the account lockout policy in effect.
+type LockoutPolicyResponse struct {
+	MaxFailures          int       `json:"max_failures" example:"5"`
+	LockoutMinutes       int       `json:"lockout_minutes" example:"15"`
+	BackoffBaseSeconds   int       `json:"backoff_base_seconds" example:"1"`
+	BackoffMaxSeconds    int       `json:"backoff_max_seconds" example:"30"`
+	FailureWindowMinutes int       `json:"failure_window_minutes" example:"15"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// MagicLinkRequest asks for a sign-in link to be emailed for the pending
+// authorization request of client_id.
+type MagicLinkRequest struct {
+	Email       string `json:"email" binding:"required,email"`
+	ClientID    string `json:"client_id" binding:"required"`
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// MagicLinkVerifyRequest redeems the token of an emailed sign-in link.
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// MagicLinkResponse answers a link request the same way whether or not
+// the email is registered.
+type MagicLinkResponse struct {
+	Message   string `json:"message"`
+	ExpiresIn int    `json:"expires_in" example:"600"`
+}
@@ -1,32 +1,52 @@
 package dto
 
 type CreateClientRequest struct {
-	Name            string   `json:"name"`
-	BaseURL         string   `json:"base_url"`
-	RedirectURI     string   `json:"redirect_uri"`
-	LogoutURI       string   `json:"logout_uri"`
-	Description     string   `json:"description"`
-	OnePortalLink   string   `json:"one_portal_link"`
-	Grants          []string `json:"grants"`
-	RoleIDs         []int    `json:"role_ids"`
-	AccessTokenTTL  int      `json:"access_token_ttl"`
-	RefreshTokenTTL int      `json:"refresh_token_ttl"`
+	Name                  string   `json:"name"`
+	BaseURL               string   `json:"base_url"`
+	RedirectURI           string   `json:"redirect_uri"`
+	RedirectURIs          []string `json:"redirect_uris"`
+	LogoutURI             string   `json:"logout_uri"`
+	FrontchannelLogoutURI string   `json:"frontchannel_logout_uri"`
+	Description           string   `json:"description"`
+	OnePortalLink         string   `json:"one_portal_link"`
+	Grants                []string `json:"grants"`
+	RoleIDs               []int    `json:"role_ids"`
+	AccessTokenTTL        int      `json:"access_token_ttl"`
+	RefreshTokenTTL       int      `json:"refresh_token_ttl"`
+	RequirePKCE           bool     `json:"require_pkce"`
+	AllowedScopes         string   `json:"allowed_scopes"`
+	TokenSigningAlg       string   `json:"token_signing_alg"`
+	RequireConsent        bool     `json:"require_consent"`
+	MinACR                string   `json:"min_acr"`
+	RequirePAR            bool     `json:"require_par"`
+	AllowMagicLink        bool     `json:"allow_magic_link"`
+	PublicClient          bool     `json:"public_client"`
 }
 
 type ClientResponse struct {
-	ID              string         `json:"id"`
-	Name            string         `json:"name"`
-	Description     string         `json:"description"`
-	ImageLocation   string         `json:"image_location"`
-	BaseURL         string         `json:"base_url"`
-	RedirectURI     string         `json:"redirect_uri"`
-	LogoutURI       string         `json:"logout_uri"`
-	OnePortalLink   string         `json:"one_portal_link"`
-	CreatedAt       string         `json:"created_at"`
-	Grants          []string       `json:"grants"`
-	AllowedRoles    []RoleResponse `json:"allowed_roles"`
-	AccessTokenTTL  int            `json:"access_token_ttl"`
-	RefreshTokenTTL int            `json:"refresh_token_ttl"`
+	ID                    string         `json:"id"`
+	Name                  string         `json:"name"`
+	Description           string         `json:"description"`
+	ImageLocation         string         `json:"image_location"`
+	BaseURL               string         `json:"base_url"`
+	RedirectURI           string         `json:"redirect_uri"`
+	RedirectURIs          []string       `json:"redirect_uris"`
+	LogoutURI             string         `json:"logout_uri"`
+	FrontchannelLogoutURI string         `json:"frontchannel_logout_uri"`
+	OnePortalLink         string         `json:"one_portal_link"`
+	CreatedAt             string         `json:"created_at"`
+	Grants                []string       `json:"grants"`
+	AllowedRoles          []RoleResponse `json:"allowed_roles"`
+	AccessTokenTTL        int            `json:"access_token_ttl"`
+	RefreshTokenTTL       int            `json:"refresh_token_ttl"`
+	RequirePKCE           bool           `json:"require_pkce"`
+	AllowedScopes         string         `json:"allowed_scopes"`
+	TokenSigningAlg       string         `json:"token_signing_alg"`
+	RequireConsent        bool           `json:"require_consent"`
+	MinACR                string         `json:"min_acr"`
+	RequirePAR            bool           `json:"require_par"`
+	AllowMagicLink        bool           `json:"allow_magic_link"`
+	PublicClient          bool           `json:"public_client"`
 }
 
 type ClientListResponse struct {
@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// ConsentRequest is the user's answer on the consent screen.
+type ConsentRequest struct {
+	ClientID string `json:"client_id" binding:"required"`
+	Scope    string `json:"scope"`
+	Approve  bool   `json:"approve"`
+}
+
+// ConsentPromptResponse tells the consent screen what the client asks
+// for. GrantedScopes are the scopes the user already agreed to.
+type ConsentPromptResponse struct {
+	ClientID      string   `json:"client_id"`
+	ClientName    string   `json:"client_name"`
+	Scopes        []string `json:"scopes"`
+	GrantedScopes []string `json:"granted_scopes"`
+}
+
+// ConsentResponse is a consent listed under /me/consents.
+type ConsentResponse struct {
+	ClientID   string    `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	GrantedAt  time.Time `json:"granted_at"`
+}
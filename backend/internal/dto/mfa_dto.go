@@ -46,3 +46,10 @@ type PasskeyBeginRequest struct {
 	Email             string `json:"email" binding:"required"`
 	PlatformAvailable *bool  `json:"platform_available"`
 }
+
+// PasskeyLoginBeginRequest starts a passwordless login. Conditional asks
+// for a challenge the browser offers through the autofill of the email
+// field instead of a modal prompt.
+type PasskeyLoginBeginRequest struct {
+	Conditional bool `json:"conditional"`
+}
@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+// OpenIDConfiguration is the OIDC discovery document served from
+// /.well-known/openid-configuration.
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	EndSessionEndpoint                string   `json:"end_session_endpoint"`
+	DeviceAuthorizationEndpoint       string   `json:"device_authorization_endpoint"`
+	PushedAuthRequestEndpoint         string   `json:"pushed_authorization_request_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	ResponseModesSupported            []string `json:"response_modes_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	ACRValuesSupported                []string `json:"acr_values_supported"`
+	FrontchannelLogoutSupported       bool     `json:"frontchannel_logout_supported"`
+	FrontchannelLogoutSession         bool     `json:"frontchannel_logout_session_supported"`
+	BackchannelLogoutSupported        bool     `json:"backchannel_logout_supported"`
+	BackchannelLogoutSession          bool     `json:"backchannel_logout_session_supported"`
+}
+
+// OIDCUserInfoResponse is the OIDC UserInfo payload. Only sub is always
+// present; the remaining claims depend on the scopes of the access token.
+type OIDCUserInfoResponse struct {
+	Subject       string `json:"sub"`
+	Name          string `json:"name,omitempty"`
+	GivenName     string `json:"given_name,omitempty"`
+	MiddleName    string `json:"middle_name,omitempty"`
+	FamilyName    string `json:"family_name,omitempty"`
+	Role          string `json:"role,omitempty"`
+	AccountType   string `json:"account_type,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+}
+
+// SigningKeyResponse describes a token signing key without its key
+// material. Retired keys carry the time they stop being published.
+type SigningKeyResponse struct {
+	KeyID     string     `json:"kid" example:"3f1c9a7e-2b4d-4e8f-9a61-0c5d7b2e8f14"`
+	Algorithm string     `json:"alg" example:"ES256"`
+	Active    bool       `json:"active" example:"true"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SigningKeyRotationResponse reports the outcome of rotating the key of
+// one algorithm. Failed rotations keep the previous key active.
+type SigningKeyRotationResponse struct {
+	Algorithm     string     `json:"alg" example:"ES256"`
+	Rotated       bool       `json:"rotated" example:"true"`
+	KeyID         string     `json:"kid,omitempty" example:"3f1c9a7e-2b4d-4e8f-9a61-0c5d7b2e8f14"`
+	PreviousKeyID string     `json:"previous_kid,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// RewrapKeysResponse counts the secrets moved onto the active encryption
+// key version.
+type RewrapKeysResponse struct {
+	SigningKeys int `json:"signing_keys" example:"4"`
+	MFASecrets  int `json:"mfa_secrets" example:"120"`
+}
@@ -4,8 +4,17 @@ package dto
  * VerifyOTPRequest contains the fields required to verify an OTP.
  */
 type VerifyOTPRequest struct {
-	Email string `json:"email" binding:"required,email"`
-	OTP   string `json:"otp" binding:"required,len=6"`
+	Email   string `json:"email" binding:"required,email"`
+	OTP     string `json:"otp" binding:"required,len=6"`
+	Purpose string `json:"purpose" binding:"omitempty,oneof=password_reset account_unlock"`
+}
+
+// VerifyOTPResponse is the payload returned after an OTP is verified. A
+// verification for the password_reset purpose carries the reset token.
+type VerifyOTPResponse struct {
+	Message    string `json:"message"`
+	ResetToken string `json:"reset_token,omitempty"`
+	ExpiresIn  int    `json:"expires_in,omitempty"`
 }
 
 // OTPSendResponse is the payload returned after an OTP is requested.
@@ -45,6 +45,7 @@ type ChangePasswordRequest struct {
 type UpdatePasswordByEmailRequest struct {
 	Email       string `json:"email" binding:"required,email"`
 	NewPassword string `json:"new_password" binding:"required"`
+	ResetToken  string `json:"reset_token" binding:"required"`
 }
 
 // UpdateStatusRequest handles patch data for updating user status
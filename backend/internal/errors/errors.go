@@ -2,7 +2,10 @@
 package errors
 
 import (
+	"net/http"
+
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,6 +26,35 @@ const (
 	CodeClientError        = 1012
 	CodeRateLimitExceeded  = 1029
 	CodeSuspended          = 1030
+	CodeAccountLocked      = 1031
+	CodeLoginDelayed       = 1032
+)
+
+// OAuth 2.0 error codes (RFC 6749 sections 4.1.2.1 and 5.2)
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthAccessDenied            = "access_denied"
+	OAuthServerError             = "server_error"
+)
+
+// OpenID Connect error codes (OIDC Core section 3.1.2.6)
+const (
+	OAuthLoginRequired       = "login_required"
+	OAuthConsentRequired     = "consent_required"
+	OAuthInteractionRequired = "interaction_required"
+)
+
+// Device authorization grant error codes (RFC 8628 section 3.5)
+const (
+	OAuthAuthorizationPending = "authorization_pending"
+	OAuthSlowDown             = "slow_down"
+	OAuthExpiredToken         = "expired_token"
 )
 
 // Send sends a standardized error response to the client.
@@ -52,3 +84,35 @@ func SendString(
 		Error:   errStr,
 	})
 }
+
+// SendOAuth sends an RFC 6749 token endpoint error response.
+func SendOAuth(c *gin.Context, status int, code string, description string) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	if status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Basic realm="token"`)
+	}
+	c.JSON(status, dto.OAuthErrorResponse{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
+// RedirectOAuth sends an RFC 6749 authorization error back to the
+// client's validated redirect URI, echoing state when present.
+func RedirectOAuth(
+	c *gin.Context,
+	redirectURI string,
+	code string,
+	description string,
+	state string,
+) {
+	c.Redirect(http.StatusFound, utils.AppendQuery(
+		redirectURI,
+		map[string]string{
+			"error":             code,
+			"error_description": description,
+			"state":             state,
+		},
+	))
+}
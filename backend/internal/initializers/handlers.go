@@ -20,9 +20,12 @@ func InitializeHandlers(db *sqlx.DB,
 
 	return &api.Handlers{
 		AuthHandler: &v1.AuthHandler{
-			AuthService:   service.AuthService,
-			LogService:    service.LogService,
-			ClientService: service.ClientService,
+			AuthService:    service.AuthService,
+			LogService:     service.LogService,
+			ClientService:  service.ClientService,
+			LogoutService:  service.LogoutService,
+			DeviceService:  service.DeviceService,
+			LockoutService: service.LockoutService,
 		},
 		ClientHandler: &v1.ClientHandler{
 			Service:    service.ClientService,
@@ -33,11 +36,15 @@ func InitializeHandlers(db *sqlx.DB,
 			LogService: service.LogService,
 		},
 		UserHandler: &v1.UserHandler{
-			Service:       service.UserService,
-			LogService:    service.LogService,
-			ClientService: service.ClientService,
-			AccessService: service.ClientAllowedUserService,
-			MFAService:    service.MFAService,
+			Service:        service.UserService,
+			LogService:     service.LogService,
+			ClientService:  service.ClientService,
+			AccessService:  service.ClientAllowedUserService,
+			MFAService:     service.MFAService,
+			OTPService:     service.OTPService,
+			AuthService:    service.AuthService,
+			LogoutService:  service.LogoutService,
+			LockoutService: service.LockoutService,
 		},
 
 		LogHandler: &v1.LogHandler{
@@ -62,6 +69,7 @@ func InitializeHandlers(db *sqlx.DB,
 			service.LogService,
 			service.UserService,
 			service.AuthService,
+			service.LockoutService,
 		),
 		MFAHandler: v1.NewMFAHandler(
 			service.MFAService,
@@ -72,13 +80,41 @@ func InitializeHandlers(db *sqlx.DB,
 			service.PasskeyService,
 			service.UserService,
 			service.AuthService,
+			service.LogService,
+		),
+		KeyHandler: &v1.KeyHandler{
+			KeyStore:   service.KeyStore,
+			MFAService: service.MFAService,
+			LogService: service.LogService,
+		},
+		ConsentHandler: &v1.ConsentHandler{
+			ConsentService: service.ConsentService,
+			AuthService:    service.AuthService,
+			LogService:     service.LogService,
+		},
+		DeviceHandler: &v1.DeviceHandler{
+			DeviceService: service.DeviceService,
+			AuthService:   service.AuthService,
+			LogService:    service.LogService,
+		},
+		LockoutHandler: &v1.LockoutHandler{
+			LockoutService: service.LockoutService,
+			LogService:     service.LogService,
+		},
+		MagicLinkHandler: v1.NewMagicLinkHandler(
+			service.MagicLinkService,
+			service.AuthService,
+			service.LogService,
 		),
 		MetricsHandler: v1.NewMetricsHandler(service.MetricsService),
 		BackupHandler:  &v1.BackupHandler{},
 		ReportHandler:  v1.NewReportHandler(service.ReportService),
 		UserRepo:       userRepo,
 		RoleRepo:       roleRepo,
-		PubKey:         PubKey,
+		Keys:           service.KeyStore,
+		TokenDenylist:  service.TokenDenylist,
+		RateLimiter:    service.RateLimiter,
+		RateLimits:     LoadRateLimitPolicies(),
 		CORS:           mw.CORSMiddleware(),
 		ClientCORS:     mw.ClientCORSMiddleware(),
 	}
@@ -0,0 +1,53 @@
+package initializers
+
+import (
+	"log"
+	"os"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+)
+
+// PKCS11Module is set by builds that link a PKCS#11 binding. It backs
+// SIGNING_KEY_PROVIDER=pkcs11.
+var PKCS11Module service.PKCS11Module
+
+// LoadEnvelopeCipher reads the versioned encryption keys. A configured key
+// that does not load stops start-up instead of leaving secrets unsealed.
+func LoadEnvelopeCipher() *utils.EnvelopeCipher {
+	cipher, err := utils.LoadEnvelopeCipher()
+	if err != nil {
+		log.Fatalf("[LoadEnvelopeCipher] Encryption key: %v", err)
+	}
+	return cipher
+}
+
+// NewKeyProvider selects where signing keys are kept from
+// SIGNING_KEY_PROVIDER: "database" (default), "keyring" or "pkcs11".
+func NewKeyProvider(cipher *utils.EnvelopeCipher) service.KeyProvider {
+	switch provider := os.Getenv("SIGNING_KEY_PROVIDER"); provider {
+	case "", "database":
+		// Keys in the database are only as safe as the KEK sealing them.
+		if cipher == nil || cipher.ActiveVersion() == 0 {
+			log.Fatal("Database key provider needs MFA_ENCRYPTION_KEYS " +
+				"or MFA_ENCRYPTION_KEY")
+		}
+		return service.NewDatabaseKeyProvider(cipher)
+	case "keyring":
+		dir := os.Getenv("SIGNING_KEYRING_DIR")
+		passphrase := os.Getenv("SIGNING_KEYRING_PASSPHRASE")
+		if dir == "" || passphrase == "" {
+			log.Fatal("Keyring provider needs SIGNING_KEYRING_DIR and " +
+				"SIGNING_KEYRING_PASSPHRASE")
+		}
+		return service.NewFileKeyringProvider(dir, passphrase)
+	case "pkcs11":
+		if PKCS11Module == nil {
+			log.Fatal("PKCS#11 provider selected but no module is linked")
+		}
+		return service.NewPKCS11KeyProvider(PKCS11Module)
+	default:
+		log.Fatal("Unknown SIGNING_KEY_PROVIDER: ", provider)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package initializers
+
+import (
+	"log"
+	"os"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/middleware"
+)
+
+// LoadRateLimitPolicies returns the default rate limit policies with the
+// overrides of RATE_LIMIT_POLICIES applied. Malformed overrides are logged
+// and the defaults kept.
+func LoadRateLimitPolicies() middleware.RateLimitPolicies {
+	defaults := middleware.DefaultRateLimitPolicies()
+
+	policies, err := middleware.ParseRateLimitPolicies(
+		os.Getenv("RATE_LIMIT_POLICIES"),
+		defaults,
+	)
+	if err != nil {
+		log.Printf("[LoadRateLimitPolicies] Using defaults: %v", err)
+		return defaults
+	}
+	return policies
+}
@@ -5,14 +5,19 @@ import (
 	"log"
 	"os"
 
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	PrivKey              *rsa.PrivateKey
-	PubKey               *rsa.PublicKey
+	// InitialSigningKey is the key pair loaded from disk. It only becomes
+	// the active signing key when no key has been registered yet.
+	InitialSigningKey    *service.SigningKey
 	PRIVATE_KEY_LOCATION = os.Getenv("RSA_PRIVATE_KEY_PATH")
 	PUBLIC_KEY_LOCATION  = os.Getenv("RSA_PUBLIC_KEY_PATH")
+	// PRIVATE_KEY_PASSPHRASE unlocks an encrypted PKCS#8 private key.
+	PRIVATE_KEY_PASSPHRASE = os.Getenv("RSA_PRIVATE_KEY_PASSPHRASE")
 )
 
 func LoadRSAKeys() {
@@ -23,17 +28,31 @@ func LoadRSAKeys() {
 		log.Fatal("Could not read private key: ", err)
 	}
 
-	PrivKey, err = jwt.ParseRSAPrivateKeyFromPEM(privBytes)
+	privKey, err := utils.ParsePrivateKeyPEM(privBytes, PRIVATE_KEY_PASSPHRASE)
 	if err != nil {
 		log.Fatal("Could not parse private key: ", err)
 	}
+	if _, ok := privKey.(*rsa.PrivateKey); !ok {
+		log.Fatal("Private key is not an RSA key")
+	}
 
 	pubBytes, err := os.ReadFile(PUBLIC_KEY_LOCATION)
 	if err != nil {
 		log.Fatal("Could not read public key: ", err)
 	}
-	PubKey, err = jwt.ParseRSAPublicKeyFromPEM(pubBytes)
+	pubKey, err := jwt.ParseRSAPublicKeyFromPEM(pubBytes)
 	if err != nil {
 		log.Fatal("Could not parse public key: ", err)
 	}
+
+	keyID := os.Getenv("KEY_ID")
+	if keyID == "" {
+		keyID = service.KeyThumbprint(pubKey)
+	}
+
+	InitialSigningKey = &service.SigningKey{
+		ID:        keyID,
+		Signer:    privKey,
+		PublicKey: pubKey,
+	}
 }
@@ -14,6 +14,12 @@ import (
 
 func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 	var appCache cache.Cache = cache.NewNoopCache()
+	var rateLimiter cache.RateLimiter = cache.NewMemoryRateLimiter()
+	// Revocations must hold on every instance, so without Redis they are
+	// kept in the database rather than in the no-op cache.
+	var denylist service.TokenDenylist = service.NewDBTokenDenylist(
+		repository.NewTokenDenylistRepository(db),
+	)
 
 	if redisURL := os.Getenv("REDIS_URL"); redisURL != "" {
 		opt, err := redis.ParseURL(redisURL)
@@ -26,6 +32,11 @@ func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 				log.Printf("[InitializeServices] Redis connection failure: %v", err)
 			} else {
 				appCache = cache.NewRedisCache(redisClient)
+				denylist = service.NewTokenDenylist(appCache)
+				rateLimiter = cache.NewRedisRateLimiter(
+					redisClient,
+					rateLimiter,
+				)
 				log.Println("[InitializeServices] Redis cache successfully initialized")
 			}
 		}
@@ -46,6 +57,17 @@ func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 	registrationRepo := repository.NewRegistrationRepository(db)
 	passkeyRepo := repository.NewPasskeyRepository(db)
 	metricsRepo := repository.NewMetricsRepository(db)
+	consentRepo := repository.NewConsentRepository(db)
+
+	envelope := LoadEnvelopeCipher()
+	keyStore := service.NewKeyStore(
+		repository.NewSigningKeyRepository(db),
+		NewKeyProvider(envelope),
+		InitialSigningKey,
+	)
+	if err := keyStore.Refresh(context.Background()); err != nil {
+		log.Printf("[InitializeServices] Signing key load warning: %v", err)
+	}
 
 	userSvc := service.NewUserService(
 		userRepo,
@@ -53,6 +75,7 @@ func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 		registrationRepo,
 		cauRepo,
 		appCache,
+		denylist,
 	)
 
 	passkeySvc, err := service.NewPasskeyService(
@@ -73,8 +96,9 @@ func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 			authRepo,
 			sessionRepo,
 			clientRepo,
-			PrivKey,
-			PubKey,
+			consentRepo,
+			keyStore,
+			denylist,
 		),
 		LogService:        service.NewLogService(logRepo),
 		PermissionService: service.NewPermissionService(permissionRepo),
@@ -92,7 +116,10 @@ func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 			otpRepo,
 			service.NewMailService(otpRepo, invRepo),
 		),
-		MFAService:     service.NewMFAService(repository.NewMFARepository(db)),
+		MFAService: service.NewMFAService(
+			repository.NewMFARepository(db),
+			envelope,
+		),
 		PasskeyService: passkeySvc,
 		MetricsService: service.NewMetricsService(
 			metricsRepo, appCache, Storage,
@@ -100,5 +127,35 @@ func InitializeServices(db *sqlx.DB) service.ServiceContainer {
 		ReportService: service.NewReportService(
 			userRepo, clientRepo, logRepo,
 		),
+		TokenDenylist: denylist,
+		KeyStore:      keyStore,
+		ConsentService: service.NewConsentService(
+			consentRepo,
+			sessionRepo,
+			clientRepo,
+		),
+		LogoutService: service.NewLogoutService(
+			repository.NewLogoutRepository(db),
+			sessionRepo,
+			clientRepo,
+			keyStore,
+		),
+		DeviceService: service.NewDeviceService(
+			repository.NewDeviceRepository(db),
+			authRepo,
+			sessionRepo,
+			clientRepo,
+		),
+		LockoutService: service.NewLockoutService(
+			repository.NewLockoutRepository(db),
+			userRepo,
+			appCache,
+		),
+		MagicLinkService: service.NewMagicLinkService(
+			repository.NewMagicLinkRepository(db),
+			userRepo,
+			clientRepo,
+		),
+		RateLimiter: rateLimiter,
 	}
 }
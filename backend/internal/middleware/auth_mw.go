@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"crypto/rsa"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -25,8 +24,12 @@ func buildMetadataForMW(m map[string]interface{}) json.RawMessage {
 	return json.RawMessage(b)
 }
 
-// AuthMiddleware validates the RSA JWT from the Authorization Header.
-func AuthMiddleware(publicKey *rsa.PublicKey, logService service.LogService) gin.HandlerFunc {
+// AuthMiddleware validates the RSA JWT from the Authorization Header against
+// the signing key named by its kid. Tokens on the denylist are rejected even
+// if their signature and exp are still valid.
+func AuthMiddleware(keys service.KeyStore, logService service.LogService,
+	denylist service.TokenDenylist,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		parts := strings.Split(authHeader, " ")
@@ -49,7 +52,7 @@ func AuthMiddleware(publicKey *rsa.PublicKey, logService service.LogService) gin
 			return
 		}
 
-		token, err := service.GetParsedToken(parts[1], publicKey)
+		token, err := service.GetParsedToken(parts[1], keys)
 		if err != nil {
 			log.Printf("[AuthMiddleware] Token Validation: %v", err)
 
@@ -79,18 +82,24 @@ func AuthMiddleware(publicKey *rsa.PublicKey, logService service.LogService) gin
 		}
 
 		claims := token.Claims.(*models.UserClaims)
+		if rejectRevokedToken(c, denylist, claims, logService,
+			"auth_header") {
+			return
+		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("client_id", claims.AuthorizedParty)
+		c.Set("scope", claims.Scope)
 		c.Next()
 	}
 }
 
 // AuthorizeRBAC validates a JWT from a Cookie and checks required roles.
-func AuthorizeRBAC(publicKey *rsa.PublicKey,
+func AuthorizeRBAC(keys service.KeyStore,
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
 	logService service.LogService,
+	denylist service.TokenDenylist,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenStr, err := c.Cookie("access_token")
@@ -112,7 +121,7 @@ func AuthorizeRBAC(publicKey *rsa.PublicKey,
 			return
 		}
 
-		token, err := service.GetParsedToken(tokenStr, publicKey)
+		token, err := service.GetParsedToken(tokenStr, keys)
 		if err != nil {
 			log.Printf("[AuthorizeRBAC] Token Validation: %v", err)
 
@@ -141,6 +150,11 @@ func AuthorizeRBAC(publicKey *rsa.PublicKey,
 		}
 
 		claims := token.Claims.(*models.UserClaims)
+		if rejectRevokedToken(c, denylist, claims, logService,
+			"access_token_cookie") {
+			return
+		}
+
 		userID, err := uuid.Parse(string(claims.UserID))
 		if err != nil {
 			log.Printf("[AuthorizeRBAC] UUID Parse Error: %v", err)
@@ -192,6 +206,35 @@ func AuthorizeRBAC(publicKey *rsa.PublicKey,
 	}
 }
 
+// rejectRevokedToken aborts with 401 when the token was revoked by jti or
+// issued before the user's revocation watermark.
+func rejectRevokedToken(c *gin.Context, denylist service.TokenDenylist,
+	claims *models.UserClaims, logService service.LogService, target string,
+) bool {
+	if denylist == nil || !denylist.IsRevoked(c.Request.Context(), claims) {
+		return false
+	}
+
+	log.Printf("[AuthMiddleware] Token Revoked: jti %s", claims.ID)
+	if logService != nil {
+		_ = logService.PostSecurityLogWithActorString(c.Request.Context(),
+			c.ClientIP(), &dto.PostAuditLogRequest{
+				Action: "revoked_token_usage",
+				Target: target,
+				Status: models.StatusFail,
+				Metadata: buildMetadataForMW(map[string]interface{}{
+					"user_id":    claims.UserID,
+					"client_id":  claims.AuthorizedParty,
+					"ip":         c.ClientIP(),
+					"user_agent": c.Request.UserAgent(),
+				}),
+			})
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized,
+		dto.ErrorResponse{Error: "invalid_token"})
+	return true
+}
+
 // HasPermission checks if a given permission string exists in the context.
 func HasPermission(c *gin.Context, permission string) bool {
 	perms, exists := c.Get("permissions")
@@ -1,59 +1,311 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/errors"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
-// rateLimiter struct holds the limiters for each IP
-type rateLimiter struct {
-	limiters map[string]*rate.Limiter
-	mu       sync.Mutex
-	rate     rate.Limit
-	burst    int
+// Request attributes a rate limit rule can count requests by. The
+// email_ip key counts an email per client IP, so requests from elsewhere
+// cannot use up the limit of someone else's email.
+const (
+	RateLimitByIP       = "ip"
+	RateLimitByEmail    = "email"
+	RateLimitByEmailIP  = "email_ip"
+	RateLimitByClientID = "client_id"
+)
+
+// RateLimitRule allows Limit requests per Window for each value of Key.
+type RateLimitRule struct {
+	Key    string
+	Limit  int
+	Window time.Duration
 }
 
-func newRateLimiter(r rate.Limit, b int) *rateLimiter {
-	return &rateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		rate:     r,
-		burst:    b,
+// RateLimitPolicies maps a route name to the rules applied to its requests.
+type RateLimitPolicies map[string][]RateLimitRule
+
+// DefaultRateLimitPolicies returns the policies applied when no override is
+// configured. Group policies (auth, otp, mfa) cover every route of a group;
+// the others add tighter rules to the routes that check secrets.
+//
+// The login email rule counts per client IP. Counted by email alone, anyone
+// could send failed logins for a victim's email and keep the victim from
+// signing in. Guessing one password from many IPs is still bounded by the
+// account lockout, which the owner can lift with an emailed unlock code.
+func DefaultRateLimitPolicies() RateLimitPolicies {
+	return RateLimitPolicies{
+		"auth": {
+			{Key: RateLimitByIP, Limit: 100, Window: 5 * time.Minute},
+		},
+		"login": {
+			{Key: RateLimitByIP, Limit: 15, Window: 3 * time.Minute},
+			{Key: RateLimitByEmailIP, Limit: 10, Window: 15 * time.Minute},
+		},
+		"token": {
+			{Key: RateLimitByIP, Limit: 60, Window: time.Minute},
+			{Key: RateLimitByClientID, Limit: 300, Window: time.Minute},
+		},
+		"otp": {
+			{Key: RateLimitByIP, Limit: 30, Window: 5 * time.Minute},
+		},
+		"otp_send": {
+			{Key: RateLimitByIP, Limit: 5, Window: 10 * time.Minute},
+			{Key: RateLimitByEmail, Limit: 3, Window: 10 * time.Minute},
+		},
+		"magic_link": {
+			{Key: RateLimitByIP, Limit: 5, Window: 10 * time.Minute},
+			{Key: RateLimitByEmail, Limit: 3, Window: 10 * time.Minute},
+		},
+		"mfa": {
+			{Key: RateLimitByIP, Limit: 60, Window: 5 * time.Minute},
+		},
+		"mfa_verify": {
+			{Key: RateLimitByIP, Limit: 15, Window: 3 * time.Minute},
+			{Key: RateLimitByEmail, Limit: 10, Window: 10 * time.Minute},
+		},
 	}
 }
 
-func (rl *rateLimiter) getLimiter(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// ParseRateLimitPolicies applies a comma separated list of overrides of the
+// form route:key=limit/window, e.g. "login:email=5/15m", on top of base. A
+// rule replaces the rule of the same key on that route, and a limit of 0
+// removes it.
+func ParseRateLimitPolicies(
+	spec string,
+	base RateLimitPolicies,
+) (RateLimitPolicies, error) {
+	policies := make(RateLimitPolicies, len(base))
+	for route, rules := range base {
+		policies[route] = append([]RateLimitRule(nil), rules...)
+	}
 
-	limiter, exists := rl.limiters[ip]
-	if !exists {
-		limiter = rate.NewLimiter(rl.rate, rl.burst)
-		rl.limiters[ip] = limiter
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, value, ok := strings.Cut(entry, "=")
+		route, key, ok2 := strings.Cut(target, ":")
+		limitStr, windowStr, ok3 := strings.Cut(value, "/")
+		if !ok || !ok2 || !ok3 || route == "" {
+			return nil, fmt.Errorf("malformed rate limit %q", entry)
+		}
+		switch key {
+		case RateLimitByIP, RateLimitByEmail, RateLimitByEmailIP,
+			RateLimitByClientID:
+		default:
+			return nil, fmt.Errorf("unknown rate limit key %q", key)
+		}
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit in %q", entry)
+		}
+		window, err := time.ParseDuration(windowStr)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid window in %q", entry)
+		}
+
+		rules := policies[route][:0]
+		for _, r := range policies[route] {
+			if r.Key != key {
+				rules = append(rules, r)
+			}
+		}
+		if limit > 0 {
+			rules = append(rules, RateLimitRule{
+				Key:    key,
+				Limit:  limit,
+				Window: window,
+			})
+		}
+		policies[route] = rules
 	}
 
-	return limiter
+	return policies, nil
 }
 
-var strictLimiter = newRateLimiter(rate.Every(12*time.Second), 15)
+// RateLimitMiddleware applies the rules of the named route policy. Every
+// rule is checked before any counts the request, so a refused request uses
+// up none of the windows; an allowed one is counted in all of them. The
+// RateLimit-* headers describe the rule closest to its limit.
+func RateLimitMiddleware(
+	limiter cache.RateLimiter,
+	policies RateLimitPolicies,
+	route string,
+) gin.HandlerFunc {
+	rules := policies[route]
 
-// RateLimitMiddleware applies a strict rate limit for sensitive endpoints.
-func RateLimitMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := strictLimiter.getLimiter(ip)
+		if len(rules) == 0 {
+			c.Next()
+			return
+		}
 
-		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
-				Error: "request limit exceeded",
-			})
+		ctx := c.Request.Context()
+
+		// 1. Check every rule
+		var fields rateLimitFields
+		var keys []string
+		var checked []RateLimitRule
+		for _, rule := range rules {
+			value := fields.value(c, rule.Key)
+			if value == "" {
+				continue
+			}
+
+			key := "ratelimit:" + route + ":" + rule.Key + ":" + value
+			res, err := limiter.Peek(ctx, key, rule.Limit, rule.Window)
+			if err != nil {
+				log.Printf("[RateLimitMiddleware] %s: %v", route, err)
+				continue
+			}
+			if !res.Allowed {
+				refuseRateLimited(c, rule, res)
+				return
+			}
+			keys = append(keys, key)
+			checked = append(checked, rule)
+		}
+
+		// 2. Count the request
+		var tightest *cache.RateLimitResult
+		var tightestRule RateLimitRule
+		for i, rule := range checked {
+			res, err := limiter.Allow(ctx, keys[i], rule.Limit, rule.Window)
+			if err != nil {
+				log.Printf("[RateLimitMiddleware] %s: %v", route, err)
+				continue
+			}
+
+			if tightest == nil || !res.Allowed ||
+				res.Remaining < tightest.Remaining {
+				tightest = &res
+				tightestRule = rule
+			}
+		}
+
+		if tightest == nil {
+			c.Next()
 			return
 		}
 
+		// A concurrent request may have taken the last slot since the
+		// check.
+		if !tightest.Allowed {
+			refuseRateLimited(c, tightestRule, *tightest)
+			return
+		}
+
+		setRateLimitHeaders(c, tightestRule, *tightest)
 		c.Next()
 	}
 }
+
+// setRateLimitHeaders describes a rule's window in the RateLimit-* headers
+// and returns the seconds until it frees up room.
+func setRateLimitHeaders(
+	c *gin.Context,
+	rule RateLimitRule,
+	res cache.RateLimitResult,
+) int {
+	reset := int(math.Ceil(res.Reset.Seconds()))
+	c.Header("RateLimit-Limit", strconv.Itoa(rule.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(reset))
+	return reset
+}
+
+// refuseRateLimited answers a request an exhausted rule refuses.
+func refuseRateLimited(
+	c *gin.Context,
+	rule RateLimitRule,
+	res cache.RateLimitResult,
+) {
+	reset := setRateLimitHeaders(c, rule, res)
+	c.Header("Retry-After", strconv.Itoa(max(1, reset)))
+	errors.SendString(
+		c,
+		http.StatusTooManyRequests,
+		errors.CodeRateLimitExceeded,
+		"Too many requests. Please try again later.",
+		"request limit exceeded",
+	)
+	c.Abort()
+}
+
+// rateLimitFields reads the email and client_id of a request once, for
+// all the rules that count by them.
+type rateLimitFields struct {
+	parsed   bool
+	email    string
+	clientID string
+}
+
+func (f *rateLimitFields) value(c *gin.Context, key string) string {
+	if key == RateLimitByIP {
+		return c.ClientIP()
+	}
+
+	if !f.parsed {
+		f.parse(c)
+	}
+	switch key {
+	case RateLimitByEmail:
+		return f.email
+	case RateLimitByEmailIP:
+		if f.email == "" {
+			return ""
+		}
+		return f.email + ":" + c.ClientIP()
+	}
+	return f.clientID
+}
+
+// parse takes the fields from a JSON or form body, falling back to the
+// query, and the client_id also from the Basic credentials of a
+// confidential client. A JSON body is restored for the handler to bind.
+func (f *rateLimitFields) parse(c *gin.Context) {
+	f.parsed = true
+
+	if c.ContentType() == gin.MIMEJSON && c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err == nil {
+			var fields struct {
+				Email    string `json:"email"`
+				ClientID string `json:"client_id"`
+			}
+			_ = json.Unmarshal(body, &fields)
+			f.email = fields.Email
+			f.clientID = fields.ClientID
+		}
+	} else {
+		f.email = c.PostForm("email")
+		f.clientID = c.PostForm("client_id")
+	}
+
+	if f.email == "" {
+		f.email = c.Query("email")
+	}
+	if f.clientID == "" {
+		f.clientID = c.Query("client_id")
+	}
+	if f.clientID == "" {
+		f.clientID, _, _ = c.Request.BasicAuth()
+	}
+	f.email = strings.ToLower(strings.TrimSpace(f.email))
+}
@@ -8,33 +8,45 @@ const (
 	GrantAuthCode          ClientGrantType = "authorization_code"
 	GrantRefreshToken      ClientGrantType = "refresh_token"
 	GrantClientCredentials ClientGrantType = "client_credentials"
+	GrantDeviceCode        ClientGrantType = "urn:ietf:params:oauth:grant-type:device_code"
 )
 
 func (g ClientGrantType) IsValid() bool {
 	switch g {
-	case GrantAuthCode, GrantRefreshToken, GrantClientCredentials:
+	case GrantAuthCode, GrantRefreshToken, GrantClientCredentials,
+		GrantDeviceCode:
 		return true
 	}
 	return false
 }
 
 type Client struct {
-	ID              []byte    `db:"id"`
-	ClientName      string    `db:"client_name"`
-	ClientSecret    string    `db:"client_secret"`
-	OldSecret       string    `db:"old_secret"`
-	BaseUrl         string    `db:"base_url"`
-	RedirectUri     string    `db:"redirect_uri"`
-	LogoutUri       string    `db:"logout_uri"`
-	Description     string    `db:"description"`
-	ImageLocation   string    `db:"image_location"`
-	OnePortalLink   *string   `db:"one_portal_link"`
-	AccessTokenTTL  int       `db:"access_token_ttl"`
-	RefreshTokenTTL int       `db:"refresh_token_ttl"`
-	CreatedAt       time.Time `db:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at"`
+	ID                    []byte    `db:"id"`
+	ClientName            string    `db:"client_name"`
+	ClientSecret          string    `db:"client_secret"`
+	OldSecret             string    `db:"old_secret"`
+	BaseUrl               string    `db:"base_url"`
+	RedirectUri           string    `db:"redirect_uri"`
+	LogoutUri             string    `db:"logout_uri"`
+	FrontchannelLogoutUri string    `db:"frontchannel_logout_uri"`
+	Description           string    `db:"description"`
+	ImageLocation         string    `db:"image_location"`
+	OnePortalLink         *string   `db:"one_portal_link"`
+	AccessTokenTTL        int       `db:"access_token_ttl"`
+	RefreshTokenTTL       int       `db:"refresh_token_ttl"`
+	RequirePKCE           bool      `db:"require_pkce"`
+	AllowedScopes         string    `db:"allowed_scopes"`
+	TokenSigningAlg       string    `db:"token_signing_alg"`
+	RequireConsent        bool      `db:"require_consent"`
+	MinACR                string    `db:"min_acr"`
+	RequirePAR            bool      `db:"require_par"`
+	AllowMagicLink        bool      `db:"allow_magic_link"`
+	PublicClient          bool      `db:"public_client"`
+	CreatedAt             time.Time `db:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at"`
 
 	Grants       []string
+	RedirectUris []string
 	AllowedRoles []Role
 }
 
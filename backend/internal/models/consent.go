@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// UserConsent is the set of scopes a user granted to a client. ClientName
+// is only filled when listing a user's consents.
+type UserConsent struct {
+	UserID     []byte    `db:"user_id"`
+	ClientID   []byte    `db:"client_id"`
+	ClientName string    `db:"client_name"`
+	Scope      string    `db:"scope"`
+	GrantedAt  time.Time `db:"granted_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LoginFailure counts the failed password attempts of an account within
+// the failure window. LockedUntil is set once the count reaches the
+// policy's limit.
+type LoginFailure struct {
+	Email        string       `db:"email"`
+	FailedCount  int          `db:"failed_count"`
+	LastFailedAt time.Time    `db:"last_failed_at"`
+	LockedUntil  sql.NullTime `db:"locked_until"`
+	ExpiresAt    time.Time    `db:"expires_at"`
+}
+
+// LockoutPolicy configures the progressive delays between failed logins
+// and the lockout that follows too many of them.
+type LockoutPolicy struct {
+	MaxFailures          int       `db:"max_failures"`
+	LockoutMinutes       int       `db:"lockout_minutes"`
+	BackoffBaseSeconds   int       `db:"backoff_base_seconds"`
+	BackoffMaxSeconds    int       `db:"backoff_max_seconds"`
+	FailureWindowMinutes int       `db:"failure_window_minutes"`
+	UpdatedAt            time.Time `db:"updated_at"`
+}
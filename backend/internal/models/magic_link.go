@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// MagicLinkToken is a single-use login link emailed to a user. Only the
+// SHA-256 hash of the token is stored, with the client and redirect URI of
+// the authorization request the login continues. AuthorizeQuery holds that
+// whole request when the browser asking for the link had one pending.
+type MagicLinkToken struct {
+	TokenHash      string    `db:"token_hash"`
+	Email          string    `db:"email"`
+	ClientID       []byte    `db:"client_id"`
+	RedirectURI    string    `db:"redirect_uri"`
+	AuthorizeQuery string    `db:"authorize_query"`
+	CreatedAt      time.Time `db:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at"`
+}
@@ -12,3 +12,20 @@ type OTP struct {
 	Attempts  int        `db:"attempts"`
 	CreatedAt time.Time  `db:"created_at"`
 }
+
+// OTP verification purposes. PurposePasswordReset also scopes the reset
+// token a verification mints to the forgot-password flow.
+const (
+	PurposePasswordReset = "password_reset"
+	PurposeAccountUnlock = "account_unlock"
+)
+
+// PasswordResetToken is a single-use token minted by a verified OTP. Only
+// the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	TokenHash string    `db:"token_hash"`
+	Email     string    `db:"email"`
+	Purpose   string    `db:"purpose"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
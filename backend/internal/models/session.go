@@ -1,12 +1,43 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
+// IdPSession is a signed-in browser session. AMR lists the authentication
+// methods used so far, space-delimited; AuthTime is when the user logged
+// in and MFATime when a second factor was last verified.
 type IdPSession struct {
-	SessionId string    `db:"session_id"`
+	SessionId string       `db:"session_id"`
+	UserId    []byte       `db:"user_id"`
+	IpAddress string       `db:"ip_address"`
+	UserAgent string       `db:"user_agent"`
+	CreatedAt time.Time    `db:"created_at"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	AMR       string       `db:"amr"`
+	AuthTime  sql.NullTime `db:"auth_time"`
+	MFATime   sql.NullTime `db:"mfa_time"`
+}
+
+// SessionClient is a client a session has signed in to. Sid is the
+// session's public identifier, never the session cookie itself.
+type SessionClient struct {
+	Sid       string    `db:"sid"`
+	ClientId  []byte    `db:"client_id"`
 	UserId    []byte    `db:"user_id"`
-	IpAddress string    `db:"ip_address"`
-	UserAgent string    `db:"user_agent"`
 	CreatedAt time.Time `db:"created_at"`
 	ExpiresAt time.Time `db:"expires_at"`
 }
+
+// LogoutNotification is a queued back-channel logout request for a client.
+type LogoutNotification struct {
+	ID            int64     `db:"id"`
+	ClientId      []byte    `db:"client_id"`
+	UserId        []byte    `db:"user_id"`
+	Sid           string    `db:"sid"`
+	Attempts      int       `db:"attempts"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+	ExpiresAt     time.Time `db:"expires_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SigningKey is a stored token signing key. The public key is PEM encoded;
+// PrivateKey holds the key provider's reference, which is the sealed or
+// plain PEM key itself for keys kept in the database.
+type SigningKey struct {
+	KeyID      string       `db:"kid"`
+	Algorithm  string       `db:"algorithm"`
+	PrivateKey string       `db:"private_key"`
+	PublicKey  string       `db:"public_key"`
+	Active     bool         `db:"active"`
+	CreatedAt  time.Time    `db:"created_at"`
+	RetiredAt  sql.NullTime `db:"retired_at"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+}
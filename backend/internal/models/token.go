@@ -7,13 +7,118 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// PKCE code challenge methods (RFC 7636).
+const (
+	PKCEMethodPlain = "plain"
+	PKCEMethodS256  = "S256"
+)
+
+// OpenID Connect scopes understood by the authorization endpoint.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// OpenID Connect prompt values understood by the authorization endpoint.
+const (
+	PromptNone          = "none"
+	PromptLogin         = "login"
+	PromptConsent       = "consent"
+	PromptSelectAccount = "select_account"
+)
+
+// Authentication method references (RFC 8176) and context classes
+// reported in ID tokens.
+const (
+	AMRPassword    = "pwd"
+	AMRMultiFactor = "mfa"
+	AMROTP         = "otp"
+	AMRTOTP        = "totp"
+	AMRHardwareKey = "hwk"
+	// AMREmailLink marks a sign-in through an emailed magic link, which
+	// stands in for the password as the first factor.
+	AMREmailLink = "email"
+
+	ACRSingleFactor = "1fa"
+	ACRMultiFactor  = "2fa"
+)
+
+// ACRValues lists the supported authentication context classes from the
+// weakest to the strongest.
+var ACRValues = []string{ACRSingleFactor, ACRMultiFactor}
+
+// JWS algorithms a client may choose for its access and ID tokens.
+const (
+	SigningAlgRS256 = "RS256"
+	SigningAlgPS256 = "PS256"
+	SigningAlgES256 = "ES256"
+	SigningAlgEdDSA = "EdDSA"
+)
+
+// SigningAlgorithms lists every supported token signing algorithm.
+var SigningAlgorithms = []string{
+	SigningAlgRS256,
+	SigningAlgPS256,
+	SigningAlgES256,
+	SigningAlgEdDSA,
+}
+
 type AuthorizationCode struct {
-	Code        string       `db:"code"`
-	ClientId    []byte       `db:"client_id"`
-	UserId      []byte       `db:"user_id"`
-	ExpiresAt   time.Time    `db:"expires_at"`
-	UsedAt      sql.NullTime `db:"used_at"`
-	RedirectURI string       `db:"redirect_uri"`
+	Code                string       `db:"code"`
+	ClientId            []byte       `db:"client_id"`
+	UserId              []byte       `db:"user_id"`
+	ExpiresAt           time.Time    `db:"expires_at"`
+	UsedAt              sql.NullTime `db:"used_at"`
+	RedirectURI         string       `db:"redirect_uri"`
+	CodeChallenge       string       `db:"code_challenge"`
+	CodeChallengeMethod string       `db:"code_challenge_method"`
+	Scope               string       `db:"scope"`
+	Nonce               string       `db:"nonce"`
+	AuthTime            sql.NullTime `db:"auth_time"`
+	AMR                 string       `db:"amr"`
+	ACR                 string       `db:"acr"`
+	Sid                 string       `db:"sid"`
+}
+
+// Device authorization request states (RFC 8628).
+const (
+	DeviceStatusPending  = "pending"
+	DeviceStatusApproved = "approved"
+	DeviceStatusDenied   = "denied"
+)
+
+// DeviceAuthorization is a device authorization request. Once the user
+// approves it, UserId and the authentication context of their session
+// are filled in and carried into the tokens the device redeems.
+type DeviceAuthorization struct {
+	DeviceCode   string       `db:"device_code"`
+	UserCode     string       `db:"user_code"`
+	ClientId     []byte       `db:"client_id"`
+	Scope        string       `db:"scope"`
+	Status       string       `db:"status"`
+	UserId       []byte       `db:"user_id"`
+	AuthTime     sql.NullTime `db:"auth_time"`
+	AMR          string       `db:"amr"`
+	ACR          string       `db:"acr"`
+	Sid          string       `db:"sid"`
+	Interval     int          `db:"poll_interval"`
+	LastPolledAt sql.NullTime `db:"last_polled_at"`
+	CreatedAt    time.Time    `db:"created_at"`
+	ExpiresAt    time.Time    `db:"expires_at"`
+}
+
+// PushedAuthorizationRequest holds the authorization request parameters
+// a client pushed to the PAR endpoint (RFC 9126), JSON encoded. RequestID
+// is the random part of the request_uri handed back to the client. UsedBy
+// holds the binding of the browser that first resolved the request.
+type PushedAuthorizationRequest struct {
+	RequestID string         `db:"request_id"`
+	ClientId  []byte         `db:"client_id"`
+	Params    string         `db:"params"`
+	UsedBy    sql.NullString `db:"used_by"`
+	CreatedAt time.Time      `db:"created_at"`
+	ExpiresAt time.Time      `db:"expires_at"`
 }
 
 type RefreshToken struct {
@@ -23,10 +128,50 @@ type RefreshToken struct {
 	UserId    []byte    `db:"user_id"`
 	ExpiresAt time.Time `db:"expires_at"`
 	Revoked   bool      `db:"revoked"`
+	Rotated   bool      `db:"rotated"`
+	Scope     string    `db:"scope"`
+	FamilyID  []byte    `db:"family_id"`
+	CreatedAt time.Time `db:"created_at"`
 }
 
 type UserClaims struct {
-	AuthorizedParty string `json:"azp,omitempty"`
-	UserID          string `json:"userId"`
+	AuthorizedParty string   `json:"azp,omitempty"`
+	UserID          string   `json:"userId"`
+	Scope           string   `json:"scope,omitempty"`
+	AuthTime        int64    `json:"auth_time,omitempty"`
+	AMR             []string `json:"amr,omitempty"`
+	ACR             string   `json:"acr,omitempty"`
+	SessionID       string   `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IDTokenClaims is the payload of an OpenID Connect ID token. Profile
+// and email claims are only populated when the matching scope was granted.
+type IDTokenClaims struct {
+	AuthorizedParty string   `json:"azp,omitempty"`
+	Nonce           string   `json:"nonce,omitempty"`
+	AuthTime        int64    `json:"auth_time,omitempty"`
+	AMR             []string `json:"amr,omitempty"`
+	ACR             string   `json:"acr,omitempty"`
+	SessionID       string   `json:"sid,omitempty"`
+	AccessTokenHash string   `json:"at_hash,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	GivenName       string   `json:"given_name,omitempty"`
+	MiddleName      string   `json:"middle_name,omitempty"`
+	FamilyName      string   `json:"family_name,omitempty"`
+	Email           string   `json:"email,omitempty"`
+	EmailVerified   *bool    `json:"email_verified,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// BackchannelLogoutEvent is the event member of a logout token (OIDC
+// Back-Channel Logout 1.0 section 2.4).
+const BackchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// LogoutTokenClaims is the payload of a back-channel logout token. It
+// names the user, the session or both, and never carries a nonce.
+type LogoutTokenClaims struct {
+	Events    map[string]struct{} `json:"events"`
+	SessionID string              `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
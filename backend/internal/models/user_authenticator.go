@@ -12,6 +12,7 @@ type UserAuthenticator struct {
 	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
 	LastUsedAt      *time.Time `db:"last_used_at" json:"last_used_at"`
 	SecretEncrypted []byte     `db:"secret_encrypted" json:"-"`
+	KeyVersion      *int       `db:"key_version" json:"-"`
 	CredentialID    []byte     `db:"credential_id" json:"-"`
 	PublicKey       []byte     `db:"public_key" json:"-"`
 	SignCount       int        `db:"sign_count" json:"sign_count"`
@@ -13,8 +13,8 @@ import (
 )
 
 type AuthCodeRepository interface {
-	StoreCode(ctx context.Context, code string, userID []byte,
-		clientID []byte, redirectURI string) error
+	StoreCode(ctx context.Context,
+		authCode *models.AuthorizationCode) error
 	ExchangeCode(ctx context.Context,
 		code string) (*models.AuthorizationCode, error)
 	GetUserForAuth(ctx context.Context,
@@ -23,15 +23,29 @@ type AuthCodeRepository interface {
 		clientSecret string) (bool, error)
 	GetClaimsByID(ctx context.Context,
 		userId []byte) (*models.UserClaims, error)
+	GetIdentityByID(ctx context.Context,
+		userId []byte) (*models.User, error)
 	StoreRefreshToken(ctx context.Context, token string, userID []byte,
-		clientID []byte, expiresAt time.Time) error
+		clientID []byte, familyID []byte, scope string, sid string,
+		expiresAt time.Time) error
 	RotateRefreshToken(ctx context.Context, oldToken,
 		newToken string, expiresAt time.Time) error
 	GetIDsFromToken(ctx context.Context,
 		token string) ([]byte, []byte, error)
+	GetRefreshToken(ctx context.Context,
+		token string) (*models.RefreshToken, error)
 	GetClientRedirectURI(ctx context.Context,
 		clientID []byte) (string, error)
 	RevokeTokens(ctx context.Context, userID []byte) error
+	RevokeRefreshToken(ctx context.Context, token string) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID []byte) error
+	StorePushedRequest(ctx context.Context,
+		par *models.PushedAuthorizationRequest) error
+	GetPushedRequest(ctx context.Context,
+		requestID string) (*models.PushedAuthorizationRequest, error)
+	ClaimPushedRequest(ctx context.Context, requestID string,
+		binding string) (bool, error)
+	DeletePushedRequest(ctx context.Context, requestID string) error
 }
 
 type authCodeRepository struct {
@@ -44,17 +58,23 @@ const (
 	DAYS   = 7
 )
 
-// StoreCode saves the generated code
-func (r *authCodeRepository) StoreCode(ctx context.Context, code string,
-	userID []byte, clientID []byte, redirectURI string,
+// StoreCode saves the generated code along with its PKCE challenge and
+// the OIDC request context needed to mint the ID token
+func (r *authCodeRepository) StoreCode(ctx context.Context,
+	authCode *models.AuthorizationCode,
 ) error {
 	query := `
 		INSERT INTO authorization_codes 
-			(code, user_id, client_id, redirect_uri, expires_at) 
-        VALUES (?, ?, ?, ?, ?)`
+			(code, user_id, client_id, redirect_uri, expires_at,
+			code_challenge, code_challenge_method, scope, nonce,
+			auth_time, amr, acr, sid) 
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	expiresAt := time.Now().Add(5 * time.Minute) // Codes are very short-lived
-	_, err := r.db.ExecContext(ctx, query, code, userID, clientID,
-		redirectURI, expiresAt)
+	_, err := r.db.ExecContext(ctx, query, authCode.Code, authCode.UserId,
+		authCode.ClientId, authCode.RedirectURI, expiresAt,
+		authCode.CodeChallenge, authCode.CodeChallengeMethod,
+		authCode.Scope, authCode.Nonce, authCode.AuthTime,
+		authCode.AMR, authCode.ACR, authCode.Sid)
 	return err
 }
 
@@ -69,7 +89,9 @@ func (r *authCodeRepository) ExchangeCode(ctx context.Context,
 	defer tx.Rollback()
 
 	var authCode models.AuthorizationCode
-	query := `SELECT code, user_id, client_id, redirect_uri, expires_at, used_at 
+	query := `SELECT code, user_id, client_id, redirect_uri, expires_at, used_at,
+              code_challenge, code_challenge_method, scope, nonce,
+              auth_time, amr, acr, sid
               FROM authorization_codes WHERE code = ? FOR UPDATE`
 
 	err = tx.GetContext(ctx, &authCode, query, code)
@@ -177,15 +199,43 @@ func (r *authCodeRepository) GetClaimsByID(ctx context.Context,
 	}, nil
 }
 
+// GetIdentityByID loads the profile fields released through OIDC scopes
+func (r *authCodeRepository) GetIdentityByID(ctx context.Context,
+	userId []byte,
+) (*models.User, error) {
+	var user models.User
+	query := `
+        SELECT
+            id,
+            COALESCE(first_name, '') AS first_name,
+            COALESCE(middle_name, '') AS middle_name,
+            COALESCE(last_name, '') AS last_name,
+            COALESCE(name_suffix, '') AS name_suffix,
+            email,
+            status
+        FROM users
+        WHERE id = ? AND status = 'active'
+        LIMIT 1`
+
+	err := r.db.GetContext(ctx, &user, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
 func (r *authCodeRepository) StoreRefreshToken(ctx context.Context,
-	token string, userID []byte, clientID []byte, expiresAt time.Time,
+	token string, userID []byte, clientID []byte, familyID []byte,
+	scope string, sid string, expiresAt time.Time,
 ) error {
 	query := `
-		INSERT INTO refresh_tokens(token, client_id, user_id, expires_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO refresh_tokens(token, client_id, user_id, expires_at,
+			scope, family_id, sid)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 	_, err := r.db.ExecContext(ctx, query, token, clientID, userID,
-		expiresAt)
+		expiresAt, scope, familyID, sid)
 	if err != nil {
 		return err
 	}
@@ -226,6 +276,28 @@ func (r *authCodeRepository) GetIDsFromToken(ctx context.Context,
 	return IDs.UserID, IDs.ClientID, nil
 }
 
+// GetRefreshToken loads a stored refresh token with its granted scope
+func (r *authCodeRepository) GetRefreshToken(ctx context.Context,
+	token string,
+) (*models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	query := `
+        SELECT id, token, client_id, user_id, expires_at,
+               (revoked_at IS NOT NULL) AS revoked,
+               (replaced_by IS NOT NULL) AS rotated,
+               scope, family_id, created_at
+        FROM refresh_tokens
+        WHERE token = ?
+    `
+
+	err := r.db.GetContext(ctx, &refreshToken, query, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &refreshToken, nil
+}
+
 func (r *authCodeRepository) GetClientRedirectURI(ctx context.Context,
 	clientID []byte,
 ) (string, error) {
@@ -250,6 +322,102 @@ func (r *authCodeRepository) RevokeTokens(ctx context.Context,
 	return nil
 }
 
+// RevokeRefreshToken revokes a single refresh token
+func (r *authCodeRepository) RevokeRefreshToken(ctx context.Context,
+	token string,
+) error {
+	query := `
+        UPDATE refresh_tokens
+        SET revoked_at = NOW()
+        WHERE token = ? AND revoked_at IS NULL
+    `
+	_, err := r.db.ExecContext(ctx, query, token)
+	return err
+}
+
+// RevokeRefreshTokenFamily revokes every active refresh token rotated from
+// the same authorization grant, leaving the user's other grants untouched
+func (r *authCodeRepository) RevokeRefreshTokenFamily(ctx context.Context,
+	familyID []byte,
+) error {
+	query := `
+        UPDATE refresh_tokens
+        SET revoked_at = NOW()
+        WHERE family_id = ? AND revoked_at IS NULL
+    `
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
+
+// StorePushedRequest saves the parameters a client pushed ahead of its
+// authorization request
+func (r *authCodeRepository) StorePushedRequest(ctx context.Context,
+	par *models.PushedAuthorizationRequest,
+) error {
+	query := `
+        INSERT INTO pushed_authorization_requests
+            (request_id, client_id, params, expires_at)
+        VALUES (?, ?, ?, ?)
+    `
+	_, err := r.db.ExecContext(ctx, query, par.RequestID, par.ClientId,
+		par.Params, par.ExpiresAt)
+	return err
+}
+
+// GetPushedRequest loads an unexpired pushed request, or nil when there is
+// none
+func (r *authCodeRepository) GetPushedRequest(ctx context.Context,
+	requestID string,
+) (*models.PushedAuthorizationRequest, error) {
+	var par models.PushedAuthorizationRequest
+	query := `
+        SELECT request_id, client_id, params, used_by, created_at,
+            expires_at
+        FROM pushed_authorization_requests
+        WHERE request_id = ? AND expires_at > NOW()
+    `
+	err := r.db.GetContext(ctx, &par, query, requestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &par, nil
+}
+
+// ClaimPushedRequest marks an unused pushed request as used by a browser
+// binding. It reports false when another resolve claimed it first.
+func (r *authCodeRepository) ClaimPushedRequest(ctx context.Context,
+	requestID string,
+	binding string,
+) (bool, error) {
+	query := `
+        UPDATE pushed_authorization_requests
+        SET used_by = ?
+        WHERE request_id = ? AND used_by IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, binding, requestID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// DeletePushedRequest removes a pushed request once a code was issued for
+// it
+func (r *authCodeRepository) DeletePushedRequest(ctx context.Context,
+	requestID string,
+) error {
+	query := `DELETE FROM pushed_authorization_requests WHERE request_id = ?`
+	_, err := r.db.ExecContext(ctx, query, requestID)
+	return err
+}
+
 func NewAuthCodeRepository(db *sqlx.DB) AuthCodeRepository {
 	return &authCodeRepository{
 		db: db,
@@ -48,6 +48,7 @@ type ClientRepository interface {
 		grants []string) error
 	SoftDelete(ctx context.Context, id []byte) error
 	GetGrantTypes(ctx context.Context, clientID []byte) ([]string, error)
+	GetRedirectURIs(ctx context.Context, clientID []byte) ([]string, error)
 	GetClientAllowedRoles(ctx context.Context,
 		clientID []byte) ([]models.Role, error)
 	ListClientBaseURLS(ctx context.Context) ([]string, error)
@@ -62,6 +63,8 @@ type ClientRepository interface {
 		newSecretHash string) error
 	DeleteAndInsertGrants(ctx context.Context, c *models.Client,
 		grants []string) error
+	DeleteAndInsertRedirectURIs(ctx context.Context,
+		c *models.Client) error
 	AdminiClientBind(ctx context.Context, userID, clientID []byte) error
 	BatchAdminClientBind(ctx context.Context, userID []byte,
 		clientIDs [][]byte) error
@@ -69,6 +72,7 @@ type ClientRepository interface {
 		clientIDs [][]byte) error
 	RemoveAdminClientBind(ctx context.Context, clientID []byte) error
 	IsClientAllowed(ctx context.Context, userID, clientID []byte) (bool, error)
+	IsUserAllowed(ctx context.Context, userID, clientID []byte) (bool, error)
 }
 
 type clientRepository struct {
@@ -85,7 +89,10 @@ func (r *clientRepository) GetByID(ctx context.Context,
 		       image_location, base_url,
 		       redirect_uri, logout_uri, updated_at,
 		       one_portal_link, access_token_ttl,
-		       refresh_token_ttl
+		       refresh_token_ttl, require_pkce, allowed_scopes,
+		       token_signing_alg, require_consent, min_acr,
+		       frontchannel_logout_uri, require_par, allow_magic_link,
+		       public_client
 		FROM clients
 		WHERE id = ? AND deleted_at IS NULL`
 
@@ -102,6 +109,11 @@ func (r *clientRepository) GetByID(ctx context.Context,
 		return nil, err
 	}
 
+	client.RedirectUris, err = r.GetRedirectURIs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	roleQuery := `
 		SELECT r.id, r.role_name, r.description
 		FROM roles r
@@ -131,7 +143,10 @@ func (r *clientRepository) ListClients(ctx context.Context, limit,
 			description, image_location,
 			base_url, redirect_uri, logout_uri, created_at,
 			one_portal_link, access_token_ttl,
-			refresh_token_ttl
+			refresh_token_ttl, require_pkce, allowed_scopes,
+			token_signing_alg, require_consent, min_acr,
+			frontchannel_logout_uri, require_par, allow_magic_link,
+			public_client
 		FROM clients
 		WHERE deleted_at IS NULL AND client_name LIKE ?
 		ORDER BY %s %s
@@ -156,7 +171,10 @@ func (r *clientRepository) ListBoundClients(ctx context.Context,
 			c.description, c.image_location,
 			c.base_url, c.redirect_uri, c.logout_uri, c.created_at,
 			c.one_portal_link, c.access_token_ttl,
-			c.refresh_token_ttl
+			c.refresh_token_ttl, c.require_pkce, c.allowed_scopes,
+			c.token_signing_alg, c.require_consent, c.min_acr,
+			c.frontchannel_logout_uri, c.require_par, c.allow_magic_link,
+			c.public_client
 		FROM clients c
 		JOIN admin_allowed_clients a ON c.id = a.client_id
 		WHERE a.user_id = ?
@@ -185,7 +203,10 @@ func (r *clientRepository) ListAllowedClients(ctx context.Context,
 			c.description, c.image_location,
 			c.base_url, c.redirect_uri, c.logout_uri, c.created_at,
 			c.one_portal_link, c.access_token_ttl,
-			c.refresh_token_ttl
+			c.refresh_token_ttl, c.require_pkce, c.allowed_scopes,
+			c.token_signing_alg, c.require_consent, c.min_acr,
+			c.frontchannel_logout_uri, c.require_par, c.allow_magic_link,
+			c.public_client
 		FROM clients c
 		JOIN client_allowed_users a ON c.id = a.client_id
 		WHERE a.user_id = ?
@@ -215,13 +236,19 @@ func (r *clientRepository) CreateClient(ctx context.Context,
 			id, client_name, client_secret,
 			base_url, redirect_uri, logout_uri,
 			description, image_location, one_portal_link,
-			access_token_ttl, refresh_token_ttl
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			access_token_ttl, refresh_token_ttl, require_pkce,
+			allowed_scopes, token_signing_alg, require_consent, min_acr,
+			frontchannel_logout_uri, require_par, allow_magic_link,
+			public_client
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	_, err = tx.ExecContext(ctx, q1, client.ID, client.ClientName,
 		client.ClientSecret, client.BaseUrl, client.RedirectUri,
 		client.LogoutUri, client.Description, client.ImageLocation,
 		client.OnePortalLink, client.AccessTokenTTL,
-		client.RefreshTokenTTL,
+		client.RefreshTokenTTL, client.RequirePKCE, client.AllowedScopes,
+		client.TokenSigningAlg, client.RequireConsent, client.MinACR,
+		client.FrontchannelLogoutUri, client.RequirePAR, client.AllowMagicLink,
+		client.PublicClient,
 	)
 	if err != nil {
 		return err
@@ -238,12 +265,23 @@ func (r *clientRepository) CreateClient(ctx context.Context,
 		}
 	}
 
-	// 3. Bind admin to client
+	// 3. Insert Registered Redirect URIs
 	q3 := `
+		INSERT INTO client_redirect_uris (client_id, redirect_uri)
+		VALUES (?, ?)
+	`
+	for _, uri := range client.RedirectUris {
+		if _, err = tx.ExecContext(ctx, q3, client.ID, uri); err != nil {
+			return err
+		}
+	}
+
+	// 4. Bind admin to client
+	q4 := `
 		INSERT INTO admin_allowed_clients (client_id, user_id)
 		VALUES (?, ?)
 	`
-	_, err = tx.ExecContext(ctx, q3, client.ID, userID)
+	_, err = tx.ExecContext(ctx, q4, client.ID, userID)
 	if err != nil {
 		return err
 	}
@@ -266,18 +304,33 @@ func (r *clientRepository) UpdateClient(ctx context.Context,
 			logout_uri = ?,
 			one_portal_link = ?,
 			access_token_ttl = ?,
-			refresh_token_ttl = ?
+			refresh_token_ttl = ?,
+			require_pkce = ?,
+			allowed_scopes = ?,
+			token_signing_alg = ?,
+			require_consent = ?,
+			min_acr = ?,
+			frontchannel_logout_uri = ?,
+			require_par = ?,
+			allow_magic_link = ?,
+			public_client = ?
 		WHERE id = ?`
 
 	_, err := r.db.ExecContext(ctx, query, c.ClientName, c.Description,
 		c.ImageLocation, c.ImageLocation, c.BaseUrl, c.RedirectUri,
 		c.LogoutUri, c.OnePortalLink, c.AccessTokenTTL,
-		c.RefreshTokenTTL, c.ID,
+		c.RefreshTokenTTL, c.RequirePKCE, c.AllowedScopes,
+		c.TokenSigningAlg, c.RequireConsent, c.MinACR, c.FrontchannelLogoutUri,
+		c.RequirePAR, c.AllowMagicLink, c.PublicClient, c.ID,
 	)
 	if err != nil {
 		return err
 	}
 
+	if err := r.DeleteAndInsertRedirectURIs(ctx, c); err != nil {
+		return err
+	}
+
 	return r.DeleteAndInsertGrants(ctx, c, grants)
 }
 
@@ -288,6 +341,19 @@ func (r *clientRepository) SoftDelete(ctx context.Context, id []byte) error {
 	return err
 }
 
+func (r *clientRepository) GetRedirectURIs(ctx context.Context,
+	clientID []byte,
+) ([]string, error) {
+	var uris []string
+	query := `
+		SELECT redirect_uri FROM client_redirect_uris
+		WHERE client_id = ?
+		ORDER BY id
+	`
+	err := r.db.SelectContext(ctx, &uris, query, clientID)
+	return uris, err
+}
+
 func (r *clientRepository) GetGrantTypes(ctx context.Context,
 	clientID []byte,
 ) ([]string, error) {
@@ -452,6 +518,34 @@ func (r *clientRepository) DeleteAndInsertGrants(ctx context.Context,
 	return tx.Commit()
 }
 
+// DeleteAndInsertRedirectURIs replaces the registered redirect URIs.
+func (r *clientRepository) DeleteAndInsertRedirectURIs(ctx context.Context,
+	c *models.Client,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	delQuery := `DELETE FROM client_redirect_uris WHERE client_id = ?`
+	if _, err = tx.ExecContext(ctx, delQuery, c.ID); err != nil {
+		return err
+	}
+
+	insQuery := `
+		INSERT INTO client_redirect_uris (client_id, redirect_uri)
+		VALUES (?, ?)
+	`
+	for _, uri := range c.RedirectUris {
+		if _, err = tx.ExecContext(ctx, insQuery, c.ID, uri); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (r *clientRepository) AdminiClientBind(ctx context.Context,
 	userID, clientID []byte,
 ) error {
@@ -548,6 +642,30 @@ func (r *clientRepository) IsClientAllowed(ctx context.Context,
 	return allowed, err
 }
 
+// IsUserAllowed reports whether the user may sign in to the client, either
+// through an individual assignment or by holding one of the client's
+// allowed roles, as listed by GetClientAllowedRoles.
+func (r *clientRepository) IsUserAllowed(ctx context.Context,
+	userID, clientID []byte,
+) (bool, error) {
+	var allowed bool
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM client_allowed_users
+			WHERE user_id = ? AND client_id = ?
+		) OR EXISTS(
+			SELECT 1
+			FROM users u
+			JOIN roles r ON r.id = u.role_id
+			JOIN users bound ON bound.role_id = r.id
+			JOIN admin_allowed_clients aac ON aac.user_id = bound.id
+			WHERE u.id = ? AND aac.client_id = ? AND r.deleted_at IS NULL
+		)`
+	err := r.db.GetContext(ctx, &allowed, query,
+		userID, clientID, userID, clientID)
+	return allowed, err
+}
+
 func NewClientRepository(db *sqlx.DB) ClientRepository {
 	return &clientRepository{db: db}
 }
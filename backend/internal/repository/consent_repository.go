@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type ConsentRepository interface {
+	GetConsent(ctx context.Context,
+		userID, clientID []byte) (*models.UserConsent, error)
+	SaveConsent(ctx context.Context, consent *models.UserConsent) error
+	ListConsentsByUser(ctx context.Context,
+		userID []byte) ([]models.UserConsent, error)
+	DeleteConsent(ctx context.Context, userID, clientID []byte) (bool, error)
+}
+
+type consentRepository struct {
+	db *sqlx.DB
+}
+
+// GetConsent returns the consent of the user for the client, or nil when
+// none was granted.
+func (r *consentRepository) GetConsent(
+	ctx context.Context,
+	userID, clientID []byte,
+) (*models.UserConsent, error) {
+	var consent models.UserConsent
+	query := `SELECT user_id, client_id, scope, granted_at
+              FROM user_consents
+              WHERE user_id = ? AND client_id = ?`
+	err := r.db.GetContext(ctx, &consent, query, userID, clientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("[GetConsent]: %w", err)
+	}
+	return &consent, nil
+}
+
+// SaveConsent stores the granted scopes and resets the grant time.
+func (r *consentRepository) SaveConsent(
+	ctx context.Context,
+	consent *models.UserConsent,
+) error {
+	query := `INSERT INTO user_consents (user_id, client_id, scope)
+              VALUES (?, ?, ?)
+              ON DUPLICATE KEY UPDATE
+                  scope = VALUES(scope), granted_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, consent.UserID,
+		consent.ClientID, consent.Scope)
+	if err != nil {
+		return fmt.Errorf("[SaveConsent]: %w", err)
+	}
+	return nil
+}
+
+// ListConsentsByUser returns the user's consents with the client names,
+// most recent first.
+func (r *consentRepository) ListConsentsByUser(
+	ctx context.Context,
+	userID []byte,
+) ([]models.UserConsent, error) {
+	consents := []models.UserConsent{}
+	query := `SELECT uc.user_id, uc.client_id, c.client_name,
+                     uc.scope, uc.granted_at
+              FROM user_consents uc
+              JOIN clients c ON c.id = uc.client_id
+              WHERE uc.user_id = ? AND c.deleted_at IS NULL
+              ORDER BY uc.granted_at DESC`
+	err := r.db.SelectContext(ctx, &consents, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListConsentsByUser]: %w", err)
+	}
+	return consents, nil
+}
+
+// DeleteConsent removes the consent and revokes the refresh tokens the
+// client holds for the user. It reports false when there was no consent.
+func (r *consentRepository) DeleteConsent(
+	ctx context.Context,
+	userID, clientID []byte,
+) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("[DeleteConsent]: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM user_consents WHERE user_id = ? AND client_id = ?`,
+		userID, clientID)
+	if err != nil {
+		return false, fmt.Errorf("[DeleteConsent] delete: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW()
+         WHERE user_id = ? AND client_id = ? AND revoked_at IS NULL`,
+		userID, clientID)
+	if err != nil {
+		return false, fmt.Errorf("[DeleteConsent] revoke: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("[DeleteConsent] commit: %w", err)
+	}
+	return true, nil
+}
+
+func NewConsentRepository(db *sqlx.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type DeviceRepository interface {
+	Create(ctx context.Context, d *models.DeviceAuthorization) error
+	GetByUserCode(ctx context.Context,
+		userCode string) (*models.DeviceAuthorization, error)
+	GetByDeviceCode(ctx context.Context,
+		deviceCode string) (*models.DeviceAuthorization, error)
+	Answer(ctx context.Context, d *models.DeviceAuthorization) (bool, error)
+	RecordPoll(ctx context.Context, deviceCode string,
+		polledAt time.Time, interval int) error
+	Delete(ctx context.Context, deviceCode string) (bool, error)
+}
+
+type deviceRepository struct {
+	db *sqlx.DB
+}
+
+const deviceColumns = `device_code, user_code, client_id, scope, status,
+                     user_id, auth_time, amr, acr, sid, poll_interval,
+                     last_polled_at, created_at, expires_at`
+
+// Create stores a new pending device authorization request.
+func (r *deviceRepository) Create(
+	ctx context.Context,
+	d *models.DeviceAuthorization,
+) error {
+	query := `INSERT INTO device_codes
+                  (device_code, user_code, client_id, scope, poll_interval,
+                  expires_at)
+              VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, d.DeviceCode, d.UserCode,
+		d.ClientId, d.Scope, d.Interval, d.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[Create]: %w", err)
+	}
+	return nil
+}
+
+// GetByUserCode returns the request the user code was issued for, or nil
+// when there is none.
+func (r *deviceRepository) GetByUserCode(
+	ctx context.Context,
+	userCode string,
+) (*models.DeviceAuthorization, error) {
+	var d models.DeviceAuthorization
+	query := `SELECT ` + deviceColumns + `
+              FROM device_codes WHERE user_code = ?`
+	err := r.db.GetContext(ctx, &d, query, userCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("[GetByUserCode]: %w", err)
+	}
+	return &d, nil
+}
+
+// GetByDeviceCode returns the request of a polling device, or nil when
+// there is none.
+func (r *deviceRepository) GetByDeviceCode(
+	ctx context.Context,
+	deviceCode string,
+) (*models.DeviceAuthorization, error) {
+	var d models.DeviceAuthorization
+	query := `SELECT ` + deviceColumns + `
+              FROM device_codes WHERE device_code = ?`
+	err := r.db.GetContext(ctx, &d, query, deviceCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("[GetByDeviceCode]: %w", err)
+	}
+	return &d, nil
+}
+
+// Answer records the user's decision on a pending, unexpired request. It
+// reports false when the request was already answered or has expired.
+func (r *deviceRepository) Answer(
+	ctx context.Context,
+	d *models.DeviceAuthorization,
+) (bool, error) {
+	query := `UPDATE device_codes
+              SET status = ?, user_id = ?, auth_time = ?, amr = ?, acr = ?,
+                  sid = ?
+              WHERE user_code = ? AND status = 'pending'
+                AND expires_at > NOW()`
+	res, err := r.db.ExecContext(ctx, query, d.Status, d.UserId,
+		d.AuthTime, d.AMR, d.ACR, d.Sid, d.UserCode)
+	if err != nil {
+		return false, fmt.Errorf("[Answer]: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("[Answer]: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RecordPoll stores when the device last polled and the interval it must
+// keep from now on.
+func (r *deviceRepository) RecordPoll(
+	ctx context.Context,
+	deviceCode string,
+	polledAt time.Time,
+	interval int,
+) error {
+	query := `UPDATE device_codes
+              SET last_polled_at = ?, poll_interval = ?
+              WHERE device_code = ?`
+	_, err := r.db.ExecContext(ctx, query, polledAt, interval, deviceCode)
+	if err != nil {
+		return fmt.Errorf("[RecordPoll]: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a redeemed, denied or expired request. It reports false
+// when the request was already removed, which lets exactly one of two
+// concurrent polls redeem an approval.
+func (r *deviceRepository) Delete(
+	ctx context.Context,
+	deviceCode string,
+) (bool, error) {
+	query := `DELETE FROM device_codes WHERE device_code = ?`
+	res, err := r.db.ExecContext(ctx, query, deviceCode)
+	if err != nil {
+		return false, fmt.Errorf("[Delete]: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("[Delete]: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func NewDeviceRepository(db *sqlx.DB) DeviceRepository {
+	return &deviceRepository{db: db}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type LockoutRepository interface {
+	GetFailure(ctx context.Context,
+		email string) (*models.LoginFailure, error)
+	RecordFailure(ctx context.Context, email string, failedAt time.Time,
+		windowStart time.Time,
+		expiresAt time.Time) (*models.LoginFailure, error)
+	Lock(ctx context.Context, email string, until time.Time) error
+	DeleteFailure(ctx context.Context, email string) error
+	GetPolicy(ctx context.Context) (*models.LockoutPolicy, error)
+	UpdatePolicy(ctx context.Context, p *models.LockoutPolicy) error
+}
+
+type lockoutRepository struct {
+	db *sqlx.DB
+}
+
+const loginFailureColumns = `email, failed_count, last_failed_at,
+                             locked_until, expires_at`
+
+// GetFailure returns the failure count of an account, or nil when it has
+// none.
+func (r *lockoutRepository) GetFailure(
+	ctx context.Context,
+	email string,
+) (*models.LoginFailure, error) {
+	var f models.LoginFailure
+	query := `SELECT ` + loginFailureColumns + `
+              FROM login_failures WHERE email = ?`
+	err := r.db.GetContext(ctx, &f, query, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("[GetFailure]: %w", err)
+	}
+	return &f, nil
+}
+
+// RecordFailure counts a failed attempt and returns the updated row. The
+// count starts over when the previous failure predates windowStart or a
+// lock has run out. The increment happens in the database so concurrent
+// attempts are all counted.
+func (r *lockoutRepository) RecordFailure(
+	ctx context.Context,
+	email string,
+	failedAt time.Time,
+	windowStart time.Time,
+	expiresAt time.Time,
+) (*models.LoginFailure, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[RecordFailure] Begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `INSERT INTO login_failures
+                  (email, failed_count, last_failed_at, expires_at)
+              VALUES (?, 1, ?, ?)
+              ON DUPLICATE KEY UPDATE
+                  failed_count = IF(last_failed_at < ?
+                      OR locked_until <= ?, 1, failed_count + 1),
+                  locked_until = IF(locked_until <= ?, NULL, locked_until),
+                  last_failed_at = VALUES(last_failed_at),
+                  expires_at = GREATEST(VALUES(expires_at),
+                      COALESCE(locked_until, VALUES(expires_at)))`
+	_, err = tx.ExecContext(ctx, query, email, failedAt, expiresAt,
+		windowStart, failedAt, failedAt)
+	if err != nil {
+		return nil, fmt.Errorf("[RecordFailure] Upsert: %w", err)
+	}
+
+	var f models.LoginFailure
+	query = `SELECT ` + loginFailureColumns + `
+             FROM login_failures WHERE email = ?`
+	if err := tx.GetContext(ctx, &f, query, email); err != nil {
+		return nil, fmt.Errorf("[RecordFailure] Select: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("[RecordFailure] Commit: %w", err)
+	}
+	return &f, nil
+}
+
+// Lock locks the account until the given time. The row is kept at least
+// as long as the lock.
+func (r *lockoutRepository) Lock(
+	ctx context.Context,
+	email string,
+	until time.Time,
+) error {
+	query := `UPDATE login_failures
+              SET locked_until = ?, expires_at = GREATEST(expires_at, ?)
+              WHERE email = ?`
+	_, err := r.db.ExecContext(ctx, query, until, until, email)
+	if err != nil {
+		return fmt.Errorf("[Lock]: %w", err)
+	}
+	return nil
+}
+
+// DeleteFailure clears the failure count and any lock of an account.
+func (r *lockoutRepository) DeleteFailure(
+	ctx context.Context,
+	email string,
+) error {
+	query := `DELETE FROM login_failures WHERE email = ?`
+	if _, err := r.db.ExecContext(ctx, query, email); err != nil {
+		return fmt.Errorf("[DeleteFailure]: %w", err)
+	}
+	return nil
+}
+
+// GetPolicy returns the account lockout policy.
+func (r *lockoutRepository) GetPolicy(
+	ctx context.Context,
+) (*models.LockoutPolicy, error) {
+	var p models.LockoutPolicy
+	query := `SELECT max_failures, lockout_minutes, backoff_base_seconds,
+                     backoff_max_seconds, failure_window_minutes, updated_at
+              FROM lockout_policy WHERE id = 1`
+	if err := r.db.GetContext(ctx, &p, query); err != nil {
+		return nil, fmt.Errorf("[GetPolicy]: %w", err)
+	}
+	return &p, nil
+}
+
+// UpdatePolicy replaces the account lockout policy.
+func (r *lockoutRepository) UpdatePolicy(
+	ctx context.Context,
+	p *models.LockoutPolicy,
+) error {
+	query := `UPDATE lockout_policy
+              SET max_failures = ?, lockout_minutes = ?,
+                  backoff_base_seconds = ?, backoff_max_seconds = ?,
+                  failure_window_minutes = ?
+              WHERE id = 1`
+	_, err := r.db.ExecContext(ctx, query, p.MaxFailures, p.LockoutMinutes,
+		p.BackoffBaseSeconds, p.BackoffMaxSeconds, p.FailureWindowMinutes)
+	if err != nil {
+		return fmt.Errorf("[UpdatePolicy]: %w", err)
+	}
+	return nil
+}
+
+func NewLockoutRepository(db *sqlx.DB) LockoutRepository {
+	return &lockoutRepository{db: db}
+}
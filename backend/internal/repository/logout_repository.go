@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type LogoutRepository interface {
+	Enqueue(ctx context.Context, n *models.LogoutNotification) error
+	ListDue(ctx context.Context,
+		limit int) ([]models.LogoutNotification, error)
+	Claim(ctx context.Context, id int64, until time.Time) (bool, error)
+	Reschedule(ctx context.Context, id int64, attempts int,
+		next time.Time) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type logoutRepository struct {
+	db *sqlx.DB
+}
+
+// Enqueue queues a back-channel logout request for immediate delivery.
+func (r *logoutRepository) Enqueue(
+	ctx context.Context,
+	n *models.LogoutNotification,
+) error {
+	query := `INSERT INTO logout_notifications
+                  (client_id, user_id, sid, expires_at)
+              VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, n.ClientId, n.UserId, n.Sid,
+		n.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[Enqueue]: %w", err)
+	}
+	return nil
+}
+
+// ListDue returns the notifications whose next attempt is due, oldest
+// first.
+func (r *logoutRepository) ListDue(
+	ctx context.Context,
+	limit int,
+) ([]models.LogoutNotification, error) {
+	notifications := []models.LogoutNotification{}
+	query := `SELECT id, client_id, user_id, sid, attempts,
+                     next_attempt_at, created_at, expires_at
+              FROM logout_notifications
+              WHERE next_attempt_at <= NOW() AND expires_at > NOW()
+              ORDER BY next_attempt_at
+              LIMIT ?`
+	err := r.db.SelectContext(ctx, &notifications, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[ListDue]: %w", err)
+	}
+	return notifications, nil
+}
+
+// Claim pushes a due notification's next attempt out to until, so other
+// instances skip it while it is being delivered. It reports false when
+// another instance claimed it first.
+func (r *logoutRepository) Claim(
+	ctx context.Context,
+	id int64,
+	until time.Time,
+) (bool, error) {
+	query := `UPDATE logout_notifications SET next_attempt_at = ?
+              WHERE id = ? AND next_attempt_at <= NOW()`
+	res, err := r.db.ExecContext(ctx, query, until, id)
+	if err != nil {
+		return false, fmt.Errorf("[Claim]: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("[Claim]: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Reschedule records a failed attempt and when to try again.
+func (r *logoutRepository) Reschedule(
+	ctx context.Context,
+	id int64,
+	attempts int,
+	next time.Time,
+) error {
+	query := `UPDATE logout_notifications
+              SET attempts = ?, next_attempt_at = ?
+              WHERE id = ?`
+	if _, err := r.db.ExecContext(ctx, query, attempts, next, id); err != nil {
+		return fmt.Errorf("[Reschedule]: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a delivered or abandoned notification.
+func (r *logoutRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM logout_notifications WHERE id = ?`
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("[Delete]: %w", err)
+	}
+	return nil
+}
+
+func NewLogoutRepository(db *sqlx.DB) LogoutRepository {
+	return &logoutRepository{db: db}
+}
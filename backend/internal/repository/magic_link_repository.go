@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type MagicLinkRepository interface {
+	CreateToken(ctx context.Context, token *models.MagicLinkToken) error
+	ConsumeToken(ctx context.Context,
+		tokenHash string) (*models.MagicLinkToken, error)
+}
+
+type magicLinkRepository struct {
+	db *sqlx.DB
+}
+
+// CreateToken stores the hash of a newly emailed login link.
+func (r *magicLinkRepository) CreateToken(ctx context.Context,
+	token *models.MagicLinkToken,
+) error {
+	query := `INSERT INTO magic_link_tokens
+                  (token_hash, email, client_id, redirect_uri,
+                   authorize_query, expires_at)
+              VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.Email,
+		token.ClientID, token.RedirectURI, token.AuthorizeQuery,
+		token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[CreateToken]: %w", err)
+	}
+	return nil
+}
+
+// ConsumeToken locks, deletes and returns the token with the given hash. It
+// returns nil when the token is unknown, already used or expired, so a link
+// signs in at most once even under concurrent requests.
+func (r *magicLinkRepository) ConsumeToken(ctx context.Context,
+	tokenHash string,
+) (*models.MagicLinkToken, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[ConsumeToken]: %w", err)
+	}
+	defer tx.Rollback()
+
+	var token models.MagicLinkToken
+	query := `SELECT token_hash, email, client_id, redirect_uri,
+                     authorize_query, created_at, expires_at
+              FROM magic_link_tokens WHERE token_hash = ? FOR UPDATE`
+	err = tx.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("[ConsumeToken]: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"DELETE FROM magic_link_tokens WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return nil, fmt.Errorf("[ConsumeToken]: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("[ConsumeToken]: %w", err)
+	}
+
+	// An expired link is deleted all the same; it can never be used.
+	if time.Now().After(token.ExpiresAt) {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func NewMagicLinkRepository(db *sqlx.DB) MagicLinkRepository {
+	return &magicLinkRepository{db: db}
+}
@@ -24,6 +24,12 @@ type MFARepository interface {
 	) error
 	// HasTOTP reports whether the user has at least one active TOTP.
 	HasTOTP(ctx context.Context, userID []byte) (bool, error)
+	GetSecretsToRewrap(
+		ctx context.Context, keyVersion int, afterID []byte, limit int,
+	) ([]models.UserAuthenticator, error)
+	UpdateSecret(
+		ctx context.Context, id []byte, secret []byte, keyVersion int,
+	) error
 }
 
 type mfaRepository struct {
@@ -34,11 +40,12 @@ func (r *mfaRepository) InsertAuthenticator(ctx context.Context,
 	auth *models.UserAuthenticator,
 ) error {
 	query := `INSERT INTO user_authenticators (id, user_id, type, name, 
-              secret_encrypted, credential_id, public_key, sign_count) 
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+              secret_encrypted, key_version, credential_id, public_key,
+              sign_count) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	_, err := r.db.ExecContext(ctx, query, auth.ID, auth.UserID, auth.Type,
-		auth.Name, auth.SecretEncrypted, auth.CredentialID, auth.PublicKey,
-		auth.SignCount)
+		auth.Name, auth.SecretEncrypted, auth.KeyVersion, auth.CredentialID,
+		auth.PublicKey, auth.SignCount)
 	if err != nil {
 		return fmt.Errorf("[InsertAuthenticator]: %w", err)
 	}
@@ -50,7 +57,8 @@ func (r *mfaRepository) GetAuthenticatorsByUserIDAndType(ctx context.Context,
 ) ([]models.UserAuthenticator, error) {
 	var auths []models.UserAuthenticator
 	query := `SELECT id, user_id, type, name, created_at, last_used_at, 
-              secret_encrypted, credential_id, public_key, sign_count 
+              secret_encrypted, key_version, credential_id, public_key,
+              sign_count 
               FROM user_authenticators WHERE user_id = ? AND type = ?`
 	err := r.db.SelectContext(ctx, &auths, query, userID, authType)
 	if err != nil {
@@ -109,6 +117,37 @@ func (r *mfaRepository) HasTOTP(
 	return count > 0, nil
 }
 
+// GetSecretsToRewrap pages through the TOTP secrets that are not yet
+// encrypted under keyVersion, ordered by id and starting after afterID.
+func (r *mfaRepository) GetSecretsToRewrap(ctx context.Context,
+	keyVersion int, afterID []byte, limit int,
+) ([]models.UserAuthenticator, error) {
+	var auths []models.UserAuthenticator
+	query := `SELECT id, secret_encrypted, key_version
+              FROM user_authenticators
+              WHERE type = 'totp' AND id > ?
+              AND (key_version IS NULL OR key_version <> ?)
+              ORDER BY id LIMIT ?`
+	err := r.db.SelectContext(ctx, &auths, query, afterID, keyVersion, limit)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSecretsToRewrap]: %w", err)
+	}
+	return auths, nil
+}
+
+// UpdateSecret stores a re-encrypted secret with its key version.
+func (r *mfaRepository) UpdateSecret(ctx context.Context,
+	id []byte, secret []byte, keyVersion int,
+) error {
+	query := `UPDATE user_authenticators
+              SET secret_encrypted = ?, key_version = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, secret, keyVersion, id)
+	if err != nil {
+		return fmt.Errorf("[UpdateSecret]: %w", err)
+	}
+	return nil
+}
+
 func NewMFARepository(db *sqlx.DB) MFARepository {
 	return &mfaRepository{db: db}
 }
@@ -15,6 +15,10 @@ type OTPRepository interface {
 	IncrementAttempts(ctx context.Context, code string) error
 	MarkAsUsed(ctx context.Context, code string) error
 	DeleteExpiredOTPs(ctx context.Context) error
+	CreateResetToken(ctx context.Context,
+		token *models.PasswordResetToken) error
+	ConsumeResetToken(ctx context.Context, tokenHash, email,
+		purpose string) (bool, error)
 }
 
 type otpRepository struct {
@@ -84,6 +88,41 @@ func (r *otpRepository) DeleteExpiredOTPs(ctx context.Context) error {
 	return err
 }
 
+// CreateResetToken stores the hash of a newly minted reset token.
+func (r *otpRepository) CreateResetToken(ctx context.Context,
+	token *models.PasswordResetToken,
+) error {
+	query := `INSERT INTO password_reset_tokens
+                  (token_hash, email, purpose, expires_at)
+              VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.Email,
+		token.Purpose, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[CreateResetToken]: %w", err)
+	}
+	return nil
+}
+
+// ConsumeResetToken deletes an unexpired reset token issued for the email
+// and purpose. It reports false when no such token exists, so a token is
+// redeemed at most once even under concurrent requests.
+func (r *otpRepository) ConsumeResetToken(ctx context.Context,
+	tokenHash, email, purpose string,
+) (bool, error) {
+	query := `DELETE FROM password_reset_tokens
+              WHERE token_hash = ? AND email = ? AND purpose = ?
+                AND expires_at > NOW()`
+	res, err := r.db.ExecContext(ctx, query, tokenHash, email, purpose)
+	if err != nil {
+		return false, fmt.Errorf("[ConsumeResetToken]: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("[ConsumeResetToken]: %w", err)
+	}
+	return rows > 0, nil
+}
+
 func NewOTPRepository(db *sqlx.DB) OTPRepository {
 	return &otpRepository{db: db}
 }
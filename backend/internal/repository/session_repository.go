@@ -12,8 +12,18 @@ import (
 type SessionRepository interface {
 	Create(ctx context.Context, s *models.IdPSession) error
 	GetByID(ctx context.Context, sessionID string) (*models.IdPSession, error)
+	UpdateAuthContext(ctx context.Context, sessionID string, amr string,
+		mfaTime time.Time) error
 	Delete(ctx context.Context, sessionID string) error
+	DeleteByUser(ctx context.Context, userID []byte) error
 	DeleteExpired(ctx context.Context) (int64, error)
+	AddClient(ctx context.Context, sc *models.SessionClient) error
+	ListClients(ctx context.Context,
+		sid string) ([]models.SessionClient, error)
+	ListClientsByUser(ctx context.Context,
+		userID []byte) ([]models.SessionClient, error)
+	DeleteClients(ctx context.Context, sid string) error
+	RevokeRefreshTokens(ctx context.Context, sid string) error
 }
 
 type sessionRepository struct {
@@ -25,11 +35,11 @@ func (r *sessionRepository) Create(ctx context.Context,
 ) error {
 	query := `
         INSERT INTO idp_sessions (session_id, user_id, ip_address,
-		user_agent, expires_at)
-        VALUES (?, ?, ?, ?, ?)
+		user_agent, expires_at, amr, auth_time, mfa_time)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
     `
 	_, err := r.db.ExecContext(ctx, query, s.SessionId, s.UserId, s.IpAddress,
-		s.UserAgent, s.ExpiresAt)
+		s.UserAgent, s.ExpiresAt, s.AMR, s.AuthTime, s.MFATime)
 	if err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
@@ -41,7 +51,7 @@ func (r *sessionRepository) GetByID(ctx context.Context,
 ) (*models.IdPSession, error) {
 	var session models.IdPSession
 	query := `SELECT session_id, user_id, ip_address, user_agent,
-			  created_at, expires_at 
+			  created_at, expires_at, amr, auth_time, mfa_time
               FROM idp_sessions WHERE session_id = ?`
 
 	err := r.db.GetContext(ctx, &session, query, sessionID)
@@ -51,6 +61,20 @@ func (r *sessionRepository) GetByID(ctx context.Context,
 	return &session, nil
 }
 
+// UpdateAuthContext records a step-up: the session's authentication
+// methods and the time the second factor was verified.
+func (r *sessionRepository) UpdateAuthContext(ctx context.Context,
+	sessionID string, amr string, mfaTime time.Time,
+) error {
+	query := `UPDATE idp_sessions SET amr = ?, mfa_time = ?
+              WHERE session_id = ?`
+	_, err := r.db.ExecContext(ctx, query, amr, mfaTime, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
 func (r *sessionRepository) Delete(ctx context.Context,
 	sessionID string,
 ) error {
@@ -62,6 +86,30 @@ func (r *sessionRepository) Delete(ctx context.Context,
 	return nil
 }
 
+// DeleteByUser ends every session of the user and forgets the clients
+// they signed in to.
+func (r *sessionRepository) DeleteByUser(ctx context.Context,
+	userID []byte,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("[DeleteByUser]: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM idp_sessions WHERE user_id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("[DeleteByUser]: %w", err)
+	}
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM idp_session_clients WHERE user_id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("[DeleteByUser]: %w", err)
+	}
+	return tx.Commit()
+}
+
 func (r *sessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
 	query := `DELETE FROM idp_sessions WHERE expires_at < ?`
 	res, err := r.db.ExecContext(ctx, query, time.Now())
@@ -71,6 +119,77 @@ func (r *sessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
 	return res.RowsAffected()
 }
 
+// AddClient records that the session signed in to a client. Signing in
+// again extends the record to the new session expiry.
+func (r *sessionRepository) AddClient(ctx context.Context,
+	sc *models.SessionClient,
+) error {
+	query := `INSERT INTO idp_session_clients
+                  (sid, client_id, user_id, expires_at)
+              VALUES (?, ?, ?, ?)
+              ON DUPLICATE KEY UPDATE expires_at = VALUES(expires_at)`
+	_, err := r.db.ExecContext(ctx, query, sc.Sid, sc.ClientId,
+		sc.UserId, sc.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("[AddClient]: %w", err)
+	}
+	return nil
+}
+
+// ListClients returns the clients the session signed in to.
+func (r *sessionRepository) ListClients(ctx context.Context,
+	sid string,
+) ([]models.SessionClient, error) {
+	clients := []models.SessionClient{}
+	query := `SELECT sid, client_id, user_id, created_at, expires_at
+              FROM idp_session_clients WHERE sid = ?`
+	err := r.db.SelectContext(ctx, &clients, query, sid)
+	if err != nil {
+		return nil, fmt.Errorf("[ListClients]: %w", err)
+	}
+	return clients, nil
+}
+
+// ListClientsByUser returns the clients every session of the user signed
+// in to.
+func (r *sessionRepository) ListClientsByUser(ctx context.Context,
+	userID []byte,
+) ([]models.SessionClient, error) {
+	clients := []models.SessionClient{}
+	query := `SELECT sid, client_id, user_id, created_at, expires_at
+              FROM idp_session_clients WHERE user_id = ?`
+	err := r.db.SelectContext(ctx, &clients, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("[ListClientsByUser]: %w", err)
+	}
+	return clients, nil
+}
+
+// DeleteClients forgets the clients of an ended session.
+func (r *sessionRepository) DeleteClients(ctx context.Context,
+	sid string,
+) error {
+	query := `DELETE FROM idp_session_clients WHERE sid = ?`
+	if _, err := r.db.ExecContext(ctx, query, sid); err != nil {
+		return fmt.Errorf("[DeleteClients]: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshTokens revokes the refresh tokens issued under a session.
+func (r *sessionRepository) RevokeRefreshTokens(ctx context.Context,
+	sid string,
+) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE sid = ? AND revoked_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, sid); err != nil {
+		return fmt.Errorf("[RevokeRefreshTokens]: %w", err)
+	}
+	return nil
+}
+
 func NewSessionRepository(db *sqlx.DB) SessionRepository {
 	return &sessionRepository{db: db}
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type SigningKeyRepository interface {
+	GetSigningKeys(ctx context.Context) ([]models.SigningKey, error)
+	CreateInitialSigningKey(ctx context.Context,
+		key *models.SigningKey) error
+	RotateSigningKey(ctx context.Context, currentKeyID string,
+		next *models.SigningKey, retiredUntil time.Time) (bool, error)
+	UpdateSigningKeyPrivateKey(ctx context.Context,
+		keyID, privateKey string) error
+}
+
+type signingKeyRepository struct {
+	db *sqlx.DB
+}
+
+// GetSigningKeys returns the active key and every retired key that is
+// still needed for verification, newest first.
+func (r *signingKeyRepository) GetSigningKeys(
+	ctx context.Context,
+) ([]models.SigningKey, error) {
+	var keys []models.SigningKey
+	query := `SELECT kid, algorithm, private_key, public_key, active,
+              created_at, retired_at, expires_at
+              FROM signing_keys
+              WHERE expires_at IS NULL OR expires_at > NOW()
+              ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &keys, query)
+	if err != nil {
+		return nil, fmt.Errorf("[GetSigningKeys]: %w", err)
+	}
+	return keys, nil
+}
+
+// CreateInitialSigningKey stores the key as the active key of its
+// algorithm unless another instance already registered one.
+func (r *signingKeyRepository) CreateInitialSigningKey(
+	ctx context.Context,
+	key *models.SigningKey,
+) error {
+	query := `INSERT INTO signing_keys
+              (kid, algorithm, private_key, public_key, active)
+              SELECT ?, ?, ?, ?, TRUE FROM DUAL
+              WHERE NOT EXISTS (
+                  SELECT 1 FROM signing_keys
+                  WHERE active = TRUE AND algorithm = ?
+              )`
+	_, err := r.db.ExecContext(ctx, query, key.KeyID, key.Algorithm,
+		key.PrivateKey, key.PublicKey, key.Algorithm)
+	if err != nil {
+		return fmt.Errorf("[CreateInitialSigningKey]: %w", err)
+	}
+	return nil
+}
+
+// RotateSigningKey retires the current key, keeping it for verification
+// until retiredUntil, and activates the next key. It reports false when
+// the current key was already rotated by another instance.
+func (r *signingKeyRepository) RotateSigningKey(
+	ctx context.Context,
+	currentKeyID string,
+	next *models.SigningKey,
+	retiredUntil time.Time,
+) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("[RotateSigningKey]: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE signing_keys
+         SET active = FALSE, retired_at = NOW(), expires_at = ?
+         WHERE kid = ? AND active = TRUE`,
+		retiredUntil, currentKeyID)
+	if err != nil {
+		return false, fmt.Errorf("[RotateSigningKey] retire: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO signing_keys
+         (kid, algorithm, private_key, public_key, active)
+         VALUES (?, ?, ?, ?, TRUE)`,
+		next.KeyID, next.Algorithm, next.PrivateKey, next.PublicKey)
+	if err != nil {
+		return false, fmt.Errorf("[RotateSigningKey] insert: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("[RotateSigningKey] commit: %w", err)
+	}
+	return true, nil
+}
+
+// UpdateSigningKeyPrivateKey replaces the stored private key, e.g. after
+// it was re-encrypted under a new encryption key version.
+func (r *signingKeyRepository) UpdateSigningKeyPrivateKey(
+	ctx context.Context,
+	keyID, privateKey string,
+) error {
+	query := `UPDATE signing_keys SET private_key = ? WHERE kid = ?`
+	_, err := r.db.ExecContext(ctx, query, privateKey, keyID)
+	if err != nil {
+		return fmt.Errorf("[UpdateSigningKeyPrivateKey]: %w", err)
+	}
+	return nil
+}
+
+func NewSigningKeyRepository(db *sqlx.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
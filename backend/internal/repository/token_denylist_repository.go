@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TokenDenylistRepository interface {
+	RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error
+	IsJTIRevoked(ctx context.Context, jti string) (bool, error)
+	SetWatermark(ctx context.Context, userID string, notBefore int64,
+		expiresAt time.Time) error
+	GetWatermark(ctx context.Context, userID string) (int64, bool, error)
+}
+
+type tokenDenylistRepository struct {
+	db *sqlx.DB
+}
+
+// RevokeJTI records a revoked access token until it expires.
+func (r *tokenDenylistRepository) RevokeJTI(
+	ctx context.Context,
+	jti string,
+	expiresAt time.Time,
+) error {
+	query := `INSERT INTO revoked_access_tokens (jti, expires_at)
+              VALUES (?, ?)
+              ON DUPLICATE KEY UPDATE expires_at = VALUES(expires_at)`
+	if _, err := r.db.ExecContext(ctx, query, jti, expiresAt); err != nil {
+		return fmt.Errorf("[RevokeJTI]: %w", err)
+	}
+	return nil
+}
+
+// IsJTIRevoked reports whether an unexpired revocation exists for jti.
+func (r *tokenDenylistRepository) IsJTIRevoked(
+	ctx context.Context,
+	jti string,
+) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM revoked_access_tokens
+              WHERE jti = ? AND expires_at > NOW()`
+	if err := r.db.GetContext(ctx, &count, query, jti); err != nil {
+		return false, fmt.Errorf("[IsJTIRevoked]: %w", err)
+	}
+	return count > 0, nil
+}
+
+// SetWatermark moves the user's "tokens issued before" watermark.
+func (r *tokenDenylistRepository) SetWatermark(
+	ctx context.Context,
+	userID string,
+	notBefore int64,
+	expiresAt time.Time,
+) error {
+	query := `INSERT INTO access_token_watermarks
+                  (user_id, not_before, expires_at)
+              VALUES (?, ?, ?)
+              ON DUPLICATE KEY UPDATE
+                  not_before = GREATEST(not_before, VALUES(not_before)),
+                  expires_at = VALUES(expires_at)`
+	_, err := r.db.ExecContext(ctx, query, userID, notBefore, expiresAt)
+	if err != nil {
+		return fmt.Errorf("[SetWatermark]: %w", err)
+	}
+	return nil
+}
+
+// GetWatermark returns the user's watermark, or false when none is set.
+func (r *tokenDenylistRepository) GetWatermark(
+	ctx context.Context,
+	userID string,
+) (int64, bool, error) {
+	var notBefore int64
+	query := `SELECT not_before FROM access_token_watermarks
+              WHERE user_id = ? AND expires_at > NOW()`
+	err := r.db.GetContext(ctx, &notBefore, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("[GetWatermark]: %w", err)
+	}
+	return notBefore, true, nil
+}
+
+func NewTokenDenylistRepository(db *sqlx.DB) TokenDenylistRepository {
+	return &tokenDenylistRepository{db: db}
+}
@@ -3,12 +3,12 @@ package service
 import (
 	"bytes"
 	"context"
-	"crypto/rsa"
+	"database/sql"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
@@ -20,30 +20,47 @@ import (
 )
 
 type AuthService interface {
-	Authorize(ctx context.Context, clientIDStr string,
+	Authorize(ctx context.Context, req dto.AuthorizeRequest,
 		sessionToken string) (string, error)
 	LoginAndAuthorize(ctx context.Context, req dto.LoginRequest,
 		ipAddress, userAgent string) (string, string, error)
 	Logout(ctx context.Context, sessionID string) error
 	ValidateSession(ctx context.Context,
 		sessionID string) (*models.IdPSession, error)
+	PushAuthorizationRequest(ctx context.Context,
+		req dto.PushedAuthorizationRequest,
+	) (*dto.PushedAuthorizationResponse, error)
+	ResolveAuthorizeRequest(ctx context.Context, req dto.AuthorizeRequest,
+		binding string) (dto.AuthorizeRequest, error)
+	ValidateRedirectURI(ctx context.Context, clientID string,
+		redirectURI string) (string, error)
 	GetJWKS(ctx context.Context) (*JWKS, error)
+	GetOpenIDConfiguration(
+		ctx context.Context) (*dto.OpenIDConfiguration, error)
 	ExchangeCodeForToken(ctx context.Context,
 		req dto.TokenExchangeRequest) (*dto.TokenResponse, error)
+	IssueClientCredentialsToken(ctx context.Context,
+		req dto.TokenExchangeRequest) (*dto.TokenResponse, error)
+	IntrospectToken(ctx context.Context,
+		req dto.IntrospectionRequest) (*dto.IntrospectionResponse, error)
+	RevokeToken(ctx context.Context, req dto.RevocationRequest) error
 	RotateRefreshToken(ctx context.Context,
 		oldToken string) (*dto.TokenResponse, error)
 	GetSessionToken(ctx context.Context, userID uuid.UUID,
-		ipAddress, userAgent string) (string, error)
+		ipAddress, userAgent, firstFactor, method string) (string, error)
 	RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error
 	RefreshBySession(ctx context.Context, sessionID string,
 		clientID string) (*dto.TokenResponse, error)
 	RevokeCookies(c *gin.Context)
 	GenerateMFAPendingToken(userID string, email string,
-		ip string, ua string) (string, error)
+		ip string, ua string, firstFactor string) (string, error)
 	ValidateMFAPendingToken(
 		tokenStr string) (*MFAPendingClaims, error)
 	CreateSessionAndSetCookie(
+		c *gin.Context, userID uuid.UUID, method string) error
+	CreatePasskeySessionAndSetCookie(
 		c *gin.Context, userID uuid.UUID) error
+	RecordSecondFactor(c *gin.Context, method string) error
 	CheckSessionOrPendingMFA(
 		c *gin.Context,
 	) (uuid.UUID, bool, func(), error)
@@ -53,37 +70,51 @@ type authService struct {
 	Repo        repository.AuthCodeRepository
 	SessionRepo repository.SessionRepository
 	ClientRepo  repository.ClientRepository
-	PrivateKey  *rsa.PrivateKey
-	PublicKey   *rsa.PublicKey
+	ConsentRepo repository.ConsentRepository
+	Keys        KeyStore
+	Denylist    TokenDenylist
 }
 
 func NewAuthService(repo repository.AuthCodeRepository,
 	sessionRepo repository.SessionRepository,
 	clientRepo repository.ClientRepository,
-	privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey,
+	consentRepo repository.ConsentRepository,
+	keys KeyStore,
+	denylist TokenDenylist,
 ) AuthService {
 	return &authService{
 		Repo:        repo,
 		SessionRepo: sessionRepo,
 		ClientRepo:  clientRepo,
-		PrivateKey:  privateKey,
-		PublicKey:   publicKey,
+		ConsentRepo: consentRepo,
+		Keys:        keys,
+		Denylist:    denylist,
 	}
 }
 
 /**
  * Authorize validates the user's session and generates an
- * authorization code for the requesting client.
+ * authorization code for the requesting client, binding any PKCE
+ * code challenge and the OIDC scope, nonce and authentication context
+ * to the issued code. The user must be allowed to use the client, and
+ * clients requiring consent only get a code once the user's stored
+ * consent covers the requested scopes. prompt=login, select_account and
+ * an exceeded max_age require a fresh login first. Clients requiring PAR
+ * must have pushed the request, which a request_uri shows.
  */
 func (s *authService) Authorize(
 	ctx context.Context,
-	clientIDStr string,
+	req dto.AuthorizeRequest,
 	sessionToken string,
 ) (string, error) {
-	clientID, err := uuid.Parse(clientIDStr)
+	clientID, err := uuid.Parse(req.ClientID)
 	if err != nil {
 		return "", fmt.Errorf("uuid parse: %w", err)
 	}
+	prompts, err := ParsePrompt(req.Prompt)
+	if err != nil {
+		return "", err
+	}
 
 	// 1. Session Validation
 	session, err := s.SessionRepo.GetByID(ctx, sessionToken)
@@ -99,25 +130,161 @@ func (s *authService) Authorize(
 		return "", fmt.Errorf("expired session")
 	}
 
+	// 1.1 Re-authentication
+	reauth, err := reauthenticationRequired(
+		session,
+		prompts,
+		req.MaxAge,
+		req.AuthAfter,
+	)
+	if err != nil {
+		return "", err
+	}
+	if reauth {
+		return "", fmt.Errorf("login required: re-authentication requested")
+	}
+
 	// 2. Client Verification
 	client, err := s.ClientRepo.GetByID(ctx, clientID[:])
 	if err != nil {
 		return "", fmt.Errorf("database query (GetClient): %w", err)
 	}
 
-	// 3. Code Generation
+	redirectURI, err := resolveRedirectURI(client, req.RedirectURI)
+	if err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(client.Grants, string(models.GrantAuthCode)) {
+		return "", fmt.Errorf(
+			"unauthorized client: authorization_code grant not allowed",
+		)
+	}
+	if client.RequirePAR && req.RequestURI == "" {
+		return "", fmt.Errorf(
+			"invalid request: pushed authorization request required",
+		)
+	}
+
+	// 2.1 User Access
+	err = s.checkUserAccess(ctx, session.UserId, req.ClientID, clientID[:])
+	if err != nil {
+		return "", err
+	}
+
+	// 2.2 Authentication Context
+	// A session below the required class must verify a second factor.
+	amr, acr, authTime := sessionAuthContext(session)
+	required := requiredACR(client.MinACR, req.ACRValues)
+	if !acrSatisfies(acr, required) {
+		return "", fmt.Errorf("step-up required: acr %s", required)
+	}
+
+	// 3. PKCE Validation
+	method, err := validateCodeChallenge(
+		client,
+		req.CodeChallenge,
+		req.CodeChallengeMethod,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	// 4. Consent for clients that ask the user first
+	// prompt=consent shows the consent screen even when consent was given.
+	scope := NormalizeScope(req.Scope)
+	if slices.Contains(prompts, models.PromptConsent) {
+		return "", fmt.Errorf("consent required: prompt=consent")
+	}
+	if client.RequireConsent {
+		consent, err := s.ConsentRepo.GetConsent(
+			ctx,
+			session.UserId,
+			clientID[:],
+		)
+		if err != nil {
+			return "", fmt.Errorf("database query (GetConsent): %w", err)
+		}
+		if !consentCovers(consent, scope) {
+			return "", fmt.Errorf("consent required")
+		}
+	}
+
+	// 5. Code Generation
 	code, err := utils.GenerateAuthorizationCode()
 	if err != nil {
 		return "", fmt.Errorf("code generation: %w", err)
 	}
-
-	userID := session.UserId
-	err = s.Repo.StoreCode(ctx, code, userID[:], clientID[:], client.RedirectUri)
+	sid := SessionSID(sessionToken)
+
+	err = s.Repo.StoreCode(ctx, &models.AuthorizationCode{
+		Code:                code,
+		UserId:              session.UserId,
+		ClientId:            clientID[:],
+		RedirectURI:         req.RedirectURI,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: method,
+		Scope:               scope,
+		Nonce:               req.Nonce,
+		AuthTime: sql.NullTime{
+			Time:  authTime,
+			Valid: true,
+		},
+		AMR: strings.Join(amr, " "),
+		ACR: acr,
+		Sid: sid,
+	})
 	if err != nil {
 		return "", fmt.Errorf("code storage: %w", err)
 	}
 
-	return fmt.Sprintf("%s?code=%s", client.RedirectUri, code), nil
+	// 6. Session Clients
+	// Logging out of the session notifies every client it signed in to.
+	err = s.SessionRepo.AddClient(ctx, &models.SessionClient{
+		Sid:       sid,
+		ClientId:  clientID[:],
+		UserId:    session.UserId,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("database query (AddClient): %w", err)
+	}
+
+	// 7. Pushed Request
+	// A request_uri is spent once a code has been issued for it.
+	if requestID, ok := strings.CutPrefix(
+		req.RequestURI,
+		RequestURIPrefix,
+	); ok {
+		_ = s.Repo.DeletePushedRequest(ctx, requestID)
+	}
+
+	return utils.AppendQuery(redirectURI, map[string]string{
+		"code":  code,
+		"state": req.State,
+	}), nil
+}
+
+/**
+ * ValidateRedirectURI resolves the redirect URI of an authorization
+ * request against the URIs registered for the client.
+ */
+func (s *authService) ValidateRedirectURI(
+	ctx context.Context,
+	clientID string,
+	redirectURI string,
+) (string, error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return "", fmt.Errorf("uuid parse: %w", err)
+	}
+
+	client, err := s.ClientRepo.GetByID(ctx, clientUUID[:])
+	if err != nil {
+		return "", fmt.Errorf("database query (GetClient): %w", err)
+	}
+
+	return resolveRedirectURI(client, redirectURI)
 }
 
 /**
@@ -151,35 +318,46 @@ func (s *authService) LoginAndAuthorize(
 
 	// 2. Client Validation
 	clientUUID, _ := uuid.Parse(req.ClientID)
-	regURI, err := s.Repo.GetClientRedirectURI(ctx, clientUUID[:])
+	_, err = s.Repo.GetClientRedirectURI(ctx, clientUUID[:])
 	if err != nil {
 		return "", "", fmt.Errorf("database query (ClientLookup): %w", err)
 	}
 
 	// 3. Generate MFA Pending Token
 	mfaPendingToken, err := GenerateMFAPendingToken(
-		s.PrivateKey,
+		s.Keys.ActiveKey(models.SigningAlgRS256),
 		claims.UserID,
 		req.Email,
 		ipAddress,
 		userAgent,
+		models.AMRPassword,
 	)
 	if err != nil {
 		return "", "", fmt.Errorf("mfa pending token generation: %w", err)
 	}
 
-	backendURL := os.Getenv("VITE_BACKEND_URL")
-	if backendURL == "" {
-		backendURL = "http://localhost:8080"
-	}
+	redirectURL := AuthorizeURL(req.ClientID, req.RedirectURI)
+	return redirectURL, mfaPendingToken, nil
+}
 
-	redirectURL := fmt.Sprintf(
-		"%s/api/v1/auth/authorize?client_id=%s&redirect_uri=%s",
-		backendURL,
-		req.ClientID,
-		url.QueryEscape(regURI),
+// AuthorizeURL is the authorize endpoint the login UI returns to once the
+// user signed in. The redirect URI is only forwarded when the login UI
+// supplied one; otherwise the pending authorize request or the client's
+// primary URI applies.
+func AuthorizeURL(clientID, redirectURI string) string {
+	return utils.AppendQuery(
+		BackendBaseURL()+"/api/v1/auth/authorize",
+		map[string]string{
+			"client_id":    clientID,
+			"redirect_uri": redirectURI,
+		},
 	)
-	return redirectURL, mfaPendingToken, nil
+}
+
+// AuthorizeQueryURL is the authorize endpoint for a whole remembered
+// authorize query.
+func AuthorizeQueryURL(query string) string {
+	return BackendBaseURL() + "/api/v1/auth/authorize?" + query
 }
 
 /**
@@ -202,6 +380,15 @@ func (s *authService) Logout(
 		return fmt.Errorf("database query (RevokeTokens): %w", err)
 	}
 
+	// 2.1 Reject access tokens that are still within their lifetime
+	userID, err := uuid.FromBytes(session.UserId)
+	if err != nil {
+		return fmt.Errorf("uuid parse: %w", err)
+	}
+	if err := s.Denylist.RevokeUserTokens(ctx, userID.String()); err != nil {
+		return err
+	}
+
 	// 3. Optional: Delete the session from DB
 	_ = s.SessionRepo.Delete(ctx, sessionID)
 
@@ -215,7 +402,10 @@ func (s *authService) RevokeAllUserTokens(
 	ctx context.Context,
 	userID uuid.UUID,
 ) error {
-	return s.Repo.RevokeTokens(ctx, userID[:])
+	if err := s.Repo.RevokeTokens(ctx, userID[:]); err != nil {
+		return err
+	}
+	return s.Denylist.RevokeUserTokens(ctx, userID.String())
 }
 
 /**
@@ -238,20 +428,33 @@ func (s *authService) ValidateSession(
 }
 
 /**
- * GetJWKS constructs the JSON Web Key Set.
+ * GetJWKS constructs the JSON Web Key Set from the active signing key and
+ * the retired keys that still verify outstanding tokens.
  */
 func (s *authService) GetJWKS(ctx context.Context) (*JWKS, error) {
-	keyID := os.Getenv("KEY_ID")
+	keys := s.Keys.Keys()
 
-	jwk := PublicKeyToJWK(s.PublicKey, keyID)
+	jwks := &JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		if jwk, ok := SigningKeyToJWK(key); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks, nil
+}
 
-	return &JWKS{
-		Keys: []JWK{jwk},
-	}, nil
+/**
+ * GetOpenIDConfiguration returns the OIDC discovery document.
+ */
+func (s *authService) GetOpenIDConfiguration(
+	ctx context.Context,
+) (*dto.OpenIDConfiguration, error) {
+	return BuildOpenIDConfiguration(), nil
 }
 
 /**
- * ExchangeCodeForToken validates the authorization code and client.
+ * ExchangeCodeForToken validates the authorization code and client and
+ * issues an ID token alongside the access token for "openid" requests.
  */
 func (s *authService) ExchangeCodeForToken(
 	ctx context.Context,
@@ -264,12 +467,17 @@ func (s *authService) ExchangeCodeForToken(
 	clientIDBin := clientUUID[:]
 
 	// 1. Authenticate Client
-	valid, err := s.Repo.VerifyClient(ctx, clientIDBin, req.ClientSecret)
+	// Confidential clients always present their secret, PKCE or not.
+	// Public clients have none and are bound to the code by PKCE below.
+	client, err := s.ClientRepo.GetByID(ctx, clientIDBin)
 	if err != nil {
 		return nil, fmt.Errorf("client verification: %w", err)
 	}
-	if !valid {
-		return nil, fmt.Errorf("client verification: invalid credentials")
+	if !client.PublicClient {
+		err = s.verifyClientSecret(ctx, req.ClientID, req.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// 2. Consume Authorization Code
@@ -280,22 +488,56 @@ func (s *authService) ExchangeCodeForToken(
 
 	// 3. Security Check: Client Mismatch
 	if !bytes.Equal(authCode.ClientId, clientIDBin) {
-		return nil, fmt.Errorf("client verification: id mismatch")
+		return nil, fmt.Errorf(
+			"grant validation: code was issued to another client",
+		)
 	}
 
-	// 4. Identity Retrieval
-	claims, err := s.Repo.GetClaimsByID(ctx, authCode.UserId)
+	// 3.1 Redirect URI Check
+	// A redirect_uri sent to /auth/authorize must be repeated verbatim.
+	if authCode.RedirectURI != "" && authCode.RedirectURI != req.RedirectURI {
+		return nil, fmt.Errorf("redirect validation: redirect_uri mismatch")
+	}
+
+	// 3.2 Proof Key Check
+	if authCode.CodeChallenge != "" {
+		if !utils.VerifyCodeChallenge(
+			req.CodeVerifier,
+			authCode.CodeChallenge,
+			authCode.CodeChallengeMethod,
+		) {
+			return nil, fmt.Errorf("pkce validation: code_verifier mismatch")
+		}
+	} else if client.PublicClient {
+		return nil, fmt.Errorf("pkce validation: code_verifier required")
+	}
+
+	// 3.3 User Access
+	// Access may have been withdrawn since the code was issued.
+	err = s.checkUserAccess(ctx, authCode.UserId, req.ClientID, clientIDBin)
 	if err != nil {
-		return nil, fmt.Errorf("database query (GetClaims): %w", err)
+		return nil, err
 	}
 
-	client, err := s.ClientRepo.GetByID(ctx, clientIDBin)
+	// 4. Identity Retrieval
+	claims, err := s.Repo.GetClaimsByID(ctx, authCode.UserId)
 	if err != nil {
-		return nil, fmt.Errorf("database query (GetClient): %w", err)
+		return nil, fmt.Errorf("database query (GetClaims): %w", err)
 	}
 
 	// 5. Token Generation
-	accessToken, err := GenerateToken(s.PrivateKey, client, *claims)
+	claims.Scope = authCode.Scope
+	claims.AMR = strings.Fields(authCode.AMR)
+	claims.ACR = authCode.ACR
+	claims.SessionID = authCode.Sid
+	if authCode.AuthTime.Valid {
+		claims.AuthTime = authCode.AuthTime.Time.Unix()
+	}
+	accessToken, err := GenerateToken(
+		s.Keys.ActiveKey(client.TokenSigningAlg),
+		client,
+		*claims,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("token generation: %w", err)
 	}
@@ -316,11 +558,16 @@ func (s *authService) ExchangeCodeForToken(
 			expiresAt := time.Now().Add(
 				time.Duration(refTTLHours) * time.Hour,
 			)
+			// Each code exchange starts a new refresh token family
+			familyID := uuid.New()
 			err = s.Repo.StoreRefreshToken(
 				ctx,
 				refreshStr,
 				authCode.UserId,
 				clientIDBin,
+				familyID[:],
+				authCode.Scope,
+				authCode.Sid,
 				expiresAt,
 			)
 			if err != nil {
@@ -329,6 +576,15 @@ func (s *authService) ExchangeCodeForToken(
 		}
 	}
 
+	// 6. ID Token Generation
+	var idToken string
+	if HasScope(authCode.Scope, models.ScopeOpenID) {
+		idToken, err = s.buildIDToken(ctx, client, authCode, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	expiresIn := client.AccessTokenTTL * 60
 	if expiresIn <= 0 {
 		expiresIn = ACCESS_TOKEN_EXPIRY
@@ -339,9 +595,126 @@ func (s *authService) ExchangeCodeForToken(
 		RefreshToken: refreshStr,
 		ExpiresIn:    expiresIn,
 		TokenType:    "Bearer",
+		Scope:        authCode.Scope,
+		IDToken:      idToken,
+	}, nil
+}
+
+/**
+ * IssueClientCredentialsToken authenticates a confidential client with its
+ * secret and mints a user-less access token (RFC 6749 section 4.4). The
+ * client is the subject and the granted scope is limited to the scopes
+ * registered on the client. No refresh token is issued.
+ */
+func (s *authService) IssueClientCredentialsToken(
+	ctx context.Context,
+	req dto.TokenExchangeRequest,
+) (*dto.TokenResponse, error) {
+	// 1. Authenticate Client
+	err := s.verifyClientSecret(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	clientUUID := uuid.MustParse(req.ClientID)
+	client, err := s.ClientRepo.GetByID(ctx, clientUUID[:])
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetClient): %w", err)
+	}
+	if !slices.Contains(client.Grants, string(models.GrantClientCredentials)) {
+		return nil, fmt.Errorf(
+			"unauthorized client: client_credentials grant not allowed",
+		)
+	}
+
+	// 2. Scope Restriction
+	scope, ok := GrantableScopes(req.Scope, client.AllowedScopes)
+	if !ok {
+		return nil, fmt.Errorf("scope validation: requested scope not allowed")
+	}
+
+	// 3. Token Generation
+	accessToken, err := GenerateToken(
+		s.Keys.ActiveKey(client.TokenSigningAlg),
+		client,
+		models.UserClaims{Scope: scope},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("token generation: %w", err)
+	}
+
+	expiresIn := client.AccessTokenTTL * 60
+	if expiresIn <= 0 {
+		expiresIn = ACCESS_TOKEN_EXPIRY
+	}
+
+	return &dto.TokenResponse{
+		AccessToken: accessToken,
+		ExpiresIn:   expiresIn,
+		TokenType:   "Bearer",
+		Scope:       scope,
 	}, nil
 }
 
+/**
+ * buildIDToken mints the ID token for a redeemed authorization code,
+ * releasing profile and email claims according to the granted scope.
+ */
+func (s *authService) buildIDToken(
+	ctx context.Context,
+	client *models.Client,
+	authCode *models.AuthorizationCode,
+	accessToken string,
+) (string, error) {
+	user, err := s.Repo.GetIdentityByID(ctx, authCode.UserId)
+	if err != nil {
+		return "", fmt.Errorf("database query (GetIdentity): %w", err)
+	}
+
+	userID, err := uuid.FromBytes(user.ID)
+	if err != nil {
+		return "", fmt.Errorf("uuid parse: %w", err)
+	}
+
+	claims := models.IDTokenClaims{
+		Nonce:     authCode.Nonce,
+		AMR:       strings.Fields(authCode.AMR),
+		ACR:       authCode.ACR,
+		SessionID: authCode.Sid,
+	}
+	claims.Subject = userID.String()
+	if authCode.AuthTime.Valid {
+		claims.AuthTime = authCode.AuthTime.Time.Unix()
+	}
+
+	if HasScope(authCode.Scope, models.ScopeProfile) {
+		claims.GivenName = user.FirstName
+		claims.MiddleName = user.MiddleName
+		claims.FamilyName = user.LastName
+		claims.Name = displayName(user)
+	}
+
+	if HasScope(authCode.Scope, models.ScopeEmail) {
+		// Accounts are activated through an emailed invitation, so the
+		// address of an active user has been verified.
+		verified := true
+		claims.Email = user.Email
+		claims.EmailVerified = &verified
+	}
+
+	idToken, err := GenerateIDToken(
+		s.Keys.ActiveKey(client.TokenSigningAlg),
+		client,
+		claims,
+		accessToken,
+	)
+	if err != nil {
+		return "", fmt.Errorf("token generation (IDToken): %w", err)
+	}
+
+	return idToken, nil
+}
+
 /**
  * RotateRefreshToken validates an existing refresh token and issues a new pair.
  */
@@ -350,12 +723,37 @@ func (s *authService) RotateRefreshToken(
 	oldToken string,
 ) (*dto.TokenResponse, error) {
 	// 1. Identify User and Client from the existing token
-	uID, cID, err := s.Repo.GetIDsFromToken(ctx, oldToken)
+	stored, err := s.Repo.GetRefreshToken(ctx, oldToken)
 	if err != nil {
 		return nil, fmt.Errorf("database query (TokenLookup): %w", err)
 	}
+	uID, cID := stored.UserId, stored.ClientId
+
+	// 1.1 Reuse Detection
+	// A rotated token is only presented again if it was copied, so the
+	// whole family is revoked. Tokens revoked by logout are just invalid.
+	if stored.Revoked {
+		if !stored.Rotated {
+			return nil, fmt.Errorf("token validation: refresh token revoked")
+		}
+
+		err = s.Repo.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"database query (RevokeRefreshTokenFamily): %w",
+				err,
+			)
+		}
+		s.notifyTokenReuse(ctx, stored)
+
+		userID, _ := uuid.FromBytes(uID)
+		return nil, fmt.Errorf(
+			"token reuse: revoked refresh token family of user %s",
+			userID,
+		)
+	}
 
-	// 1.1 Verify Client Grant Type
+	// 1.2 Verify Client Grant Type
 	grants, _ := s.ClientRepo.GetGrantTypes(ctx, cID)
 	if !slices.Contains(grants, "refresh_token") {
 		return nil, fmt.Errorf("client verification: missing refresh_token grant")
@@ -366,6 +764,17 @@ func (s *authService) RotateRefreshToken(
 		return nil, fmt.Errorf("database query (GetClient): %w", err)
 	}
 
+	// 1.3 User Access
+	// Access may have been withdrawn since the token was issued.
+	clientUUID, err := uuid.FromBytes(cID)
+	if err != nil {
+		return nil, fmt.Errorf("uuid parse: %w", err)
+	}
+	err = s.checkUserAccess(ctx, uID, clientUUID.String(), cID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 2. Generate and persist new Refresh Token
 	newToken, err := utils.GenerateRandomString(SECRET_ENTROPY)
 	if err != nil {
@@ -392,7 +801,12 @@ func (s *authService) RotateRefreshToken(
 	}
 
 	// 4. Mint new Access Token
-	accessToken, err := GenerateToken(s.PrivateKey, client, *claims)
+	claims.Scope = stored.Scope
+	accessToken, err := GenerateToken(
+		s.Keys.ActiveKey(client.TokenSigningAlg),
+		client,
+		*claims,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("token generation (JWT): %w", err)
 	}
@@ -407,9 +821,36 @@ func (s *authService) RotateRefreshToken(
 		RefreshToken: newToken,
 		ExpiresIn:    expiresIn,
 		TokenType:    "Bearer",
+		Scope:        stored.Scope,
 	}, nil
 }
 
+/**
+ * notifyTokenReuse emails the user about a replayed refresh token when
+ * NOTIFY_REFRESH_TOKEN_REUSE is enabled. Delivery failures are ignored so
+ * they never mask the reuse error itself.
+ */
+func (s *authService) notifyTokenReuse(
+	ctx context.Context,
+	stored *models.RefreshToken,
+) {
+	if os.Getenv("NOTIFY_REFRESH_TOKEN_REUSE") != "true" {
+		return
+	}
+
+	user, err := s.Repo.GetIdentityByID(ctx, stored.UserId)
+	if err != nil || user.Email == "" {
+		return
+	}
+
+	clientName := "an application"
+	if client, err := s.ClientRepo.GetByID(ctx, stored.ClientId); err == nil {
+		clientName = client.ClientName
+	}
+
+	_ = utils.SendTokenReuseAlertEmail(user.Email, clientName)
+}
+
 /**
  * RefreshBySession issues a new access token based on a valid session ID.
  */
@@ -439,6 +880,11 @@ func (s *authService) RefreshBySession(
 		return nil, fmt.Errorf("database query (GetClient): %w", err)
 	}
 
+	err = s.checkUserAccess(ctx, session.UserId, clientIDStr, cUUID[:])
+	if err != nil {
+		return nil, err
+	}
+
 	// 3. Retrieve Identity
 	claims, err := s.Repo.GetClaimsByID(ctx, session.UserId)
 	if err != nil {
@@ -446,7 +892,14 @@ func (s *authService) RefreshBySession(
 	}
 
 	// 4. Mint new Access Token
-	accessToken, err := GenerateToken(s.PrivateKey, client, *claims)
+	amr, acr, authTime := sessionAuthContext(session)
+	claims.AMR, claims.ACR, claims.AuthTime = amr, acr, authTime.Unix()
+	claims.SessionID = SessionSID(sessionID)
+	accessToken, err := GenerateToken(
+		s.Keys.ActiveKey(client.TokenSigningAlg),
+		client,
+		*claims,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("token generation (JWT): %w", err)
 	}
@@ -458,11 +911,41 @@ func (s *authService) RefreshBySession(
 	}, nil
 }
 
+/**
+ * GetSessionToken creates a session for a user who signed in with the
+ * given first factor, the password unless named otherwise, and second
+ * factor method. An email OTP proves the same inbox as a magic link and
+ * is refused as the second factor after one.
+ */
 func (s *authService) GetSessionToken(ctx context.Context,
-	userID uuid.UUID, ipAddress, userAgent string,
+	userID uuid.UUID, ipAddress, userAgent, firstFactor, method string,
+) (string, error) {
+	if firstFactor == "" {
+		firstFactor = models.AMRPassword
+	}
+	if firstFactor == models.AMREmailLink && method == models.AMROTP {
+		return "", fmt.Errorf(
+			"second factor: email otp after an email link",
+		)
+	}
+	return s.createSession(
+		ctx,
+		userID,
+		ipAddress,
+		userAgent,
+		firstFactor+" "+method,
+		true,
+	)
+}
+
+// createSession stores a session authenticated with the given methods.
+// A multi-factor session also records the time of its second factor.
+func (s *authService) createSession(ctx context.Context,
+	userID uuid.UUID, ipAddress, userAgent, amr string, multiFactor bool,
 ) (string, error) {
 	sessionID, _ := utils.GenerateRandomString(32)
-	expiry := time.Now().AddDate(
+	now := time.Now()
+	expiry := now.AddDate(
 		SESSION_YEARS,
 		SESSION_MONTHS,
 		SESSION_DAYS,
@@ -474,6 +957,9 @@ func (s *authService) GetSessionToken(ctx context.Context,
 		IpAddress: ipAddress,
 		UserAgent: userAgent,
 		ExpiresAt: expiry,
+		AMR:       amr,
+		AuthTime:  sql.NullTime{Time: now, Valid: true},
+		MFATime:   sql.NullTime{Time: now, Valid: multiFactor},
 	}
 
 	if err := s.SessionRepo.Create(ctx, session); err != nil {
@@ -510,30 +996,83 @@ func (s *authService) GenerateMFAPendingToken(
 	email string,
 	ip string,
 	ua string,
+	firstFactor string,
 ) (string, error) {
-	return GenerateMFAPendingToken(s.PrivateKey, userID, email, ip, ua)
+	return GenerateMFAPendingToken(
+		s.Keys.ActiveKey(models.SigningAlgRS256),
+		userID,
+		email,
+		ip,
+		ua,
+		firstFactor,
+	)
 }
 
 func (s *authService) ValidateMFAPendingToken(
 	tokenStr string,
 ) (*MFAPendingClaims, error) {
-	return ValidateMFAPendingToken(tokenStr, s.PublicKey)
+	return ValidateMFAPendingToken(tokenStr, s.Keys)
 }
 
+/**
+ * CreateSessionAndSetCookie signs in a user who verified the given second
+ * factor. The first factor is read from the user's pending MFA token.
+ */
 func (s *authService) CreateSessionAndSetCookie(
 	c *gin.Context,
 	userID uuid.UUID,
+	method string,
 ) error {
+	var firstFactor string
+	claims, err := s.ValidateMFAPendingToken(pendingMFAToken(c))
+	if err == nil && claims.UserID == userID.String() {
+		firstFactor = claims.FirstFactor
+	}
+
 	sessionID, err := s.GetSessionToken(
 		c.Request.Context(),
 		userID,
 		c.ClientIP(),
 		c.Request.UserAgent(),
+		firstFactor,
+		method,
+	)
+	if err != nil {
+		return err
+	}
+
+	setSessionCookie(c, sessionID)
+	return nil
+}
+
+/**
+ * CreatePasskeySessionAndSetCookie signs a user in with a discoverable
+ * passkey alone. The ceremony requires user verification, so the passkey
+ * proves both possession and a PIN or biometric and the session starts at
+ * 2fa with amr=hwk.
+ */
+func (s *authService) CreatePasskeySessionAndSetCookie(
+	c *gin.Context,
+	userID uuid.UUID,
+) error {
+	sessionID, err := s.createSession(
+		c.Request.Context(),
+		userID,
+		c.ClientIP(),
+		c.Request.UserAgent(),
+		models.AMRHardwareKey,
+		true,
 	)
 	if err != nil {
 		return err
 	}
 
+	setSessionCookie(c, sessionID)
+	return nil
+}
+
+// setSessionCookie hands the browser the cookie of a new session.
+func setSessionCookie(c *gin.Context, sessionID string) {
 	maxAge := int(time.Hour.Seconds() * 24 * SESSION_DAYS)
 	c.SetSameSite(http.SameSiteStrictMode)
 	c.SetCookie(
@@ -545,7 +1084,44 @@ func (s *authService) CreateSessionAndSetCookie(
 		true,
 		true,
 	)
+}
+
+/**
+ * RecordSecondFactor notes a second factor verified on an existing
+ * session, raising it back to 2fa for clients that asked for a step-up.
+ */
+func (s *authService) RecordSecondFactor(
+	c *gin.Context,
+	method string,
+) error {
+	sessionID, err := c.Cookie(SESSION_COOKIE_NAME)
+	if err != nil || sessionID == "" {
+		return fmt.Errorf("session validation: no session")
+	}
 
+	ctx := c.Request.Context()
+	session, err := s.SessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("database query (GetSession): %w", err)
+	}
+
+	methods, _, _ := sessionAuthContext(session)
+	methods = slices.DeleteFunc(methods, func(m string) bool {
+		return m == models.AMRMultiFactor
+	})
+	if !slices.Contains(methods, method) {
+		methods = append(methods, method)
+	}
+
+	err = s.SessionRepo.UpdateAuthContext(
+		ctx,
+		sessionID,
+		strings.Join(methods, " "),
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("database query (UpdateAuthContext): %w", err)
+	}
 	return nil
 }
 
@@ -566,18 +1142,7 @@ func (s *authService) CheckSessionOrPendingMFA(
 	}
 
 	// 2. Try checking the pending MFA token
-	var tokenStr string
-	pendingCookie, err := c.Cookie("idp_mfa_pending")
-	if err == nil && pendingCookie != "" {
-		tokenStr = pendingCookie
-	} else {
-		// Fallback to Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-			tokenStr = authHeader[7:]
-		}
-	}
-
+	tokenStr := pendingMFAToken(c)
 	if tokenStr == "" {
 		return uuid.Nil, false, nil, fmt.Errorf("pending cookie missing")
 	}
@@ -602,17 +1167,13 @@ func (s *authService) CheckSessionOrPendingMFA(
 		}
 	}
 
-	parsedToken, err := GetParsedToken(tokenStr, s.PublicKey)
+	parsedToken, err := GetParsedToken(tokenStr, s.Keys)
 	if err == nil && parsedToken.Valid {
+		// Only access tokens issued to a user carry userId; client
+		// credentials tokens have none and cannot act for a user.
 		if accessClaims, ok := parsedToken.Claims.(*models.UserClaims); ok {
-			var finalUserID string
-			if accessClaims.UserID != "" {
-				finalUserID = accessClaims.UserID
-			} else {
-				finalUserID = accessClaims.Subject
-			}
 			if parsedUID, parseErr := uuid.Parse(
-				finalUserID,
+				accessClaims.UserID,
 			); parseErr == nil {
 				return parsedUID, false, func() {}, nil
 			}
@@ -621,3 +1182,105 @@ func (s *authService) CheckSessionOrPendingMFA(
 
 	return uuid.Nil, false, nil, fmt.Errorf("invalid token or user ID")
 }
+
+// pendingMFAToken returns the pending MFA token of the request, taken from
+// its cookie or, failing that, the Authorization header.
+func pendingMFAToken(c *gin.Context) string {
+	pendingCookie, err := c.Cookie("idp_mfa_pending")
+	if err == nil && pendingCookie != "" {
+		return pendingCookie
+	}
+	authHeader := c.GetHeader("Authorization")
+	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+		return authHeader[7:]
+	}
+	return ""
+}
+
+/**
+ * validateCodeChallenge checks the PKCE parameters of an authorization
+ * request against the client's policy and returns the effective
+ * challenge method. RFC 7636 defaults a missing method to "plain".
+ */
+func validateCodeChallenge(
+	client *models.Client,
+	challenge string,
+	method string,
+) (string, error) {
+	if challenge == "" {
+		if method != "" {
+			return "", fmt.Errorf(
+				"pkce validation: code_challenge_method without challenge",
+			)
+		}
+		// A public client has no secret, so PKCE is its only protection.
+		if client.RequirePKCE || client.PublicClient {
+			return "", fmt.Errorf("pkce validation: code_challenge required")
+		}
+		return "", nil
+	}
+
+	if method == "" {
+		method = models.PKCEMethodPlain
+	}
+	if method != models.PKCEMethodS256 && method != models.PKCEMethodPlain {
+		return "", fmt.Errorf("pkce validation: unsupported method %q", method)
+	}
+
+	if !utils.IsValidPKCEValue(challenge) {
+		return "", fmt.Errorf("pkce validation: malformed code_challenge")
+	}
+
+	return method, nil
+}
+
+/**
+ * resolveRedirectURI returns the redirect URI to use for an authorization
+ * request. The requested value must exactly match a registered URI; an
+ * empty value falls back to the client's primary redirect URI.
+ */
+func resolveRedirectURI(
+	client *models.Client,
+	requested string,
+) (string, error) {
+	if requested == "" {
+		if client.RedirectUri == "" {
+			return "", fmt.Errorf(
+				"redirect validation: no registered redirect_uri",
+			)
+		}
+		return client.RedirectUri, nil
+	}
+
+	if requested == client.RedirectUri ||
+		slices.Contains(client.RedirectUris, requested) {
+		return requested, nil
+	}
+
+	return "", fmt.Errorf("redirect validation: unregistered redirect_uri")
+}
+
+/**
+ * checkUserAccess verifies that the user is assigned to the client or
+ * holds one of its allowed roles. The IdP's own client serves account
+ * self-service and is open to every user.
+ */
+func (s *authService) checkUserAccess(
+	ctx context.Context,
+	userID []byte,
+	clientID string,
+	clientIDBin []byte,
+) error {
+	if clientID == os.Getenv("CLIENT_ID") {
+		return nil
+	}
+
+	allowed, err := s.ClientRepo.IsUserAllowed(ctx, userID, clientIDBin)
+	if err != nil {
+		return fmt.Errorf("database query (IsUserAllowed): %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("access denied: user is not allowed for this client")
+	}
+	return nil
+}
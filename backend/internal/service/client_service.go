@@ -98,17 +98,27 @@ func (s *clientService) CreateClient(
 
 	// 3. Model Mapping
 	clientModel := &models.Client{
-		ID:              clientID[:],
-		ClientName:      req.Name,
-		ClientSecret:    hashedSecret,
-		BaseUrl:         req.BaseURL,
-		RedirectUri:     req.RedirectURI,
-		LogoutUri:       req.LogoutURI,
-		Description:     req.Description,
-		ImageLocation:   imagePath,
-		OnePortalLink:   onePortalLink,
-		AccessTokenTTL:  req.AccessTokenTTL,
-		RefreshTokenTTL: req.RefreshTokenTTL,
+		ID:                    clientID[:],
+		ClientName:            req.Name,
+		ClientSecret:          hashedSecret,
+		BaseUrl:               req.BaseURL,
+		RedirectUri:           req.RedirectURI,
+		RedirectUris:          req.RedirectURIs,
+		LogoutUri:             req.LogoutURI,
+		FrontchannelLogoutUri: req.FrontchannelLogoutURI,
+		Description:           req.Description,
+		ImageLocation:         imagePath,
+		OnePortalLink:         onePortalLink,
+		AccessTokenTTL:        req.AccessTokenTTL,
+		RefreshTokenTTL:       req.RefreshTokenTTL,
+		RequirePKCE:           req.RequirePKCE,
+		AllowedScopes:         NormalizeScopeList(req.AllowedScopes),
+		TokenSigningAlg:       req.TokenSigningAlg,
+		RequireConsent:        req.RequireConsent,
+		MinACR:                req.MinACR,
+		RequirePAR:            req.RequirePAR,
+		AllowMagicLink:        req.AllowMagicLink,
+		PublicClient:          req.PublicClient,
 	}
 
 	// 4. Persistence
@@ -263,17 +273,26 @@ func (s *clientService) GetClientList(
 		}
 
 		res = append(res, dto.ClientResponse{
-			ID:              id.String(),
-			Name:            cl.ClientName,
-			Description:     cl.Description,
-			ImageLocation:   imgUrl,
-			BaseURL:         cl.BaseUrl,
-			RedirectURI:     cl.RedirectUri,
-			LogoutURI:       cl.LogoutUri,
-			OnePortalLink:   derefString(cl.OnePortalLink),
-			CreatedAt:       cl.CreatedAt.Format(TIME_LAYOUT),
-			AccessTokenTTL:  cl.AccessTokenTTL,
-			RefreshTokenTTL: cl.RefreshTokenTTL,
+			ID:                    id.String(),
+			Name:                  cl.ClientName,
+			Description:           cl.Description,
+			ImageLocation:         imgUrl,
+			BaseURL:               cl.BaseUrl,
+			RedirectURI:           cl.RedirectUri,
+			LogoutURI:             cl.LogoutUri,
+			FrontchannelLogoutURI: cl.FrontchannelLogoutUri,
+			OnePortalLink:         derefString(cl.OnePortalLink),
+			CreatedAt:             cl.CreatedAt.Format(TIME_LAYOUT),
+			AccessTokenTTL:        cl.AccessTokenTTL,
+			RefreshTokenTTL:       cl.RefreshTokenTTL,
+			RequirePKCE:           cl.RequirePKCE,
+			AllowedScopes:         cl.AllowedScopes,
+			TokenSigningAlg:       cl.TokenSigningAlg,
+			RequireConsent:        cl.RequireConsent,
+			MinACR:                cl.MinACR,
+			RequirePAR:            cl.RequirePAR,
+			AllowMagicLink:        cl.AllowMagicLink,
+			PublicClient:          cl.PublicClient,
 		})
 	}
 
@@ -352,17 +371,26 @@ func (s *clientService) GetBoundClients(
 		imgURL, _ := GetPresignedURL(ctx, cl.ImageLocation, s.Storage)
 
 		res = append(res, dto.ClientResponse{
-			ID:              id.String(),
-			Name:            cl.ClientName,
-			Description:     cl.Description,
-			ImageLocation:   imgURL,
-			BaseURL:         cl.BaseUrl,
-			RedirectURI:     cl.RedirectUri,
-			LogoutURI:       cl.LogoutUri,
-			OnePortalLink:   derefString(cl.OnePortalLink),
-			CreatedAt:       cl.CreatedAt.Format(TIME_LAYOUT),
-			AccessTokenTTL:  cl.AccessTokenTTL,
-			RefreshTokenTTL: cl.RefreshTokenTTL,
+			ID:                    id.String(),
+			Name:                  cl.ClientName,
+			Description:           cl.Description,
+			ImageLocation:         imgURL,
+			BaseURL:               cl.BaseUrl,
+			RedirectURI:           cl.RedirectUri,
+			LogoutURI:             cl.LogoutUri,
+			FrontchannelLogoutURI: cl.FrontchannelLogoutUri,
+			OnePortalLink:         derefString(cl.OnePortalLink),
+			CreatedAt:             cl.CreatedAt.Format(TIME_LAYOUT),
+			AccessTokenTTL:        cl.AccessTokenTTL,
+			RefreshTokenTTL:       cl.RefreshTokenTTL,
+			RequirePKCE:           cl.RequirePKCE,
+			AllowedScopes:         cl.AllowedScopes,
+			TokenSigningAlg:       cl.TokenSigningAlg,
+			RequireConsent:        cl.RequireConsent,
+			MinACR:                cl.MinACR,
+			RequirePAR:            cl.RequirePAR,
+			AllowMagicLink:        cl.AllowMagicLink,
+			PublicClient:          cl.PublicClient,
 		})
 	}
 
@@ -441,17 +469,26 @@ func (s *clientService) GetAllowedClients(
 		imgURL, _ := GetPresignedURL(ctx, cl.ImageLocation, s.Storage)
 
 		res = append(res, dto.ClientResponse{
-			ID:              id.String(),
-			Name:            cl.ClientName,
-			Description:     cl.Description,
-			ImageLocation:   imgURL,
-			BaseURL:         cl.BaseUrl,
-			RedirectURI:     cl.RedirectUri,
-			LogoutURI:       cl.LogoutUri,
-			OnePortalLink:   derefString(cl.OnePortalLink),
-			CreatedAt:       cl.CreatedAt.Format(TIME_LAYOUT),
-			AccessTokenTTL:  cl.AccessTokenTTL,
-			RefreshTokenTTL: cl.RefreshTokenTTL,
+			ID:                    id.String(),
+			Name:                  cl.ClientName,
+			Description:           cl.Description,
+			ImageLocation:         imgURL,
+			BaseURL:               cl.BaseUrl,
+			RedirectURI:           cl.RedirectUri,
+			LogoutURI:             cl.LogoutUri,
+			FrontchannelLogoutURI: cl.FrontchannelLogoutUri,
+			OnePortalLink:         derefString(cl.OnePortalLink),
+			CreatedAt:             cl.CreatedAt.Format(TIME_LAYOUT),
+			AccessTokenTTL:        cl.AccessTokenTTL,
+			RefreshTokenTTL:       cl.RefreshTokenTTL,
+			RequirePKCE:           cl.RequirePKCE,
+			AllowedScopes:         cl.AllowedScopes,
+			TokenSigningAlg:       cl.TokenSigningAlg,
+			RequireConsent:        cl.RequireConsent,
+			MinACR:                cl.MinACR,
+			RequirePAR:            cl.RequirePAR,
+			AllowMagicLink:        cl.AllowMagicLink,
+			PublicClient:          cl.PublicClient,
 		})
 	}
 
@@ -520,21 +557,50 @@ func (s *clientService) GetClientByID(
 	}
 
 	return &dto.ClientResponse{
-		ID:              id.String(),
-		Name:            cl.ClientName,
-		Description:     cl.Description,
-		ImageLocation:   imgUrl,
-		BaseURL:         cl.BaseUrl,
-		RedirectURI:     cl.RedirectUri,
-		LogoutURI:       cl.LogoutUri,
-		OnePortalLink:   derefString(cl.OnePortalLink),
-		Grants:          grants,
-		AllowedRoles:    roleResponses,
-		AccessTokenTTL:  cl.AccessTokenTTL,
-		RefreshTokenTTL: cl.RefreshTokenTTL,
+		ID:                    id.String(),
+		Name:                  cl.ClientName,
+		Description:           cl.Description,
+		ImageLocation:         imgUrl,
+		BaseURL:               cl.BaseUrl,
+		RedirectURI:           cl.RedirectUri,
+		RedirectURIs:          cl.RedirectUris,
+		LogoutURI:             cl.LogoutUri,
+		FrontchannelLogoutURI: cl.FrontchannelLogoutUri,
+		OnePortalLink:         derefString(cl.OnePortalLink),
+		Grants:                grants,
+		AllowedRoles:          roleResponses,
+		AccessTokenTTL:        cl.AccessTokenTTL,
+		RefreshTokenTTL:       cl.RefreshTokenTTL,
+		RequirePKCE:           cl.RequirePKCE,
+		AllowedScopes:         cl.AllowedScopes,
+		TokenSigningAlg:       cl.TokenSigningAlg,
+		RequireConsent:        cl.RequireConsent,
+		MinACR:                cl.MinACR,
+		RequirePAR:            cl.RequirePAR,
+		AllowMagicLink:        cl.AllowMagicLink,
+		PublicClient:          cl.PublicClient,
 	}, nil
 }
 
+/**
+ * keepRedirectURIs rebuilds the registered redirect URIs around a new
+ * primary URI when an update does not send the full list. The old primary
+ * is replaced and every additional URI is kept.
+ */
+func keepRedirectURIs(existing *models.Client, primary string) []string {
+	uris := []string{}
+	if primary != "" {
+		uris = append(uris, primary)
+	}
+	for _, uri := range existing.RedirectUris {
+		if uri == existing.RedirectUri || slices.Contains(uris, uri) {
+			continue
+		}
+		uris = append(uris, uri)
+	}
+	return uris
+}
+
 /**
  * UpdateClient handles the business logic for modifying an
  * existing client, including optional image replacement.
@@ -577,17 +643,32 @@ func (s *clientService) UpdateClient(
 		onePortalLink = &req.OnePortalLink
 	}
 
+	redirectURIs := req.RedirectURIs
+	if redirectURIs == nil {
+		redirectURIs = keepRedirectURIs(existing, req.RedirectURI)
+	}
+
 	clientModel := &models.Client{
-		ID:              id[:],
-		ClientName:      req.Name,
-		BaseUrl:         req.BaseURL,
-		RedirectUri:     req.RedirectURI,
-		LogoutUri:       req.LogoutURI,
-		Description:     req.Description,
-		ImageLocation:   imagePath,
-		OnePortalLink:   onePortalLink,
-		AccessTokenTTL:  req.AccessTokenTTL,
-		RefreshTokenTTL: req.RefreshTokenTTL,
+		ID:                    id[:],
+		ClientName:            req.Name,
+		BaseUrl:               req.BaseURL,
+		RedirectUri:           req.RedirectURI,
+		RedirectUris:          redirectURIs,
+		LogoutUri:             req.LogoutURI,
+		FrontchannelLogoutUri: req.FrontchannelLogoutURI,
+		Description:           req.Description,
+		ImageLocation:         imagePath,
+		OnePortalLink:         onePortalLink,
+		AccessTokenTTL:        req.AccessTokenTTL,
+		RefreshTokenTTL:       req.RefreshTokenTTL,
+		RequirePKCE:           req.RequirePKCE,
+		AllowedScopes:         NormalizeScopeList(req.AllowedScopes),
+		TokenSigningAlg:       req.TokenSigningAlg,
+		RequireConsent:        req.RequireConsent,
+		MinACR:                req.MinACR,
+		RequirePAR:            req.RequirePAR,
+		AllowMagicLink:        req.AllowMagicLink,
+		PublicClient:          req.PublicClient,
 	}
 
 	err = s.Repo.UpdateClient(ctx, clientModel, req.Grants)
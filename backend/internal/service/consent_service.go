@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/google/uuid"
+)
+
+type ConsentService interface {
+	GetConsentPrompt(ctx context.Context, sessionToken string,
+		clientID string, scope string) (*dto.ConsentPromptResponse, error)
+	GrantConsent(ctx context.Context, sessionToken string,
+		clientID string, scope string) error
+	ListConsents(ctx context.Context,
+		userID uuid.UUID) ([]dto.ConsentResponse, error)
+	RevokeConsent(ctx context.Context, userID, clientID uuid.UUID) error
+}
+
+type consentService struct {
+	Repo        repository.ConsentRepository
+	SessionRepo repository.SessionRepository
+	ClientRepo  repository.ClientRepository
+}
+
+func NewConsentService(
+	repo repository.ConsentRepository,
+	sessionRepo repository.SessionRepository,
+	clientRepo repository.ClientRepository,
+) ConsentService {
+	return &consentService{
+		Repo:        repo,
+		SessionRepo: sessionRepo,
+		ClientRepo:  clientRepo,
+	}
+}
+
+/**
+ * GetConsentPrompt describes a pending consent for the signed-in user:
+ * the client, the scopes it requests and those already granted.
+ */
+func (s *consentService) GetConsentPrompt(
+	ctx context.Context,
+	sessionToken string,
+	clientID string,
+	scope string,
+) (*dto.ConsentPromptResponse, error) {
+	session, client, err := s.resolve(ctx, sessionToken, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := s.Repo.GetConsent(ctx, session.UserId, client.ID)
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetConsent): %w", err)
+	}
+	granted := []string{}
+	if consent != nil {
+		granted = strings.Fields(consent.Scope)
+	}
+
+	return &dto.ConsentPromptResponse{
+		ClientID:      clientID,
+		ClientName:    client.ClientName,
+		Scopes:        strings.Fields(NormalizeScope(scope)),
+		GrantedScopes: granted,
+	}, nil
+}
+
+/**
+ * GrantConsent records that the signed-in user allows the client the
+ * requested scopes, in addition to any granted before.
+ */
+func (s *consentService) GrantConsent(
+	ctx context.Context,
+	sessionToken string,
+	clientID string,
+	scope string,
+) error {
+	session, client, err := s.resolve(ctx, sessionToken, clientID)
+	if err != nil {
+		return err
+	}
+
+	consent, err := s.Repo.GetConsent(ctx, session.UserId, client.ID)
+	if err != nil {
+		return fmt.Errorf("database query (GetConsent): %w", err)
+	}
+	granted := NormalizeScope(scope)
+	if consent != nil {
+		granted = NormalizeScopeList(consent.Scope + " " + granted)
+	}
+
+	err = s.Repo.SaveConsent(ctx, &models.UserConsent{
+		UserID:   session.UserId,
+		ClientID: client.ID,
+		Scope:    granted,
+	})
+	if err != nil {
+		return fmt.Errorf("database query (SaveConsent): %w", err)
+	}
+	return nil
+}
+
+/**
+ * ListConsents returns every client the user has granted consent to.
+ */
+func (s *consentService) ListConsents(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]dto.ConsentResponse, error) {
+	consents, err := s.Repo.ListConsentsByUser(ctx, userID[:])
+	if err != nil {
+		return nil, fmt.Errorf("database query (ListConsents): %w", err)
+	}
+
+	resp := make([]dto.ConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		clientID, err := uuid.FromBytes(consent.ClientID)
+		if err != nil {
+			continue
+		}
+		resp = append(resp, dto.ConsentResponse{
+			ClientID:   clientID.String(),
+			ClientName: consent.ClientName,
+			Scopes:     strings.Fields(consent.Scope),
+			GrantedAt:  consent.GrantedAt,
+		})
+	}
+	return resp, nil
+}
+
+/**
+ * RevokeConsent withdraws the user's consent for the client and revokes
+ * the refresh tokens issued to it, so the next authorization asks again.
+ */
+func (s *consentService) RevokeConsent(
+	ctx context.Context,
+	userID, clientID uuid.UUID,
+) error {
+	found, err := s.Repo.DeleteConsent(ctx, userID[:], clientID[:])
+	if err != nil {
+		return fmt.Errorf("database query (DeleteConsent): %w", err)
+	}
+	if !found {
+		return fmt.Errorf("consent not found")
+	}
+	return nil
+}
+
+// resolve loads the active session and the client of a consent request.
+func (s *consentService) resolve(
+	ctx context.Context,
+	sessionToken string,
+	clientID string,
+) (*models.IdPSession, *models.Client, error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("uuid parse: %w", err)
+	}
+
+	session, err := s.SessionRepo.GetByID(ctx, sessionToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("database query (GetSession): %w", err)
+	}
+	if session == nil || time.Now().After(session.ExpiresAt) {
+		return nil, nil, fmt.Errorf("session validation: expired")
+	}
+
+	client, err := s.ClientRepo.GetByID(ctx, clientUUID[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("database query (GetClient): %w", err)
+	}
+	return session, client, nil
+}
+
+// consentCovers reports whether a stored consent includes every
+// requested scope.
+func consentCovers(consent *models.UserConsent, scope string) bool {
+	if consent == nil {
+		return false
+	}
+	granted := strings.Fields(consent.Scope)
+	for _, requested := range strings.Fields(scope) {
+		if !slices.Contains(granted, requested) {
+			return false
+		}
+	}
+	return true
+}
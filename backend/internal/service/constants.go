@@ -14,8 +14,58 @@ const (
 	DEFAULT_PAGE        = 1
 	ACCESS_TOKEN_EXPIRY = 3600
 
+	// AUTHORIZE_REQUEST_COOKIE_NAME holds the authorize query string while
+	// the user is sent through the login UI.
+	AUTHORIZE_REQUEST_COOKIE_NAME = "idp_authorize_request"
+	// AUTHORIZE_REQUEST_TTL represents the pending request lifetime in seconds
+	AUTHORIZE_REQUEST_TTL = 600
+
+	// MFA_ASSURANCE_TTL represents, in minutes, how long a verified second
+	// factor keeps a session at the 2fa authentication context class
+	MFA_ASSURANCE_TTL = 60
+
+	// LOGOUT_TOKEN_TTL represents the back-channel logout token lifetime
+	// in seconds
+	LOGOUT_TOKEN_TTL = 120
+	// LOGOUT_RETRY_HOURS represents how long an undelivered back-channel
+	// logout request is retried
+	LOGOUT_RETRY_HOURS = 24
+	// LOGOUT_REQUEST_TIMEOUT represents, in seconds, how long a client's
+	// back-channel logout URI may take to answer
+	LOGOUT_REQUEST_TIMEOUT = 5
+	// LOGOUT_CONFIRM_COOKIE_NAME holds the token of a logout the user is
+	// asked to confirm because the request carried no id_token_hint
+	LOGOUT_CONFIRM_COOKIE_NAME = "idp_logout_confirm"
+	// LOGOUT_CONFIRM_TTL represents the confirmation lifetime in seconds
+	LOGOUT_CONFIRM_TTL = 300
+
+	// DEVICE_CODE_TTL represents the device code lifetime in seconds
+	DEVICE_CODE_TTL = 600
+	// DEVICE_POLL_INTERVAL represents, in seconds, how long a device waits
+	// between token requests; slow_down adds the same amount again
+	DEVICE_POLL_INTERVAL = 5
+
+	// PAR_REQUEST_TTL represents the lifetime of a pushed authorization
+	// request in seconds; like a remembered authorize request it has to
+	// outlast the trip through the login page
+	PAR_REQUEST_TTL = 600
+	// PAR_BINDING_COOKIE_NAME holds the random value that binds a resolved
+	// request_uri to the browser that first used it
+	PAR_BINDING_COOKIE_NAME = "idp_par_binding"
+
+	// RESET_TOKEN_TTL represents, in seconds, how long the token minted
+	// by a verified OTP may be used to reset the password
+	RESET_TOKEN_TTL = 600
+
+	// MAGIC_LINK_TTL represents, in seconds, how long an emailed sign-in
+	// link stays valid; no longer than the authorize request it continues
+	MAGIC_LINK_TTL = AUTHORIZE_REQUEST_TTL
+
 	// DefaultAccessTokenTTL represents access token duration in minutes
 	DefaultAccessTokenTTL = 60
 	// DefaultRefreshTokenTTL represents refresh token duration in hours
 	DefaultRefreshTokenTTL = 168
+	// MaxAccessTokenTTL represents the longest access token duration a
+	// client may configure, in minutes
+	MaxAccessTokenTTL = 1440
 )
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/google/uuid"
+)
+
+type DeviceService interface {
+	StartDeviceAuthorization(ctx context.Context,
+		req dto.DeviceAuthorizationRequest,
+	) (*dto.DeviceAuthorizationResponse, error)
+	GetDeviceVerification(ctx context.Context, sessionToken string,
+		userCode string) (*dto.DeviceVerificationResponse, error)
+	AnswerDeviceVerification(ctx context.Context, sessionToken string,
+		req dto.DeviceVerificationRequest) error
+	PollDeviceCode(ctx context.Context,
+		req dto.TokenExchangeRequest) (string, error)
+}
+
+type deviceService struct {
+	Repo        repository.DeviceRepository
+	AuthRepo    repository.AuthCodeRepository
+	SessionRepo repository.SessionRepository
+	ClientRepo  repository.ClientRepository
+}
+
+func NewDeviceService(
+	repo repository.DeviceRepository,
+	authRepo repository.AuthCodeRepository,
+	sessionRepo repository.SessionRepository,
+	clientRepo repository.ClientRepository,
+) DeviceService {
+	return &deviceService{
+		Repo:        repo,
+		AuthRepo:    authRepo,
+		SessionRepo: sessionRepo,
+		ClientRepo:  clientRepo,
+	}
+}
+
+/**
+ * StartDeviceAuthorization issues a device code and a user code for a
+ * client allowed to use the device grant. The device shows the user code
+ * and the verification URI, then polls the token endpoint with the
+ * device code.
+ */
+func (s *deviceService) StartDeviceAuthorization(
+	ctx context.Context,
+	req dto.DeviceAuthorizationRequest,
+) (*dto.DeviceAuthorizationResponse, error) {
+	clientUUID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("uuid parse: %w", err)
+	}
+
+	// 1. Authenticate Client
+	// Public clients such as CLI tools cannot keep a secret; every other
+	// client has to present it.
+	client, err := s.ClientRepo.GetByID(ctx, clientUUID[:])
+	if err != nil {
+		return nil, fmt.Errorf("client verification: %w", err)
+	}
+	if !client.PublicClient || req.ClientSecret != "" {
+		if req.ClientSecret == "" {
+			return nil, fmt.Errorf("client verification: missing secret")
+		}
+		valid, err := s.AuthRepo.VerifyClient(
+			ctx,
+			clientUUID[:],
+			req.ClientSecret,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("client verification: %w", err)
+		}
+		if !valid {
+			return nil, fmt.Errorf("client verification: invalid credentials")
+		}
+	}
+	if !slices.Contains(client.Grants, string(models.GrantDeviceCode)) {
+		return nil, fmt.Errorf(
+			"unauthorized client: device_code grant not allowed",
+		)
+	}
+
+	// 2. Code Generation
+	deviceCode, err := utils.GenerateRandomString(SECRET_ENTROPY)
+	if err != nil {
+		return nil, fmt.Errorf("code generation: %w", err)
+	}
+	userCode, err := utils.GenerateUserCode()
+	if err != nil {
+		return nil, fmt.Errorf("code generation: %w", err)
+	}
+
+	err = s.Repo.Create(ctx, &models.DeviceAuthorization{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		ClientId:   clientUUID[:],
+		Scope:      NormalizeScope(req.Scope),
+		Interval:   DEVICE_POLL_INTERVAL,
+		ExpiresAt:  time.Now().Add(DEVICE_CODE_TTL * time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("database query (CreateDeviceCode): %w", err)
+	}
+
+	verificationURI := os.Getenv("CLIENT_BASE_URL") + "/device"
+	return &dto.DeviceAuthorizationResponse{
+		DeviceCode:      deviceCode,
+		UserCode:        userCode,
+		VerificationURI: verificationURI,
+		VerificationURIComplete: utils.AppendQuery(
+			verificationURI,
+			map[string]string{"user_code": userCode},
+		),
+		ExpiresIn: DEVICE_CODE_TTL,
+		Interval:  DEVICE_POLL_INTERVAL,
+	}, nil
+}
+
+/**
+ * GetDeviceVerification describes the device request a user code was
+ * issued for, so the signed-in user can check it before approving.
+ */
+func (s *deviceService) GetDeviceVerification(
+	ctx context.Context,
+	sessionToken string,
+	userCode string,
+) (*dto.DeviceVerificationResponse, error) {
+	if _, err := s.session(ctx, sessionToken); err != nil {
+		return nil, err
+	}
+	device, client, err := s.pending(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID, _ := uuid.FromBytes(device.ClientId)
+	return &dto.DeviceVerificationResponse{
+		UserCode:   device.UserCode,
+		ClientID:   clientID.String(),
+		ClientName: client.ClientName,
+		Scopes:     strings.Fields(device.Scope),
+	}, nil
+}
+
+/**
+ * AnswerDeviceVerification records whether the signed-in user approves
+ * the device request. An approval binds the user and the authentication
+ * context of their session, which must satisfy the client's minimum
+ * class, to the request.
+ */
+func (s *deviceService) AnswerDeviceVerification(
+	ctx context.Context,
+	sessionToken string,
+	req dto.DeviceVerificationRequest,
+) error {
+	session, err := s.session(ctx, sessionToken)
+	if err != nil {
+		return err
+	}
+	device, client, err := s.pending(ctx, req.UserCode)
+	if err != nil {
+		return err
+	}
+
+	answer := &models.DeviceAuthorization{
+		UserCode: device.UserCode,
+		Status:   models.DeviceStatusDenied,
+		UserId:   session.UserId,
+	}
+	if req.Approve {
+		clientID, _ := uuid.FromBytes(device.ClientId)
+		if clientID.String() != os.Getenv("CLIENT_ID") {
+			allowed, err := s.ClientRepo.IsUserAllowed(
+				ctx,
+				session.UserId,
+				device.ClientId,
+			)
+			if err != nil {
+				return fmt.Errorf("database query (IsUserAllowed): %w", err)
+			}
+			if !allowed {
+				return fmt.Errorf(
+					"access denied: user is not allowed for this client",
+				)
+			}
+		}
+
+		amr, acr, authTime := sessionAuthContext(session)
+		required := requiredACR(client.MinACR, "")
+		if !acrSatisfies(acr, required) {
+			return fmt.Errorf("step-up required: acr %s", required)
+		}
+
+		answer.Status = models.DeviceStatusApproved
+		answer.AuthTime = sql.NullTime{Time: authTime, Valid: true}
+		answer.AMR = strings.Join(amr, " ")
+		answer.ACR = acr
+		answer.Sid = SessionSID(sessionToken)
+	}
+
+	answered, err := s.Repo.Answer(ctx, answer)
+	if err != nil {
+		return fmt.Errorf("database query (AnswerDeviceCode): %w", err)
+	}
+	if !answered {
+		return fmt.Errorf("invalid user code: already answered or expired")
+	}
+
+	// Logging out of the session also notifies the device's client.
+	if req.Approve {
+		err = s.SessionRepo.AddClient(ctx, &models.SessionClient{
+			Sid:       answer.Sid,
+			ClientId:  device.ClientId,
+			UserId:    session.UserId,
+			ExpiresAt: session.ExpiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("database query (AddClient): %w", err)
+		}
+	}
+	return nil
+}
+
+/**
+ * PollDeviceCode answers a device polling the token endpoint. Until the
+ * user approves it fails with "authorization pending", and with "slow
+ * down" when the device polls faster than its interval, which then grows.
+ * An approved request is redeemed once: it is swapped for an
+ * authorization code bound to the device code through PKCE, which the
+ * caller exchanges like any other code.
+ */
+func (s *deviceService) PollDeviceCode(
+	ctx context.Context,
+	req dto.TokenExchangeRequest,
+) (string, error) {
+	clientUUID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return "", fmt.Errorf("uuid parse: %w", err)
+	}
+
+	// 1. Device Lookup
+	device, err := s.Repo.GetByDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		return "", fmt.Errorf("database query (GetDeviceCode): %w", err)
+	}
+	if device == nil {
+		return "", fmt.Errorf("grant validation: device code not found")
+	}
+	if !bytes.Equal(device.ClientId, clientUUID[:]) {
+		return "", fmt.Errorf(
+			"grant validation: device code was issued to another client",
+		)
+	}
+
+	now := time.Now()
+	if now.After(device.ExpiresAt) {
+		_, _ = s.Repo.Delete(ctx, device.DeviceCode)
+		return "", fmt.Errorf("expired token: device code expired")
+	}
+
+	// 2. Polling Interval
+	interval := device.Interval
+	tooSoon := device.LastPolledAt.Valid &&
+		now.Sub(device.LastPolledAt.Time) <
+			time.Duration(interval)*time.Second
+	if tooSoon {
+		interval += DEVICE_POLL_INTERVAL
+	}
+	err = s.Repo.RecordPoll(ctx, device.DeviceCode, now, interval)
+	if err != nil {
+		return "", fmt.Errorf("database query (RecordPoll): %w", err)
+	}
+	if tooSoon {
+		return "", fmt.Errorf("slow down: poll interval is %ds", interval)
+	}
+
+	// 3. User Decision
+	switch device.Status {
+	case models.DeviceStatusPending:
+		return "", fmt.Errorf("authorization pending")
+	case models.DeviceStatusDenied:
+		_, _ = s.Repo.Delete(ctx, device.DeviceCode)
+		return "", fmt.Errorf("device denied: the user denied the request")
+	}
+
+	redeemed, err := s.Repo.Delete(ctx, device.DeviceCode)
+	if err != nil {
+		return "", fmt.Errorf("database query (DeleteDeviceCode): %w", err)
+	}
+	if !redeemed {
+		return "", fmt.Errorf("grant validation: device code already used")
+	}
+
+	// 4. Code Generation
+	code, err := utils.GenerateAuthorizationCode()
+	if err != nil {
+		return "", fmt.Errorf("code generation: %w", err)
+	}
+	err = s.AuthRepo.StoreCode(ctx, &models.AuthorizationCode{
+		Code:                code,
+		UserId:              device.UserId,
+		ClientId:            device.ClientId,
+		CodeChallenge:       utils.ComputeS256Challenge(device.DeviceCode),
+		CodeChallengeMethod: models.PKCEMethodS256,
+		Scope:               device.Scope,
+		AuthTime:            device.AuthTime,
+		AMR:                 device.AMR,
+		ACR:                 device.ACR,
+		Sid:                 device.Sid,
+	})
+	if err != nil {
+		return "", fmt.Errorf("code storage: %w", err)
+	}
+	return code, nil
+}
+
+// session loads the active session of the user on the verification page.
+func (s *deviceService) session(
+	ctx context.Context,
+	sessionToken string,
+) (*models.IdPSession, error) {
+	session, err := s.SessionRepo.GetByID(ctx, sessionToken)
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetSession): %w", err)
+	}
+	if session == nil || time.Now().After(session.ExpiresAt) {
+		return nil, fmt.Errorf("session validation: expired")
+	}
+	return session, nil
+}
+
+// pending loads the unanswered, unexpired request of a user code and its
+// client.
+func (s *deviceService) pending(
+	ctx context.Context,
+	userCode string,
+) (*models.DeviceAuthorization, *models.Client, error) {
+	device, err := s.Repo.GetByUserCode(ctx, utils.NormalizeUserCode(userCode))
+	if err != nil {
+		return nil, nil, fmt.Errorf("database query (GetDeviceCode): %w", err)
+	}
+	if device == nil || device.Status != models.DeviceStatusPending ||
+		time.Now().After(device.ExpiresAt) {
+		return nil, nil, fmt.Errorf("invalid user code: not found or expired")
+	}
+
+	client, err := s.ClientRepo.GetByID(ctx, device.ClientId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("database query (GetClient): %w", err)
+	}
+	return device, client, nil
+}
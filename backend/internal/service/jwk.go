@@ -1,7 +1,10 @@
 package service
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"crypto/sha256"
 	"encoding/base64"
 	"math/big"
 )
@@ -11,8 +14,11 @@ type JWK struct {
 	KeyID     string `json:"kid"`
 	Use       string `json:"use"`
 	Algorithm string `json:"alg"`
-	Modulus   string `json:"n"`
-	Exponent  string `json:"e"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
 }
 
 type JWKS struct {
@@ -34,3 +40,50 @@ func PublicKeyToJWK(pub *rsa.PublicKey, kid string) JWK {
 		Exponent:  eStr,
 	}
 }
+
+// SigningKeyToJWK publishes a signing key as an RSA, EC (P-256) or OKP
+// (Ed25519) JWK.
+func SigningKeyToJWK(key *SigningKey) (JWK, bool) {
+	switch pub := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk := PublicKeyToJWK(pub, key.ID)
+		jwk.Algorithm = key.Algorithm
+		return jwk, true
+	case *ecdsa.PublicKey:
+		// Uncompressed point: 0x04 || X || Y
+		point, err := pub.Bytes()
+		if err != nil {
+			return JWK{}, false
+		}
+		size := (len(point) - 1) / 2
+		return JWK{
+			KeyType:   "EC",
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+			Curve:     "P-256",
+			X:         base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+			Y:         base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			KeyType:   "OKP",
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(pub),
+		}, true
+	}
+	return JWK{}, false
+}
+
+// KeyThumbprint computes the RFC 7638 JWK thumbprint of an RSA public key.
+// It serves as a stable kid when none is configured.
+func KeyThumbprint(pub *rsa.PublicKey) string {
+	jwk := PublicKeyToJWK(pub, "")
+	canonical := `{"e":"` + jwk.Exponent + `","kty":"RSA","n":"` +
+		jwk.Modulus + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
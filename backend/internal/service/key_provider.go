@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+)
+
+const (
+	envelopeKeyPrefix = "envelope:"
+	keyringKeyPrefix  = "keyring:"
+	pkcs11KeyPrefix   = "pkcs11:"
+)
+
+// ErrKeyImportUnsupported is returned by providers that can only use keys
+// they generated themselves.
+var ErrKeyImportUnsupported = errors.New("key import not supported")
+
+// ErrKeyRotationConflict is returned by a rotation that lost to another
+// instance rotating the same key.
+var ErrKeyRotationConflict = errors.New(
+	"key rotation: key already rotated by another instance",
+)
+
+/**
+ * KeyProvider creates and resolves the private halves of signing keys.
+ * The reference it returns is stored with the key in place of the key
+ * material and is all the provider needs to load the signer again.
+ */
+type KeyProvider interface {
+	GenerateKey(ctx context.Context,
+		kid, alg string) (crypto.Signer, string, error)
+	ImportKey(ctx context.Context,
+		kid string, key crypto.Signer) (string, error)
+	LoadKey(ctx context.Context, kid, ref string) (crypto.Signer, error)
+}
+
+// keyRewrapper is implemented by providers whose references embed
+// encrypted key material that follows the encryption key version.
+type keyRewrapper interface {
+	RewrapKey(ref string) (string, bool, error)
+}
+
+// keyDeleter is implemented by providers that hold key material outside
+// the signing key table, so a key that was never stored can be removed.
+type keyDeleter interface {
+	DeleteKey(ctx context.Context, kid, ref string) error
+}
+
+/**
+ * PKCS11Module is the part of a PKCS#11 token (HSM, smart card or KMS
+ * shim) the PKCS#11 provider uses. Bindings to a vendor library implement
+ * it. Keys are addressed by their label and never leave the token.
+ */
+type PKCS11Module interface {
+	GenerateKeyPair(ctx context.Context,
+		label, alg string) (crypto.Signer, error)
+	FindKeyPair(ctx context.Context, label string) (crypto.Signer, error)
+	DestroyKeyPair(ctx context.Context, label string) error
+}
+
+type databaseKeyProvider struct {
+	Cipher *utils.EnvelopeCipher
+}
+
+/**
+ * NewDatabaseKeyProvider keeps the private keys in the signing key table
+ * as PKCS#8 sealed in an envelope. Without a cipher, or before a KEK is
+ * configured, keys are stored as plain PEM.
+ */
+func NewDatabaseKeyProvider(cipher *utils.EnvelopeCipher) KeyProvider {
+	return &databaseKeyProvider{Cipher: cipher}
+}
+
+func (p *databaseKeyProvider) GenerateKey(
+	ctx context.Context,
+	kid, alg string,
+) (crypto.Signer, string, error) {
+	key, err := generateSigner(alg)
+	if err != nil {
+		return nil, "", err
+	}
+	ref, err := p.ImportKey(ctx, kid, key)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, ref, nil
+}
+
+func (p *databaseKeyProvider) ImportKey(
+	ctx context.Context,
+	kid string,
+	key crypto.Signer,
+) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("key encoding: %w", err)
+	}
+	if p.Cipher == nil || p.Cipher.ActiveVersion() == 0 {
+		return string(pem.EncodeToMemory(&pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: der,
+		})), nil
+	}
+
+	sealed, err := p.Cipher.Seal(der)
+	if err != nil {
+		return "", fmt.Errorf("key encryption: %w", err)
+	}
+	return envelopeKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (p *databaseKeyProvider) LoadKey(
+	ctx context.Context,
+	kid, ref string,
+) (crypto.Signer, error) {
+	encoded, sealed := strings.CutPrefix(ref, envelopeKeyPrefix)
+	if !sealed {
+		return utils.ParsePrivateKeyPEM([]byte(ref), "")
+	}
+	if p.Cipher == nil {
+		return nil, fmt.Errorf("key %s is encrypted, no cipher configured",
+			kid)
+	}
+
+	envelope, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("key decoding: %w", err)
+	}
+	der, err := p.Cipher.Open(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("key decryption: %w", err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("key decoding: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key decoding: unsupported type %T", key)
+	}
+	return signer, nil
+}
+
+/**
+ * RewrapKey moves a stored key onto the active KEK version. Plain PEM
+ * keys are sealed for the first time.
+ */
+func (p *databaseKeyProvider) RewrapKey(ref string) (string, bool, error) {
+	if p.Cipher == nil || p.Cipher.ActiveVersion() == 0 {
+		return ref, false, nil
+	}
+
+	encoded, sealed := strings.CutPrefix(ref, envelopeKeyPrefix)
+	if !sealed {
+		key, err := utils.ParsePrivateKeyPEM([]byte(ref), "")
+		if err != nil {
+			return "", false, fmt.Errorf("key decoding: %w", err)
+		}
+		ref, err = p.ImportKey(context.Background(), "", key)
+		return ref, err == nil, err
+	}
+
+	envelope, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", false, fmt.Errorf("key decoding: %w", err)
+	}
+	envelope, changed, err := p.Cipher.Rewrap(envelope)
+	if err != nil || !changed {
+		return ref, false, err
+	}
+	return envelopeKeyPrefix + base64.StdEncoding.EncodeToString(envelope),
+		true, nil
+}
+
+type fileKeyringProvider struct {
+	Dir        string
+	Passphrase string
+}
+
+/**
+ * NewFileKeyringProvider keeps every private key in its own
+ * passphrase-protected PKCS#8 file, <dir>/<kid>.pem. Only the file
+ * reference is stored in the database.
+ */
+func NewFileKeyringProvider(dir, passphrase string) KeyProvider {
+	return &fileKeyringProvider{Dir: dir, Passphrase: passphrase}
+}
+
+func (p *fileKeyringProvider) GenerateKey(
+	ctx context.Context,
+	kid, alg string,
+) (crypto.Signer, string, error) {
+	key, err := generateSigner(alg)
+	if err != nil {
+		return nil, "", err
+	}
+	ref, err := p.ImportKey(ctx, kid, key)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, ref, nil
+}
+
+func (p *fileKeyringProvider) ImportKey(
+	ctx context.Context,
+	kid string,
+	key crypto.Signer,
+) (string, error) {
+	path, err := p.path(kid)
+	if err != nil {
+		return "", err
+	}
+	data, err := utils.EncryptPKCS8(key, p.Passphrase)
+	if err != nil {
+		return "", fmt.Errorf("key encryption: %w", err)
+	}
+	if err := os.MkdirAll(p.Dir, 0o700); err != nil {
+		return "", fmt.Errorf("keyring: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("keyring: %w", err)
+	}
+	return keyringKeyPrefix + kid, nil
+}
+
+func (p *fileKeyringProvider) LoadKey(
+	ctx context.Context,
+	kid, ref string,
+) (crypto.Signer, error) {
+	name, ok := strings.CutPrefix(ref, keyringKeyPrefix)
+	if !ok {
+		return nil, fmt.Errorf("key %s is not held by the keyring", kid)
+	}
+	path, err := p.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("keyring: %w", err)
+	}
+	return utils.ParsePrivateKeyPEM(data, p.Passphrase)
+}
+
+func (p *fileKeyringProvider) DeleteKey(
+	ctx context.Context,
+	kid, ref string,
+) error {
+	name, ok := strings.CutPrefix(ref, keyringKeyPrefix)
+	if !ok {
+		return fmt.Errorf("key %s is not held by the keyring", kid)
+	}
+	path, err := p.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("keyring: %w", err)
+	}
+	return nil
+}
+
+// path maps a kid to its key file and rejects kids that would escape the
+// keyring directory.
+func (p *fileKeyringProvider) path(kid string) (string, error) {
+	if kid == "" || kid != filepath.Base(kid) || strings.HasPrefix(kid, ".") {
+		return "", fmt.Errorf("keyring: invalid key id %q", kid)
+	}
+	return filepath.Join(p.Dir, kid+".pem"), nil
+}
+
+type pkcs11KeyProvider struct {
+	Module PKCS11Module
+}
+
+/**
+ * NewPKCS11KeyProvider generates and uses keys inside a PKCS#11 token.
+ * Keys cannot be imported, so the key loaded from disk is replaced by one
+ * generated on the token.
+ */
+func NewPKCS11KeyProvider(module PKCS11Module) KeyProvider {
+	return &pkcs11KeyProvider{Module: module}
+}
+
+func (p *pkcs11KeyProvider) GenerateKey(
+	ctx context.Context,
+	kid, alg string,
+) (crypto.Signer, string, error) {
+	key, err := p.Module.GenerateKeyPair(ctx, kid, alg)
+	if err != nil {
+		return nil, "", fmt.Errorf("pkcs11: %w", err)
+	}
+	if !keyMatchesAlgorithm(key.Public(), alg) {
+		return nil, "", fmt.Errorf("pkcs11: key type does not match %s", alg)
+	}
+	return key, pkcs11KeyPrefix + kid, nil
+}
+
+func (p *pkcs11KeyProvider) ImportKey(
+	ctx context.Context,
+	kid string,
+	key crypto.Signer,
+) (string, error) {
+	return "", ErrKeyImportUnsupported
+}
+
+func (p *pkcs11KeyProvider) LoadKey(
+	ctx context.Context,
+	kid, ref string,
+) (crypto.Signer, error) {
+	label, ok := strings.CutPrefix(ref, pkcs11KeyPrefix)
+	if !ok {
+		return nil, fmt.Errorf("key %s is not held by the token", kid)
+	}
+	key, err := p.Module.FindKeyPair(ctx, label)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: %w", err)
+	}
+	return key, nil
+}
+
+func (p *pkcs11KeyProvider) DeleteKey(
+	ctx context.Context,
+	kid, ref string,
+) error {
+	label, ok := strings.CutPrefix(ref, pkcs11KeyPrefix)
+	if !ok {
+		return fmt.Errorf("key %s is not held by the token", kid)
+	}
+	if err := p.Module.DestroyKeyPair(ctx, label); err != nil {
+		return fmt.Errorf("pkcs11: %w", err)
+	}
+	return nil
+}
+
+// generateSigner creates a fresh software key pair for the algorithm.
+func generateSigner(alg string) (crypto.Signer, error) {
+	var (
+		key crypto.Signer
+		err error
+	)
+
+	switch alg {
+	case models.SigningAlgRS256, models.SigningAlgPS256:
+		key, err = rsa.GenerateKey(rand.Reader, signingKeyBits)
+	case models.SigningAlgES256:
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case models.SigningAlgEdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("key validation: unsupported algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key generation: %w", err)
+	}
+	return key, nil
+}
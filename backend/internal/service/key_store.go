@@ -0,0 +1,630 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// signingKeyBits is the RSA modulus size of generated keys.
+	signingKeyBits = 2048
+	// keySyncMaxInterval bounds how long an instance keeps signing with a
+	// key that another instance retired.
+	keySyncMaxInterval = time.Hour
+	// signingKeyRetention keeps a retired key published until every token
+	// it signed has expired.
+	signingKeyRetention = MaxAccessTokenTTL*time.Minute + keySyncMaxInterval
+	// keyRefreshInterval limits how often an unknown kid triggers a reload
+	// of the stored keys.
+	keyRefreshInterval = 30 * time.Second
+)
+
+// SigningKey is a loaded token signing key pair. Retired keys only verify.
+// Signer holds the private half, which may live outside the process, and
+// its public key matches Algorithm.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Signer    crypto.Signer
+	PublicKey crypto.PublicKey
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Method returns the JWS signing method of the key.
+func (k *SigningKey) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+/**
+ * Sign produces the JWS signature of the signing input. The digest is
+ * computed here so the signer only ever sees what a PKCS#11 token would.
+ */
+func (k *SigningKey) Sign(signingInput []byte) ([]byte, error) {
+	if k.Signer == nil {
+		return nil, fmt.Errorf("signing key %s has no private key", k.ID)
+	}
+
+	digest := sha256.Sum256(signingInput)
+	switch k.Algorithm {
+	case models.SigningAlgRS256:
+		return k.Signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	case models.SigningAlgPS256:
+		return k.Signer.Sign(rand.Reader, digest[:], &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       crypto.SHA256,
+		})
+	case models.SigningAlgES256:
+		der, err := k.Signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		// JWS wants the fixed size r || s instead of the ASN.1 form.
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(der, &sig); err != nil {
+			return nil, fmt.Errorf("invalid ECDSA signature: %w", err)
+		}
+		out := make([]byte, 64)
+		sig.R.FillBytes(out[:32])
+		sig.S.FillBytes(out[32:])
+		return out, nil
+	case models.SigningAlgEdDSA:
+		return k.Signer.Sign(rand.Reader, signingInput, crypto.Hash(0))
+	}
+	return nil, fmt.Errorf("key validation: unsupported algorithm %q",
+		k.Algorithm)
+}
+
+// KeyStore holds one active signing key per algorithm and the retired keys
+// that still verify outstanding tokens.
+type KeyStore interface {
+	ActiveKey(alg string) *SigningKey
+	VerificationKey(kid string) (*SigningKey, error)
+	Keys() []*SigningKey
+	Refresh(ctx context.Context) error
+	Rotate(ctx context.Context, alg string) (*SigningKey, error)
+	// Rewrap moves the stored private keys onto the active encryption key
+	// version and returns how many were rewritten.
+	Rewrap(ctx context.Context) (int, error)
+}
+
+type keyStore struct {
+	Repo        repository.SigningKeyRepository
+	Provider    KeyProvider
+	mu          sync.RWMutex
+	initial     *SigningKey
+	active      map[string]*SigningKey
+	keys        []*SigningKey
+	lastRefresh time.Time
+}
+
+/**
+ * NewKeyStore creates a key store that starts out with the given RSA key
+ * as the active RS256 key. The first Refresh registers it in the
+ * repository unless another RS256 key is already active there, and
+ * generates keys for the other algorithms through the provider. A nil
+ * repository keeps the keys in memory only; a nil provider stores them in
+ * the repository unencrypted.
+ */
+func NewKeyStore(
+	repo repository.SigningKeyRepository,
+	provider KeyProvider,
+	initial *SigningKey,
+) KeyStore {
+	if initial.Algorithm == "" {
+		initial.Algorithm = models.SigningAlgRS256
+	}
+	if provider == nil {
+		provider = NewDatabaseKeyProvider(nil)
+	}
+	return &keyStore{
+		Repo:     repo,
+		Provider: provider,
+		initial:  initial,
+		active:   map[string]*SigningKey{initial.Algorithm: initial},
+		keys:     []*SigningKey{initial},
+	}
+}
+
+/**
+ * ActiveKey returns the key new tokens of the algorithm are signed with.
+ * An empty or not yet provisioned algorithm falls back to RS256.
+ */
+func (s *keyStore) ActiveKey(alg string) *SigningKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if key, ok := s.active[alg]; ok {
+		return key
+	}
+	return s.active[models.SigningAlgRS256]
+}
+
+/**
+ * Keys returns the active keys followed by the retired keys that have not
+ * expired yet.
+ */
+func (s *keyStore) Keys() []*SigningKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]*SigningKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		if k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+/**
+ * VerificationKey returns the key for a kid. Unknown kids reload the
+ * stored keys, at most once per refresh interval, so keys rotated by
+ * another instance are picked up.
+ */
+func (s *keyStore) VerificationKey(kid string) (*SigningKey, error) {
+	if key := s.findKey(kid); key != nil {
+		return key, nil
+	}
+
+	s.mu.RLock()
+	stale := time.Since(s.lastRefresh) > keyRefreshInterval
+	s.mu.RUnlock()
+	if s.Repo != nil && stale {
+		ctx, cancel := context.WithTimeout(
+			context.Background(),
+			5*time.Second,
+		)
+		defer cancel()
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("[KeyStore] Refresh: %v", err)
+		}
+		if key := s.findKey(kid); key != nil {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// findKey looks a kid up among the unexpired keys. Tokens issued before
+// kids were assigned carry none and match the key loaded from disk.
+func (s *keyStore) findKey(kid string) *SigningKey {
+	if kid == "" {
+		if s.initial == nil {
+			return nil
+		}
+		kid = s.initial.ID
+	}
+	for _, k := range s.Keys() {
+		if k.ID == kid {
+			return k
+		}
+	}
+	return nil
+}
+
+/**
+ * Refresh reloads the keys from the repository and provisions an active
+ * key for every supported algorithm that has none yet. The initial key
+ * becomes the RS256 key.
+ */
+func (s *keyStore) Refresh(ctx context.Context) error {
+	if s.Repo == nil {
+		return s.provisionInMemory()
+	}
+
+	rows, err := s.Repo.GetSigningKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("database query (GetSigningKeys): %w", err)
+	}
+
+	missing := missingAlgorithms(rows)
+	for _, alg := range missing {
+		row, err := s.provisionKey(ctx, alg)
+		if err != nil {
+			return err
+		}
+		err = s.Repo.CreateInitialSigningKey(ctx, row)
+		if err != nil {
+			return fmt.Errorf(
+				"database query (CreateInitialSigningKey): %w",
+				err,
+			)
+		}
+	}
+	if len(missing) > 0 {
+		rows, err = s.Repo.GetSigningKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("database query (GetSigningKeys): %w", err)
+		}
+	}
+
+	active := make(map[string]*SigningKey)
+	keys := make([]*SigningKey, 0, len(rows))
+	for i := range rows {
+		key, err := s.decodeSigningKey(ctx, &rows[i])
+		if err != nil {
+			log.Printf("[KeyStore] Skipping key %s: %v", rows[i].KeyID, err)
+			continue
+		}
+		if _, ok := active[key.Algorithm]; rows[i].Active && !ok {
+			active[key.Algorithm] = key
+		}
+		keys = append(keys, key)
+	}
+	if active[models.SigningAlgRS256] == nil {
+		return fmt.Errorf("key validation: no active RS256 signing key")
+	}
+
+	s.mu.Lock()
+	s.active = active
+	s.keys = keys
+	s.lastRefresh = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// provisionKey creates the stored form of the first key of an algorithm.
+// The initial key is imported when the provider accepts it.
+func (s *keyStore) provisionKey(
+	ctx context.Context,
+	alg string,
+) (*models.SigningKey, error) {
+	if key := s.initial; key != nil && key.Algorithm == alg {
+		ref, err := s.Provider.ImportKey(ctx, key.ID, key.Signer)
+		if err == nil {
+			return encodeSigningKey(key, ref)
+		}
+		if !errors.Is(err, ErrKeyImportUnsupported) {
+			return nil, err
+		}
+	}
+
+	_, row, err := s.generateSigningKey(ctx, alg)
+	return row, err
+}
+
+// provisionInMemory generates the missing algorithm keys of a store
+// without repository.
+func (s *keyStore) provisionInMemory() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, alg := range models.SigningAlgorithms {
+		if _, ok := s.active[alg]; ok {
+			continue
+		}
+		key, err := newMemoryKey(alg)
+		if err != nil {
+			return err
+		}
+		s.active[alg] = key
+		s.keys = append(s.keys, key)
+	}
+	return nil
+}
+
+/**
+ * Rotate generates a new active key for the algorithm. The previous key is
+ * retired but stays published until the tokens it signed have expired.
+ * When another instance rotated first, the generated key is discarded,
+ * the store picks up the other key and ErrKeyRotationConflict is returned.
+ */
+func (s *keyStore) Rotate(
+	ctx context.Context,
+	alg string,
+) (*SigningKey, error) {
+	s.mu.RLock()
+	current, ok := s.active[alg]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("key validation: no active %s key", alg)
+	}
+
+	if s.Repo == nil {
+		next, err := newMemoryKey(alg)
+		if err != nil {
+			return nil, err
+		}
+		retiredUntil := next.CreatedAt.Add(signingKeyRetention)
+
+		s.mu.Lock()
+		retired := *current
+		retired.ExpiresAt = retiredUntil
+		keys := []*SigningKey{next, &retired}
+		for _, k := range s.keys {
+			if k.ID != current.ID {
+				keys = append(keys, k)
+			}
+		}
+		s.active[alg] = next
+		s.keys = keys
+		s.mu.Unlock()
+		return next, nil
+	}
+
+	next, row, err := s.generateSigningKey(ctx, alg)
+	if err != nil {
+		return nil, err
+	}
+	retiredUntil := next.CreatedAt.Add(signingKeyRetention)
+	rotated, err := s.Repo.RotateSigningKey(ctx, current.ID, row, retiredUntil)
+	if err != nil {
+		s.discardKey(ctx, row)
+		return nil, fmt.Errorf("database query (RotateSigningKey): %w", err)
+	}
+	if !rotated {
+		s.discardKey(ctx, row)
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("[KeyStore] Refresh: %v", err)
+		}
+		return nil, ErrKeyRotationConflict
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.ActiveKey(alg), nil
+}
+
+// discardKey removes the provider's material of a generated key that was
+// never stored. Keys kept in the table itself have nothing to remove.
+func (s *keyStore) discardKey(ctx context.Context, row *models.SigningKey) {
+	deleter, ok := s.Provider.(keyDeleter)
+	if !ok {
+		return
+	}
+	if err := deleter.DeleteKey(ctx, row.KeyID, row.PrivateKey); err != nil {
+		log.Printf("[KeyRotation] Discard %s: %v", row.KeyID, err)
+	}
+}
+
+/**
+ * Rewrap re-encrypts the stored private keys under the active encryption
+ * key version. Providers that keep keys outside the database have nothing
+ * to rewrap.
+ */
+func (s *keyStore) Rewrap(ctx context.Context) (int, error) {
+	rewrapper, ok := s.Provider.(keyRewrapper)
+	if s.Repo == nil || !ok {
+		return 0, nil
+	}
+
+	rows, err := s.Repo.GetSigningKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("database query (GetSigningKeys): %w", err)
+	}
+
+	count := 0
+	for _, row := range rows {
+		ref, changed, err := rewrapper.RewrapKey(row.PrivateKey)
+		if err != nil {
+			return count, fmt.Errorf("key %s: %w", row.KeyID, err)
+		}
+		if !changed {
+			continue
+		}
+		err = s.Repo.UpdateSigningKeyPrivateKey(ctx, row.KeyID, ref)
+		if err != nil {
+			return count, fmt.Errorf(
+				"database query (UpdateSigningKeyPrivateKey): %w",
+				err,
+			)
+		}
+		count++
+	}
+	return count, nil
+}
+
+/**
+ * StartKeyRotation periodically reloads the signing keys and rotates each
+ * active key once it is older than maxAge. The keys are checked a few
+ * times per rotation period, and at least every keySyncMaxInterval. A
+ * maxAge of zero disables scheduled rotation but keeps the keys in sync
+ * with other instances.
+ */
+func StartKeyRotation(
+	ctx context.Context,
+	keys KeyStore,
+	maxAge time.Duration,
+) {
+	interval := keySyncMaxInterval
+	if maxAge > 0 {
+		interval = min(max(maxAge/24, time.Minute), keySyncMaxInterval)
+	}
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		log.Printf("[KeyRotation] Initialized, max key age %s, "+
+			"checked every %s", maxAge, interval)
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := keys.Refresh(ctx); err != nil {
+					log.Printf("[KeyRotation] Refresh: %v", err)
+					continue
+				}
+				if maxAge <= 0 {
+					continue
+				}
+				for _, alg := range models.SigningAlgorithms {
+					active := keys.ActiveKey(alg)
+					if active.Algorithm != alg ||
+						time.Since(active.CreatedAt) < maxAge {
+						continue
+					}
+					rotated, err := keys.Rotate(ctx, alg)
+					if err != nil {
+						log.Printf("[KeyRotation] Rotate %s: %v", alg, err)
+						continue
+					}
+					log.Printf("[KeyRotation] Active %s key is now %s",
+						alg, rotated.ID)
+				}
+			case <-ctx.Done():
+				log.Printf("[KeyRotation] Shutting down")
+				return
+			}
+		}
+	}()
+}
+
+// missingAlgorithms lists the supported algorithms without an active key.
+func missingAlgorithms(rows []models.SigningKey) []string {
+	var missing []string
+	for _, alg := range models.SigningAlgorithms {
+		found := false
+		for _, row := range rows {
+			if row.Active && row.Algorithm == alg {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, alg)
+		}
+	}
+	return missing
+}
+
+// newMemoryKey creates a software key for a store without repository.
+func newMemoryKey(alg string) (*SigningKey, error) {
+	signer, err := generateSigner(alg)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{
+		ID:        uuid.NewString(),
+		Algorithm: alg,
+		Signer:    signer,
+		PublicKey: signer.Public(),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// generateSigningKey creates a new key through the provider and returns
+// it together with its stored form.
+func (s *keyStore) generateSigningKey(
+	ctx context.Context,
+	alg string,
+) (*SigningKey, *models.SigningKey, error) {
+	kid := uuid.NewString()
+	signer, ref, err := s.Provider.GenerateKey(ctx, kid, alg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := &SigningKey{
+		ID:        kid,
+		Algorithm: alg,
+		Signer:    signer,
+		PublicKey: signer.Public(),
+		CreatedAt: time.Now(),
+	}
+	row, err := encodeSigningKey(key, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, row, nil
+}
+
+// encodeSigningKey converts a key pair into its stored form. The private
+// half is kept as the provider reference.
+func encodeSigningKey(
+	key *SigningKey,
+	ref string,
+) (*models.SigningKey, error) {
+	pubDER, err := x509.MarshalPKIXPublicKey(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("key encoding: %w", err)
+	}
+
+	return &models.SigningKey{
+		KeyID:      key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: ref,
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "PUBLIC KEY",
+			Bytes: pubDER,
+		})),
+	}, nil
+}
+
+// decodeSigningKey parses a stored key. Retired keys are loaded without
+// their private half.
+func (s *keyStore) decodeSigningKey(
+	ctx context.Context,
+	row *models.SigningKey,
+) (*SigningKey, error) {
+	block, _ := pem.Decode([]byte(row.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("key decoding: invalid public key PEM")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("key decoding: %w", err)
+	}
+	if !keyMatchesAlgorithm(publicKey, row.Algorithm) {
+		return nil, fmt.Errorf(
+			"key decoding: key type does not match %s",
+			row.Algorithm,
+		)
+	}
+
+	key := &SigningKey{
+		ID:        row.KeyID,
+		Algorithm: row.Algorithm,
+		PublicKey: publicKey,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.ExpiresAt.Valid {
+		key.ExpiresAt = row.ExpiresAt.Time
+	}
+	if !row.Active {
+		return key, nil
+	}
+
+	key.Signer, err = s.Provider.LoadKey(ctx, row.KeyID, row.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("key decoding: %w", err)
+	}
+	return key, nil
+}
+
+// keyMatchesAlgorithm reports whether a public key can verify the
+// algorithm.
+func keyMatchesAlgorithm(publicKey crypto.PublicKey, alg string) bool {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return alg == models.SigningAlgRS256 || alg == models.SigningAlgPS256
+	case *ecdsa.PublicKey:
+		return alg == models.SigningAlgES256 && k.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		return alg == models.SigningAlgEdDSA
+	}
+	return false
+}
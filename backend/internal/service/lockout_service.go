@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	lockoutFailureKey = "lockout:failures:"
+	lockoutPolicyKey  = "lockout:policy"
+	// lockoutPolicyTTL bounds how long an instance keeps a cached policy
+	// when the invalidation of an update did not reach the cache.
+	lockoutPolicyTTL = 5 * time.Minute
+)
+
+// defaultLockoutPolicy applies while the stored policy cannot be read.
+var defaultLockoutPolicy = models.LockoutPolicy{
+	MaxFailures:          5,
+	LockoutMinutes:       15,
+	BackoffBaseSeconds:   1,
+	BackoffMaxSeconds:    30,
+	FailureWindowMinutes: 15,
+}
+
+// LockoutStatus tells the caller that an account may not attempt a login
+// for RetryAfter seconds, either because it is locked or because it failed
+// too recently.
+type LockoutStatus struct {
+	Locked     bool
+	RetryAfter int
+}
+
+type LockoutService interface {
+	CheckLogin(ctx context.Context, email string) (*LockoutStatus, error)
+	RecordFailure(ctx context.Context, email string) (*LockoutStatus, error)
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+	UnlockUser(ctx context.Context, id uuid.UUID) (string, error)
+	GetPolicy(ctx context.Context) (*dto.LockoutPolicyResponse, error)
+	UpdatePolicy(ctx context.Context, req dto.LockoutPolicyRequest) error
+}
+
+type lockoutService struct {
+	Repo     repository.LockoutRepository
+	UserRepo repository.UserRepository
+	Cache    cache.Cache
+}
+
+func NewLockoutService(
+	repo repository.LockoutRepository,
+	userRepo repository.UserRepository,
+	c cache.Cache,
+) LockoutService {
+	return &lockoutService{
+		Repo:     repo,
+		UserRepo: userRepo,
+		Cache:    c,
+	}
+}
+
+/**
+ * CheckLogin reports whether the account may attempt a login now. It
+ * returns nil when it may, and otherwise how long the account is locked
+ * or, after recent failures, how long the progressive delay still runs.
+ */
+func (s *lockoutService) CheckLogin(
+	ctx context.Context,
+	email string,
+) (*LockoutStatus, error) {
+	f, err := s.failure(ctx, normalizeEmail(email))
+	if err != nil || f == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if f.LockedUntil.Valid {
+		if now.Before(f.LockedUntil.Time) {
+			return &LockoutStatus{
+				Locked:     true,
+				RetryAfter: secondsUntil(now, f.LockedUntil.Time),
+			}, nil
+		}
+		// The lock has run out; the next failure starts a new count.
+		return nil, nil
+	}
+
+	policy := s.policy(ctx)
+	window := time.Duration(policy.FailureWindowMinutes) * time.Minute
+	if now.Sub(f.LastFailedAt) > window {
+		return nil, nil
+	}
+
+	next := f.LastFailedAt.Add(backoffDelay(policy, f.FailedCount))
+	if now.Before(next) {
+		return &LockoutStatus{RetryAfter: secondsUntil(now, next)}, nil
+	}
+	return nil, nil
+}
+
+/**
+ * RecordFailure counts a failed password attempt and locks the account
+ * once the policy's limit is reached within the failure window. It
+ * returns the resulting lock or delay, or nil when the next attempt may
+ * follow immediately.
+ */
+func (s *lockoutService) RecordFailure(
+	ctx context.Context,
+	email string,
+) (*LockoutStatus, error) {
+	email = normalizeEmail(email)
+	policy := s.policy(ctx)
+	now := time.Now()
+	window := time.Duration(policy.FailureWindowMinutes) * time.Minute
+
+	f, err := s.Repo.RecordFailure(
+		ctx,
+		email,
+		now,
+		now.Add(-window),
+		now.Add(window),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database query (RecordFailure): %w", err)
+	}
+
+	locked := f.LockedUntil.Valid && now.Before(f.LockedUntil.Time)
+	if !locked && f.FailedCount >= policy.MaxFailures {
+		until := now.Add(time.Duration(policy.LockoutMinutes) * time.Minute)
+		if err := s.Repo.Lock(ctx, email, until); err != nil {
+			return nil, fmt.Errorf("database query (Lock): %w", err)
+		}
+		f.LockedUntil = sql.NullTime{Time: until, Valid: true}
+		if f.ExpiresAt.Before(until) {
+			f.ExpiresAt = until
+		}
+		locked = true
+	}
+	s.cacheFailure(ctx, f)
+
+	if locked {
+		return &LockoutStatus{
+			Locked:     true,
+			RetryAfter: secondsUntil(now, f.LockedUntil.Time),
+		}, nil
+	}
+	if delay := backoffDelay(policy, f.FailedCount); delay > 0 {
+		return &LockoutStatus{RetryAfter: int(delay.Seconds())}, nil
+	}
+	return nil, nil
+}
+
+/**
+ * RecordSuccess clears the failure count after a successful login.
+ */
+func (s *lockoutService) RecordSuccess(
+	ctx context.Context,
+	email string,
+) error {
+	email = normalizeEmail(email)
+	f, err := s.failure(ctx, email)
+	if err != nil || f == nil {
+		return err
+	}
+	return s.Unlock(ctx, email)
+}
+
+/**
+ * Unlock clears the failure count and any lock of an account, after the
+ * owner proved control of the email or at an administrator's request.
+ */
+func (s *lockoutService) Unlock(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if err := s.Repo.DeleteFailure(ctx, email); err != nil {
+		return fmt.Errorf("database query (DeleteFailure): %w", err)
+	}
+	_ = s.Cache.Delete(ctx, lockoutFailureKey+email)
+	return nil
+}
+
+/**
+ * UnlockUser unlocks the account of a user and returns its email.
+ */
+func (s *lockoutService) UnlockUser(
+	ctx context.Context,
+	id uuid.UUID,
+) (string, error) {
+	user, err := s.UserRepo.GetUserById(ctx, id[:], nil, true)
+	if err != nil {
+		return "", fmt.Errorf("database query (GetUserById): %w", err)
+	}
+	if user == nil {
+		return "", fmt.Errorf("user not found")
+	}
+	return user.Email, s.Unlock(ctx, user.Email)
+}
+
+/**
+ * GetPolicy returns the account lockout policy in effect.
+ */
+func (s *lockoutService) GetPolicy(
+	ctx context.Context,
+) (*dto.LockoutPolicyResponse, error) {
+	p, err := s.Repo.GetPolicy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetPolicy): %w", err)
+	}
+	return &dto.LockoutPolicyResponse{
+		MaxFailures:          p.MaxFailures,
+		LockoutMinutes:       p.LockoutMinutes,
+		BackoffBaseSeconds:   p.BackoffBaseSeconds,
+		BackoffMaxSeconds:    p.BackoffMaxSeconds,
+		FailureWindowMinutes: p.FailureWindowMinutes,
+		UpdatedAt:            p.UpdatedAt,
+	}, nil
+}
+
+/**
+ * UpdatePolicy replaces the account lockout policy. Counts already
+ * running are judged by the new policy from the next attempt on.
+ */
+func (s *lockoutService) UpdatePolicy(
+	ctx context.Context,
+	req dto.LockoutPolicyRequest,
+) error {
+	if req.BackoffMaxSeconds < req.BackoffBaseSeconds {
+		return fmt.Errorf(
+			"invalid policy: backoff_max_seconds is below the base",
+		)
+	}
+
+	err := s.Repo.UpdatePolicy(ctx, &models.LockoutPolicy{
+		MaxFailures:          req.MaxFailures,
+		LockoutMinutes:       req.LockoutMinutes,
+		BackoffBaseSeconds:   req.BackoffBaseSeconds,
+		BackoffMaxSeconds:    req.BackoffMaxSeconds,
+		FailureWindowMinutes: req.FailureWindowMinutes,
+	})
+	if err != nil {
+		return fmt.Errorf("database query (UpdatePolicy): %w", err)
+	}
+	_ = s.Cache.Delete(ctx, lockoutPolicyKey)
+	return nil
+}
+
+// failure reads the failure count of an account through the cache and
+// falls back to the database, which keeps counts across cache restarts.
+func (s *lockoutService) failure(
+	ctx context.Context,
+	email string,
+) (*models.LoginFailure, error) {
+	val, ok, err := s.Cache.Get(ctx, lockoutFailureKey+email)
+	if err == nil && ok {
+		var f models.LoginFailure
+		if json.Unmarshal([]byte(val), &f) == nil {
+			return &f, nil
+		}
+	}
+
+	f, err := s.Repo.GetFailure(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetFailure): %w", err)
+	}
+	if f != nil {
+		s.cacheFailure(ctx, f)
+	}
+	return f, nil
+}
+
+// cacheFailure stores a failure count until the row itself expires.
+func (s *lockoutService) cacheFailure(
+	ctx context.Context,
+	f *models.LoginFailure,
+) {
+	ttl := time.Until(f.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+	val, err := json.Marshal(f)
+	if err != nil {
+		return
+	}
+	_ = s.Cache.Set(ctx, lockoutFailureKey+f.Email, string(val), ttl)
+}
+
+// policy returns the lockout policy through the cache. The default policy
+// applies when it cannot be read, so logins stay protected.
+func (s *lockoutService) policy(ctx context.Context) models.LockoutPolicy {
+	val, ok, err := s.Cache.Get(ctx, lockoutPolicyKey)
+	if err == nil && ok {
+		var p models.LockoutPolicy
+		if json.Unmarshal([]byte(val), &p) == nil {
+			return p
+		}
+	}
+
+	p, err := s.Repo.GetPolicy(ctx)
+	if err != nil {
+		log.Printf("[Lockout] Policy: %v", err)
+		return defaultLockoutPolicy
+	}
+	if val, err := json.Marshal(p); err == nil {
+		_ = s.Cache.Set(ctx, lockoutPolicyKey, string(val), lockoutPolicyTTL)
+	}
+	return *p
+}
+
+// backoffDelay is the wait the policy requires after the given number of
+// failures: none after the first, then the base doubling up to the max.
+func backoffDelay(policy models.LockoutPolicy, failures int) time.Duration {
+	if failures < 2 || policy.BackoffBaseSeconds <= 0 {
+		return 0
+	}
+	seconds := float64(policy.BackoffBaseSeconds) *
+		math.Pow(2, float64(failures-2))
+	seconds = math.Min(seconds, float64(policy.BackoffMaxSeconds))
+	return time.Duration(seconds) * time.Second
+}
+
+// secondsUntil rounds the time left until t up to whole seconds.
+func secondsUntil(now, t time.Time) int {
+	return max(1, int(math.Ceil(t.Sub(now).Seconds())))
+}
+
+// normalizeEmail keys failure counts case-insensitively, as emails are
+// matched on login.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
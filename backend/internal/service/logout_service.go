@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// logoutBatchSize is the number of queued notifications sent per tick.
+	logoutBatchSize = 50
+	// logoutRetryBase and logoutRetryMax bound the exponential backoff
+	// between delivery attempts.
+	logoutRetryBase = 30 * time.Second
+	logoutRetryMax  = time.Hour
+)
+
+type LogoutService interface {
+	EndSession(ctx context.Context, req dto.EndSessionRequest,
+		sessionID string) (*dto.EndSessionResult, error)
+	LogoutUser(ctx context.Context, userID uuid.UUID) error
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+type logoutService struct {
+	Repo        repository.LogoutRepository
+	SessionRepo repository.SessionRepository
+	ClientRepo  repository.ClientRepository
+	Keys        KeyStore
+	HTTPClient  *http.Client
+}
+
+func NewLogoutService(
+	repo repository.LogoutRepository,
+	sessionRepo repository.SessionRepository,
+	clientRepo repository.ClientRepository,
+	keys KeyStore,
+) LogoutService {
+	return &logoutService{
+		Repo:        repo,
+		SessionRepo: sessionRepo,
+		ClientRepo:  clientRepo,
+		Keys:        keys,
+		HTTPClient: &http.Client{
+			Timeout: LOGOUT_REQUEST_TIMEOUT * time.Second,
+			// A logout URI answers directly; redirects are not followed.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+/**
+ * EndSession handles RP-initiated logout. The id_token_hint identifies
+ * the client and must belong to the user of the browser session, which
+ * is ended, its clients notified and the refresh tokens issued under it
+ * revoked. Without a hint the session is only ended once the user has
+ * confirmed the logout. The post_logout_redirect_uri must be
+ * registered for the client; without one the user lands on the login UI.
+ */
+func (s *logoutService) EndSession(
+	ctx context.Context,
+	req dto.EndSessionRequest,
+	sessionID string,
+) (*dto.EndSessionResult, error) {
+	// 1. Client Identification
+	clientID := req.ClientID
+	var hint *models.IDTokenClaims
+	if req.IDTokenHint != "" {
+		var err error
+		hint, err = ParseIDTokenHint(req.IDTokenHint, s.Keys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: id_token_hint: %w", err)
+		}
+		if clientID != "" && clientID != hint.Audience[0] {
+			return nil, fmt.Errorf(
+				"invalid request: client_id does not match id_token_hint",
+			)
+		}
+		clientID = hint.Audience[0]
+	}
+
+	// 2. Post-Logout Redirect
+	result := &dto.EndSessionResult{
+		RedirectURL: os.Getenv("CLIENT_BASE_URL") + "/login",
+	}
+	if req.PostLogoutRedirectURI != "" {
+		redirectURI, err := s.postLogoutRedirect(
+			ctx,
+			clientID,
+			req.PostLogoutRedirectURI,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result.RedirectURL = utils.AppendQuery(redirectURI,
+			map[string]string{"state": req.State})
+	}
+
+	// 3. Session Termination
+	if sessionID == "" {
+		return result, nil
+	}
+	if hint == nil && !req.Confirmed {
+		return nil, fmt.Errorf(
+			"invalid request: logout without id_token_hint not confirmed",
+		)
+	}
+	session, err := s.SessionRepo.GetByID(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetSession): %w", err)
+	}
+
+	if hint != nil {
+		userID, err := uuid.FromBytes(session.UserId)
+		if err != nil || hint.Subject != userID.String() {
+			return nil, fmt.Errorf(
+				"invalid request: id_token_hint does not match the session",
+			)
+		}
+	}
+
+	sid := SessionSID(sessionID)
+	clients, err := s.SessionRepo.ListClients(ctx, sid)
+	if err != nil {
+		return nil, fmt.Errorf("database query (ListClients): %w", err)
+	}
+	result.FrontchannelURIs = s.notifyClients(ctx, clients)
+
+	if err := s.SessionRepo.DeleteClients(ctx, sid); err != nil {
+		return nil, fmt.Errorf("database query (DeleteClients): %w", err)
+	}
+	if err := s.SessionRepo.RevokeRefreshTokens(ctx, sid); err != nil {
+		return nil, fmt.Errorf(
+			"database query (RevokeRefreshTokens): %w",
+			err,
+		)
+	}
+	if err := s.SessionRepo.Delete(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("database query (DeleteSession): %w", err)
+	}
+
+	result.UserID = session.UserId
+	return result, nil
+}
+
+/**
+ * LogoutUser ends every session of the user and notifies each client
+ * those sessions signed in to over the back channel.
+ */
+func (s *logoutService) LogoutUser(
+	ctx context.Context,
+	userID uuid.UUID,
+) error {
+	clients, err := s.SessionRepo.ListClientsByUser(ctx, userID[:])
+	if err != nil {
+		return fmt.Errorf("database query (ListClients): %w", err)
+	}
+	s.notifyClients(ctx, clients)
+
+	if err := s.SessionRepo.DeleteByUser(ctx, userID[:]); err != nil {
+		return fmt.Errorf("database query (DeleteSessions): %w", err)
+	}
+	return nil
+}
+
+/**
+ * DeliverPending posts the due back-channel logout tokens and returns how
+ * many were delivered. Failed deliveries back off exponentially and are
+ * dropped once they expire.
+ */
+func (s *logoutService) DeliverPending(ctx context.Context) (int, error) {
+	due, err := s.Repo.ListDue(ctx, logoutBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("database query (ListDue): %w", err)
+	}
+
+	delivered := 0
+	for _, n := range due {
+		lease := time.Now().Add(2 * LOGOUT_REQUEST_TIMEOUT * time.Second)
+		claimed, err := s.Repo.Claim(ctx, n.ID, lease)
+		if err != nil {
+			return delivered, fmt.Errorf("database query (Claim): %w", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		err = s.deliver(ctx, &n)
+		if err == nil {
+			delivered++
+			if err := s.Repo.Delete(ctx, n.ID); err != nil {
+				log.Printf("[BackchannelLogout] Delete %d: %v", n.ID, err)
+			}
+			continue
+		}
+		log.Printf("[BackchannelLogout] Deliver %d: %v", n.ID, err)
+
+		attempts := n.Attempts + 1
+		next := time.Now().Add(logoutRetryDelay(attempts))
+		if !next.Before(n.ExpiresAt) {
+			log.Printf("[BackchannelLogout] Giving up on %d after %d attempts",
+				n.ID, attempts)
+			err = s.Repo.Delete(ctx, n.ID)
+		} else {
+			err = s.Repo.Reschedule(ctx, n.ID, attempts, next)
+		}
+		if err != nil {
+			log.Printf("[BackchannelLogout] Reschedule %d: %v", n.ID, err)
+		}
+	}
+	return delivered, nil
+}
+
+// postLogoutRedirect checks that the URI is registered for the client as
+// a redirect URI or its base URL.
+func (s *logoutService) postLogoutRedirect(
+	ctx context.Context,
+	clientID string,
+	redirectURI string,
+) (string, error) {
+	if clientID == "" {
+		return "", fmt.Errorf("invalid request: post_logout_redirect_uri " +
+			"requires client_id or id_token_hint")
+	}
+	cUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return "", fmt.Errorf("invalid request: client_id: %w", err)
+	}
+
+	client, err := s.ClientRepo.GetByID(ctx, cUUID[:])
+	if err != nil {
+		return "", fmt.Errorf("database query (GetClient): %w", err)
+	}
+	if redirectURI == client.BaseUrl {
+		return redirectURI, nil
+	}
+	if _, err := resolveRedirectURI(client, redirectURI); err != nil {
+		return "", fmt.Errorf(
+			"invalid request: post_logout_redirect_uri is not registered",
+		)
+	}
+	return redirectURI, nil
+}
+
+// notifyClients queues a back-channel logout for every client with a
+// logout URI and returns the front-channel logout URIs to load. The IdP's
+// own client shares the session cookie and needs neither.
+func (s *logoutService) notifyClients(
+	ctx context.Context,
+	clients []models.SessionClient,
+) []string {
+	ownClientID := os.Getenv("CLIENT_ID")
+	expiresAt := time.Now().Add(LOGOUT_RETRY_HOURS * time.Hour)
+
+	var frontchannel []string
+	for _, sc := range clients {
+		client, err := s.ClientRepo.GetByID(ctx, sc.ClientId)
+		if err != nil {
+			log.Printf("[Logout] GetClient: %v", err)
+			continue
+		}
+		clientID, _ := uuid.FromBytes(client.ID)
+		if clientID.String() == ownClientID {
+			continue
+		}
+
+		if client.LogoutUri != "" {
+			err = s.Repo.Enqueue(ctx, &models.LogoutNotification{
+				ClientId:  client.ID,
+				UserId:    sc.UserId,
+				Sid:       sc.Sid,
+				ExpiresAt: expiresAt,
+			})
+			if err != nil {
+				log.Printf("[Logout] Enqueue: %v", err)
+			}
+		}
+		if client.FrontchannelLogoutUri != "" {
+			frontchannel = append(frontchannel, utils.AppendQuery(
+				client.FrontchannelLogoutUri,
+				map[string]string{
+					"iss": os.Getenv("CLIENT_BASE_URL"),
+					"sid": sc.Sid,
+				},
+			))
+		}
+	}
+	return frontchannel
+}
+
+// deliver posts a logout token for the notification to the client's
+// back-channel logout URI.
+func (s *logoutService) deliver(
+	ctx context.Context,
+	n *models.LogoutNotification,
+) error {
+	client, err := s.ClientRepo.GetByID(ctx, n.ClientId)
+	if err != nil {
+		return fmt.Errorf("database query (GetClient): %w", err)
+	}
+	if client.LogoutUri == "" {
+		return nil
+	}
+
+	userID, err := uuid.FromBytes(n.UserId)
+	if err != nil {
+		return fmt.Errorf("uuid parse: %w", err)
+	}
+	token, err := GenerateLogoutToken(
+		s.Keys.ActiveKey(client.TokenSigningAlg),
+		client,
+		userID.String(),
+		n.Sid,
+	)
+	if err != nil {
+		return fmt.Errorf("token generation: %w", err)
+	}
+
+	form := url.Values{"logout_token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		client.LogoutUri, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("logout request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("logout request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("logout request: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// logoutRetryDelay is the wait before the given delivery attempt.
+func logoutRetryDelay(attempts int) time.Duration {
+	delay := logoutRetryBase
+	for i := 1; i < attempts && delay < logoutRetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, logoutRetryMax)
+}
+
+/**
+ * StartBackchannelLogout periodically delivers the queued back-channel
+ * logout tokens until the context is cancelled.
+ */
+func StartBackchannelLogout(
+	ctx context.Context,
+	logout LogoutService,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		log.Printf("[BackchannelLogout] Initialized")
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := logout.DeliverPending(ctx); err != nil {
+					log.Printf("[BackchannelLogout] %v", err)
+				}
+			case <-ctx.Done():
+				log.Printf("[BackchannelLogout] Shutting down")
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/google/uuid"
+)
+
+// MagicLinkLogin is the user a redeemed magic link signs in, and the
+// authorization request the login continues. AuthorizeQuery is the whole
+// pending request when the link carries one.
+type MagicLinkLogin struct {
+	UserID         uuid.UUID
+	Email          string
+	ClientID       string
+	RedirectURI    string
+	AuthorizeQuery string
+}
+
+type MagicLinkService interface {
+	RequestLink(ctx context.Context,
+		email, clientID, redirectURI, authorizeQuery string) error
+	ConsumeLink(ctx context.Context, token string) (*MagicLinkLogin, error)
+}
+
+type magicLinkService struct {
+	Repo       repository.MagicLinkRepository
+	UserRepo   repository.UserRepository
+	ClientRepo repository.ClientRepository
+}
+
+func NewMagicLinkService(
+	repo repository.MagicLinkRepository,
+	userRepo repository.UserRepository,
+	clientRepo repository.ClientRepository,
+) MagicLinkService {
+	return &magicLinkService{
+		Repo:       repo,
+		UserRepo:   userRepo,
+		ClientRepo: clientRepo,
+	}
+}
+
+/**
+ * RequestLink emails a single-use sign-in link to the user of the email,
+ * for a client that allows magic-link login. Unknown and suspended
+ * accounts get no email but the same answer, so the endpoint does not
+ * reveal which emails are registered; the email is sent in the background
+ * for the same reason. The pending authorize query of the asking browser
+ * is stored with the link, so the login continues the same request in
+ * whichever browser the link is opened.
+ */
+func (s *magicLinkService) RequestLink(
+	ctx context.Context,
+	email, clientID, redirectURI, authorizeQuery string,
+) error {
+	client, err := s.allowedClient(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if _, err := resolveRedirectURI(client, redirectURI); err != nil {
+		return err
+	}
+
+	email = normalizeEmail(email)
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("database query (GetUserByEmail): %w", err)
+	}
+	if user == nil || user.Status.IsRestricted() {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomString(SECRET_ENTROPY)
+	if err != nil {
+		return fmt.Errorf("magic link: generate token: %w", err)
+	}
+
+	err = s.Repo.CreateToken(ctx, &models.MagicLinkToken{
+		TokenHash:   utils.HashToken(token),
+		Email:       user.Email,
+		ClientID:    client.ID,
+		RedirectURI: redirectURI,
+		AuthorizeQuery: pendingAuthorizeQuery(
+			authorizeQuery,
+			clientID,
+			redirectURI,
+		),
+		ExpiresAt: time.Now().Add(MAGIC_LINK_TTL * time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("database query (CreateToken): %w", err)
+	}
+
+	go func() {
+		err := utils.SendMagicLinkEmail(
+			user.Email,
+			token,
+			client.ClientName,
+			MAGIC_LINK_TTL/60,
+		)
+		if err != nil {
+			log.Printf("[MagicLinkService] Send Email: %v", err)
+		}
+	}()
+	return nil
+}
+
+/**
+ * ConsumeLink redeems a magic-link token. It fails when the token is
+ * unknown, expired or already used, when the client no longer allows
+ * magic-link login, or when the account was suspended since the link was
+ * sent.
+ */
+func (s *magicLinkService) ConsumeLink(
+	ctx context.Context,
+	token string,
+) (*MagicLinkLogin, error) {
+	if token == "" {
+		return nil, errors.New("invalid magic link")
+	}
+
+	link, err := s.Repo.ConsumeToken(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("database query (ConsumeToken): %w", err)
+	}
+	if link == nil {
+		return nil, errors.New("invalid magic link")
+	}
+
+	clientUUID, err := uuid.FromBytes(link.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("uuid parse: %w", err)
+	}
+	if _, err := s.allowedClient(ctx, clientUUID.String()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.GetUserByEmail(ctx, link.Email)
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetUserByEmail): %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("invalid magic link")
+	}
+	if user.Status.IsRestricted() {
+		return nil, errors.New("user is suspended")
+	}
+
+	userID, err := uuid.FromBytes(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("uuid parse: %w", err)
+	}
+
+	return &MagicLinkLogin{
+		UserID:         userID,
+		Email:          user.Email,
+		ClientID:       clientUUID.String(),
+		RedirectURI:    link.RedirectURI,
+		AuthorizeQuery: link.AuthorizeQuery,
+	}, nil
+}
+
+// pendingAuthorizeQuery keeps a remembered authorize query only when it
+// belongs to the client and redirect URI the link is asked for.
+func pendingAuthorizeQuery(saved, clientID, redirectURI string) string {
+	query, err := url.ParseQuery(saved)
+	if err != nil || !strings.EqualFold(query.Get("client_id"), clientID) {
+		return ""
+	}
+	if redirectURI != "" && query.Get("redirect_uri") != redirectURI {
+		return ""
+	}
+	return query.Encode()
+}
+
+// allowedClient loads a client that has magic-link login turned on.
+func (s *magicLinkService) allowedClient(
+	ctx context.Context,
+	clientID string,
+) (*models.Client, error) {
+	clientUUID, err := uuid.Parse(clientID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client: %w", err)
+	}
+
+	client, err := s.ClientRepo.GetByID(ctx, clientUUID[:])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("invalid client: not found")
+		}
+		return nil, fmt.Errorf("database query (GetByID): %w", err)
+	}
+	if !client.AllowMagicLink {
+		return nil, errors.New("magic link not allowed for this client")
+	}
+	return client, nil
+}
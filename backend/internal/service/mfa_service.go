@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/url"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
@@ -28,10 +29,17 @@ type MFAService interface {
 		id []byte, userID []byte) error
 	// HasTOTP reports whether the user has a registered TOTP.
 	HasTOTP(ctx context.Context, userID []byte) (bool, error)
+	// RewrapSecrets moves every TOTP secret onto the active encryption
+	// key version and returns how many were rewritten.
+	RewrapSecrets(ctx context.Context) (int, error)
 }
 
+// rewrapBatchSize is the number of secrets re-encrypted per query.
+const rewrapBatchSize = 100
+
 type mfaService struct {
 	mfaRepo repository.MFARepository
+	cipher  *utils.EnvelopeCipher
 }
 
 func (s *mfaService) GenerateTOTPSetup(ctx context.Context,
@@ -62,10 +70,11 @@ func (s *mfaService) FinalizeTOTP(ctx context.Context, userID []byte,
 		return nil, fmt.Errorf("invalid verification code")
 	}
 
-	encryptedSecret, err := utils.Encrypt([]byte(secret))
+	encryptedSecret, err := s.cipher.Seal([]byte(secret))
 	if err != nil {
 		return nil, fmt.Errorf("[MFAService] Encrypt: %w", err)
 	}
+	keyVersion := s.cipher.ActiveVersion()
 
 	id := uuid.New()
 	auth := &models.UserAuthenticator{
@@ -74,6 +83,7 @@ func (s *mfaService) FinalizeTOTP(ctx context.Context, userID []byte,
 		Type:            "totp",
 		Name:            name,
 		SecretEncrypted: encryptedSecret,
+		KeyVersion:      &keyVersion,
 	}
 
 	err = s.mfaRepo.InsertAuthenticator(ctx, auth)
@@ -92,10 +102,11 @@ func (s *mfaService) SetupTOTP(ctx context.Context, userID []byte,
 		return "", nil, fmt.Errorf("[MFAService] Secret Gen: %w", err)
 	}
 
-	encryptedSecret, err := utils.Encrypt([]byte(secret))
+	encryptedSecret, err := s.cipher.Seal([]byte(secret))
 	if err != nil {
 		return "", nil, fmt.Errorf("[MFAService] Encrypt: %w", err)
 	}
+	keyVersion := s.cipher.ActiveVersion()
 
 	id := uuid.New()
 	auth := &models.UserAuthenticator{
@@ -104,6 +115,7 @@ func (s *mfaService) SetupTOTP(ctx context.Context, userID []byte,
 		Type:            "totp",
 		Name:            "TOTP Authenticator",
 		SecretEncrypted: encryptedSecret,
+		KeyVersion:      &keyVersion,
 	}
 
 	err = s.mfaRepo.InsertAuthenticator(ctx, auth)
@@ -169,7 +181,7 @@ func (s *mfaService) VerifyCode(ctx context.Context, userID []byte,
 		}
 
 		for _, auth := range auths {
-			decrypted, err := utils.Decrypt(auth.SecretEncrypted)
+			decrypted, err := s.decryptSecret(&auth)
 			if err != nil {
 				continue
 			}
@@ -249,6 +261,75 @@ func (s *mfaService) HasTOTP(
 	return s.mfaRepo.HasTOTP(ctx, userID)
 }
 
-func NewMFAService(mfaRepo repository.MFARepository) MFAService {
-	return &mfaService{mfaRepo: mfaRepo}
+/**
+ * RewrapSecrets walks the TOTP secrets not yet on the active key version.
+ * Envelopes only get their data key re-wrapped; secrets from before
+ * envelope encryption are decrypted with the legacy key and sealed anew.
+ * Secrets that cannot be decrypted are skipped and logged.
+ */
+func (s *mfaService) RewrapSecrets(ctx context.Context) (int, error) {
+	version := s.cipher.ActiveVersion()
+	if version == 0 {
+		return 0, fmt.Errorf("[MFAService] Rewrap: no encryption key")
+	}
+
+	count := 0
+	afterID := []byte{}
+	for {
+		auths, err := s.mfaRepo.GetSecretsToRewrap(ctx, version, afterID,
+			rewrapBatchSize)
+		if err != nil {
+			return count, fmt.Errorf("[MFAService] DB Fetch: %w", err)
+		}
+
+		for _, auth := range auths {
+			var secret []byte
+			if auth.KeyVersion == nil {
+				var plain []byte
+				plain, err = s.cipher.OpenLegacy(auth.SecretEncrypted)
+				if err == nil {
+					secret, err = s.cipher.Seal(plain)
+				}
+			} else {
+				secret, _, err = s.cipher.Rewrap(auth.SecretEncrypted)
+			}
+			if err != nil {
+				log.Printf("[MFAService] Rewrap %x: %v", auth.ID, err)
+				continue
+			}
+
+			err = s.mfaRepo.UpdateSecret(ctx, auth.ID, secret, version)
+			if err != nil {
+				return count, fmt.Errorf("[MFAService] DB Update: %w", err)
+			}
+			count++
+		}
+
+		if len(auths) < rewrapBatchSize {
+			return count, nil
+		}
+		afterID = auths[len(auths)-1].ID
+	}
+}
+
+// decryptSecret opens a stored TOTP secret. Rows without a key version
+// predate envelope encryption.
+func (s *mfaService) decryptSecret(
+	auth *models.UserAuthenticator,
+) ([]byte, error) {
+	if auth.KeyVersion == nil {
+		return s.cipher.OpenLegacy(auth.SecretEncrypted)
+	}
+	return s.cipher.Open(auth.SecretEncrypted)
+}
+
+/**
+ * NewMFAService creates the MFA service. TOTP secrets are sealed with the
+ * envelope cipher's active key version.
+ */
+func NewMFAService(
+	mfaRepo repository.MFARepository,
+	cipher *utils.EnvelopeCipher,
+) MFAService {
+	return &mfaService{mfaRepo: mfaRepo, cipher: cipher}
 }
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+)
+
+// SupportedScopes lists the scopes the authorization endpoint will grant.
+var SupportedScopes = []string{
+	models.ScopeOpenID,
+	models.ScopeProfile,
+	models.ScopeEmail,
+}
+
+// SupportedClaims lists the claims that may appear in an ID token.
+var SupportedClaims = []string{
+	"sub", "iss", "aud", "exp", "iat", "azp", "nonce", "auth_time",
+	"amr", "acr", "sid", "at_hash", "name", "given_name", "middle_name",
+	"family_name", "email", "email_verified", "role", "account_type",
+}
+
+// BackendBaseURL returns the public base URL of the API.
+func BackendBaseURL() string {
+	backendURL := os.Getenv("VITE_BACKEND_URL")
+	if backendURL == "" {
+		backendURL = "http://localhost:8080"
+	}
+	return strings.TrimRight(backendURL, "/")
+}
+
+// BuildOpenIDConfiguration assembles the OIDC discovery document.
+func BuildOpenIDConfiguration() *dto.OpenIDConfiguration {
+	backendURL := BackendBaseURL()
+
+	return &dto.OpenIDConfiguration{
+		Issuer:                os.Getenv("CLIENT_BASE_URL"),
+		AuthorizationEndpoint: backendURL + "/api/v1/auth/authorize",
+		TokenEndpoint:         backendURL + "/api/v1/auth/token",
+		UserInfoEndpoint:      backendURL + "/api/v1/userinfo",
+		JWKSURI:               backendURL + "/.well-known/jwks.json",
+		IntrospectionEndpoint: backendURL + "/api/v1/auth/introspect",
+		RevocationEndpoint:    backendURL + "/api/v1/auth/revoke",
+		EndSessionEndpoint:    backendURL + "/api/v1/auth/end-session",
+		ScopesSupported:       SupportedScopes,
+		ResponseTypesSupported: []string{
+			"code",
+		},
+		ResponseModesSupported: []string{
+			"query",
+		},
+		GrantTypesSupported: []string{
+			string(models.GrantAuthCode),
+			string(models.GrantRefreshToken),
+			string(models.GrantClientCredentials),
+			string(models.GrantDeviceCode),
+		},
+		SubjectTypesSupported: []string{
+			"public",
+		},
+		IDTokenSigningAlgValuesSupported: models.SigningAlgorithms,
+		TokenEndpointAuthMethodsSupported: []string{
+			"client_secret_basic",
+			"client_secret_post",
+			"none",
+		},
+		CodeChallengeMethodsSupported: []string{
+			models.PKCEMethodS256,
+			models.PKCEMethodPlain,
+		},
+		ClaimsSupported:             SupportedClaims,
+		ACRValuesSupported:          models.ACRValues,
+		FrontchannelLogoutSupported: true,
+		FrontchannelLogoutSession:   true,
+		BackchannelLogoutSupported:  true,
+		BackchannelLogoutSession:    true,
+		PushedAuthRequestEndpoint:   backendURL + "/api/v1/auth/par",
+		DeviceAuthorizationEndpoint: backendURL +
+			"/api/v1/auth/device_authorization",
+	}
+}
+
+// NormalizeScope drops unsupported and duplicate scope values while
+// keeping the order in which the client requested them.
+func NormalizeScope(raw string) string {
+	var granted []string
+	for _, scope := range strings.Fields(raw) {
+		if slices.Contains(SupportedScopes, scope) &&
+			!slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return strings.Join(granted, " ")
+}
+
+// NormalizeScopeList drops duplicate values from a space-delimited scope
+// list. Unlike NormalizeScope it accepts any scope value, which lets
+// administrators register API scopes for machine clients.
+func NormalizeScopeList(raw string) string {
+	var scopes []string
+	for _, scope := range strings.Fields(raw) {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.Join(scopes, " ")
+}
+
+// GrantableScopes limits the requested scopes to those the client is
+// allowed. An empty request receives every allowed scope. The second
+// return value is false when scopes were requested but none are allowed.
+func GrantableScopes(requested string, allowed string) (string, bool) {
+	if strings.TrimSpace(requested) == "" {
+		return NormalizeScopeList(allowed), true
+	}
+
+	var granted []string
+	for _, scope := range strings.Fields(NormalizeScopeList(requested)) {
+		if HasScope(allowed, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return strings.Join(granted, " "), len(granted) > 0
+}
+
+// HasScope reports whether a space-delimited scope string contains scope.
+func HasScope(scopes string, scope string) bool {
+	return slices.Contains(strings.Fields(scopes), scope)
+}
+
+// displayName joins the non-empty name parts of a user.
+func displayName(user *models.User) string {
+	return strings.Join(strings.Fields(strings.Join([]string{
+		user.FirstName,
+		user.MiddleName,
+		user.LastName,
+		user.NameSuffix,
+	}, " ")), " ")
+}
+
+// sessionAuthContext reports how the user behind a session authenticated:
+// the methods used, the authentication context class and the login time.
+// A verified second factor keeps the session at 2fa for MFA_ASSURANCE_TTL
+// minutes, after which clients requiring 2fa ask for a step-up and the
+// amr no longer claims "mfa". Sessions created before methods were
+// recorded passed a second factor at login.
+func sessionAuthContext(
+	session *models.IdPSession,
+) ([]string, string, time.Time) {
+	authTime := session.CreatedAt
+	if session.AuthTime.Valid {
+		authTime = session.AuthTime.Time
+	}
+
+	methods := strings.Fields(session.AMR)
+	mfaTime := session.MFATime
+	if len(methods) == 0 {
+		methods = []string{models.AMRPassword, models.AMROTP}
+		mfaTime.Time, mfaTime.Valid = session.CreatedAt, true
+	}
+
+	acr := models.ACRSingleFactor
+	if mfaTime.Valid &&
+		time.Since(mfaTime.Time) < MFA_ASSURANCE_TTL*time.Minute {
+		acr = models.ACRMultiFactor
+	}
+	if len(methods) > 1 && acr == models.ACRMultiFactor {
+		methods = append(methods, models.AMRMultiFactor)
+	}
+	return methods, acr, authTime
+}
+
+// SessionSID derives the sid claim of a session. The session ID is the
+// secret held in the browser cookie, so clients only ever see its hash.
+func SessionSID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// requiredACR combines the client's minimum authentication context class
+// with the acr_values of the request. Any requested value is acceptable,
+// so the weakest known one applies.
+func requiredACR(minACR string, acrValues string) string {
+	required := slices.Index(models.ACRValues, minACR)
+	requested := -1
+	for _, value := range strings.Fields(acrValues) {
+		i := slices.Index(models.ACRValues, value)
+		if i >= 0 && (requested < 0 || i < requested) {
+			requested = i
+		}
+	}
+
+	required = max(required, requested)
+	if required < 0 {
+		return ""
+	}
+	return models.ACRValues[required]
+}
+
+// acrSatisfies reports whether the class acr meets the required class.
+func acrSatisfies(acr string, required string) bool {
+	return required == "" ||
+		slices.Index(models.ACRValues, acr) >=
+			slices.Index(models.ACRValues, required)
+}
+
+// ParsePrompt splits the prompt parameter of an authorization request.
+// "none" cannot be combined with any other value.
+func ParsePrompt(raw string) ([]string, error) {
+	prompts := strings.Fields(raw)
+	if slices.Contains(prompts, models.PromptNone) && len(prompts) > 1 {
+		return nil, fmt.Errorf(
+			"invalid request: prompt=none cannot be combined",
+		)
+	}
+	return prompts, nil
+}
+
+// reauthenticationRequired reports whether the request asks for a fresh
+// login, through prompt=login or select_account, a max_age the session
+// has outlived, or a remembered auth_after the session predates.
+func reauthenticationRequired(
+	session *models.IdPSession,
+	prompts []string,
+	maxAge string,
+	authAfter string,
+) (bool, error) {
+	if slices.Contains(prompts, models.PromptLogin) ||
+		slices.Contains(prompts, models.PromptSelectAccount) {
+		return true, nil
+	}
+	if authAfter != "" {
+		after, err := strconv.ParseInt(authAfter, 10, 64)
+		if err != nil || session.CreatedAt.Unix() < after {
+			return true, nil
+		}
+	}
+	if maxAge == "" {
+		return false, nil
+	}
+
+	seconds, err := strconv.Atoi(maxAge)
+	if err != nil || seconds < 0 {
+		return false, fmt.Errorf(
+			"invalid request: max_age must be a non-negative integer",
+		)
+	}
+	age := time.Since(session.CreatedAt)
+	return age > time.Duration(seconds)*time.Second, nil
+}
@@ -15,6 +15,8 @@ import (
 type OTPService interface {
 	SendOTP(ctx context.Context, email string) (int, bool, error)
 	VerifyOTP(ctx context.Context, email, code string) error
+	IssueResetToken(ctx context.Context, email string) (string, error)
+	ConsumeResetToken(ctx context.Context, email, token string) error
 }
 
 type otpService struct {
@@ -109,6 +111,60 @@ func (s *otpService) VerifyOTP(ctx context.Context,
 	return nil
 }
 
+/**
+ * IssueResetToken mints a single-use token that lets the holder reset the
+ * password of the email whose OTP was just verified. The caller must only
+ * invoke it after VerifyOTP succeeds; the plain token is returned once and
+ * only its hash is stored.
+ */
+func (s *otpService) IssueResetToken(ctx context.Context,
+	email string,
+) (string, error) {
+	token, err := utils.GenerateRandomString(SECRET_ENTROPY)
+	if err != nil {
+		return "", fmt.Errorf("[OTPService] Generate reset token: %w", err)
+	}
+
+	err = s.otpRepo.CreateResetToken(ctx, &models.PasswordResetToken{
+		TokenHash: utils.HashToken(token),
+		Email:     email,
+		Purpose:   models.PurposePasswordReset,
+		ExpiresAt: time.Now().Add(RESET_TOKEN_TTL * time.Second),
+	})
+	if err != nil {
+		return "", fmt.Errorf("[OTPService] Save reset token: %w", err)
+	}
+
+	return token, nil
+}
+
+/**
+ * ConsumeResetToken redeems a reset token for the email. It fails when the
+ * token is unknown, expired, already used or was minted for another email.
+ */
+func (s *otpService) ConsumeResetToken(ctx context.Context,
+	email, token string,
+) error {
+	if token == "" {
+		return errors.New("invalid reset token")
+	}
+
+	ok, err := s.otpRepo.ConsumeResetToken(
+		ctx,
+		utils.HashToken(token),
+		email,
+		models.PurposePasswordReset,
+	)
+	if err != nil {
+		return fmt.Errorf("[OTPService] Consume reset token: %w", err)
+	}
+	if !ok {
+		return errors.New("invalid reset token")
+	}
+
+	return nil
+}
+
 func NewOTPService(orp repository.OTPRepository,
 	ms MailService,
 ) OTPService {
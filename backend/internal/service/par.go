@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/google/uuid"
+)
+
+// RequestURIPrefix starts every request_uri issued by the PAR endpoint
+// (RFC 9126 section 2.2).
+const RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+/**
+ * PushAuthorizationRequest validates the parameters of an authorization
+ * request and stores them ahead of the redirect (RFC 9126), so they never
+ * travel in the browser's URL. Confidential clients always authenticate
+ * with their secret; only public clients may skip it, and they must
+ * protect the request with PKCE. The returned request_uri stands in for
+ * the parameters on /auth/authorize.
+ */
+func (s *authService) PushAuthorizationRequest(
+	ctx context.Context,
+	req dto.PushedAuthorizationRequest,
+) (*dto.PushedAuthorizationResponse, error) {
+	clientUUID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("uuid parse: %w", err)
+	}
+
+	// 1. Authenticate Client
+	client, err := s.ClientRepo.GetByID(ctx, clientUUID[:])
+	if err != nil {
+		return nil, fmt.Errorf("client verification: %w", err)
+	}
+	if !client.PublicClient || req.ClientSecret != "" {
+		err = s.verifyClientSecret(ctx, req.ClientID, req.ClientSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !slices.Contains(client.Grants, string(models.GrantAuthCode)) {
+		return nil, fmt.Errorf(
+			"unauthorized client: authorization_code grant not allowed",
+		)
+	}
+
+	// 2. Request Validation
+	// The checks /auth/authorize would make are made now, while the
+	// client can still be told directly.
+	if req.RequestURI != "" {
+		return nil, fmt.Errorf("invalid request: request_uri cannot be pushed")
+	}
+	if req.ResponseType != "" && req.ResponseType != "code" {
+		return nil, fmt.Errorf("invalid request: unsupported response_type")
+	}
+	if _, err := resolveRedirectURI(client, req.RedirectURI); err != nil {
+		return nil, err
+	}
+	if _, err := ParsePrompt(req.Prompt); err != nil {
+		return nil, err
+	}
+	_, err = validateCodeChallenge(
+		client,
+		req.CodeChallenge,
+		req.CodeChallengeMethod,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Storage
+	params, err := json.Marshal(req.AuthorizeRequest)
+	if err != nil {
+		return nil, fmt.Errorf("request encoding: %w", err)
+	}
+	requestID, err := utils.GenerateRandomString(SECRET_ENTROPY)
+	if err != nil {
+		return nil, fmt.Errorf("request_uri generation: %w", err)
+	}
+
+	err = s.Repo.StorePushedRequest(ctx, &models.PushedAuthorizationRequest{
+		RequestID: requestID,
+		ClientId:  clientUUID[:],
+		Params:    string(params),
+		ExpiresAt: time.Now().Add(PAR_REQUEST_TTL * time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("database query (StorePushedRequest): %w", err)
+	}
+
+	return &dto.PushedAuthorizationResponse{
+		RequestURI: RequestURIPrefix + requestID,
+		ExpiresIn:  PAR_REQUEST_TTL,
+	}, nil
+}
+
+/**
+ * ResolveAuthorizeRequest replaces an authorization request made with a
+ * request_uri by the parameters the client pushed. Other parameters sent
+ * alongside request_uri are ignored, except the auth_after the IdP
+ * remembered across the login page. The first resolve marks the request
+ * used by the given browser binding; only that browser may resolve it
+ * again, which it does after the login page, until a code is issued.
+ */
+func (s *authService) ResolveAuthorizeRequest(
+	ctx context.Context,
+	req dto.AuthorizeRequest,
+	binding string,
+) (dto.AuthorizeRequest, error) {
+	clientUUID, err := uuid.Parse(req.ClientID)
+	if err != nil {
+		return req, fmt.Errorf("uuid parse: %w", err)
+	}
+
+	requestID, ok := strings.CutPrefix(req.RequestURI, RequestURIPrefix)
+	if !ok {
+		return req, fmt.Errorf("invalid request: malformed request_uri")
+	}
+	par, err := s.Repo.GetPushedRequest(ctx, requestID)
+	if err != nil {
+		return req, fmt.Errorf("database query (GetPushedRequest): %w", err)
+	}
+	if par == nil {
+		return req, fmt.Errorf("invalid request: request_uri expired")
+	}
+	if !bytes.Equal(par.ClientId, clientUUID[:]) {
+		return req, fmt.Errorf(
+			"invalid request: request_uri was issued to another client",
+		)
+	}
+	if binding == "" {
+		return req, fmt.Errorf("invalid request: missing browser binding")
+	}
+	if !par.UsedBy.Valid {
+		claimed, err := s.Repo.ClaimPushedRequest(ctx, requestID, binding)
+		if err != nil {
+			return req, fmt.Errorf(
+				"database query (ClaimPushedRequest): %w",
+				err,
+			)
+		}
+		if !claimed {
+			return req, fmt.Errorf("invalid request: request_uri already used")
+		}
+	} else if subtle.ConstantTimeCompare(
+		[]byte(par.UsedBy.String),
+		[]byte(binding),
+	) != 1 {
+		return req, fmt.Errorf("invalid request: request_uri already used")
+	}
+
+	var pushed dto.AuthorizeRequest
+	if err := json.Unmarshal([]byte(par.Params), &pushed); err != nil {
+		return req, fmt.Errorf("request decoding: %w", err)
+	}
+	pushed.ClientID = req.ClientID
+	pushed.RequestURI = req.RequestURI
+	if req.AuthAfter != "" {
+		pushed.AuthAfter = req.AuthAfter
+	}
+	return pushed, nil
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
 	"github.com/go-webauthn/webauthn/protocol"
@@ -54,6 +55,14 @@ type PasskeyService interface {
 	) error
 	// HasPasskey reports whether the user has a registered passkey.
 	HasPasskey(ctx context.Context, email string) (bool, error)
+	// BeginLogin starts a usernameless login with a discoverable passkey.
+	BeginLogin(
+		ctx context.Context, conditional bool, r *http.Request,
+	) ([]byte, error)
+	// FinishLogin verifies the assertion and returns the signed-in user.
+	FinishLogin(
+		ctx context.Context, r *http.Request,
+	) (*dto.UserResponse, error)
 }
 
 type memorySession struct {
@@ -219,7 +228,13 @@ func (s *passkeyService) buildPasskeyUser(
 			"[PasskeyService] User Lookup: %w", err,
 		)
 	}
+	return s.passkeyUserFor(ctx, user)
+}
 
+// passkeyUserFor returns the PasskeyUser of a loaded user.
+func (s *passkeyService) passkeyUserFor(
+	ctx context.Context, user *dto.UserResponse,
+) (*PasskeyUser, error) {
 	uid, err := uuid.Parse(user.ID)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -469,6 +484,117 @@ func (s *passkeyService) HasPasskey(
 	return s.passkeyRepo.HasPasskey(ctx, uid[:])
 }
 
+// BeginLogin generates a challenge for any discoverable passkey of the
+// RP, so the browser offers the user's passkeys without asking for an
+// email. A conditional login is offered through the autofill of the
+// email field. User verification is required, as the passkey replaces
+// the password.
+func (s *passkeyService) BeginLogin(
+	ctx context.Context, conditional bool, r *http.Request,
+) ([]byte, error) {
+	rpid := rpidFromRequest(r)
+	wa, err := s.getWebAuthn(rpid)
+	if err != nil {
+		return nil, err
+	}
+
+	mediation := protocol.MediationDefault
+	if conditional {
+		mediation = protocol.MediationConditional
+	}
+	assertion, session, err := wa.BeginDiscoverableMediatedLogin(
+		mediation,
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Begin Login: %w", err,
+		)
+	}
+
+	// The ceremony has no email yet; the challenge that the browser
+	// signs and echoes back identifies it instead.
+	_ = s.setSessionData("login_"+session.Challenge, session)
+
+	raw, err := json.Marshal(assertion)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Marshal Challenge: %w", err,
+		)
+	}
+	return raw, nil
+}
+
+// FinishLogin resolves the user from the user handle of the discoverable
+// credential, verifies the assertion and updates the sign count.
+func (s *passkeyService) FinishLogin(
+	ctx context.Context, r *http.Request,
+) (*dto.UserResponse, error) {
+	parsed, err := protocol.ParseCredentialRequestResponse(r)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Finish Login: %w", err,
+		)
+	}
+
+	session, err := s.popSessionData(
+		"login_" + parsed.Response.CollectedClientData.Challenge,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Finish Login: %w", err,
+		)
+	}
+
+	rpid := rpidFromRequest(r)
+	wa, err := s.getWebAuthn(rpid)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *dto.UserResponse
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		uid, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user handle")
+		}
+		me, err := s.userService.GetMe(ctx, uid)
+		if err != nil {
+			return nil, err
+		}
+		user, err = s.userService.GetUserByEmail(ctx, me.Email)
+		if err != nil {
+			return nil, err
+		}
+		return s.passkeyUserFor(ctx, user)
+	}
+
+	_, cred, err := wa.ValidatePasskeyLogin(handler, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Finish Login: %w", err,
+		)
+	}
+
+	if models.UserStatus(user.Status).IsRestricted() {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Finish Login: user is suspended",
+		)
+	}
+
+	err = s.passkeyRepo.UpdatePasskeySignCount(
+		ctx,
+		cred.ID,
+		cred.Authenticator.SignCount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"[PasskeyService] Update Sign Count: %w", err,
+		)
+	}
+	return user, nil
+}
+
 // getWebAuthn returns a customized WebAuthn instance for the given RPID.
 func (s *passkeyService) getWebAuthn(
 	rpid string,
@@ -1,5 +1,7 @@
 package service
 
+import "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+
 type ServiceContainer struct {
 	ClientService            ClientService
 	RoleService              RoleService
@@ -15,4 +17,12 @@ type ServiceContainer struct {
 	PasskeyService           PasskeyService
 	MetricsService           MetricsService
 	ReportService            ReportService
+	TokenDenylist            TokenDenylist
+	KeyStore                 KeyStore
+	ConsentService           ConsentService
+	LogoutService            LogoutService
+	DeviceService            DeviceService
+	LockoutService           LockoutService
+	MagicLinkService         MagicLinkService
+	RateLimiter              cache.RateLimiter
 }
@@ -1,10 +1,13 @@
 package service
 
 import (
-	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
@@ -12,9 +15,19 @@ import (
 	"github.com/google/uuid"
 )
 
-// GenerateToken creates a signed OIDC JWT using RS256.
-// It accepts the pre-loaded privateKey object for maximum performance.
-func GenerateToken(privateKey *rsa.PrivateKey,
+// Token typ headers. Every token the IdP signs shares the same keys, so
+// the typ is what keeps an ID token or a logout token from passing as an
+// access token (RFC 9068, RFC 8725 section 3.11).
+const (
+	AccessTokenType     = "at+jwt"
+	IDTokenType         = "JWT"
+	LogoutTokenType     = "logout+jwt"
+	MFAPendingTokenType = "mfa-pending+jwt"
+)
+
+// GenerateToken creates a signed OIDC JWT with the key's algorithm.
+// The kid header names the signing key so verifiers can pick it from JWKS.
+func GenerateToken(key *SigningKey,
 	client *models.Client, claims models.UserClaims,
 ) (string, error) {
 	now := time.Now()
@@ -26,6 +39,12 @@ func GenerateToken(privateKey *rsa.PrivateKey,
 
 	claims.AuthorizedParty = clientIDStr.String()
 
+	// Machine tokens carry no user, so the client is the subject.
+	subject := claims.UserID
+	if subject == "" {
+		subject = claims.AuthorizedParty
+	}
+
 	ttlMinutes := client.AccessTokenTTL
 	if ttlMinutes <= 0 {
 		ttlMinutes = DefaultAccessTokenTTL
@@ -33,20 +52,21 @@ func GenerateToken(privateKey *rsa.PrivateKey,
 	duration := time.Duration(ttlMinutes) * time.Minute
 
 	claims.RegisteredClaims = jwt.RegisteredClaims{
-		Subject:   claims.ID,
+		Subject:   subject,
 		Issuer:    os.Getenv("CLIENT_BASE_URL"),
 		Audience:  jwt.ClaimStrings{client.BaseUrl},
 		ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
 		IssuedAt:  jwt.NewNumericDate(now),
 		NotBefore: jwt.NewNumericDate(now),
-		ID:        fmt.Sprintf("%d", now.UnixNano()),
+		ID:        uuid.NewString(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token := jwt.NewWithClaims(key.Method(), claims)
 
-	token.Header["kid"] = os.Getenv("KEY_ID")
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = AccessTokenType
 
-	signedToken, err := token.SignedString(privateKey)
+	signedToken, err := signToken(token, key)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT: %w", err)
 	}
@@ -54,8 +74,149 @@ func GenerateToken(privateKey *rsa.PrivateKey,
 	return signedToken, nil
 }
 
-func ValidateToken(token string, publicKey *rsa.PublicKey) (bool, error) {
-	parsedToken, err := GetParsedToken(token, publicKey)
+// GenerateIDToken creates a signed OIDC ID token for the client. The
+// audience is the client ID and at_hash binds it to the access token.
+func GenerateIDToken(key *SigningKey,
+	client *models.Client, claims models.IDTokenClaims, accessToken string,
+) (string, error) {
+	now := time.Now()
+
+	clientIDStr, err := uuid.FromBytes(client.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get uuid from client bytes: %v", err)
+	}
+
+	claims.AuthorizedParty = clientIDStr.String()
+	if accessToken != "" {
+		claims.AccessTokenHash = accessTokenHash(accessToken, key.Algorithm)
+	}
+
+	ttlMinutes := client.AccessTokenTTL
+	if ttlMinutes <= 0 {
+		ttlMinutes = DefaultAccessTokenTTL
+	}
+	duration := time.Duration(ttlMinutes) * time.Minute
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Subject:   claims.Subject,
+		Issuer:    os.Getenv("CLIENT_BASE_URL"),
+		Audience:  jwt.ClaimStrings{clientIDStr.String()},
+		ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = IDTokenType
+
+	signedToken, err := signToken(token, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign ID token: %w", err)
+	}
+
+	return signedToken, nil
+}
+
+// GenerateLogoutToken creates the signed logout token posted to a client's
+// back-channel logout URI. The typ header marks it as a logout token so it
+// cannot be mistaken for an ID token.
+func GenerateLogoutToken(key *SigningKey,
+	client *models.Client, subject string, sid string,
+) (string, error) {
+	now := time.Now()
+
+	clientIDStr, err := uuid.FromBytes(client.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get uuid from client bytes: %v", err)
+	}
+
+	claims := models.LogoutTokenClaims{
+		Events: map[string]struct{}{
+			models.BackchannelLogoutEvent: {},
+		},
+		SessionID: sid,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    os.Getenv("CLIENT_BASE_URL"),
+			Audience:  jwt.ClaimStrings{clientIDStr.String()},
+			ExpiresAt: jwt.NewNumericDate(now.Add(LOGOUT_TOKEN_TTL * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+	}
+
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = LogoutTokenType
+
+	signedToken, err := signToken(token, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign logout token: %w", err)
+	}
+
+	return signedToken, nil
+}
+
+// ParseIDTokenHint verifies the signature of an ID token passed back as
+// id_token_hint. Expired tokens are accepted: the hint only identifies
+// the client and the session being logged out. ID tokens issued before
+// they carried a typ are accepted as well.
+func ParseIDTokenHint(
+	token string,
+	keys KeyStore,
+) (*models.IDTokenClaims, error) {
+	parsedToken, err := jwt.ParseWithClaims(
+		token,
+		&models.IDTokenClaims{},
+		keyFunc(keys),
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	typ, _ := parsedToken.Header["typ"].(string)
+	if typ != "" && typ != IDTokenType {
+		return nil, fmt.Errorf("invalid id token hint: typ %q", typ)
+	}
+
+	claims, ok := parsedToken.Claims.(*models.IDTokenClaims)
+	if !ok || len(claims.Audience) == 0 ||
+		claims.Issuer != os.Getenv("CLIENT_BASE_URL") {
+		return nil, fmt.Errorf("invalid id token hint")
+	}
+
+	return claims, nil
+}
+
+// signToken serializes the token and signs it with the key's signer, which
+// unlike jwt's own methods works for keys held outside the process.
+func signToken(token *jwt.Token, key *SigningKey) (string, error) {
+	signingString, err := token.SigningString()
+	if err != nil {
+		return "", err
+	}
+	sig, err := key.Sign([]byte(signingString))
+	if err != nil {
+		return "", err
+	}
+	return signingString + "." + base64.RawURLEncoding.EncodeToString(sig),
+		nil
+}
+
+// accessTokenHash computes the at_hash claim: the base64url encoding of
+// the left-most half of the token's hash. The hash is SHA-256 for the
+// RS256, PS256 and ES256 algorithms and SHA-512 for EdDSA with Ed25519.
+func accessTokenHash(accessToken string, alg string) string {
+	if alg == models.SigningAlgEdDSA {
+		sum := sha512.Sum512([]byte(accessToken))
+		return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+	}
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
+
+func ValidateToken(token string, keys KeyStore) (bool, error) {
+	parsedToken, err := GetParsedToken(token, keys)
 	if err != nil {
 		log.Printf("[ValidateToken] Validation failed: %v", err)
 		return false, err
@@ -64,46 +225,53 @@ func ValidateToken(token string, publicKey *rsa.PublicKey) (bool, error) {
 	return parsedToken.Valid, nil
 }
 
-func GetParsedToken(token string, publicKey *rsa.PublicKey) (jwt.Token, error) {
+// GetParsedToken parses an access token and verifies it with the key
+// named by its kid header, so tokens signed before a key rotation remain
+// valid. Any other token type the IdP signs is rejected.
+func GetParsedToken(token string, keys KeyStore) (jwt.Token, error) {
 	parsedToken, err := jwt.ParseWithClaims(
 		token,
 		&models.UserClaims{},
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return publicKey, nil
-		},
+		keyFunc(keys),
 	)
 
 	if err != nil {
 		return jwt.Token{}, err
 	}
+	typ, _ := parsedToken.Header["typ"].(string)
+	if !strings.EqualFold(typ, AccessTokenType) {
+		return jwt.Token{}, fmt.Errorf("unexpected token type %q", typ)
+	}
 	return *parsedToken, err
 }
 
+// MFAPendingClaims identify a user who passed the first factor, named by
+// FirstFactor, and still has to verify a second one.
 type MFAPendingClaims struct {
-	UserID    string `json:"user_id"`
-	Email     string `json:"email"`
-	IPAddress string `json:"ip_address"`
-	UserAgent string `json:"user_agent"`
+	UserID      string `json:"user_id"`
+	Email       string `json:"email"`
+	IPAddress   string `json:"ip_address"`
+	UserAgent   string `json:"user_agent"`
+	FirstFactor string `json:"first_factor,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateMFAPendingToken creates a signed JWT for pending MFA verification.
 func GenerateMFAPendingToken(
-	privateKey *rsa.PrivateKey,
+	key *SigningKey,
 	userID string,
 	email string,
 	ip string,
 	ua string,
+	firstFactor string,
 ) (string, error) {
 	now := time.Now()
 	claims := MFAPendingClaims{
-		UserID:    userID,
-		Email:     email,
-		IPAddress: ip,
-		UserAgent: ua,
+		UserID:      userID,
+		Email:       email,
+		IPAddress:   ip,
+		UserAgent:   ua,
+		FirstFactor: firstFactor,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   userID,
 			Issuer:    os.Getenv("CLIENT_BASE_URL"),
@@ -113,30 +281,30 @@ func GenerateMFAPendingToken(
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	token.Header["kid"] = os.Getenv("KEY_ID")
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = MFAPendingTokenType
 
-	return token.SignedString(privateKey)
+	return signToken(token, key)
 }
 
-// ValidateMFAPendingToken parses and validates a pending MFA token.
+// ValidateMFAPendingToken parses and validates a pending MFA token. Tokens
+// of any other type are rejected.
 func ValidateMFAPendingToken(
 	tokenStr string,
-	publicKey *rsa.PublicKey,
+	keys KeyStore,
 ) (*MFAPendingClaims, error) {
 	parsedToken, err := jwt.ParseWithClaims(
 		tokenStr,
 		&MFAPendingClaims{},
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return publicKey, nil
-		},
+		keyFunc(keys),
 	)
 	if err != nil {
 		return nil, err
 	}
+	if typ, _ := parsedToken.Header["typ"].(string); typ != MFAPendingTokenType {
+		return nil, fmt.Errorf("unexpected token type %q", typ)
+	}
 
 	claims, ok := parsedToken.Claims.(*MFAPendingClaims)
 	if !ok || !parsedToken.Valid {
@@ -145,3 +313,19 @@ func ValidateMFAPendingToken(
 
 	return claims, nil
 }
+
+// keyFunc resolves the verification key from the token's kid header. The
+// token's alg must be the algorithm the key was generated for.
+func keyFunc(keys KeyStore) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := keys.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return key.PublicKey, nil
+	}
+}
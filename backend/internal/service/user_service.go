@@ -26,6 +26,8 @@ type UserService interface {
 	) (*dto.UserResponse, error)
 	GetUserByEmail(ctx context.Context, email string) (*dto.UserResponse, error)
 	GetMe(ctx context.Context, userID uuid.UUID) (*dto.UserInfoResponse, error)
+	GetUserInfo(ctx context.Context, userID uuid.UUID,
+		scope string) (*dto.OIDCUserInfoResponse, error)
 	GetFilteredUserList(ctx context.Context, permissions []string,
 		userID uuid.UUID, limit, page int,
 		sortBy, order string, status string,
@@ -67,6 +69,7 @@ type userService struct {
 	RegRepo    repository.RegistrationRepository
 	CAURepo    repository.ClientAllowedUserRepository
 	Cache      cache.Cache
+	Denylist   TokenDenylist
 }
 
 func NewUserService(
@@ -75,6 +78,7 @@ func NewUserService(
 	regRepo repository.RegistrationRepository,
 	cauRepo repository.ClientAllowedUserRepository,
 	c cache.Cache,
+	denylist TokenDenylist,
 ) UserService {
 	return &userService{
 		Repo:       repo,
@@ -82,6 +86,7 @@ func NewUserService(
 		RegRepo:    regRepo,
 		CAURepo:    cauRepo,
 		Cache:      c,
+		Denylist:   denylist,
 	}
 }
 
@@ -510,6 +515,9 @@ func (s *userService) GetMe(
 	if err != nil {
 		return nil, fmt.Errorf("database query (GetUser): %w", err)
 	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
 
 	return &dto.UserInfoResponse{
 		ID:         userID.String(),
@@ -522,6 +530,46 @@ func (s *userService) GetMe(
 	}, nil
 }
 
+/**
+ * GetUserInfo returns the OIDC UserInfo claims for the user, releasing
+ * profile and email claims only when the access token carries the
+ * matching scope.
+ */
+func (s *userService) GetUserInfo(
+	ctx context.Context,
+	userID uuid.UUID,
+	scope string,
+) (*dto.OIDCUserInfoResponse, error) {
+	user, err := s.Repo.GetUserById(ctx, userID[:], nil, true)
+	if err != nil {
+		return nil, fmt.Errorf("database query (GetUser): %w", err)
+	}
+	if user == nil || !user.Status.CanLogin() {
+		return nil, fmt.Errorf("user lookup: user not found")
+	}
+
+	resp := &dto.OIDCUserInfoResponse{
+		Subject: userID.String(),
+	}
+
+	if HasScope(scope, models.ScopeProfile) {
+		resp.Name = displayName(user)
+		resp.GivenName = user.FirstName
+		resp.MiddleName = user.MiddleName
+		resp.FamilyName = user.LastName
+		resp.Role = user.Role.RoleName
+		resp.AccountType = user.AccountType
+	}
+
+	if HasScope(scope, models.ScopeEmail) {
+		verified := true
+		resp.Email = user.Email
+		resp.EmailVerified = &verified
+	}
+
+	return resp, nil
+}
+
 /**
  * GetFilteredUserList routes the request to fetch either all or bound users.
  */
@@ -739,7 +787,7 @@ func (s *userService) UpdateUserPassword(
 
 	_, _ = s.Cache.Incr(ctx, "cache:version:users")
 
-	return nil
+	return s.Denylist.RevokeUserTokens(ctx, id.String())
 }
 
 /**
@@ -787,6 +835,11 @@ func (s *userService) UpdateUserStatus(
 
 	_, _ = s.Cache.Incr(ctx, "cache:version:users")
 
+	// Suspended and deactivated users lose their access tokens at once
+	if !status.CanLogin() {
+		return s.Denylist.RevokeUserTokens(ctx, id.String())
+	}
+
 	return nil
 }
 
@@ -820,7 +873,7 @@ func (s *userService) UpdateUserRole(
 			return fmt.Errorf("database query (UpdateUserRole): %w", err)
 		}
 		_, _ = s.Cache.Incr(ctx, "cache:version:users")
-		return nil
+		return s.Denylist.RevokeUserTokens(ctx, id.String())
 	}
 
 	return fmt.Errorf("permission validation: unauthorized to update roles")
@@ -856,6 +909,10 @@ func (s *userService) UpdateUserAccountAndRole(
 		if err != nil {
 			return fmt.Errorf("failed to update user role: %w", err)
 		}
+		err = s.Denylist.RevokeUserTokens(ctx, id.String())
+		if err != nil {
+			return err
+		}
 	}
 
 	var nullAccountTypeID sql.NullInt64
@@ -1038,7 +1095,7 @@ func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
 
 	_, _ = s.Cache.Incr(ctx, "cache:version:users")
 
-	return nil
+	return s.Denylist.RevokeUserTokens(ctx, id.String())
 }
 
 func (s *userService) mapToUserResponse(
@@ -4,11 +4,14 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -90,6 +93,15 @@ func CompareSecret(hashed, plain string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
 }
 
+/**
+ * HashToken returns the hex SHA-256 digest of a high-entropy token. Unlike
+ * HashSecret it is deterministic, so the stored digest can be looked up.
+ */
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func GenerateRandomString(length int) (string, error) {
 	b := make([]byte, length)
 
@@ -115,3 +127,44 @@ func GenerateOTP() (string, error) {
 
 	return otp, nil
 }
+
+// userCodeAlphabet leaves out vowels, so user codes never spell words,
+// and characters easily confused with each other.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+/**
+ * GenerateUserCode returns a random device flow user code formatted as
+ * XXXX-XXXX (RFC 8628 section 6.1).
+ */
+func GenerateUserCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	code := make([]byte, 0, 9)
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, userCodeAlphabet[n.Int64()])
+	}
+	return string(code), nil
+}
+
+/**
+ * NormalizeUserCode brings a user code as typed by the user into the
+ * XXXX-XXXX form. Case, spaces and dashes are ignored.
+ */
+func NormalizeUserCode(input string) string {
+	var code []byte
+	for _, r := range strings.ToUpper(input) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			code = append(code, byte(r))
+		}
+	}
+	if len(code) != 8 {
+		return string(code)
+	}
+	return string(code[:4]) + "-" + string(code[4:])
+}
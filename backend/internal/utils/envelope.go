@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envelopeMagic prefixes every envelope so it cannot be mistaken for a
+// ciphertext produced by Encrypt.
+var envelopeMagic = []byte("ENV1")
+
+const (
+	// envelopeHeaderSize is the magic followed by the KEK version.
+	envelopeHeaderSize = 8
+	// wrappedKeySize is a GCM nonce, a 256-bit data key and the GCM tag.
+	wrappedKeySize = 12 + 32 + 16
+)
+
+/**
+ * EnvelopeCipher encrypts small secrets under a fresh data key that is
+ * itself wrapped by a versioned key-encryption key (KEK). Rotating the
+ * KEK only requires re-wrapping the data keys, not re-encrypting the
+ * secrets. The header is authenticated together with the wrapped key.
+ *
+ * An envelope is laid out as
+ *
+ *	"ENV1" | KEK version (uint32) | wrapped data key | nonce | ciphertext
+ */
+type EnvelopeCipher struct {
+	keys   map[uint32][]byte
+	active uint32
+	legacy []byte
+}
+
+/**
+ * NewEnvelopeCipher creates a cipher sealing with the active KEK version.
+ * The legacy key decrypts values written by Encrypt before envelopes were
+ * used and may be nil.
+ */
+func NewEnvelopeCipher(
+	keys map[uint32][]byte,
+	active uint32,
+	legacy []byte,
+) (*EnvelopeCipher, error) {
+	for version, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf(
+				"invalid encryption key version %d: want 32 bytes", version)
+		}
+	}
+	if _, ok := keys[active]; len(keys) > 0 && !ok {
+		return nil, fmt.Errorf("unknown active key version %d", active)
+	}
+	return &EnvelopeCipher{keys: keys, active: active, legacy: legacy}, nil
+}
+
+/**
+ * LoadEnvelopeCipher reads the KEKs from the environment.
+ * MFA_ENCRYPTION_KEYS lists them as "version:hexkey" pairs separated by
+ * commas and MFA_ENCRYPTION_KEY_VERSION selects the active one (default:
+ * the highest). MFA_ENCRYPTION_KEY remains the legacy key and, when no
+ * versioned keys are set, also serves as version 1.
+ */
+func LoadEnvelopeCipher() (*EnvelopeCipher, error) {
+	var legacy []byte
+	if keyHex := os.Getenv("MFA_ENCRYPTION_KEY"); keyHex != "" {
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key: %w", err)
+		}
+		legacy = key
+	}
+
+	keys := make(map[uint32][]byte)
+	var active uint32
+	entries := strings.Split(os.Getenv("MFA_ENCRYPTION_KEYS"), ",")
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		versionStr, keyHex, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid encryption key entry %q", entry)
+		}
+		version, err := strconv.ParseUint(versionStr, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid encryption key version %q",
+				versionStr)
+		}
+		key, err := hex.DecodeString(keyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key: %w", err)
+		}
+		keys[uint32(version)] = key
+		active = max(active, uint32(version))
+	}
+	if len(keys) == 0 && legacy != nil {
+		keys[1] = legacy
+		active = 1
+	}
+
+	if v := os.Getenv("MFA_ENCRYPTION_KEY_VERSION"); v != "" {
+		version, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key version %q", v)
+		}
+		active = uint32(version)
+	}
+
+	return NewEnvelopeCipher(keys, active, legacy)
+}
+
+// ActiveVersion returns the KEK version new envelopes are sealed with.
+func (c *EnvelopeCipher) ActiveVersion() int {
+	return int(c.active)
+}
+
+/**
+ * Seal encrypts the plain text under a new data key wrapped with the
+ * active KEK.
+ */
+func (c *EnvelopeCipher) Seal(plainText []byte) ([]byte, error) {
+	kek, ok := c.keys[c.active]
+	if !ok {
+		return nil, fmt.Errorf("no encryption key configured")
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	header := envelopeHeader(c.active)
+	wrapped, err := sealGCM(kek, dataKey, header)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealGCM(dataKey, plainText, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(header)+len(wrapped)+len(sealed))
+	out = append(out, header...)
+	out = append(out, wrapped...)
+	return append(out, sealed...), nil
+}
+
+// Open decrypts an envelope produced by Seal with any known KEK version.
+func (c *EnvelopeCipher) Open(envelope []byte) ([]byte, error) {
+	dataKey, err := c.unwrap(envelope)
+	if err != nil {
+		return nil, err
+	}
+	payload := envelope[envelopeHeaderSize+wrappedKeySize:]
+	return openGCM(dataKey, payload, nil)
+}
+
+/**
+ * OpenLegacy decrypts a value written by Encrypt with the legacy key.
+ */
+func (c *EnvelopeCipher) OpenLegacy(cipherText []byte) ([]byte, error) {
+	if c.legacy == nil {
+		return nil, fmt.Errorf("no legacy encryption key configured")
+	}
+	return openGCM(c.legacy, cipherText, nil)
+}
+
+/**
+ * Rewrap re-wraps the data key of an envelope with the active KEK. The
+ * encrypted payload is carried over unchanged. The flag reports whether
+ * the envelope was rewritten; envelopes already on the active version are
+ * returned as is.
+ */
+func (c *EnvelopeCipher) Rewrap(envelope []byte) ([]byte, bool, error) {
+	version, err := EnvelopeVersion(envelope)
+	if err != nil {
+		return nil, false, err
+	}
+	if version == c.ActiveVersion() {
+		return envelope, false, nil
+	}
+
+	dataKey, err := c.unwrap(envelope)
+	if err != nil {
+		return nil, false, err
+	}
+	kek, ok := c.keys[c.active]
+	if !ok {
+		return nil, false, fmt.Errorf("no encryption key configured")
+	}
+
+	header := envelopeHeader(c.active)
+	wrapped, err := sealGCM(kek, dataKey, header)
+	if err != nil {
+		return nil, false, err
+	}
+
+	payload := envelope[envelopeHeaderSize+wrappedKeySize:]
+	out := make([]byte, 0, len(envelope))
+	out = append(out, header...)
+	out = append(out, wrapped...)
+	return append(out, payload...), true, nil
+}
+
+// EnvelopeVersion returns the KEK version an envelope was sealed with.
+func EnvelopeVersion(envelope []byte) (int, error) {
+	if len(envelope) < envelopeHeaderSize+wrappedKeySize ||
+		!bytes.HasPrefix(envelope, envelopeMagic) {
+		return 0, fmt.Errorf("invalid envelope")
+	}
+	version := binary.BigEndian.Uint32(envelope[len(envelopeMagic):])
+	return int(version), nil
+}
+
+// unwrap decrypts the data key of an envelope.
+func (c *EnvelopeCipher) unwrap(envelope []byte) ([]byte, error) {
+	version, err := EnvelopeVersion(envelope)
+	if err != nil {
+		return nil, err
+	}
+	kek, ok := c.keys[uint32(version)]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key version %d", version)
+	}
+	return openGCM(kek,
+		envelope[envelopeHeaderSize:envelopeHeaderSize+wrappedKeySize],
+		envelope[:envelopeHeaderSize])
+}
+
+func envelopeHeader(version uint32) []byte {
+	header := make([]byte, envelopeHeaderSize)
+	copy(header, envelopeMagic)
+	binary.BigEndian.PutUint32(header[len(envelopeMagic):], version)
+	return header
+}
+
+// sealGCM encrypts with AES-GCM and returns nonce || ciphertext.
+func sealGCM(key, plainText, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plainText, aad), nil
+}
+
+// openGCM reverses sealGCM.
+func openGCM(key, cipherText, aad []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(cipherText) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, sealed := cipherText[:nonceSize], cipherText[nonceSize:]
+	return gcm.Open(nil, nonce, sealed, aad)
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"html"
+	"net/url"
 	"os"
 
 	"github.com/resend/resend-go/v3"
@@ -64,6 +66,70 @@ func SendInvitationEmail(toEmail string, invitationCode string) error {
 	return nil
 }
 
+// SendTokenReuseAlertEmail warns a user that one of their revoked refresh
+// tokens was presented again and the sessions of that app were signed out.
+func SendTokenReuseAlertEmail(toEmail string, clientName string) error {
+	apiKey := os.Getenv("RESEND_API_KEY")
+	fromEmail := os.Getenv("RESEND_FROM_EMAIL")
+	fromName := os.Getenv("RESEND_FROM_NAME")
+
+	if apiKey == "" || fromEmail == "" {
+		return fmt.Errorf("mailer: missing resend configuration")
+	}
+
+	client := resend.NewClient(apiKey)
+	from := fmt.Sprintf("%s <%s>", fromName, fromEmail)
+	params := &resend.SendEmailRequest{
+		From:    from,
+		To:      []string{toEmail},
+		Subject: "Security Alert: Suspicious Sign-In Activity",
+		Html:    buildTokenReuseEmailHTML(clientName),
+	}
+
+	_, err := client.Emails.Send(params)
+	if err != nil {
+		return fmt.Errorf("[SendTokenReuseAlertEmail]: %w", err)
+	}
+	return nil
+}
+
+// SendMagicLinkEmail sends a single-use sign-in link for clientName. The
+// link opens the login UI, which redeems the token; expiresIn is its
+// lifetime in minutes.
+func SendMagicLinkEmail(
+	toEmail string,
+	token string,
+	clientName string,
+	expiresIn int,
+) error {
+	apiKey := os.Getenv("RESEND_API_KEY")
+	fromEmail := os.Getenv("RESEND_FROM_EMAIL")
+	fromName := os.Getenv("RESEND_FROM_NAME")
+	clientBaseURL := os.Getenv("CLIENT_BASE_URL")
+
+	if apiKey == "" || fromEmail == "" {
+		return fmt.Errorf("mailer: missing resend configuration")
+	}
+
+	linkURL := fmt.Sprintf("%s/magic-link?token=%s",
+		clientBaseURL, url.QueryEscape(token))
+
+	client := resend.NewClient(apiKey)
+	from := fmt.Sprintf("%s <%s>", fromName, fromEmail)
+	params := &resend.SendEmailRequest{
+		From:    from,
+		To:      []string{toEmail},
+		Subject: "Your Sign-In Link for " + clientName,
+		Html:    buildMagicLinkEmailHTML(linkURL, clientName, expiresIn),
+	}
+
+	_, err := client.Emails.Send(params)
+	if err != nil {
+		return fmt.Errorf("[SendMagicLinkEmail]: %w", err)
+	}
+	return nil
+}
+
 func buildOTPEmailHTML(otp string) string {
 	content := fmt.Sprintf(`
 		<table role="presentation" width="100%%" cellpadding="0" cellspacing="0">
@@ -126,6 +192,84 @@ func buildInvitationEmailHTML(regURL string) string {
 	return buildEmailShell(content)
 }
 
+func buildTokenReuseEmailHTML(clientName string) string {
+	content := fmt.Sprintf(`
+		<table role="presentation" width="100%%" cellpadding="0" cellspacing="0">
+			<tr>
+				<td style="padding: 34px 60px 18px; text-align: left;">
+					<h1 style="margin: 0; color: #050505; font-size: 26px; line-height: 1.35; font-weight: 800;">
+						Suspicious sign-in activity
+					</h1>
+				</td>
+			</tr>
+			<tr>
+				<td style="padding: 10px 64px 26px;">
+					<p style="margin: 0 0 16px; color: #050505; font-size: 15px; line-height: 1.45;">
+						An old sign-in token for <strong>%s</strong> was used again. This can mean that the token was copied from one of your devices.
+					</p>
+					<p style="margin: 0 0 16px; color: #050505; font-size: 15px; line-height: 1.45;">
+						As a precaution, you have been signed out of that app. Please sign in again and change your password if you do not recognize this activity.
+					</p>
+				</td>
+			</tr>
+		</table>`,
+		html.EscapeString(clientName),
+	)
+
+	return buildEmailShell(content)
+}
+
+func buildMagicLinkEmailHTML(
+	linkURL string,
+	clientName string,
+	expiresIn int,
+) string {
+	linkURL = html.EscapeString(linkURL)
+	content := fmt.Sprintf(`
+		<table role="presentation" width="100%%" cellpadding="0" cellspacing="0">
+			<tr>
+				<td style="padding: 34px 60px 18px; text-align: left;">
+					<h1 style="margin: 0; color: #050505; font-size: 26px; line-height: 1.35; font-weight: 800;">
+						Sign in to %s
+					</h1>
+				</td>
+			</tr>
+			<tr>
+				<td style="padding: 10px 64px 0;">
+					<p style="margin: 0 0 16px; color: #050505; font-size: 15px; line-height: 1.45;">
+						We received a request to sign in with this email address. Click the button below to continue:
+					</p>
+					<p style="margin: 0 0 20px; text-align: center;">
+						<a href="%s" style="display: inline-block; min-width: 210px; padding: 13px 20px; border-radius: 7px; background: #9b0000; color: #ffffff; font-size: 16px; line-height: 1; font-weight: 800; text-decoration: none;">
+							Sign In <span style="color: #ffc400; font-size: 20px; line-height: 0;">&#8594;</span>
+						</a>
+					</p>
+					<p style="margin: 0 0 16px; color: #050505; font-size: 15px; line-height: 1.45;">
+						This link works once and expires in %d minutes. If you did not request it, you can safely ignore this email.
+					</p>
+					<p style="margin: 12px 0 0; color: #898989; font-size: 13px; line-height: 1.45;">
+						If the button doesn't work, copy and paste this link:
+					</p>
+					<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="margin-bottom: 26px;">
+						<tr>
+							<td style="padding: 0;">
+								<a href="%s" style="color: #9b0000; font-size: 12px; line-height: 1.35; font-weight: 700; word-break: break-all;">%s</a>
+							</td>
+						</tr>
+					</table>
+				</td>
+			</tr>
+		</table>`,
+		html.EscapeString(clientName),
+		linkURL,
+		expiresIn,
+		linkURL,
+		linkURL,
+	)
+
+	return buildEmailShell(content)
+}
+
 func buildEmailShell(content string) string {
 	return fmt.Sprintf(`
 		<div style="margin: 0; padding: 20px; background: #ffffff; font-family: Arial, Helvetica, sans-serif;">
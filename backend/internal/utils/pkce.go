@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+const (
+	pkceMinLength = 43
+	pkceMaxLength = 128
+)
+
+/**
+ * IsValidPKCEValue reports whether s is a syntactically valid code
+ * verifier or plain code challenge as defined by RFC 7636 section 4.1.
+ */
+func IsValidPKCEValue(s string) bool {
+	if len(s) < pkceMinLength || len(s) > pkceMaxLength {
+		return false
+	}
+
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z',
+			r >= 'a' && r <= 'z',
+			r >= '0' && r <= '9',
+			r == '-', r == '.', r == '_', r == '~':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * ComputeS256Challenge derives the S256 code challenge for a verifier.
+ */
+func ComputeS256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+/**
+ * VerifyCodeChallenge checks a code verifier against the challenge
+ * stored with an authorization code. Methods other than "S256" and
+ * "plain" are rejected.
+ */
+func VerifyCodeChallenge(verifier, challenge, method string) bool {
+	if !IsValidPKCEValue(verifier) || challenge == "" {
+		return false
+	}
+
+	var computed string
+	switch method {
+	case "S256":
+		computed = ComputeS256Challenge(verifier)
+	case "plain":
+		computed = verifier
+	default:
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(
+		[]byte(computed),
+		[]byte(challenge),
+	) == 1
+}
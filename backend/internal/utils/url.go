@@ -0,0 +1,24 @@
+package utils
+
+import "net/url"
+
+/**
+ * AppendQuery adds the non-empty params to the query string of base,
+ * preserving any query components the URI was registered with.
+ */
+func AppendQuery(base string, params map[string]string) string {
+	parsed, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+
+	query := parsed.Query()
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
@@ -1,18 +1,23 @@
 package handler_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 )
 
@@ -104,17 +109,29 @@ func TestAuthorize_SessionInvalidRedirectsToLogin(t *testing.T) {
 		AnyTimes()
 
 	mockAuthService.EXPECT().
-		Authorize(gomock.Any(), clientID, sessionToken).
+		ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+		Return(redirectURI, nil)
+
+	mockAuthService.EXPECT().
+		ValidateSession(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("no session")).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		Authorize(gomock.Any(), dto.AuthorizeRequest{
+			ClientID:    clientID,
+			RedirectURI: redirectURI,
+		}, sessionToken).
 		Return("", fmt.Errorf("expired session"))
 
+	// The session cookie is never logged as the actor.
 	mockLogService.EXPECT().
 		PostAuditLogWithActorString(
 			gomock.Any(),
-			sessionToken,
+			gomock.Not(sessionToken),
 			gomock.Any(),
 		).
 		Return(nil).
-		AnyTimes()
+		Times(1)
 
 	mockAuthService.EXPECT().
 		RevokeCookies(gomock.Any())
@@ -150,3 +167,843 @@ func TestAuthorize_SessionInvalidRedirectsToLogin(t *testing.T) {
 		)
 	}
 }
+
+/**
+ * TestPostTokenExchange_UnsupportedGrantType verifies that the token
+ * endpoint answers with an RFC 6749 error body.
+ */
+func TestPostTokenExchange_UnsupportedGrantType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := &v1.AuthHandler{
+		AuthService:   mocks.NewMockAuthService(ctrl),
+		ClientService: mocks.NewMockClientService(ctrl),
+		LogService:    mocks.NewMockLogService(ctrl),
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body := `{"grant_type":"password","code":"abc","client_id":"x"}`
+	c.Request, _ = http.NewRequest(
+		"POST",
+		"/auth/token",
+		strings.NewReader(body),
+	)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.PostTokenExchange(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+
+	var resp dto.OAuthErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected OAuth error body, got %s", w.Body.String())
+	}
+	if resp.Error != "unsupported_grant_type" {
+		t.Errorf("expected unsupported_grant_type, got %q", resp.Error)
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("expected Cache-Control no-store")
+	}
+}
+
+/**
+ * TestAuthorize_UnauthorizedClientRedirectsWithError verifies that
+ * authorization errors are sent back to the client's redirect URI along
+ * with the original state.
+ */
+func TestAuthorize_UnauthorizedClientRedirectsWithError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mocks.NewMockClientService(ctrl),
+		LogService:    mockLogService,
+	}
+
+	clientID := "test-client-id"
+	redirectURI := "http://example.com/callback"
+
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("test-client").
+		AnyTimes()
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	mockAuthService.EXPECT().
+		ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+		Return(redirectURI, nil)
+	mockAuthService.EXPECT().
+		ValidateSession(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("no session")).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		Authorize(gomock.Any(), gomock.Any(), "session").
+		Return("", fmt.Errorf(
+			"unauthorized client: authorization_code grant not allowed",
+		))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqURL := "/auth/authorize?client_id=" + clientID +
+		"&redirect_uri=" + url.QueryEscape(redirectURI) + "&state=abc"
+	c.Request, _ = http.NewRequest("GET", reqURL, nil)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  service.SESSION_COOKIE_NAME,
+		Value: "session",
+	})
+
+	handler.Authorize(c)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected status 302, got %d", w.Code)
+	}
+
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if location.Host != "example.com" {
+		t.Errorf("expected redirect to client, got %s", location)
+	}
+	if location.Query().Get("error") != "unauthorized_client" {
+		t.Errorf("expected unauthorized_client, got %s", location)
+	}
+	if location.Query().Get("state") != "abc" {
+		t.Errorf("expected state to be echoed, got %s", location)
+	}
+}
+
+/**
+ * TestAuthorize_PromptNoneWithoutSession verifies that prompt=none
+ * answers login_required instead of showing the login page.
+ */
+func TestAuthorize_PromptNoneWithoutSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mocks.NewMockClientService(ctrl),
+		LogService:    mockLogService,
+	}
+
+	clientID := "test-client-id"
+	redirectURI := "http://example.com/callback"
+
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("test-client").
+		AnyTimes()
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+		Return(redirectURI, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqURL := "/auth/authorize?client_id=" + clientID +
+		"&redirect_uri=" + url.QueryEscape(redirectURI) +
+		"&state=abc&prompt=none"
+	c.Request, _ = http.NewRequest("GET", reqURL, nil)
+
+	handler.Authorize(c)
+
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if location.Host != "example.com" {
+		t.Fatalf("expected redirect to client, got %s", location)
+	}
+	if location.Query().Get("error") != "login_required" {
+		t.Errorf("expected login_required, got %s", location)
+	}
+	if location.Query().Get("state") != "abc" {
+		t.Errorf("expected state to be echoed, got %s", location)
+	}
+}
+
+/**
+ * TestAuthorize_PromptNoneConsentRequired verifies that prompt=none
+ * answers consent_required instead of showing the consent screen.
+ */
+func TestAuthorize_PromptNoneConsentRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mocks.NewMockClientService(ctrl),
+		LogService:    mockLogService,
+	}
+
+	clientID := "test-client-id"
+	redirectURI := "http://example.com/callback"
+
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("test-client").
+		AnyTimes()
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+		Return(redirectURI, nil)
+	mockAuthService.EXPECT().
+		ValidateSession(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("no session")).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		Authorize(gomock.Any(), gomock.Any(), "session").
+		Return("", fmt.Errorf("consent required"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqURL := "/auth/authorize?client_id=" + clientID +
+		"&redirect_uri=" + url.QueryEscape(redirectURI) + "&prompt=none"
+	c.Request, _ = http.NewRequest("GET", reqURL, nil)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  service.SESSION_COOKIE_NAME,
+		Value: "session",
+	})
+
+	handler.Authorize(c)
+
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if location.Query().Get("error") != "consent_required" {
+		t.Errorf("expected consent_required, got %s", location)
+	}
+}
+
+/**
+ * TestAuthorize_StepUpRequired verifies that a session below the required
+ * authentication context is sent to the step-up page, or answered with
+ * interaction_required when prompt=none.
+ */
+func TestAuthorize_StepUpRequired(t *testing.T) {
+	tests := []struct {
+		name      string
+		prompt    string
+		wantPath  string
+		wantError string
+	}{
+		{name: "step-up page", wantPath: "/step-up"},
+		{
+			name:      "prompt none",
+			prompt:    "none",
+			wantError: "interaction_required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthService := mocks.NewMockAuthService(ctrl)
+			mockLogService := mocks.NewMockLogService(ctrl)
+
+			handler := &v1.AuthHandler{
+				AuthService:   mockAuthService,
+				ClientService: mocks.NewMockClientService(ctrl),
+				LogService:    mockLogService,
+			}
+
+			clientID := "test-client-id"
+			redirectURI := "http://example.com/callback"
+
+			mockLogService.EXPECT().
+				ResolveClientName(gomock.Any(), clientID).
+				Return("test-client").
+				AnyTimes()
+			mockLogService.EXPECT().
+				PostAuditLogWithActorString(
+					gomock.Any(), gomock.Any(), gomock.Any(),
+				).
+				Return(nil).
+				AnyTimes()
+			mockAuthService.EXPECT().
+				ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+				Return(redirectURI, nil)
+			mockAuthService.EXPECT().
+				ValidateSession(gomock.Any(), gomock.Any()).
+				Return(nil, fmt.Errorf("no session")).
+				AnyTimes()
+			mockAuthService.EXPECT().
+				Authorize(gomock.Any(), gomock.Any(), "session").
+				Return("", fmt.Errorf("step-up required: acr 2fa"))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			reqURL := "/auth/authorize?client_id=" + clientID +
+				"&redirect_uri=" + url.QueryEscape(redirectURI) +
+				"&acr_values=2fa&prompt=" + tt.prompt
+			c.Request, _ = http.NewRequest("GET", reqURL, nil)
+			c.Request.AddCookie(&http.Cookie{
+				Name:  service.SESSION_COOKIE_NAME,
+				Value: "session",
+			})
+
+			handler.Authorize(c)
+
+			location, _ := url.Parse(w.Header().Get("Location"))
+			if tt.wantError != "" &&
+				location.Query().Get("error") != tt.wantError {
+				t.Errorf("expected %s, got %s", tt.wantError, location)
+			}
+			if tt.wantPath != "" &&
+				(!strings.HasSuffix(location.Path, tt.wantPath) ||
+					location.Query().Get("acr_values") != "2fa") {
+				t.Errorf("expected step-up redirect, got %s", location)
+			}
+		})
+	}
+}
+
+/**
+ * TestAuthorize_LoginHintPrefillsLogin verifies that login_hint and the
+ * prompt are passed on to the login page while the remembered request
+ * replaces the prompt with the auth_after it requires, so the user is not
+ * sent back to log in again but an older session still is not accepted.
+ */
+func TestAuthorize_LoginHintPrefillsLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mocks.NewMockClientService(ctrl),
+		LogService:    mockLogService,
+	}
+
+	clientID := "test-client-id"
+	redirectURI := "http://example.com/callback"
+
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("test-client").
+		AnyTimes()
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+		Return(redirectURI, nil)
+	mockAuthService.EXPECT().
+		ValidateSession(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("no session")).
+		AnyTimes()
+	mockAuthService.EXPECT().
+		Authorize(gomock.Any(), gomock.Any(), "session").
+		Return("", fmt.Errorf("login required: re-authentication requested"))
+	mockAuthService.EXPECT().
+		RevokeCookies(gomock.Any())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqURL := "/auth/authorize?client_id=" + clientID +
+		"&redirect_uri=" + url.QueryEscape(redirectURI) +
+		"&prompt=login&max_age=0&login_hint=" +
+		url.QueryEscape("jane@example.com")
+	c.Request, _ = http.NewRequest("GET", reqURL, nil)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  service.SESSION_COOKIE_NAME,
+		Value: "session",
+	})
+
+	before := time.Now().Unix()
+	handler.Authorize(c)
+
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if location.Path != "/login" {
+		t.Fatalf("expected redirect to login, got %s", location)
+	}
+	if location.Query().Get("login_hint") != "jane@example.com" {
+		t.Errorf("expected login_hint, got %s", location)
+	}
+	if location.Query().Get("prompt") != "login" {
+		t.Errorf("expected prompt, got %s", location)
+	}
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name != service.AUTHORIZE_REQUEST_COOKIE_NAME {
+			continue
+		}
+		raw, _ := url.QueryUnescape(cookie.Value)
+		saved, _ := url.ParseQuery(raw)
+		if saved.Has("prompt") || saved.Has("max_age") {
+			t.Errorf("expected prompt to be dropped, got %q", cookie.Value)
+		}
+		authAfter, _ := strconv.ParseInt(saved.Get("auth_after"), 10, 64)
+		if authAfter < before {
+			t.Errorf("expected auth_after to require a new login, got %q",
+				cookie.Value)
+		}
+		if saved.Get("login_hint") != "jane@example.com" {
+			t.Errorf("expected login_hint to be kept, got %q", cookie.Value)
+		}
+		return
+	}
+	t.Error("expected the authorize request to be remembered")
+}
+
+/**
+ * TestEndSession verifies that RP-initiated logout clears the session
+ * cookie and either redirects straight away or first renders the page
+ * loading the front-channel logout URIs.
+ */
+func TestEndSession(t *testing.T) {
+	tests := []struct {
+		name         string
+		frontchannel []string
+		wantStatus   int
+	}{
+		{name: "redirect", wantStatus: http.StatusFound},
+		{
+			name:         "front-channel page",
+			frontchannel: []string{"https://app.example.com/fc?sid=abc"},
+			wantStatus:   http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthService := mocks.NewMockAuthService(ctrl)
+			mockLogService := mocks.NewMockLogService(ctrl)
+			mockLogoutService := mocks.NewMockLogoutService(ctrl)
+
+			handler := &v1.AuthHandler{
+				AuthService:   mockAuthService,
+				LogService:    mockLogService,
+				LogoutService: mockLogoutService,
+			}
+
+			redirectURL := "https://app.example.com/?state=xyz"
+			mockLogoutService.EXPECT().
+				EndSession(gomock.Any(), gomock.Any(), "session").
+				DoAndReturn(func(
+					_ interface{},
+					req dto.EndSessionRequest,
+					_ string,
+				) (*dto.EndSessionResult, error) {
+					if req.State != "xyz" {
+						t.Errorf("expected state xyz, got %q", req.State)
+					}
+					return &dto.EndSessionResult{
+						RedirectURL:      redirectURL,
+						FrontchannelURIs: tt.frontchannel,
+						UserID:           []byte("user"),
+					}, nil
+				})
+			mockAuthService.EXPECT().RevokeCookies(gomock.Any())
+			mockLogService.EXPECT().
+				PostAuditLog(gomock.Any(), []byte("user"), gomock.Any()).
+				Return(nil)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET",
+				"/auth/end-session?id_token_hint=hint&state=xyz"+
+					"&post_logout_redirect_uri="+
+					url.QueryEscape("https://app.example.com/"), nil)
+			c.Request.AddCookie(&http.Cookie{
+				Name:  service.SESSION_COOKIE_NAME,
+				Value: "session",
+			})
+
+			handler.EndSession(c)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusFound &&
+				w.Header().Get("Location") != redirectURL {
+				t.Errorf("unexpected redirect %s", w.Header().Get("Location"))
+			}
+			body := w.Body.String()
+			if tt.wantStatus == http.StatusOK &&
+				(!strings.Contains(body, `<iframe src="`+
+					"https://app.example.com/fc?sid=abc") ||
+					!strings.Contains(body, "url=https://app.example.com/")) {
+				t.Errorf("unexpected logout page %s", body)
+			}
+		})
+	}
+}
+
+/**
+ * TestEndSession_ConfirmsWithoutHint verifies that a logout without an
+ * id_token_hint shows a confirmation page first and only ends the session
+ * when the page is posted back with the matching token.
+ */
+func TestEndSession_ConfirmsWithoutHint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+	mockLogoutService := mocks.NewMockLogoutService(ctrl)
+
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		LogService:    mockLogService,
+		LogoutService: mockLogoutService,
+	}
+
+	// 1. A plain GET only renders the confirmation page.
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET",
+		"/auth/end-session?state=xyz", nil)
+	handler.EndSession(c)
+
+	if w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), `name="confirm"`) {
+		t.Fatalf("expected confirmation page, got %d %s",
+			w.Code, w.Body.String())
+	}
+	var confirm string
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == service.LOGOUT_CONFIRM_COOKIE_NAME {
+			confirm, _ = url.QueryUnescape(cookie.Value)
+		}
+	}
+	if confirm == "" {
+		t.Fatal("expected a confirmation cookie")
+	}
+
+	post := func(value string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		form := url.Values{"confirm": {value}, "state": {"xyz"}}
+		c.Request, _ = http.NewRequest("POST", "/auth/end-session",
+			strings.NewReader(form.Encode()))
+		c.Request.Header.Set("Content-Type",
+			"application/x-www-form-urlencoded")
+		c.Request.AddCookie(&http.Cookie{
+			Name:  service.LOGOUT_CONFIRM_COOKIE_NAME,
+			Value: url.QueryEscape(confirm),
+		})
+		handler.EndSession(c)
+		// A redirect answering a POST has no body to flush the status.
+		c.Writer.WriteHeaderNow()
+		return w
+	}
+
+	// 2. A post without the matching token is asked again.
+	if w := post("forged"); w.Code != http.StatusOK {
+		t.Fatalf("expected confirmation page, got %d", w.Code)
+	}
+
+	// 3. The confirmed post ends the session.
+	mockLogoutService.EXPECT().
+		EndSession(gomock.Any(), gomock.Any(), "").
+		Return(&dto.EndSessionResult{
+			RedirectURL: "https://idp.example.com/login",
+		}, nil)
+	mockAuthService.EXPECT().RevokeCookies(gomock.Any())
+
+	w = post(confirm)
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect, got %d", w.Code)
+	}
+}
+
+/**
+ * TestPostTokenExchange_ClientSecretBasic verifies that form-urlencoded
+ * client credentials sent in an HTTP Basic header reach the code exchange.
+ */
+func TestPostTokenExchange_ClientSecretBasic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockClientService := mocks.NewMockClientService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mockClientService,
+		LogService:    mockLogService,
+	}
+
+	clientID := "6f1c1f0e-3b7a-4a59-9f43-4f6b0f0c2d11"
+	mockClientService.EXPECT().
+		GetClientByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&dto.ClientResponse{
+			Grants: []string{string(models.GrantAuthCode)},
+		}, nil)
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("Portal")
+	mockAuthService.EXPECT().
+		ExchangeCodeForToken(gomock.Any(), dto.TokenExchangeRequest{
+			GrantType:    string(models.GrantAuthCode),
+			Code:         "auth-code",
+			ClientID:     clientID,
+			ClientSecret: "s3/cret +",
+		}).
+		Return(&dto.TokenResponse{AccessToken: "at"}, nil)
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), "Portal", gomock.Any()).
+		Return(nil)
+	mockLogService.EXPECT().
+		PostSecurityLogWithActorString(gomock.Any(), "Portal", gomock.Any()).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	form := url.Values{
+		"grant_type": {string(models.GrantAuthCode)},
+		"code":       {"auth-code"},
+		"client_id":  {clientID},
+	}
+	c.Request, _ = http.NewRequest(
+		"POST",
+		"/auth/token",
+		strings.NewReader(form.Encode()),
+	)
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c.Request.SetBasicAuth(clientID, url.QueryEscape("s3/cret +"))
+
+	handler.PostTokenExchange(c)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+/**
+ * TestPostTokenExchange_ClientSecretBasicConflicts verifies that a request
+ * using two authentication methods, or naming another client in the body
+ * than in the Basic header, is rejected.
+ */
+func TestPostTokenExchange_ClientSecretBasicConflicts(t *testing.T) {
+	clientID := "6f1c1f0e-3b7a-4a59-9f43-4f6b0f0c2d11"
+	tests := []struct {
+		name string
+		form url.Values
+	}{
+		{
+			name: "secret in body and header",
+			form: url.Values{"client_secret": {"s3cret"}},
+		},
+		{
+			name: "other client_id in body",
+			form: url.Values{
+				"client_id": {"0d5c7a0e-8f31-4b7e-9d2c-1a6e5f4b3c21"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			handler := &v1.AuthHandler{
+				AuthService:   mocks.NewMockAuthService(ctrl),
+				ClientService: mocks.NewMockClientService(ctrl),
+				LogService:    mocks.NewMockLogService(ctrl),
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			tt.form.Set("grant_type", string(models.GrantAuthCode))
+			tt.form.Set("code", "auth-code")
+			c.Request, _ = http.NewRequest(
+				"POST",
+				"/auth/token",
+				strings.NewReader(tt.form.Encode()),
+			)
+			c.Request.Header.Set(
+				"Content-Type",
+				"application/x-www-form-urlencoded",
+			)
+			c.Request.SetBasicAuth(clientID, "s3cret")
+
+			handler.PostTokenExchange(c)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s",
+					w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+/**
+ * TestPostTokenExchange_DeviceCodePending verifies that a device polling
+ * before the user answered receives authorization_pending.
+ */
+func TestPostTokenExchange_DeviceCodePending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockClientService := mocks.NewMockClientService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+	mockDeviceService := mocks.NewMockDeviceService(ctrl)
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mockClientService,
+		LogService:    mockLogService,
+		DeviceService: mockDeviceService,
+	}
+
+	clientID := "6f1c1f0e-3b7a-4a59-9f43-4f6b0f0c2d11"
+	mockClientService.EXPECT().
+		GetClientByID(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&dto.ClientResponse{
+			Grants: []string{string(models.GrantDeviceCode)},
+		}, nil)
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("Kiosk")
+	mockDeviceService.EXPECT().
+		PollDeviceCode(gomock.Any(), gomock.Any()).
+		Return("", fmt.Errorf("authorization pending"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	form := url.Values{
+		"grant_type":  {string(models.GrantDeviceCode)},
+		"client_id":   {clientID},
+		"device_code": {"device-code"},
+	}
+	c.Request, _ = http.NewRequest(
+		"POST",
+		"/auth/token",
+		strings.NewReader(form.Encode()),
+	)
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	handler.PostTokenExchange(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	var resp dto.OAuthErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected OAuth error body, got %s", w.Body.String())
+	}
+	if resp.Error != "authorization_pending" {
+		t.Errorf("expected authorization_pending, got %q", resp.Error)
+	}
+}
+
+/**
+ * TestAuthorize_AuditActor verifies that a granted authorization is logged
+ * under the signed-in user's email and never under the session cookie.
+ */
+func TestAuthorize_AuditActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+
+	handler := &v1.AuthHandler{
+		AuthService:   mockAuthService,
+		ClientService: mocks.NewMockClientService(ctrl),
+		LogService:    mockLogService,
+	}
+
+	clientID := "test-client-id"
+	redirectURI := "http://example.com/callback"
+	userID := uuid.New()
+
+	mockLogService.EXPECT().
+		ResolveClientName(gomock.Any(), clientID).
+		Return("test-client").
+		AnyTimes()
+	mockAuthService.EXPECT().
+		ValidateRedirectURI(gomock.Any(), clientID, redirectURI).
+		Return(redirectURI, nil)
+	mockAuthService.EXPECT().
+		Authorize(gomock.Any(), gomock.Any(), "session").
+		Return(redirectURI+"?code=abc", nil)
+	mockAuthService.EXPECT().
+		ValidateSession(gomock.Any(), "session").
+		Return(&models.IdPSession{UserId: userID[:]}, nil)
+	mockLogService.EXPECT().
+		GetUserEmail(gomock.Any(), userID[:]).
+		Return("jane@example.com", nil)
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(
+			gomock.Any(), "jane@example.com", gomock.Any(),
+		).
+		Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqURL := "/auth/authorize?client_id=" + clientID +
+		"&redirect_uri=" + url.QueryEscape(redirectURI)
+	c.Request, _ = http.NewRequest("GET", reqURL, nil)
+	c.Request.AddCookie(&http.Cookie{
+		Name:  service.SESSION_COOKIE_NAME,
+		Value: "session",
+	})
+
+	handler.Authorize(c)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected status 302, got %d", w.Code)
+	}
+}
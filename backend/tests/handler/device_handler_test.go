@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestPostDevice_AuditActor verifies that a device answer is logged under
+ * the signed-in user's email and never under the session cookie.
+ */
+func TestPostDevice_AuditActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeviceService := mocks.NewMockDeviceService(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+
+	handler := &v1.DeviceHandler{
+		DeviceService: mockDeviceService,
+		AuthService:   mockAuthService,
+		LogService:    mockLogService,
+	}
+
+	userID := uuid.New()
+	mockAuthService.EXPECT().
+		ValidateSession(gomock.Any(), "session").
+		Return(&models.IdPSession{UserId: userID[:]}, nil)
+	mockLogService.EXPECT().
+		GetUserEmail(gomock.Any(), userID[:]).
+		Return("jane@example.com", nil)
+	mockDeviceService.EXPECT().
+		AnswerDeviceVerification(gomock.Any(), "session", gomock.Any()).
+		Return(nil)
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(
+			gomock.Any(), "jane@example.com", gomock.Any(),
+		).
+		Return(nil)
+
+	r := gin.New()
+	r.POST("/auth/device", handler.PostDevice)
+
+	body := `{"user_code":"ABCD-EFGH","approve":true}`
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/auth/device",
+		bytes.NewBufferString(body),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{
+		Name:  service.SESSION_COOKIE_NAME,
+		Value: "session",
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestPostRotateSigningKey_PartialFailure verifies that a failed algorithm
+ * does not hide the keys already rotated for the others.
+ */
+func TestPostRotateSigningKey_PartialFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Only RS256 has an active key, so every other algorithm fails.
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := service.NewKeyStore(nil, nil, &service.SigningKey{
+		ID:        "rs256-key",
+		Signer:    privKey,
+		PublicKey: &privKey.PublicKey,
+	})
+
+	mockLogService := mocks.NewMockLogService(ctrl)
+	mockLogService.EXPECT().
+		GetUserEmail(gomock.Any(), gomock.Any()).
+		Return("admin@example.com", nil)
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), "admin@example.com",
+			gomock.Any()).
+		Return(nil).
+		Times(len(models.SigningAlgorithms))
+
+	handler := &v1.KeyHandler{KeyStore: keys, LogService: mockLogService}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/admin/keys/rotate", nil)
+	c.Set("permissions", []string{"Manage Signing Keys"})
+
+	handler.PostRotateSigningKey(c)
+
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status 207, got %d", w.Code)
+	}
+
+	var resp []dto.SigningKeyRotationResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected rotation results, got %s", w.Body.String())
+	}
+	if len(resp) != len(models.SigningAlgorithms) {
+		t.Fatalf("expected one result per algorithm, got %+v", resp)
+	}
+	for _, r := range resp {
+		wantRotated := r.Algorithm == models.SigningAlgRS256
+		if r.Rotated != wantRotated {
+			t.Errorf("expected %s rotated=%v, got %+v",
+				r.Algorithm, wantRotated, r)
+		}
+	}
+	if keys.ActiveKey(models.SigningAlgRS256).ID == "rs256-key" {
+		t.Errorf("expected the RS256 key to be rotated")
+	}
+}
@@ -0,0 +1,178 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func newMagicLinkHandler(ctrl *gomock.Controller) (
+	*v1.MagicLinkHandler,
+	*mocks.MockMagicLinkService,
+	*mocks.MockAuthService,
+) {
+	mockMagicLinkService := mocks.NewMockMagicLinkService(ctrl)
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	mockLogService := mocks.NewMockLogService(ctrl)
+	mockLogService.EXPECT().
+		PostAuditLogWithActorString(gomock.Any(), gomock.Any(),
+			gomock.Any()).
+		Return(nil).AnyTimes()
+	mockLogService.EXPECT().
+		PostSecurityLogWithActorString(gomock.Any(), gomock.Any(),
+			gomock.Any()).
+		Return(nil).AnyTimes()
+	handler := v1.NewMagicLinkHandler(
+		mockMagicLinkService,
+		mockAuthService,
+		mockLogService,
+	)
+	return handler, mockMagicLinkService, mockAuthService
+}
+
+func serveMagicLink(
+	path string,
+	handlerFunc gin.HandlerFunc,
+	body string,
+) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST(path, handlerFunc)
+	req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestPostMagicLinkHandler verifies that a link request is answered the
+// same for any email, and refused for clients without magic links.
+func TestPostMagicLinkHandler(t *testing.T) {
+	clientID := uuid.New().String()
+	body := `{"email":"user@example.com","client_id":"` + clientID + `"}`
+
+	t.Run("sends the link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, mockMagicLinkService, _ := newMagicLinkHandler(ctrl)
+		mockMagicLinkService.EXPECT().
+			RequestLink(gomock.Any(), "user@example.com", clientID, "",
+				"").
+			Return(nil)
+
+		w := serveMagicLink("/auth/magic-link", handler.PostMagicLink, body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("refuses clients without magic links", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, mockMagicLinkService, _ := newMagicLinkHandler(ctrl)
+		mockMagicLinkService.EXPECT().
+			RequestLink(gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any()).
+			Return(errors.New("magic link not allowed for this client"))
+
+		w := serveMagicLink("/auth/magic-link", handler.PostMagicLink, body)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("rejects an invalid email", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, _, _ := newMagicLinkHandler(ctrl)
+		w := serveMagicLink("/auth/magic-link", handler.PostMagicLink,
+			`{"email":"nope","client_id":"`+clientID+`"}`)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+}
+
+// TestPostVerifyMagicLinkHandler verifies that a redeemed link only starts
+// the MFA step, as a password login does, and returns the authorize URL of
+// the whole pending request; spent links are refused.
+func TestPostVerifyMagicLinkHandler(t *testing.T) {
+	clientID := uuid.New().String()
+	userID := uuid.New()
+	body := `{"token":"link-token"}`
+
+	t.Run("issues the mfa pending token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, mockMagicLinkService, mockAuthService :=
+			newMagicLinkHandler(ctrl)
+		mockMagicLinkService.EXPECT().
+			ConsumeLink(gomock.Any(), "link-token").
+			Return(&service.MagicLinkLogin{
+				UserID:      userID,
+				Email:       "user@example.com",
+				ClientID:    clientID,
+				RedirectURI: "https://app.example.com/callback",
+				AuthorizeQuery: "client_id=" + clientID +
+					"&state=xyz",
+			}, nil)
+		mockAuthService.EXPECT().
+			GenerateMFAPendingToken(userID.String(), "user@example.com",
+				gomock.Any(), gomock.Any(), models.AMREmailLink).
+			Return("pending", nil)
+
+		w := serveMagicLink("/auth/magic-link/verify",
+			handler.PostVerifyMagicLink, body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp map[string]string
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !strings.Contains(resp["redirect_url"], "state=xyz") {
+			t.Errorf("unexpected redirect_url %q", resp["redirect_url"])
+		}
+		if resp["mfa_pending_token"] != "pending" {
+			t.Errorf("expected the mfa pending token, got %q",
+				resp["mfa_pending_token"])
+		}
+		if !strings.Contains(w.Header().Get("Set-Cookie"),
+			"idp_mfa_pending=pending") {
+			t.Errorf("expected the mfa pending cookie, got %q",
+				w.Header().Get("Set-Cookie"))
+		}
+	})
+
+	t.Run("refuses a spent link", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, mockMagicLinkService, _ := newMagicLinkHandler(ctrl)
+		mockMagicLinkService.EXPECT().
+			ConsumeLink(gomock.Any(), "link-token").
+			Return(nil, errors.New("invalid magic link"))
+
+		w := serveMagicLink("/auth/magic-link/verify",
+			handler.PostVerifyMagicLink, body)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+}
@@ -3,8 +3,10 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	v1 "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
@@ -230,6 +232,7 @@ func TestGetHasPasskeyHandler(t *testing.T) {
 			mockPasskeyService,
 			mockUserService,
 			mockAuthService,
+			mocks.NewMockLogService(ctrl),
 		)
 
 		gin.SetMode(gin.TestMode)
@@ -283,6 +286,7 @@ func TestGetHasPasskeyHandler(t *testing.T) {
 			mockPasskeyService,
 			mockUserService,
 			mockAuthService,
+			mocks.NewMockLogService(ctrl),
 		)
 
 		gin.SetMode(gin.TestMode)
@@ -298,3 +302,107 @@ func TestGetHasPasskeyHandler(t *testing.T) {
 		}
 	})
 }
+
+// TestFinishPasskeyLoginHandler verifies that a verified passkey creates the
+// session directly and returns the authorize URL, and that suspended users
+// are refused.
+func TestFinishPasskeyLoginHandler(t *testing.T) {
+	clientID := uuid.New().String()
+	userID := uuid.New()
+
+	newHandler := func(ctrl *gomock.Controller) (
+		*v1.PasskeyHandler,
+		*mocks.MockPasskeyService,
+		*mocks.MockAuthService,
+	) {
+		mockPasskeyService := mocks.NewMockPasskeyService(ctrl)
+		mockAuthService := mocks.NewMockAuthService(ctrl)
+		mockLogService := mocks.NewMockLogService(ctrl)
+		mockLogService.EXPECT().
+			PostAuditLogWithActorString(gomock.Any(), gomock.Any(),
+				gomock.Any()).
+			Return(nil).AnyTimes()
+		mockLogService.EXPECT().
+			PostSecurityLogWithActorString(gomock.Any(), gomock.Any(),
+				gomock.Any()).
+			Return(nil).AnyTimes()
+		handler := v1.NewPasskeyHandler(
+			mockPasskeyService,
+			mocks.NewMockUserService(ctrl),
+			mockAuthService,
+			mockLogService,
+		)
+		return handler, mockPasskeyService, mockAuthService
+	}
+
+	serve := func(
+		handler *v1.PasskeyHandler,
+		query string,
+	) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		r := gin.New()
+		r.POST("/auth/passkey/login/finish", handler.FinishPasskeyLogin)
+		req, _ := http.NewRequest(
+			"POST",
+			"/auth/passkey/login/finish?"+query,
+			bytes.NewBufferString(`{}`),
+		)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("creates the session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, mockPasskeyService, mockAuthService := newHandler(ctrl)
+		mockPasskeyService.EXPECT().
+			FinishLogin(gomock.Any(), gomock.Any()).
+			Return(&dto.UserResponse{
+				ID:    userID.String(),
+				Email: "test@example.com",
+			}, nil)
+		mockAuthService.EXPECT().
+			CreatePasskeySessionAndSetCookie(gomock.Any(), userID).
+			Return(nil)
+
+		w := serve(handler, "client_id="+clientID)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var body map[string]string
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !strings.Contains(body["redirect_url"], "client_id="+clientID) {
+			t.Errorf("unexpected redirect_url %q", body["redirect_url"])
+		}
+	})
+
+	t.Run("refuses suspended users", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, mockPasskeyService, _ := newHandler(ctrl)
+		mockPasskeyService.EXPECT().
+			FinishLogin(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("Finish Login: user is suspended"))
+
+		w := serve(handler, "client_id="+clientID)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("rejects an invalid client_id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		handler, _, _ := newHandler(ctrl)
+		w := serve(handler, "client_id=nope")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+}
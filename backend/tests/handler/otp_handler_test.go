@@ -0,0 +1,155 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/api/v1"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestVerifyOTP_StepUp verifies that an email code verified on a signed-in
+ * session records a second factor only when the code was sent to the
+ * session's own user.
+ */
+func TestVerifyOTP_StepUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		sameUser   bool
+		wantRecord int
+	}{
+		{name: "own email", sameUser: true, wantRecord: 1},
+		{name: "other email", sameUser: false, wantRecord: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOTPService := mocks.NewMockOTPService(ctrl)
+			mockLogService := mocks.NewMockLogService(ctrl)
+			mockUserService := mocks.NewMockUserService(ctrl)
+			mockAuthService := mocks.NewMockAuthService(ctrl)
+
+			handler := v1.NewOTPHandler(
+				mockOTPService,
+				mockLogService,
+				mockUserService,
+				mockAuthService,
+				mocks.NewMockLockoutService(ctrl),
+			)
+
+			sessionUser := uuid.New()
+			emailUser := uuid.New()
+			if tt.sameUser {
+				emailUser = sessionUser
+			}
+
+			mockOTPService.EXPECT().
+				VerifyOTP(gomock.Any(), "jane@example.com", "123456").
+				Return(nil)
+			mockAuthService.EXPECT().
+				ValidateSession(gomock.Any(), "session").
+				Return(&models.IdPSession{UserId: sessionUser[:]}, nil)
+			mockUserService.EXPECT().
+				GetUserByEmail(gomock.Any(), "jane@example.com").
+				Return(&dto.UserResponse{ID: emailUser.String()}, nil)
+			mockAuthService.EXPECT().
+				RecordSecondFactor(gomock.Any(), models.AMROTP).
+				Return(nil).
+				Times(tt.wantRecord)
+			mockLogService.EXPECT().
+				PostAuditLogWithActorString(
+					gomock.Any(), gomock.Any(), gomock.Any(),
+				).
+				Return(nil).
+				AnyTimes()
+			mockLogService.EXPECT().
+				PostSecurityLogWithActorString(
+					gomock.Any(), gomock.Any(), gomock.Any(),
+				).
+				Return(nil).
+				AnyTimes()
+
+			r := gin.New()
+			r.POST("/otp/verify", handler.VerifyOTP)
+
+			body := `{"email":"jane@example.com","otp":"123456"}`
+			req, _ := http.NewRequest(
+				http.MethodPost,
+				"/otp/verify",
+				bytes.NewBufferString(body),
+			)
+			req.Header.Set("Content-Type", "application/json")
+			req.AddCookie(&http.Cookie{
+				Name:  service.SESSION_COOKIE_NAME,
+				Value: "session",
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s",
+					w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+/**
+ * TestVerifyOTP_AfterMagicLink verifies that an email code is refused as
+ * the second factor of a magic-link sign-in and never spent.
+ */
+func TestVerifyOTP_AfterMagicLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthService(ctrl)
+	handler := v1.NewOTPHandler(
+		mocks.NewMockOTPService(ctrl),
+		mocks.NewMockLogService(ctrl),
+		mocks.NewMockUserService(ctrl),
+		mockAuthService,
+		mocks.NewMockLockoutService(ctrl),
+	)
+
+	mockAuthService.EXPECT().
+		ValidateMFAPendingToken("pending").
+		Return(&service.MFAPendingClaims{
+			UserID:      uuid.NewString(),
+			Email:       "jane@example.com",
+			FirstFactor: models.AMREmailLink,
+		}, nil)
+
+	r := gin.New()
+	r.POST("/otp/verify", handler.VerifyOTP)
+
+	body := `{"email":"jane@example.com","otp":"123456"}`
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/otp/verify",
+		bytes.NewBufferString(body),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "idp_mfa_pending", Value: "pending"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,178 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// TestRateLimitMiddleware verifies that each rule counts its own key, that
+// the exhausted rule refuses the request with Retry-After without counting
+// it in the other rules, and that the JSON body still reaches the handler.
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	policies := middleware.RateLimitPolicies{
+		"login": {
+			{Key: middleware.RateLimitByIP, Limit: 5, Window: time.Minute},
+			{Key: middleware.RateLimitByEmail, Limit: 2, Window: time.Minute},
+		},
+	}
+	r.POST("/login",
+		middleware.RateLimitMiddleware(
+			cache.NewMemoryRateLimiter(),
+			policies,
+			"login",
+		),
+		func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.String(http.StatusOK, string(body))
+		},
+	)
+
+	login := func(email string) *httptest.ResponseRecorder {
+		body := `{"email":"` + email + `"}`
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/login",
+			strings.NewReader(body),
+		)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := login("a@example.com")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "a@") {
+		t.Fatalf("expected the body to reach the handler, got %d %q",
+			w.Code, w.Body.String())
+	}
+	if w.Header().Get("RateLimit-Limit") != "2" ||
+		w.Header().Get("RateLimit-Remaining") != "1" {
+		t.Errorf("expected the email rule in the headers, got %v",
+			w.Header())
+	}
+
+	// Emails are counted case-insensitively.
+	login("A@Example.com")
+	w = login("a@example.com")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	// Other emails are only bound by the IP rule, which did not count the
+	// refused request and has three requests left.
+	for _, email := range []string{
+		"b@example.com",
+		"c@example.com",
+		"d@example.com",
+	} {
+		if w = login(email); w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d", email, w.Code)
+		}
+	}
+	if w = login("e@example.com"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the IP rule to refuse, got %d", w.Code)
+	}
+}
+
+// TestRateLimitMiddleware_EmailPerIP verifies that the email_ip rule counts
+// an email separately for every client IP, so requests from one IP cannot
+// use up the limit of the same email elsewhere.
+func TestRateLimitMiddleware_EmailPerIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	policies := middleware.RateLimitPolicies{
+		"login": {
+			{Key: middleware.RateLimitByEmailIP, Limit: 1, Window: time.Minute},
+		},
+	}
+	r.POST("/login",
+		middleware.RateLimitMiddleware(
+			cache.NewMemoryRateLimiter(),
+			policies,
+			"login",
+		),
+		func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		},
+	)
+
+	login := func(remoteAddr string) int {
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/login",
+			strings.NewReader(`{"email":"a@example.com"}`),
+		)
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := login("192.0.2.1:1000"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := login("192.0.2.1:1001"); code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 from the same IP, got %d", code)
+	}
+	if code := login("198.51.100.7:1000"); code != http.StatusOK {
+		t.Errorf("expected 200 from another IP, got %d", code)
+	}
+}
+
+// TestParseRateLimitPolicies verifies that overrides replace, add and
+// remove rules without changing the base policies.
+func TestParseRateLimitPolicies(t *testing.T) {
+	base := middleware.RateLimitPolicies{
+		"login": {
+			{Key: middleware.RateLimitByIP, Limit: 15, Window: time.Minute},
+			{Key: middleware.RateLimitByEmail, Limit: 10, Window: time.Minute},
+		},
+	}
+
+	policies, err := middleware.ParseRateLimitPolicies(
+		"login:email=5/15m, login:ip=0/1m, token:client_id=100/1m",
+		base,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	login := policies["login"]
+	if len(login) != 1 || login[0].Key != middleware.RateLimitByEmail ||
+		login[0].Limit != 5 || login[0].Window != 15*time.Minute {
+		t.Errorf("unexpected login rules %+v", login)
+	}
+	if len(policies["token"]) != 1 {
+		t.Errorf("expected a token rule, got %+v", policies["token"])
+	}
+	if len(base["login"]) != 2 || base["login"][1].Limit != 10 {
+		t.Errorf("base policies were modified: %+v", base["login"])
+	}
+
+	for _, spec := range []string{
+		"login=5/1m",
+		"login:user=5/1m",
+		"login:ip=five/1m",
+		"login:ip=5/soon",
+	} {
+		if _, err := middleware.ParseRateLimitPolicies(spec, base); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
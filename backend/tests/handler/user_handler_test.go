@@ -3,6 +3,7 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -509,3 +510,120 @@ func TestPostRestoreUserHandler(t *testing.T) {
 		)
 	}
 }
+
+/**
+ * TestPatchUserPasswordByEmailHandler verifies that a forgotten password is
+ * only reset with a valid reset token and that every session of the user
+ * is ended afterwards.
+ */
+func TestPatchUserPasswordByEmailHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRequest := func() (*httptest.ResponseRecorder, *gin.Context) {
+		body, _ := json.Marshal(dto.UpdatePasswordByEmailRequest{
+			Email:       "user@example.com",
+			NewPassword: "N3w-Passw0rd!",
+			ResetToken:  "reset-token",
+		})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(
+			"PATCH",
+			"/user/password/forgot",
+			bytes.NewBuffer(body),
+		)
+		c.Request.Header.Set("Content-Type", "application/json")
+		return w, c
+	}
+
+	t.Run("rejects an invalid reset token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockUserService(ctrl)
+		mockOTPService := mocks.NewMockOTPService(ctrl)
+		mockLogService := mocks.NewMockLogService(ctrl)
+		handler := &v1.UserHandler{
+			Service:    mockService,
+			LogService: mockLogService,
+			OTPService: mockOTPService,
+		}
+
+		mockLogService.EXPECT().GetUserEmail(gomock.Any(), gomock.Any()).
+			Return("", nil).AnyTimes()
+		mockLogService.EXPECT().
+			PostAuditLogWithActorString(gomock.Any(), gomock.Any(),
+				gomock.Any()).
+			Return(nil).AnyTimes()
+		mockLogService.EXPECT().
+			PostSecurityLogWithActorString(gomock.Any(), gomock.Any(),
+				gomock.Any()).
+			Return(nil).AnyTimes()
+		mockOTPService.EXPECT().
+			ConsumeResetToken(gomock.Any(), "user@example.com",
+				"reset-token").
+			Return(errors.New("invalid reset token"))
+
+		w, c := newRequest()
+		handler.PatchUserPasswordByEmail(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("resets the password and ends sessions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mocks.NewMockUserService(ctrl)
+		mockOTPService := mocks.NewMockOTPService(ctrl)
+		mockAuthService := mocks.NewMockAuthService(ctrl)
+		mockLogoutService := mocks.NewMockLogoutService(ctrl)
+		mockLockoutService := mocks.NewMockLockoutService(ctrl)
+		mockLogService := mocks.NewMockLogService(ctrl)
+		handler := &v1.UserHandler{
+			Service:        mockService,
+			LogService:     mockLogService,
+			OTPService:     mockOTPService,
+			AuthService:    mockAuthService,
+			LogoutService:  mockLogoutService,
+			LockoutService: mockLockoutService,
+		}
+
+		userID := uuid.New()
+		mockLogService.EXPECT().GetUserEmail(gomock.Any(), gomock.Any()).
+			Return("", nil).AnyTimes()
+		mockLogService.EXPECT().
+			PostAuditLogWithActorString(gomock.Any(), gomock.Any(),
+				gomock.Any()).
+			Return(nil).AnyTimes()
+		mockOTPService.EXPECT().
+			ConsumeResetToken(gomock.Any(), "user@example.com",
+				"reset-token").
+			Return(nil)
+		mockService.EXPECT().
+			UpdateUserPasswordByEmail(gomock.Any(), "user@example.com",
+				"N3w-Passw0rd!").
+			Return(nil)
+		mockLockoutService.EXPECT().
+			Unlock(gomock.Any(), "user@example.com").
+			Return(nil)
+		mockService.EXPECT().
+			GetUserByEmail(gomock.Any(), "user@example.com").
+			Return(&dto.UserResponse{ID: userID.String()}, nil)
+		mockAuthService.EXPECT().
+			RevokeAllUserTokens(gomock.Any(), userID).
+			Return(nil)
+		mockLogoutService.EXPECT().
+			LogoutUser(gomock.Any(), userID).
+			Return(nil)
+
+		w, c := newRequest()
+		handler.PatchUserPasswordByEmail(c)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -46,18 +46,18 @@ func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
 }
 
 // Authorize mocks base method.
-func (m *MockAuthService) Authorize(ctx context.Context, clientIDStr, sessionToken string) (string, error) {
+func (m *MockAuthService) Authorize(ctx context.Context, req dto.AuthorizeRequest, sessionToken string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorize", ctx, clientIDStr, sessionToken)
+	ret := m.ctrl.Call(m, "Authorize", ctx, req, sessionToken)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Authorize indicates an expected call of Authorize.
-func (mr *MockAuthServiceMockRecorder) Authorize(ctx, clientIDStr, sessionToken any) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) Authorize(ctx, req, sessionToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAuthService)(nil).Authorize), ctx, clientIDStr, sessionToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAuthService)(nil).Authorize), ctx, req, sessionToken)
 }
 
 // CheckSessionOrPendingMFA mocks base method.
@@ -77,18 +77,32 @@ func (mr *MockAuthServiceMockRecorder) CheckSessionOrPendingMFA(c any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSessionOrPendingMFA", reflect.TypeOf((*MockAuthService)(nil).CheckSessionOrPendingMFA), c)
 }
 
+// CreatePasskeySessionAndSetCookie mocks base method.
+func (m *MockAuthService) CreatePasskeySessionAndSetCookie(c *gin.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasskeySessionAndSetCookie", c, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasskeySessionAndSetCookie indicates an expected call of CreatePasskeySessionAndSetCookie.
+func (mr *MockAuthServiceMockRecorder) CreatePasskeySessionAndSetCookie(c, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasskeySessionAndSetCookie", reflect.TypeOf((*MockAuthService)(nil).CreatePasskeySessionAndSetCookie), c, userID)
+}
+
 // CreateSessionAndSetCookie mocks base method.
-func (m *MockAuthService) CreateSessionAndSetCookie(c *gin.Context, userID uuid.UUID) error {
+func (m *MockAuthService) CreateSessionAndSetCookie(c *gin.Context, userID uuid.UUID, method string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSessionAndSetCookie", c, userID)
+	ret := m.ctrl.Call(m, "CreateSessionAndSetCookie", c, userID, method)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateSessionAndSetCookie indicates an expected call of CreateSessionAndSetCookie.
-func (mr *MockAuthServiceMockRecorder) CreateSessionAndSetCookie(c, userID any) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) CreateSessionAndSetCookie(c, userID, method any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionAndSetCookie", reflect.TypeOf((*MockAuthService)(nil).CreateSessionAndSetCookie), c, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionAndSetCookie", reflect.TypeOf((*MockAuthService)(nil).CreateSessionAndSetCookie), c, userID, method)
 }
 
 // ExchangeCodeForToken mocks base method.
@@ -107,18 +121,18 @@ func (mr *MockAuthServiceMockRecorder) ExchangeCodeForToken(ctx, req any) *gomoc
 }
 
 // GenerateMFAPendingToken mocks base method.
-func (m *MockAuthService) GenerateMFAPendingToken(userID, email, ip, ua string) (string, error) {
+func (m *MockAuthService) GenerateMFAPendingToken(userID, email, ip, ua, firstFactor string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateMFAPendingToken", userID, email, ip, ua)
+	ret := m.ctrl.Call(m, "GenerateMFAPendingToken", userID, email, ip, ua, firstFactor)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateMFAPendingToken indicates an expected call of GenerateMFAPendingToken.
-func (mr *MockAuthServiceMockRecorder) GenerateMFAPendingToken(userID, email, ip, ua any) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) GenerateMFAPendingToken(userID, email, ip, ua, firstFactor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateMFAPendingToken", reflect.TypeOf((*MockAuthService)(nil).GenerateMFAPendingToken), userID, email, ip, ua)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateMFAPendingToken", reflect.TypeOf((*MockAuthService)(nil).GenerateMFAPendingToken), userID, email, ip, ua, firstFactor)
 }
 
 // GetJWKS mocks base method.
@@ -136,19 +150,64 @@ func (mr *MockAuthServiceMockRecorder) GetJWKS(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJWKS", reflect.TypeOf((*MockAuthService)(nil).GetJWKS), ctx)
 }
 
+// GetOpenIDConfiguration mocks base method.
+func (m *MockAuthService) GetOpenIDConfiguration(ctx context.Context) (*dto.OpenIDConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenIDConfiguration", ctx)
+	ret0, _ := ret[0].(*dto.OpenIDConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenIDConfiguration indicates an expected call of GetOpenIDConfiguration.
+func (mr *MockAuthServiceMockRecorder) GetOpenIDConfiguration(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenIDConfiguration", reflect.TypeOf((*MockAuthService)(nil).GetOpenIDConfiguration), ctx)
+}
+
 // GetSessionToken mocks base method.
-func (m *MockAuthService) GetSessionToken(ctx context.Context, userID uuid.UUID, ipAddress, userAgent string) (string, error) {
+func (m *MockAuthService) GetSessionToken(ctx context.Context, userID uuid.UUID, ipAddress, userAgent, firstFactor, method string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSessionToken", ctx, userID, ipAddress, userAgent)
+	ret := m.ctrl.Call(m, "GetSessionToken", ctx, userID, ipAddress, userAgent, firstFactor, method)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSessionToken indicates an expected call of GetSessionToken.
-func (mr *MockAuthServiceMockRecorder) GetSessionToken(ctx, userID, ipAddress, userAgent any) *gomock.Call {
+func (mr *MockAuthServiceMockRecorder) GetSessionToken(ctx, userID, ipAddress, userAgent, firstFactor, method any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionToken", reflect.TypeOf((*MockAuthService)(nil).GetSessionToken), ctx, userID, ipAddress, userAgent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionToken", reflect.TypeOf((*MockAuthService)(nil).GetSessionToken), ctx, userID, ipAddress, userAgent, firstFactor, method)
+}
+
+// IntrospectToken mocks base method.
+func (m *MockAuthService) IntrospectToken(ctx context.Context, req dto.IntrospectionRequest) (*dto.IntrospectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IntrospectToken", ctx, req)
+	ret0, _ := ret[0].(*dto.IntrospectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IntrospectToken indicates an expected call of IntrospectToken.
+func (mr *MockAuthServiceMockRecorder) IntrospectToken(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IntrospectToken", reflect.TypeOf((*MockAuthService)(nil).IntrospectToken), ctx, req)
+}
+
+// IssueClientCredentialsToken mocks base method.
+func (m *MockAuthService) IssueClientCredentialsToken(ctx context.Context, req dto.TokenExchangeRequest) (*dto.TokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueClientCredentialsToken", ctx, req)
+	ret0, _ := ret[0].(*dto.TokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueClientCredentialsToken indicates an expected call of IssueClientCredentialsToken.
+func (mr *MockAuthServiceMockRecorder) IssueClientCredentialsToken(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueClientCredentialsToken", reflect.TypeOf((*MockAuthService)(nil).IssueClientCredentialsToken), ctx, req)
 }
 
 // LoginAndAuthorize mocks base method.
@@ -181,6 +240,35 @@ func (mr *MockAuthServiceMockRecorder) Logout(ctx, sessionID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthService)(nil).Logout), ctx, sessionID)
 }
 
+// PushAuthorizationRequest mocks base method.
+func (m *MockAuthService) PushAuthorizationRequest(ctx context.Context, req dto.PushedAuthorizationRequest) (*dto.PushedAuthorizationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushAuthorizationRequest", ctx, req)
+	ret0, _ := ret[0].(*dto.PushedAuthorizationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushAuthorizationRequest indicates an expected call of PushAuthorizationRequest.
+func (mr *MockAuthServiceMockRecorder) PushAuthorizationRequest(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushAuthorizationRequest", reflect.TypeOf((*MockAuthService)(nil).PushAuthorizationRequest), ctx, req)
+}
+
+// RecordSecondFactor mocks base method.
+func (m *MockAuthService) RecordSecondFactor(c *gin.Context, method string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSecondFactor", c, method)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSecondFactor indicates an expected call of RecordSecondFactor.
+func (mr *MockAuthServiceMockRecorder) RecordSecondFactor(c, method any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSecondFactor", reflect.TypeOf((*MockAuthService)(nil).RecordSecondFactor), c, method)
+}
+
 // RefreshBySession mocks base method.
 func (m *MockAuthService) RefreshBySession(ctx context.Context, sessionID, clientID string) (*dto.TokenResponse, error) {
 	m.ctrl.T.Helper()
@@ -196,6 +284,21 @@ func (mr *MockAuthServiceMockRecorder) RefreshBySession(ctx, sessionID, clientID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshBySession", reflect.TypeOf((*MockAuthService)(nil).RefreshBySession), ctx, sessionID, clientID)
 }
 
+// ResolveAuthorizeRequest mocks base method.
+func (m *MockAuthService) ResolveAuthorizeRequest(ctx context.Context, req dto.AuthorizeRequest, binding string) (dto.AuthorizeRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAuthorizeRequest", ctx, req, binding)
+	ret0, _ := ret[0].(dto.AuthorizeRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAuthorizeRequest indicates an expected call of ResolveAuthorizeRequest.
+func (mr *MockAuthServiceMockRecorder) ResolveAuthorizeRequest(ctx, req, binding any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAuthorizeRequest", reflect.TypeOf((*MockAuthService)(nil).ResolveAuthorizeRequest), ctx, req, binding)
+}
+
 // RevokeAllUserTokens mocks base method.
 func (m *MockAuthService) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -222,6 +325,20 @@ func (mr *MockAuthServiceMockRecorder) RevokeCookies(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeCookies", reflect.TypeOf((*MockAuthService)(nil).RevokeCookies), c)
 }
 
+// RevokeToken mocks base method.
+func (m *MockAuthService) RevokeToken(ctx context.Context, req dto.RevocationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockAuthServiceMockRecorder) RevokeToken(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockAuthService)(nil).RevokeToken), ctx, req)
+}
+
 // RotateRefreshToken mocks base method.
 func (m *MockAuthService) RotateRefreshToken(ctx context.Context, oldToken string) (*dto.TokenResponse, error) {
 	m.ctrl.T.Helper()
@@ -252,6 +369,21 @@ func (mr *MockAuthServiceMockRecorder) ValidateMFAPendingToken(tokenStr any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateMFAPendingToken", reflect.TypeOf((*MockAuthService)(nil).ValidateMFAPendingToken), tokenStr)
 }
 
+// ValidateRedirectURI mocks base method.
+func (m *MockAuthService) ValidateRedirectURI(ctx context.Context, clientID, redirectURI string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRedirectURI", ctx, clientID, redirectURI)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateRedirectURI indicates an expected call of ValidateRedirectURI.
+func (mr *MockAuthServiceMockRecorder) ValidateRedirectURI(ctx, clientID, redirectURI any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRedirectURI", reflect.TypeOf((*MockAuthService)(nil).ValidateRedirectURI), ctx, clientID, redirectURI)
+}
+
 // ValidateSession mocks base method.
 func (m *MockAuthService) ValidateSession(ctx context.Context, sessionID string) (*models.IdPSession, error) {
 	m.ctrl.T.Helper()
@@ -42,6 +42,35 @@ func (m *MockAuthCodeRepository) EXPECT() *MockAuthCodeRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ClaimPushedRequest mocks base method.
+func (m *MockAuthCodeRepository) ClaimPushedRequest(ctx context.Context, requestID, binding string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPushedRequest", ctx, requestID, binding)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPushedRequest indicates an expected call of ClaimPushedRequest.
+func (mr *MockAuthCodeRepositoryMockRecorder) ClaimPushedRequest(ctx, requestID, binding any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPushedRequest", reflect.TypeOf((*MockAuthCodeRepository)(nil).ClaimPushedRequest), ctx, requestID, binding)
+}
+
+// DeletePushedRequest mocks base method.
+func (m *MockAuthCodeRepository) DeletePushedRequest(ctx context.Context, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePushedRequest", ctx, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePushedRequest indicates an expected call of DeletePushedRequest.
+func (mr *MockAuthCodeRepositoryMockRecorder) DeletePushedRequest(ctx, requestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePushedRequest", reflect.TypeOf((*MockAuthCodeRepository)(nil).DeletePushedRequest), ctx, requestID)
+}
+
 // ExchangeCode mocks base method.
 func (m *MockAuthCodeRepository) ExchangeCode(ctx context.Context, code string) (*models.AuthorizationCode, error) {
 	m.ctrl.T.Helper()
@@ -103,11 +132,53 @@ func (mr *MockAuthCodeRepositoryMockRecorder) GetIDsFromToken(ctx, token any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDsFromToken", reflect.TypeOf((*MockAuthCodeRepository)(nil).GetIDsFromToken), ctx, token)
 }
 
+// GetIdentityByID mocks base method.
+func (m *MockAuthCodeRepository) GetIdentityByID(ctx context.Context, userId []byte) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentityByID", ctx, userId)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentityByID indicates an expected call of GetIdentityByID.
+func (mr *MockAuthCodeRepositoryMockRecorder) GetIdentityByID(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentityByID", reflect.TypeOf((*MockAuthCodeRepository)(nil).GetIdentityByID), ctx, userId)
+}
+
+// GetPushedRequest mocks base method.
+func (m *MockAuthCodeRepository) GetPushedRequest(ctx context.Context, requestID string) (*models.PushedAuthorizationRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPushedRequest", ctx, requestID)
+	ret0, _ := ret[0].(*models.PushedAuthorizationRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPushedRequest indicates an expected call of GetPushedRequest.
+func (mr *MockAuthCodeRepositoryMockRecorder) GetPushedRequest(ctx, requestID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPushedRequest", reflect.TypeOf((*MockAuthCodeRepository)(nil).GetPushedRequest), ctx, requestID)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockAuthCodeRepository) GetRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, token)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockAuthCodeRepositoryMockRecorder) GetRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockAuthCodeRepository)(nil).GetRefreshToken), ctx, token)
+}
+
 // GetUserForAuth mocks base method.
-func (m *MockAuthCodeRepository) GetUserForAuth(
-	ctx context.Context,
-	email string,
-) (*models.UserClaims, string, string, error) {
+func (m *MockAuthCodeRepository) GetUserForAuth(ctx context.Context, email string) (*models.UserClaims, string, string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserForAuth", ctx, email)
 	ret0, _ := ret[0].(*models.UserClaims)
@@ -123,6 +194,34 @@ func (mr *MockAuthCodeRepositoryMockRecorder) GetUserForAuth(ctx, email any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForAuth", reflect.TypeOf((*MockAuthCodeRepository)(nil).GetUserForAuth), ctx, email)
 }
 
+// RevokeRefreshToken mocks base method.
+func (m *MockAuthCodeRepository) RevokeRefreshToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockAuthCodeRepositoryMockRecorder) RevokeRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockAuthCodeRepository)(nil).RevokeRefreshToken), ctx, token)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockAuthCodeRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockAuthCodeRepositoryMockRecorder) RevokeRefreshTokenFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockAuthCodeRepository)(nil).RevokeRefreshTokenFamily), ctx, familyID)
+}
+
 // RevokeTokens mocks base method.
 func (m *MockAuthCodeRepository) RevokeTokens(ctx context.Context, userID []byte) error {
 	m.ctrl.T.Helper()
@@ -152,31 +251,45 @@ func (mr *MockAuthCodeRepositoryMockRecorder) RotateRefreshToken(ctx, oldToken,
 }
 
 // StoreCode mocks base method.
-func (m *MockAuthCodeRepository) StoreCode(ctx context.Context, code string, userID, clientID []byte, redirectURI string) error {
+func (m *MockAuthCodeRepository) StoreCode(ctx context.Context, authCode *models.AuthorizationCode) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreCode", ctx, code, userID, clientID, redirectURI)
+	ret := m.ctrl.Call(m, "StoreCode", ctx, authCode)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreCode indicates an expected call of StoreCode.
-func (mr *MockAuthCodeRepositoryMockRecorder) StoreCode(ctx, code, userID, clientID, redirectURI any) *gomock.Call {
+func (mr *MockAuthCodeRepositoryMockRecorder) StoreCode(ctx, authCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCode", reflect.TypeOf((*MockAuthCodeRepository)(nil).StoreCode), ctx, authCode)
+}
+
+// StorePushedRequest mocks base method.
+func (m *MockAuthCodeRepository) StorePushedRequest(ctx context.Context, par *models.PushedAuthorizationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorePushedRequest", ctx, par)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StorePushedRequest indicates an expected call of StorePushedRequest.
+func (mr *MockAuthCodeRepositoryMockRecorder) StorePushedRequest(ctx, par any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreCode", reflect.TypeOf((*MockAuthCodeRepository)(nil).StoreCode), ctx, code, userID, clientID, redirectURI)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorePushedRequest", reflect.TypeOf((*MockAuthCodeRepository)(nil).StorePushedRequest), ctx, par)
 }
 
 // StoreRefreshToken mocks base method.
-func (m *MockAuthCodeRepository) StoreRefreshToken(ctx context.Context, token string, userID, clientID []byte, expiresAt time.Time) error {
+func (m *MockAuthCodeRepository) StoreRefreshToken(ctx context.Context, token string, userID, clientID, familyID []byte, scope, sid string, expiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StoreRefreshToken", ctx, token, userID, clientID, expiresAt)
+	ret := m.ctrl.Call(m, "StoreRefreshToken", ctx, token, userID, clientID, familyID, scope, sid, expiresAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StoreRefreshToken indicates an expected call of StoreRefreshToken.
-func (mr *MockAuthCodeRepositoryMockRecorder) StoreRefreshToken(ctx, token, userID, clientID, expiresAt any) *gomock.Call {
+func (mr *MockAuthCodeRepositoryMockRecorder) StoreRefreshToken(ctx, token, userID, clientID, familyID, scope, sid, expiresAt any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRefreshToken", reflect.TypeOf((*MockAuthCodeRepository)(nil).StoreRefreshToken), ctx, token, userID, clientID, expiresAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRefreshToken", reflect.TypeOf((*MockAuthCodeRepository)(nil).StoreRefreshToken), ctx, token, userID, clientID, familyID, scope, sid, expiresAt)
 }
 
 // VerifyClient mocks base method.
@@ -156,6 +156,20 @@ func (mr *MockClientRepositoryMockRecorder) DeleteAndInsertGrants(ctx, c, grants
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAndInsertGrants", reflect.TypeOf((*MockClientRepository)(nil).DeleteAndInsertGrants), ctx, c, grants)
 }
 
+// DeleteAndInsertRedirectURIs mocks base method.
+func (m *MockClientRepository) DeleteAndInsertRedirectURIs(ctx context.Context, c *models.Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAndInsertRedirectURIs", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAndInsertRedirectURIs indicates an expected call of DeleteAndInsertRedirectURIs.
+func (mr *MockClientRepositoryMockRecorder) DeleteAndInsertRedirectURIs(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAndInsertRedirectURIs", reflect.TypeOf((*MockClientRepository)(nil).DeleteAndInsertRedirectURIs), ctx, c)
+}
+
 // GetByID mocks base method.
 func (m *MockClientRepository) GetByID(ctx context.Context, id []byte) (*models.Client, error) {
 	m.ctrl.T.Helper()
@@ -201,6 +215,21 @@ func (mr *MockClientRepositoryMockRecorder) GetGrantTypes(ctx, clientID any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrantTypes", reflect.TypeOf((*MockClientRepository)(nil).GetGrantTypes), ctx, clientID)
 }
 
+// GetRedirectURIs mocks base method.
+func (m *MockClientRepository) GetRedirectURIs(ctx context.Context, clientID []byte) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedirectURIs", ctx, clientID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedirectURIs indicates an expected call of GetRedirectURIs.
+func (mr *MockClientRepositoryMockRecorder) GetRedirectURIs(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedirectURIs", reflect.TypeOf((*MockClientRepository)(nil).GetRedirectURIs), ctx, clientID)
+}
+
 // IsClientAllowed mocks base method.
 func (m *MockClientRepository) IsClientAllowed(ctx context.Context, userID, clientID []byte) (bool, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +245,21 @@ func (mr *MockClientRepositoryMockRecorder) IsClientAllowed(ctx, userID, clientI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsClientAllowed", reflect.TypeOf((*MockClientRepository)(nil).IsClientAllowed), ctx, userID, clientID)
 }
 
+// IsUserAllowed mocks base method.
+func (m *MockClientRepository) IsUserAllowed(ctx context.Context, userID, clientID []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUserAllowed", ctx, userID, clientID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUserAllowed indicates an expected call of IsUserAllowed.
+func (mr *MockClientRepositoryMockRecorder) IsUserAllowed(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserAllowed", reflect.TypeOf((*MockClientRepository)(nil).IsUserAllowed), ctx, userID, clientID)
+}
+
 // ListAllowedClients mocks base method.
 func (m *MockClientRepository) ListAllowedClients(ctx context.Context, limit, offset int, keyword string, userID []byte, sortBy, order string) ([]models.Client, error) {
 	m.ctrl.T.Helper()
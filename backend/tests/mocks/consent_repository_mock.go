@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/consent_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/consent_repository.go -destination=tests/mocks/consent_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConsentRepository is a mock of ConsentRepository interface.
+type MockConsentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockConsentRepositoryMockRecorder is the mock recorder for MockConsentRepository.
+type MockConsentRepositoryMockRecorder struct {
+	mock *MockConsentRepository
+}
+
+// NewMockConsentRepository creates a new mock instance.
+func NewMockConsentRepository(ctrl *gomock.Controller) *MockConsentRepository {
+	mock := &MockConsentRepository{ctrl: ctrl}
+	mock.recorder = &MockConsentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsentRepository) EXPECT() *MockConsentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteConsent mocks base method.
+func (m *MockConsentRepository) DeleteConsent(ctx context.Context, userID, clientID []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConsent indicates an expected call of DeleteConsent.
+func (mr *MockConsentRepositoryMockRecorder) DeleteConsent(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConsent", reflect.TypeOf((*MockConsentRepository)(nil).DeleteConsent), ctx, userID, clientID)
+}
+
+// GetConsent mocks base method.
+func (m *MockConsentRepository) GetConsent(ctx context.Context, userID, clientID []byte) (*models.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(*models.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConsent indicates an expected call of GetConsent.
+func (mr *MockConsentRepositoryMockRecorder) GetConsent(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsent", reflect.TypeOf((*MockConsentRepository)(nil).GetConsent), ctx, userID, clientID)
+}
+
+// ListConsentsByUser mocks base method.
+func (m *MockConsentRepository) ListConsentsByUser(ctx context.Context, userID []byte) ([]models.UserConsent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConsentsByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.UserConsent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConsentsByUser indicates an expected call of ListConsentsByUser.
+func (mr *MockConsentRepositoryMockRecorder) ListConsentsByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConsentsByUser", reflect.TypeOf((*MockConsentRepository)(nil).ListConsentsByUser), ctx, userID)
+}
+
+// SaveConsent mocks base method.
+func (m *MockConsentRepository) SaveConsent(ctx context.Context, consent *models.UserConsent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveConsent", ctx, consent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveConsent indicates an expected call of SaveConsent.
+func (mr *MockConsentRepositoryMockRecorder) SaveConsent(ctx, consent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConsent", reflect.TypeOf((*MockConsentRepository)(nil).SaveConsent), ctx, consent)
+}
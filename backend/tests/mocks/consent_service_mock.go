@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/consent_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/consent_service.go -destination=tests/mocks/consent_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConsentService is a mock of ConsentService interface.
+type MockConsentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockConsentServiceMockRecorder is the mock recorder for MockConsentService.
+type MockConsentServiceMockRecorder struct {
+	mock *MockConsentService
+}
+
+// NewMockConsentService creates a new mock instance.
+func NewMockConsentService(ctrl *gomock.Controller) *MockConsentService {
+	mock := &MockConsentService{ctrl: ctrl}
+	mock.recorder = &MockConsentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsentService) EXPECT() *MockConsentServiceMockRecorder {
+	return m.recorder
+}
+
+// GetConsentPrompt mocks base method.
+func (m *MockConsentService) GetConsentPrompt(ctx context.Context, sessionToken, clientID, scope string) (*dto.ConsentPromptResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsentPrompt", ctx, sessionToken, clientID, scope)
+	ret0, _ := ret[0].(*dto.ConsentPromptResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConsentPrompt indicates an expected call of GetConsentPrompt.
+func (mr *MockConsentServiceMockRecorder) GetConsentPrompt(ctx, sessionToken, clientID, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsentPrompt", reflect.TypeOf((*MockConsentService)(nil).GetConsentPrompt), ctx, sessionToken, clientID, scope)
+}
+
+// GrantConsent mocks base method.
+func (m *MockConsentService) GrantConsent(ctx context.Context, sessionToken, clientID, scope string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantConsent", ctx, sessionToken, clientID, scope)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantConsent indicates an expected call of GrantConsent.
+func (mr *MockConsentServiceMockRecorder) GrantConsent(ctx, sessionToken, clientID, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantConsent", reflect.TypeOf((*MockConsentService)(nil).GrantConsent), ctx, sessionToken, clientID, scope)
+}
+
+// ListConsents mocks base method.
+func (m *MockConsentService) ListConsents(ctx context.Context, userID uuid.UUID) ([]dto.ConsentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConsents", ctx, userID)
+	ret0, _ := ret[0].([]dto.ConsentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConsents indicates an expected call of ListConsents.
+func (mr *MockConsentServiceMockRecorder) ListConsents(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConsents", reflect.TypeOf((*MockConsentService)(nil).ListConsents), ctx, userID)
+}
+
+// RevokeConsent mocks base method.
+func (m *MockConsentService) RevokeConsent(ctx context.Context, userID, clientID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeConsent indicates an expected call of RevokeConsent.
+func (mr *MockConsentServiceMockRecorder) RevokeConsent(ctx, userID, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeConsent", reflect.TypeOf((*MockConsentService)(nil).RevokeConsent), ctx, userID, clientID)
+}
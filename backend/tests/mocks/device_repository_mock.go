@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/device_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/device_repository.go -destination=tests/mocks/device_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceRepository is a mock of DeviceRepository interface.
+type MockDeviceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceRepositoryMockRecorder is the mock recorder for MockDeviceRepository.
+type MockDeviceRepositoryMockRecorder struct {
+	mock *MockDeviceRepository
+}
+
+// NewMockDeviceRepository creates a new mock instance.
+func NewMockDeviceRepository(ctrl *gomock.Controller) *MockDeviceRepository {
+	mock := &MockDeviceRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceRepository) EXPECT() *MockDeviceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Answer mocks base method.
+func (m *MockDeviceRepository) Answer(ctx context.Context, d *models.DeviceAuthorization) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Answer", ctx, d)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Answer indicates an expected call of Answer.
+func (mr *MockDeviceRepositoryMockRecorder) Answer(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Answer", reflect.TypeOf((*MockDeviceRepository)(nil).Answer), ctx, d)
+}
+
+// Create mocks base method.
+func (m *MockDeviceRepository) Create(ctx context.Context, d *models.DeviceAuthorization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceRepositoryMockRecorder) Create(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceRepository)(nil).Create), ctx, d)
+}
+
+// Delete mocks base method.
+func (m *MockDeviceRepository) Delete(ctx context.Context, deviceCode string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, deviceCode)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeviceRepositoryMockRecorder) Delete(ctx, deviceCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeviceRepository)(nil).Delete), ctx, deviceCode)
+}
+
+// GetByDeviceCode mocks base method.
+func (m *MockDeviceRepository) GetByDeviceCode(ctx context.Context, deviceCode string) (*models.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDeviceCode", ctx, deviceCode)
+	ret0, _ := ret[0].(*models.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDeviceCode indicates an expected call of GetByDeviceCode.
+func (mr *MockDeviceRepositoryMockRecorder) GetByDeviceCode(ctx, deviceCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDeviceCode", reflect.TypeOf((*MockDeviceRepository)(nil).GetByDeviceCode), ctx, deviceCode)
+}
+
+// GetByUserCode mocks base method.
+func (m *MockDeviceRepository) GetByUserCode(ctx context.Context, userCode string) (*models.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserCode", ctx, userCode)
+	ret0, _ := ret[0].(*models.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserCode indicates an expected call of GetByUserCode.
+func (mr *MockDeviceRepositoryMockRecorder) GetByUserCode(ctx, userCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserCode", reflect.TypeOf((*MockDeviceRepository)(nil).GetByUserCode), ctx, userCode)
+}
+
+// RecordPoll mocks base method.
+func (m *MockDeviceRepository) RecordPoll(ctx context.Context, deviceCode string, polledAt time.Time, interval int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPoll", ctx, deviceCode, polledAt, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPoll indicates an expected call of RecordPoll.
+func (mr *MockDeviceRepositoryMockRecorder) RecordPoll(ctx, deviceCode, polledAt, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPoll", reflect.TypeOf((*MockDeviceRepository)(nil).RecordPoll), ctx, deviceCode, polledAt, interval)
+}
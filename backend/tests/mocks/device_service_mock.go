@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/device_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/device_service.go -destination=tests/mocks/device_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeviceService is a mock of DeviceService interface.
+type MockDeviceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceServiceMockRecorder is the mock recorder for MockDeviceService.
+type MockDeviceServiceMockRecorder struct {
+	mock *MockDeviceService
+}
+
+// NewMockDeviceService creates a new mock instance.
+func NewMockDeviceService(ctrl *gomock.Controller) *MockDeviceService {
+	mock := &MockDeviceService{ctrl: ctrl}
+	mock.recorder = &MockDeviceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceService) EXPECT() *MockDeviceServiceMockRecorder {
+	return m.recorder
+}
+
+// AnswerDeviceVerification mocks base method.
+func (m *MockDeviceService) AnswerDeviceVerification(ctx context.Context, sessionToken string, req dto.DeviceVerificationRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnswerDeviceVerification", ctx, sessionToken, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnswerDeviceVerification indicates an expected call of AnswerDeviceVerification.
+func (mr *MockDeviceServiceMockRecorder) AnswerDeviceVerification(ctx, sessionToken, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerDeviceVerification", reflect.TypeOf((*MockDeviceService)(nil).AnswerDeviceVerification), ctx, sessionToken, req)
+}
+
+// GetDeviceVerification mocks base method.
+func (m *MockDeviceService) GetDeviceVerification(ctx context.Context, sessionToken, userCode string) (*dto.DeviceVerificationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeviceVerification", ctx, sessionToken, userCode)
+	ret0, _ := ret[0].(*dto.DeviceVerificationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeviceVerification indicates an expected call of GetDeviceVerification.
+func (mr *MockDeviceServiceMockRecorder) GetDeviceVerification(ctx, sessionToken, userCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceVerification", reflect.TypeOf((*MockDeviceService)(nil).GetDeviceVerification), ctx, sessionToken, userCode)
+}
+
+// PollDeviceCode mocks base method.
+func (m *MockDeviceService) PollDeviceCode(ctx context.Context, req dto.TokenExchangeRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollDeviceCode", ctx, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PollDeviceCode indicates an expected call of PollDeviceCode.
+func (mr *MockDeviceServiceMockRecorder) PollDeviceCode(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollDeviceCode", reflect.TypeOf((*MockDeviceService)(nil).PollDeviceCode), ctx, req)
+}
+
+// StartDeviceAuthorization mocks base method.
+func (m *MockDeviceService) StartDeviceAuthorization(ctx context.Context, req dto.DeviceAuthorizationRequest) (*dto.DeviceAuthorizationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDeviceAuthorization", ctx, req)
+	ret0, _ := ret[0].(*dto.DeviceAuthorizationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartDeviceAuthorization indicates an expected call of StartDeviceAuthorization.
+func (mr *MockDeviceServiceMockRecorder) StartDeviceAuthorization(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDeviceAuthorization", reflect.TypeOf((*MockDeviceService)(nil).StartDeviceAuthorization), ctx, req)
+}
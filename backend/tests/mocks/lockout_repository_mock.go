@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/lockout_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/lockout_repository.go -destination=tests/mocks/lockout_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLockoutRepository is a mock of LockoutRepository interface.
+type MockLockoutRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLockoutRepositoryMockRecorder is the mock recorder for MockLockoutRepository.
+type MockLockoutRepositoryMockRecorder struct {
+	mock *MockLockoutRepository
+}
+
+// NewMockLockoutRepository creates a new mock instance.
+func NewMockLockoutRepository(ctrl *gomock.Controller) *MockLockoutRepository {
+	mock := &MockLockoutRepository{ctrl: ctrl}
+	mock.recorder = &MockLockoutRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockoutRepository) EXPECT() *MockLockoutRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteFailure mocks base method.
+func (m *MockLockoutRepository) DeleteFailure(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFailure", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFailure indicates an expected call of DeleteFailure.
+func (mr *MockLockoutRepositoryMockRecorder) DeleteFailure(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFailure", reflect.TypeOf((*MockLockoutRepository)(nil).DeleteFailure), ctx, email)
+}
+
+// GetFailure mocks base method.
+func (m *MockLockoutRepository) GetFailure(ctx context.Context, email string) (*models.LoginFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailure", ctx, email)
+	ret0, _ := ret[0].(*models.LoginFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFailure indicates an expected call of GetFailure.
+func (mr *MockLockoutRepositoryMockRecorder) GetFailure(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailure", reflect.TypeOf((*MockLockoutRepository)(nil).GetFailure), ctx, email)
+}
+
+// GetPolicy mocks base method.
+func (m *MockLockoutRepository) GetPolicy(ctx context.Context) (*models.LockoutPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", ctx)
+	ret0, _ := ret[0].(*models.LockoutPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockLockoutRepositoryMockRecorder) GetPolicy(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockLockoutRepository)(nil).GetPolicy), ctx)
+}
+
+// Lock mocks base method.
+func (m *MockLockoutRepository) Lock(ctx context.Context, email string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, email, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLockoutRepositoryMockRecorder) Lock(ctx, email, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLockoutRepository)(nil).Lock), ctx, email, until)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLockoutRepository) RecordFailure(ctx context.Context, email string, failedAt, windowStart, expiresAt time.Time) (*models.LoginFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, email, failedAt, windowStart, expiresAt)
+	ret0, _ := ret[0].(*models.LoginFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLockoutRepositoryMockRecorder) RecordFailure(ctx, email, failedAt, windowStart, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLockoutRepository)(nil).RecordFailure), ctx, email, failedAt, windowStart, expiresAt)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockLockoutRepository) UpdatePolicy(ctx context.Context, p *models.LockoutPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockLockoutRepositoryMockRecorder) UpdatePolicy(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockLockoutRepository)(nil).UpdatePolicy), ctx, p)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/lockout_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/lockout_service.go -destination=tests/mocks/lockout_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	service "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLockoutService is a mock of LockoutService interface.
+type MockLockoutService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockLockoutServiceMockRecorder is the mock recorder for MockLockoutService.
+type MockLockoutServiceMockRecorder struct {
+	mock *MockLockoutService
+}
+
+// NewMockLockoutService creates a new mock instance.
+func NewMockLockoutService(ctrl *gomock.Controller) *MockLockoutService {
+	mock := &MockLockoutService{ctrl: ctrl}
+	mock.recorder = &MockLockoutServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockoutService) EXPECT() *MockLockoutServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckLogin mocks base method.
+func (m *MockLockoutService) CheckLogin(ctx context.Context, email string) (*service.LockoutStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLogin", ctx, email)
+	ret0, _ := ret[0].(*service.LockoutStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLogin indicates an expected call of CheckLogin.
+func (mr *MockLockoutServiceMockRecorder) CheckLogin(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLogin", reflect.TypeOf((*MockLockoutService)(nil).CheckLogin), ctx, email)
+}
+
+// GetPolicy mocks base method.
+func (m *MockLockoutService) GetPolicy(ctx context.Context) (*dto.LockoutPolicyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", ctx)
+	ret0, _ := ret[0].(*dto.LockoutPolicyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockLockoutServiceMockRecorder) GetPolicy(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockLockoutService)(nil).GetPolicy), ctx)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLockoutService) RecordFailure(ctx context.Context, email string) (*service.LockoutStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, email)
+	ret0, _ := ret[0].(*service.LockoutStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLockoutServiceMockRecorder) RecordFailure(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLockoutService)(nil).RecordFailure), ctx, email)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockLockoutService) RecordSuccess(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSuccess", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockLockoutServiceMockRecorder) RecordSuccess(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockLockoutService)(nil).RecordSuccess), ctx, email)
+}
+
+// Unlock mocks base method.
+func (m *MockLockoutService) Unlock(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockLockoutServiceMockRecorder) Unlock(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLockoutService)(nil).Unlock), ctx, email)
+}
+
+// UnlockUser mocks base method.
+func (m *MockLockoutService) UnlockUser(ctx context.Context, id uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockLockoutServiceMockRecorder) UnlockUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockLockoutService)(nil).UnlockUser), ctx, id)
+}
+
+// UpdatePolicy mocks base method.
+func (m *MockLockoutService) UpdatePolicy(ctx context.Context, req dto.LockoutPolicyRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePolicy", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePolicy indicates an expected call of UpdatePolicy.
+func (mr *MockLockoutServiceMockRecorder) UpdatePolicy(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePolicy", reflect.TypeOf((*MockLockoutService)(nil).UpdatePolicy), ctx, req)
+}
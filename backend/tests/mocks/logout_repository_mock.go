@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/logout_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/logout_repository.go -destination=tests/mocks/logout_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLogoutRepository is a mock of LogoutRepository interface.
+type MockLogoutRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogoutRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLogoutRepositoryMockRecorder is the mock recorder for MockLogoutRepository.
+type MockLogoutRepositoryMockRecorder struct {
+	mock *MockLogoutRepository
+}
+
+// NewMockLogoutRepository creates a new mock instance.
+func NewMockLogoutRepository(ctrl *gomock.Controller) *MockLogoutRepository {
+	mock := &MockLogoutRepository{ctrl: ctrl}
+	mock.recorder = &MockLogoutRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogoutRepository) EXPECT() *MockLogoutRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockLogoutRepository) Claim(ctx context.Context, id int64, until time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, id, until)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockLogoutRepositoryMockRecorder) Claim(ctx, id, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockLogoutRepository)(nil).Claim), ctx, id, until)
+}
+
+// Delete mocks base method.
+func (m *MockLogoutRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLogoutRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLogoutRepository)(nil).Delete), ctx, id)
+}
+
+// Enqueue mocks base method.
+func (m *MockLogoutRepository) Enqueue(ctx context.Context, n *models.LogoutNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockLogoutRepositoryMockRecorder) Enqueue(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockLogoutRepository)(nil).Enqueue), ctx, n)
+}
+
+// ListDue mocks base method.
+func (m *MockLogoutRepository) ListDue(ctx context.Context, limit int) ([]models.LogoutNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, limit)
+	ret0, _ := ret[0].([]models.LogoutNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockLogoutRepositoryMockRecorder) ListDue(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockLogoutRepository)(nil).ListDue), ctx, limit)
+}
+
+// Reschedule mocks base method.
+func (m *MockLogoutRepository) Reschedule(ctx context.Context, id int64, attempts int, next time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, id, attempts, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockLogoutRepositoryMockRecorder) Reschedule(ctx, id, attempts, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockLogoutRepository)(nil).Reschedule), ctx, id, attempts, next)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/logout_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/logout_service.go -destination=tests/mocks/logout_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLogoutService is a mock of LogoutService interface.
+type MockLogoutService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogoutServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockLogoutServiceMockRecorder is the mock recorder for MockLogoutService.
+type MockLogoutServiceMockRecorder struct {
+	mock *MockLogoutService
+}
+
+// NewMockLogoutService creates a new mock instance.
+func NewMockLogoutService(ctrl *gomock.Controller) *MockLogoutService {
+	mock := &MockLogoutService{ctrl: ctrl}
+	mock.recorder = &MockLogoutServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLogoutService) EXPECT() *MockLogoutServiceMockRecorder {
+	return m.recorder
+}
+
+// DeliverPending mocks base method.
+func (m *MockLogoutService) DeliverPending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverPending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverPending indicates an expected call of DeliverPending.
+func (mr *MockLogoutServiceMockRecorder) DeliverPending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverPending", reflect.TypeOf((*MockLogoutService)(nil).DeliverPending), ctx)
+}
+
+// EndSession mocks base method.
+func (m *MockLogoutService) EndSession(ctx context.Context, req dto.EndSessionRequest, sessionID string) (*dto.EndSessionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndSession", ctx, req, sessionID)
+	ret0, _ := ret[0].(*dto.EndSessionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndSession indicates an expected call of EndSession.
+func (mr *MockLogoutServiceMockRecorder) EndSession(ctx, req, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndSession", reflect.TypeOf((*MockLogoutService)(nil).EndSession), ctx, req, sessionID)
+}
+
+// LogoutUser mocks base method.
+func (m *MockLogoutService) LogoutUser(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutUser indicates an expected call of LogoutUser.
+func (mr *MockLogoutServiceMockRecorder) LogoutUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutUser", reflect.TypeOf((*MockLogoutService)(nil).LogoutUser), ctx, userID)
+}
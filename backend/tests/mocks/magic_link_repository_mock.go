@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/magic_link_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/magic_link_repository.go -destination=tests/mocks/magic_link_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMagicLinkRepository is a mock of MagicLinkRepository interface.
+type MockMagicLinkRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkRepositoryMockRecorder is the mock recorder for MockMagicLinkRepository.
+type MockMagicLinkRepositoryMockRecorder struct {
+	mock *MockMagicLinkRepository
+}
+
+// NewMockMagicLinkRepository creates a new mock instance.
+func NewMockMagicLinkRepository(ctrl *gomock.Controller) *MockMagicLinkRepository {
+	mock := &MockMagicLinkRepository{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkRepository) EXPECT() *MockMagicLinkRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeToken mocks base method.
+func (m *MockMagicLinkRepository) ConsumeToken(ctx context.Context, tokenHash string) (*models.MagicLinkToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.MagicLinkToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeToken indicates an expected call of ConsumeToken.
+func (mr *MockMagicLinkRepositoryMockRecorder) ConsumeToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeToken", reflect.TypeOf((*MockMagicLinkRepository)(nil).ConsumeToken), ctx, tokenHash)
+}
+
+// CreateToken mocks base method.
+func (m *MockMagicLinkRepository) CreateToken(ctx context.Context, token *models.MagicLinkToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockMagicLinkRepositoryMockRecorder) CreateToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockMagicLinkRepository)(nil).CreateToken), ctx, token)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/magic_link_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/magic_link_service.go -destination=tests/mocks/magic_link_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	service "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMagicLinkService is a mock of MagicLinkService interface.
+type MockMagicLinkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkServiceMockRecorder is the mock recorder for MockMagicLinkService.
+type MockMagicLinkServiceMockRecorder struct {
+	mock *MockMagicLinkService
+}
+
+// NewMockMagicLinkService creates a new mock instance.
+func NewMockMagicLinkService(ctrl *gomock.Controller) *MockMagicLinkService {
+	mock := &MockMagicLinkService{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkService) EXPECT() *MockMagicLinkServiceMockRecorder {
+	return m.recorder
+}
+
+// ConsumeLink mocks base method.
+func (m *MockMagicLinkService) ConsumeLink(ctx context.Context, token string) (*service.MagicLinkLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeLink", ctx, token)
+	ret0, _ := ret[0].(*service.MagicLinkLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeLink indicates an expected call of ConsumeLink.
+func (mr *MockMagicLinkServiceMockRecorder) ConsumeLink(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeLink", reflect.TypeOf((*MockMagicLinkService)(nil).ConsumeLink), ctx, token)
+}
+
+// RequestLink mocks base method.
+func (m *MockMagicLinkService) RequestLink(ctx context.Context, email, clientID, redirectURI, authorizeQuery string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestLink", ctx, email, clientID, redirectURI, authorizeQuery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestLink indicates an expected call of RequestLink.
+func (mr *MockMagicLinkServiceMockRecorder) RequestLink(ctx, email, clientID, redirectURI, authorizeQuery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestLink", reflect.TypeOf((*MockMagicLinkService)(nil).RequestLink), ctx, email, clientID, redirectURI, authorizeQuery)
+}
@@ -85,6 +85,36 @@ func (mr *MockMFARepositoryMockRecorder) GetAuthenticatorsByUserIDAndType(ctx, u
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthenticatorsByUserIDAndType", reflect.TypeOf((*MockMFARepository)(nil).GetAuthenticatorsByUserIDAndType), ctx, userID, authType)
 }
 
+// GetSecretsToRewrap mocks base method.
+func (m *MockMFARepository) GetSecretsToRewrap(ctx context.Context, keyVersion int, afterID []byte, limit int) ([]models.UserAuthenticator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecretsToRewrap", ctx, keyVersion, afterID, limit)
+	ret0, _ := ret[0].([]models.UserAuthenticator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecretsToRewrap indicates an expected call of GetSecretsToRewrap.
+func (mr *MockMFARepositoryMockRecorder) GetSecretsToRewrap(ctx, keyVersion, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecretsToRewrap", reflect.TypeOf((*MockMFARepository)(nil).GetSecretsToRewrap), ctx, keyVersion, afterID, limit)
+}
+
+// HasTOTP mocks base method.
+func (m *MockMFARepository) HasTOTP(ctx context.Context, userID []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasTOTP", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasTOTP indicates an expected call of HasTOTP.
+func (mr *MockMFARepositoryMockRecorder) HasTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTOTP", reflect.TypeOf((*MockMFARepository)(nil).HasTOTP), ctx, userID)
+}
+
 // InsertAuthenticator mocks base method.
 func (m *MockMFARepository) InsertAuthenticator(ctx context.Context, auth *models.UserAuthenticator) error {
 	m.ctrl.T.Helper()
@@ -113,25 +143,16 @@ func (mr *MockMFARepositoryMockRecorder) UpdateLastUsedAt(ctx, id any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsedAt", reflect.TypeOf((*MockMFARepository)(nil).UpdateLastUsedAt), ctx, id)
 }
 
-// HasTOTP mocks base method.
-func (m *MockMFARepository) HasTOTP(
-	ctx context.Context, userID []byte,
-) (bool, error) {
+// UpdateSecret mocks base method.
+func (m *MockMFARepository) UpdateSecret(ctx context.Context, id, secret []byte, keyVersion int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HasTOTP", ctx, userID)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateSecret", ctx, id, secret, keyVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// HasTOTP indicates an expected call of HasTOTP.
-func (mr *MockMFARepositoryMockRecorder) HasTOTP(
-	ctx, userID any,
-) *gomock.Call {
+// UpdateSecret indicates an expected call of UpdateSecret.
+func (mr *MockMFARepositoryMockRecorder) UpdateSecret(ctx, id, secret, keyVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(
-		mr.mock, "HasTOTP",
-		reflect.TypeOf((*MockMFARepository)(nil).HasTOTP),
-		ctx, userID,
-	)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockMFARepository)(nil).UpdateSecret), ctx, id, secret, keyVersion)
 }
@@ -87,6 +87,21 @@ func (mr *MockMFAServiceMockRecorder) GetAuthenticatorList(ctx, userID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthenticatorList", reflect.TypeOf((*MockMFAService)(nil).GetAuthenticatorList), ctx, userID)
 }
 
+// HasTOTP mocks base method.
+func (m *MockMFAService) HasTOTP(ctx context.Context, userID []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasTOTP", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasTOTP indicates an expected call of HasTOTP.
+func (mr *MockMFAServiceMockRecorder) HasTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTOTP", reflect.TypeOf((*MockMFAService)(nil).HasTOTP), ctx, userID)
+}
+
 // RemoveAuthenticator mocks base method.
 func (m *MockMFAService) RemoveAuthenticator(ctx context.Context, id, userID []byte) error {
 	m.ctrl.T.Helper()
@@ -101,6 +116,21 @@ func (mr *MockMFAServiceMockRecorder) RemoveAuthenticator(ctx, id, userID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAuthenticator", reflect.TypeOf((*MockMFAService)(nil).RemoveAuthenticator), ctx, id, userID)
 }
 
+// RewrapSecrets mocks base method.
+func (m *MockMFAService) RewrapSecrets(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RewrapSecrets", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RewrapSecrets indicates an expected call of RewrapSecrets.
+func (mr *MockMFAServiceMockRecorder) RewrapSecrets(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RewrapSecrets", reflect.TypeOf((*MockMFAService)(nil).RewrapSecrets), ctx)
+}
+
 // SetupTOTP mocks base method.
 func (m *MockMFAService) SetupTOTP(ctx context.Context, userID []byte, email string) (string, []string, error) {
 	m.ctrl.T.Helper()
@@ -131,26 +161,3 @@ func (mr *MockMFAServiceMockRecorder) VerifyCode(ctx, userID, code any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCode", reflect.TypeOf((*MockMFAService)(nil).VerifyCode), ctx, userID, code)
 }
-
-// HasTOTP mocks base method.
-func (m *MockMFAService) HasTOTP(
-	ctx context.Context, userID []byte,
-) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HasTOTP", ctx, userID)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// HasTOTP indicates an expected call of HasTOTP.
-func (mr *MockMFAServiceMockRecorder) HasTOTP(
-	ctx, userID any,
-) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(
-		mr.mock, "HasTOTP",
-		reflect.TypeOf((*MockMFAService)(nil).HasTOTP),
-		ctx, userID,
-	)
-}
@@ -41,6 +41,21 @@ func (m *MockOTPRepository) EXPECT() *MockOTPRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ConsumeResetToken mocks base method.
+func (m *MockOTPRepository) ConsumeResetToken(ctx context.Context, tokenHash, email, purpose string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeResetToken", ctx, tokenHash, email, purpose)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeResetToken indicates an expected call of ConsumeResetToken.
+func (mr *MockOTPRepositoryMockRecorder) ConsumeResetToken(ctx, tokenHash, email, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeResetToken", reflect.TypeOf((*MockOTPRepository)(nil).ConsumeResetToken), ctx, tokenHash, email, purpose)
+}
+
 // CreateOTP mocks base method.
 func (m *MockOTPRepository) CreateOTP(ctx context.Context, otp *models.OTP) error {
 	m.ctrl.T.Helper()
@@ -55,6 +70,20 @@ func (mr *MockOTPRepositoryMockRecorder) CreateOTP(ctx, otp any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOTP", reflect.TypeOf((*MockOTPRepository)(nil).CreateOTP), ctx, otp)
 }
 
+// CreateResetToken mocks base method.
+func (m *MockOTPRepository) CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResetToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateResetToken indicates an expected call of CreateResetToken.
+func (mr *MockOTPRepositoryMockRecorder) CreateResetToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetToken", reflect.TypeOf((*MockOTPRepository)(nil).CreateResetToken), ctx, token)
+}
+
 // DeleteExpiredOTPs mocks base method.
 func (m *MockOTPRepository) DeleteExpiredOTPs(ctx context.Context) error {
 	m.ctrl.T.Helper()
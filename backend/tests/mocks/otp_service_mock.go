@@ -40,11 +40,37 @@ func (m *MockOTPService) EXPECT() *MockOTPServiceMockRecorder {
 	return m.recorder
 }
 
+// ConsumeResetToken mocks base method.
+func (m *MockOTPService) ConsumeResetToken(ctx context.Context, email, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeResetToken", ctx, email, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeResetToken indicates an expected call of ConsumeResetToken.
+func (mr *MockOTPServiceMockRecorder) ConsumeResetToken(ctx, email, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeResetToken", reflect.TypeOf((*MockOTPService)(nil).ConsumeResetToken), ctx, email, token)
+}
+
+// IssueResetToken mocks base method.
+func (m *MockOTPService) IssueResetToken(ctx context.Context, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueResetToken", ctx, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueResetToken indicates an expected call of IssueResetToken.
+func (mr *MockOTPServiceMockRecorder) IssueResetToken(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueResetToken", reflect.TypeOf((*MockOTPService)(nil).IssueResetToken), ctx, email)
+}
+
 // SendOTP mocks base method.
-func (m *MockOTPService) SendOTP(
-	ctx context.Context,
-	email string,
-) (int, bool, error) {
+func (m *MockOTPService) SendOTP(ctx context.Context, email string) (int, bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SendOTP", ctx, email)
 	ret0, _ := ret[0].(int)
@@ -54,17 +80,9 @@ func (m *MockOTPService) SendOTP(
 }
 
 // SendOTP indicates an expected call of SendOTP.
-func (mr *MockOTPServiceMockRecorder) SendOTP(
-	ctx, email any,
-) *gomock.Call {
+func (mr *MockOTPServiceMockRecorder) SendOTP(ctx, email any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(
-		mr.mock,
-		"SendOTP",
-		reflect.TypeOf((*MockOTPService)(nil).SendOTP),
-		ctx,
-		email,
-	)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendOTP", reflect.TypeOf((*MockOTPService)(nil).SendOTP), ctx, email)
 }
 
 // VerifyOTP mocks base method.
@@ -14,6 +14,7 @@ import (
 	http "net/http"
 	reflect "reflect"
 
+	dto "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +42,21 @@ func (m *MockPasskeyService) EXPECT() *MockPasskeyServiceMockRecorder {
 	return m.recorder
 }
 
+// BeginLogin mocks base method.
+func (m *MockPasskeyService) BeginLogin(ctx context.Context, conditional bool, r *http.Request) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx, conditional, r)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockPasskeyServiceMockRecorder) BeginLogin(ctx, conditional, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockPasskeyService)(nil).BeginLogin), ctx, conditional, r)
+}
+
 // BeginRegistration mocks base method.
 func (m *MockPasskeyService) BeginRegistration(ctx context.Context, email string, platformAvailable bool, r *http.Request) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +87,21 @@ func (mr *MockPasskeyServiceMockRecorder) BeginVerification(ctx, email, platform
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginVerification", reflect.TypeOf((*MockPasskeyService)(nil).BeginVerification), ctx, email, platformAvailable, r)
 }
 
+// FinishLogin mocks base method.
+func (m *MockPasskeyService) FinishLogin(ctx context.Context, r *http.Request) (*dto.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", ctx, r)
+	ret0, _ := ret[0].(*dto.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockPasskeyServiceMockRecorder) FinishLogin(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockPasskeyService)(nil).FinishLogin), ctx, r)
+}
+
 // FinishRegistration mocks base method.
 func (m *MockPasskeyService) FinishRegistration(ctx context.Context, email string, r *http.Request) error {
 	m.ctrl.T.Helper()
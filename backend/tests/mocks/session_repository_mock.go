@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,20 @@ func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddClient mocks base method.
+func (m *MockSessionRepository) AddClient(ctx context.Context, sc *models.SessionClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddClient", ctx, sc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddClient indicates an expected call of AddClient.
+func (mr *MockSessionRepositoryMockRecorder) AddClient(ctx, sc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddClient", reflect.TypeOf((*MockSessionRepository)(nil).AddClient), ctx, sc)
+}
+
 // Create mocks base method.
 func (m *MockSessionRepository) Create(ctx context.Context, s *models.IdPSession) error {
 	m.ctrl.T.Helper()
@@ -69,6 +84,34 @@ func (mr *MockSessionRepositoryMockRecorder) Delete(ctx, sessionID any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionRepository)(nil).Delete), ctx, sessionID)
 }
 
+// DeleteByUser mocks base method.
+func (m *MockSessionRepository) DeleteByUser(ctx context.Context, userID []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockSessionRepositoryMockRecorder) DeleteByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockSessionRepository)(nil).DeleteByUser), ctx, userID)
+}
+
+// DeleteClients mocks base method.
+func (m *MockSessionRepository) DeleteClients(ctx context.Context, sid string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClients", ctx, sid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClients indicates an expected call of DeleteClients.
+func (mr *MockSessionRepositoryMockRecorder) DeleteClients(ctx, sid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClients", reflect.TypeOf((*MockSessionRepository)(nil).DeleteClients), ctx, sid)
+}
+
 // DeleteExpired mocks base method.
 func (m *MockSessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -98,3 +141,61 @@ func (mr *MockSessionRepositoryMockRecorder) GetByID(ctx, sessionID any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSessionRepository)(nil).GetByID), ctx, sessionID)
 }
+
+// ListClients mocks base method.
+func (m *MockSessionRepository) ListClients(ctx context.Context, sid string) ([]models.SessionClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClients", ctx, sid)
+	ret0, _ := ret[0].([]models.SessionClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClients indicates an expected call of ListClients.
+func (mr *MockSessionRepositoryMockRecorder) ListClients(ctx, sid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockSessionRepository)(nil).ListClients), ctx, sid)
+}
+
+// ListClientsByUser mocks base method.
+func (m *MockSessionRepository) ListClientsByUser(ctx context.Context, userID []byte) ([]models.SessionClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClientsByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.SessionClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClientsByUser indicates an expected call of ListClientsByUser.
+func (mr *MockSessionRepositoryMockRecorder) ListClientsByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClientsByUser", reflect.TypeOf((*MockSessionRepository)(nil).ListClientsByUser), ctx, userID)
+}
+
+// RevokeRefreshTokens mocks base method.
+func (m *MockSessionRepository) RevokeRefreshTokens(ctx context.Context, sid string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokens", ctx, sid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokens indicates an expected call of RevokeRefreshTokens.
+func (mr *MockSessionRepositoryMockRecorder) RevokeRefreshTokens(ctx, sid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokens", reflect.TypeOf((*MockSessionRepository)(nil).RevokeRefreshTokens), ctx, sid)
+}
+
+// UpdateAuthContext mocks base method.
+func (m *MockSessionRepository) UpdateAuthContext(ctx context.Context, sessionID, amr string, mfaTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthContext", ctx, sessionID, amr, mfaTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthContext indicates an expected call of UpdateAuthContext.
+func (mr *MockSessionRepositoryMockRecorder) UpdateAuthContext(ctx, sessionID, amr, mfaTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthContext", reflect.TypeOf((*MockSessionRepository)(nil).UpdateAuthContext), ctx, sessionID, amr, mfaTime)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/signing_key_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/signing_key_repository.go -destination=tests/mocks/signing_key_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSigningKeyRepository is a mock of SigningKeyRepository interface.
+type MockSigningKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSigningKeyRepositoryMockRecorder is the mock recorder for MockSigningKeyRepository.
+type MockSigningKeyRepositoryMockRecorder struct {
+	mock *MockSigningKeyRepository
+}
+
+// NewMockSigningKeyRepository creates a new mock instance.
+func NewMockSigningKeyRepository(ctrl *gomock.Controller) *MockSigningKeyRepository {
+	mock := &MockSigningKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockSigningKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningKeyRepository) EXPECT() *MockSigningKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateInitialSigningKey mocks base method.
+func (m *MockSigningKeyRepository) CreateInitialSigningKey(ctx context.Context, key *models.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInitialSigningKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInitialSigningKey indicates an expected call of CreateInitialSigningKey.
+func (mr *MockSigningKeyRepositoryMockRecorder) CreateInitialSigningKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInitialSigningKey", reflect.TypeOf((*MockSigningKeyRepository)(nil).CreateInitialSigningKey), ctx, key)
+}
+
+// GetSigningKeys mocks base method.
+func (m *MockSigningKeyRepository) GetSigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigningKeys", ctx)
+	ret0, _ := ret[0].([]models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigningKeys indicates an expected call of GetSigningKeys.
+func (mr *MockSigningKeyRepositoryMockRecorder) GetSigningKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigningKeys", reflect.TypeOf((*MockSigningKeyRepository)(nil).GetSigningKeys), ctx)
+}
+
+// RotateSigningKey mocks base method.
+func (m *MockSigningKeyRepository) RotateSigningKey(ctx context.Context, currentKeyID string, next *models.SigningKey, retiredUntil time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSigningKey", ctx, currentKeyID, next, retiredUntil)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSigningKey indicates an expected call of RotateSigningKey.
+func (mr *MockSigningKeyRepositoryMockRecorder) RotateSigningKey(ctx, currentKeyID, next, retiredUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSigningKey", reflect.TypeOf((*MockSigningKeyRepository)(nil).RotateSigningKey), ctx, currentKeyID, next, retiredUntil)
+}
+
+// UpdateSigningKeyPrivateKey mocks base method.
+func (m *MockSigningKeyRepository) UpdateSigningKeyPrivateKey(ctx context.Context, keyID, privateKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSigningKeyPrivateKey", ctx, keyID, privateKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSigningKeyPrivateKey indicates an expected call of UpdateSigningKeyPrivateKey.
+func (mr *MockSigningKeyRepositoryMockRecorder) UpdateSigningKeyPrivateKey(ctx, keyID, privateKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSigningKeyPrivateKey", reflect.TypeOf((*MockSigningKeyRepository)(nil).UpdateSigningKeyPrivateKey), ctx, keyID, privateKey)
+}
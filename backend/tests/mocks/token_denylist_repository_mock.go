@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/token_denylist_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/token_denylist_repository.go -destination=tests/mocks/token_denylist_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenDenylistRepository is a mock of TokenDenylistRepository interface.
+type MockTokenDenylistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenDenylistRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenDenylistRepositoryMockRecorder is the mock recorder for MockTokenDenylistRepository.
+type MockTokenDenylistRepositoryMockRecorder struct {
+	mock *MockTokenDenylistRepository
+}
+
+// NewMockTokenDenylistRepository creates a new mock instance.
+func NewMockTokenDenylistRepository(ctrl *gomock.Controller) *MockTokenDenylistRepository {
+	mock := &MockTokenDenylistRepository{ctrl: ctrl}
+	mock.recorder = &MockTokenDenylistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenDenylistRepository) EXPECT() *MockTokenDenylistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetWatermark mocks base method.
+func (m *MockTokenDenylistRepository) GetWatermark(ctx context.Context, userID string) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWatermark", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWatermark indicates an expected call of GetWatermark.
+func (mr *MockTokenDenylistRepositoryMockRecorder) GetWatermark(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWatermark", reflect.TypeOf((*MockTokenDenylistRepository)(nil).GetWatermark), ctx, userID)
+}
+
+// IsJTIRevoked mocks base method.
+func (m *MockTokenDenylistRepository) IsJTIRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsJTIRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsJTIRevoked indicates an expected call of IsJTIRevoked.
+func (mr *MockTokenDenylistRepositoryMockRecorder) IsJTIRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsJTIRevoked", reflect.TypeOf((*MockTokenDenylistRepository)(nil).IsJTIRevoked), ctx, jti)
+}
+
+// RevokeJTI mocks base method.
+func (m *MockTokenDenylistRepository) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeJTI", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeJTI indicates an expected call of RevokeJTI.
+func (mr *MockTokenDenylistRepositoryMockRecorder) RevokeJTI(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeJTI", reflect.TypeOf((*MockTokenDenylistRepository)(nil).RevokeJTI), ctx, jti, expiresAt)
+}
+
+// SetWatermark mocks base method.
+func (m *MockTokenDenylistRepository) SetWatermark(ctx context.Context, userID string, notBefore int64, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWatermark", ctx, userID, notBefore, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWatermark indicates an expected call of SetWatermark.
+func (mr *MockTokenDenylistRepositoryMockRecorder) SetWatermark(ctx, userID, notBefore, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWatermark", reflect.TypeOf((*MockTokenDenylistRepository)(nil).SetWatermark), ctx, userID, notBefore, expiresAt)
+}
@@ -205,6 +205,21 @@ func (mr *MockUserServiceMockRecorder) GetUserByID(ctx, id, adminID, permissions
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserService)(nil).GetUserByID), ctx, id, adminID, permissions)
 }
 
+// GetUserInfo mocks base method.
+func (m *MockUserService) GetUserInfo(ctx context.Context, userID uuid.UUID, scope string) (*dto.OIDCUserInfoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfo", ctx, userID, scope)
+	ret0, _ := ret[0].(*dto.OIDCUserInfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfo indicates an expected call of GetUserInfo.
+func (mr *MockUserServiceMockRecorder) GetUserInfo(ctx, userID, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockUserService)(nil).GetUserInfo), ctx, userID, scope)
+}
+
 // GetUserList mocks base method.
 func (m *MockUserService) GetUserList(ctx context.Context, limit, page int, sortBy, order string) (*dto.UserSimplifiedResponseList, error) {
 	m.ctrl.T.Helper()
@@ -55,7 +55,16 @@ func TestGetClientByID(t *testing.T) {
 		WithArgs(clientID[:]).
 		WillReturnRows(grantRows)
 
-	// 3. Mock allowed roles query
+	// 3. Mock registered redirect URIs query
+	redirectRows := sqlmock.NewRows([]string{"redirect_uri"}).
+		AddRow("http://localhost:3000/callback").
+		AddRow("http://localhost:3000/silent-callback")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT redirect_uri FROM client_redirect_uris")).
+		WithArgs(clientID[:]).
+		WillReturnRows(redirectRows)
+
+	// 4. Mock allowed roles query
 	roleRows := sqlmock.NewRows([]string{"id", "role_name", "description"})
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT r.id, r.role_name")).
@@ -76,6 +85,10 @@ func TestGetClientByID(t *testing.T) {
 		t.Errorf("expected name %s, got %s", clientName, client.ClientName)
 	}
 
+	if len(client.RedirectUris) != 2 {
+		t.Errorf("expected 2 redirect URIs, got %d", len(client.RedirectUris))
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("unmet expectations: %s", err)
 	}
@@ -167,3 +180,36 @@ func TestSyncAdminClientBind(t *testing.T) {
 		t.Errorf("unmet expectations: %s", err)
 	}
 }
+
+/**
+ * TestIsUserAllowed verifies that access is checked against both the
+ * individual assignments and the client's allowed roles.
+ */
+func TestIsUserAllowed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewClientRepository(sqlx.NewDb(db, "mysql"))
+	userID, clientID := []byte("user"), []byte("client")
+
+	mock.ExpectQuery(
+		"client_allowed_users(.|\n)*role_id(.|\n)*admin_allowed_clients",
+	).
+		WithArgs(userID, clientID, userID, clientID).
+		WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(true))
+
+	allowed, err := repo.IsUserAllowed(context.Background(), userID, clientID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !allowed {
+		t.Error("expected user to be allowed")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %s", err)
+	}
+}
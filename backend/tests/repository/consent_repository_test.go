@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+/**
+ * TestDeleteConsent verifies that revoking a consent also revokes the
+ * refresh tokens of the client in the same transaction.
+ */
+func TestDeleteConsent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewConsentRepository(sqlx.NewDb(db, "mysql"))
+	userID, clientID := []byte("user"), []byte("client")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_consents")).
+		WithArgs(userID, clientID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE refresh_tokens")).
+		WithArgs(userID, clientID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	found, err := repo.DeleteConsent(context.Background(), userID, clientID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !found {
+		t.Error("expected consent to be found")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %s", err)
+	}
+}
+
+/**
+ * TestDeleteConsent_NotFound verifies that no tokens are revoked when the
+ * user never consented.
+ */
+func TestDeleteConsent_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewConsentRepository(sqlx.NewDb(db, "mysql"))
+	userID, clientID := []byte("user"), []byte("client")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_consents")).
+		WithArgs(userID, clientID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	found, err := repo.DeleteConsent(context.Background(), userID, clientID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if found {
+		t.Error("expected consent not to be found")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %s", err)
+	}
+}
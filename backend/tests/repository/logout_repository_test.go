@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+/**
+ * TestClaimLogoutNotification verifies that a notification can only be
+ * claimed while it is due, so one instance delivers it.
+ */
+func TestClaimLogoutNotification(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		want     bool
+	}{
+		{name: "due", affected: 1, want: true},
+		{name: "claimed elsewhere", affected: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("failed to open sqlmock: %s", err)
+			}
+			defer db.Close()
+
+			repo := repository.NewLogoutRepository(sqlx.NewDb(db, "mysql"))
+			until := time.Now().Add(time.Minute)
+
+			mock.ExpectExec(regexp.QuoteMeta(
+				"UPDATE logout_notifications SET next_attempt_at = ?",
+			)).
+				WithArgs(until, int64(7)).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			claimed, err := repo.Claim(context.Background(), 7, until)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if claimed != tt.want {
+				t.Errorf("expected claimed %v, got %v", tt.want, claimed)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %s", err)
+			}
+		})
+	}
+}
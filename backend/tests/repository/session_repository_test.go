@@ -54,3 +54,61 @@ func TestGetSessionByID(t *testing.T) {
 		t.Errorf("unmet expectations: %s", err)
 	}
 }
+
+/**
+ * TestUpdateSessionAuthContext verifies that a step-up stores the
+ * session's methods and second factor time.
+ */
+func TestUpdateSessionAuthContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSessionRepository(sqlx.NewDb(db, "mysql"))
+	now := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE idp_sessions SET amr = ?")).
+		WithArgs("pwd otp totp", now, "sess-123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateAuthContext(
+		context.Background(), "sess-123", "pwd otp totp", now,
+	)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %s", err)
+	}
+}
+
+/**
+ * TestRevokeSessionRefreshTokens verifies that ending a session revokes
+ * only the live refresh tokens issued under its sid.
+ */
+func TestRevokeSessionRefreshTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSessionRepository(sqlx.NewDb(db, "mysql"))
+
+	mock.ExpectExec("UPDATE refresh_tokens(.|\n)*WHERE sid = \\? " +
+		"AND revoked_at IS NULL").
+		WithArgs("sid-123").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.RevokeRefreshTokens(context.Background(), "sid-123")
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %s", err)
+	}
+}
@@ -0,0 +1,94 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+/**
+ * TestRotateSigningKey verifies that the current key is retired and the
+ * next key activated in one transaction.
+ */
+func TestRotateSigningKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSigningKeyRepository(sqlx.NewDb(db, "mysql"))
+	next := &models.SigningKey{
+		KeyID:      "next-kid",
+		Algorithm:  "RS256",
+		PrivateKey: "private-pem",
+		PublicKey:  "public-pem",
+	}
+	retiredUntil := time.Now().Add(24 * time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE signing_keys")).
+		WithArgs(retiredUntil, "current-kid").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO signing_keys")).
+		WithArgs("next-kid", "RS256", "private-pem", "public-pem").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rotated, err := repo.RotateSigningKey(
+		context.Background(),
+		"current-kid",
+		next,
+		retiredUntil,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rotated {
+		t.Error("expected the key to be rotated")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+/**
+ * TestRotateSigningKey_AlreadyRotated verifies that no key is inserted
+ * when another instance retired the current key first.
+ */
+func TestRotateSigningKey_AlreadyRotated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %s", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSigningKeyRepository(sqlx.NewDb(db, "mysql"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE signing_keys")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	rotated, err := repo.RotateSigningKey(
+		context.Background(),
+		"current-kid",
+		&models.SigningKey{KeyID: "next-kid"},
+		time.Now(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated {
+		t.Error("expected no rotation")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
@@ -4,23 +4,31 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"database/sql"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 )
 
 /**
- * TestAuthLogout verifies that logout revokes tokens and deletes session.
+ * TestAuthLogout verifies that logout revokes tokens, moves the user's
+ * access token watermark and deletes the session.
  */
 func TestAuthLogout(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -29,16 +37,20 @@ func TestAuthLogout(t *testing.T) {
 	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
 	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
 	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	store := &mockCache{store: make(map[string]string)}
 
 	authService := service.NewAuthService(
 		mockAuthRepo,
 		mockSessionRepo,
 		mockClientRepo,
-		nil, nil, // Keys not needed for logout
+		nil,
+		nil, // Keys not needed for logout
+		service.NewTokenDenylist(store),
 	)
 
 	sessionID := "valid-session-id"
-	userID := []byte("user-uuid")
+	userUUID := uuid.New()
+	userID := userUUID[:]
 	session := &models.IdPSession{
 		SessionId: sessionID,
 		UserId:    userID,
@@ -70,10 +82,14 @@ func TestAuthLogout(t *testing.T) {
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
+	if _, ok := store.store["auth:tokens_not_before:"+userUUID.String()]; !ok {
+		t.Errorf("expected access token watermark to be set")
+	}
 }
 
 /**
- * TestCheckSessionOrPendingMFA_Fallback verifies fallback token validation.
+ * TestCheckSessionOrPendingMFA_Fallback verifies fallback token validation
+ * and that only user access tokens and pending MFA tokens are accepted.
  */
 func TestCheckSessionOrPendingMFA_Fallback(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -83,7 +99,7 @@ func TestCheckSessionOrPendingMFA_Fallback(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to generate rsa key: %v", err)
 	}
-	publicKey := &privateKey.PublicKey
+	keys := testKeyStore(privateKey)
 
 	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
 	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
@@ -93,13 +109,13 @@ func TestCheckSessionOrPendingMFA_Fallback(t *testing.T) {
 		mockAuthRepo,
 		mockSessionRepo,
 		mockClientRepo,
-		privateKey,
-		publicKey,
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
-	// Set CLIENT_BASE_URL and KEY_ID env for token generation
+	// Set CLIENT_BASE_URL env for token generation
 	os.Setenv("CLIENT_BASE_URL", "http://localhost:8080")
-	os.Setenv("KEY_ID", "test-key-id")
 
 	userID := uuid.New()
 	clientID := uuid.New()
@@ -114,18 +130,23 @@ func TestCheckSessionOrPendingMFA_Fallback(t *testing.T) {
 	}
 
 	// 1. Generate OIDC Access Token
-	accessToken, err := service.GenerateToken(privateKey, client, claims)
+	accessToken, err := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		client,
+		claims,
+	)
 	if err != nil {
 		t.Fatalf("failed to generate access token: %v", err)
 	}
 
 	// 2. Generate Pending MFA Token
 	pendingToken, err := service.GenerateMFAPendingToken(
-		privateKey,
+		keys.ActiveKey(models.SigningAlgRS256),
 		userID.String(),
 		"test@email.com",
 		"127.0.0.1",
 		"Mozilla",
+		models.AMRPassword,
 	)
 	if err != nil {
 		t.Fatalf("failed to generate pending mfa token: %v", err)
@@ -152,6 +173,33 @@ func TestCheckSessionOrPendingMFA_Fallback(t *testing.T) {
 		}
 	})
 
+	// 3. Generate ID Token naming the user as subject
+	idClaims := models.IDTokenClaims{}
+	idClaims.Subject = userID.String()
+	idToken, err := service.GenerateIDToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		client,
+		idClaims,
+		accessToken,
+	)
+	if err != nil {
+		t.Fatalf("failed to generate id token: %v", err)
+	}
+
+	// Test that an ID Token held by a client is rejected
+	t.Run("ID Token Rejected", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest("GET", "/mfa/verify", nil)
+		req.Header.Set("Authorization", "Bearer "+idToken)
+		c.Request = req
+
+		if _, _, _, err := s.CheckSessionOrPendingMFA(c); err == nil {
+			t.Error("expected id token to be rejected")
+		}
+	})
+
 	// Test Pending MFA Token in Authorization header
 	t.Run("Pending MFA Token", func(t *testing.T) {
 		gin.SetMode(gin.TestMode)
@@ -190,7 +238,9 @@ func TestLoginAndAuthorize_SuspendedUser(t *testing.T) {
 		mockAuthRepo,
 		mockSessionRepo,
 		mockClientRepo,
-		nil, nil,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	req := dto.LoginRequest{
@@ -213,3 +263,1622 @@ func TestLoginAndAuthorize_SuspendedUser(t *testing.T) {
 		t.Errorf("expected suspended error, got %v", err)
 	}
 }
+
+/**
+ * TestCreatePasskeySessionAndSetCookie verifies that a passwordless passkey
+ * session records amr=hwk alone and sets the session cookie.
+ */
+func TestCreatePasskeySessionAndSetCookie(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	authService := service.NewAuthService(
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mockSessionRepo,
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	userID := uuid.New()
+	var stored *models.IdPSession
+	mockSessionRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s *models.IdPSession) error {
+			stored = s
+			return nil
+		})
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/auth/passkey/login/finish", nil)
+
+	err := authService.CreatePasskeySessionAndSetCookie(c, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.AMR != models.AMRHardwareKey {
+		t.Errorf("expected amr %q, got %q", models.AMRHardwareKey, stored.AMR)
+	}
+	if uuid.UUID(stored.UserId) != userID || !stored.MFATime.Valid {
+		t.Errorf("unexpected session %+v", stored)
+	}
+	cookie := w.Header().Get("Set-Cookie")
+	if !strings.Contains(cookie, service.SESSION_COOKIE_NAME+"="+
+		stored.SessionId) {
+		t.Errorf("expected the session cookie, got %q", cookie)
+	}
+}
+
+/**
+ * TestCreateSessionAndSetCookie_MagicLink verifies that a session after a
+ * magic link records amr=email with its second factor, and that an email
+ * OTP is refused as that second factor.
+ */
+func TestCreateSessionAndSetCookie_MagicLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	keys := testKeyStore(privateKey)
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	authService := service.NewAuthService(
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mockSessionRepo,
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	userID := uuid.New()
+	pendingToken, err := service.GenerateMFAPendingToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		userID.String(),
+		"user@example.com",
+		"127.0.0.1",
+		"Mozilla",
+		models.AMREmailLink,
+	)
+	if err != nil {
+		t.Fatalf("failed to generate pending mfa token: %v", err)
+	}
+
+	newContext := func() (*gin.Context, *httptest.ResponseRecorder) {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/mfa/verify", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  "idp_mfa_pending",
+			Value: pendingToken,
+		})
+		return c, w
+	}
+
+	c, _ := newContext()
+	err = authService.CreateSessionAndSetCookie(c, userID, models.AMROTP)
+	if err == nil {
+		t.Fatal("expected an email otp after a magic link to be refused")
+	}
+
+	var stored *models.IdPSession
+	mockSessionRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s *models.IdPSession) error {
+			stored = s
+			return nil
+		})
+
+	c, w := newContext()
+	err = authService.CreateSessionAndSetCookie(c, userID, models.AMRTOTP)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantAMR := models.AMREmailLink + " " + models.AMRTOTP
+	if stored.AMR != wantAMR {
+		t.Errorf("expected amr %q, got %q", wantAMR, stored.AMR)
+	}
+	if !stored.MFATime.Valid {
+		t.Error("expected a second factor time on the session")
+	}
+	if !strings.Contains(w.Header().Get("Set-Cookie"),
+		service.SESSION_COOKIE_NAME+"="+stored.SessionId) {
+		t.Errorf("expected the session cookie, got %q",
+			w.Header().Get("Set-Cookie"))
+	}
+}
+
+/**
+ * TestExchangeCodeForToken_PKCEPublicClient verifies that a public client
+ * can redeem a code with a matching code_verifier and no secret.
+ */
+func TestExchangeCodeForToken_PKCEPublicClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+	mockAuthRepo.EXPECT().
+		ExchangeCode(gomock.Any(), "auth-code").
+		Return(&models.AuthorizationCode{
+			ClientId:            clientID[:],
+			UserId:              userID[:],
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: models.PKCEMethodS256,
+		}, nil)
+
+	mockAuthRepo.EXPECT().
+		GetClaimsByID(gomock.Any(), userID[:]).
+		Return(&models.UserClaims{UserID: userID.String()}, nil)
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:], PublicClient: true}, nil)
+
+	mockClientRepo.EXPECT().
+		GetGrantTypes(gomock.Any(), clientID[:]).
+		Return([]string{"authorization_code"}, nil)
+
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil)
+
+	res, err := authService.ExchangeCodeForToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			Code:         "auth-code",
+			CodeVerifier: verifier,
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.AccessToken == "" {
+		t.Error("expected access token to be issued")
+	}
+}
+
+/**
+ * TestExchangeCodeForToken_PKCEMismatch verifies that a wrong
+ * code_verifier is rejected before any token is issued.
+ */
+func TestExchangeCodeForToken_PKCEMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:], PublicClient: true}, nil)
+
+	mockAuthRepo.EXPECT().
+		ExchangeCode(gomock.Any(), "auth-code").
+		Return(&models.AuthorizationCode{
+			ClientId:            clientID[:],
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: models.PKCEMethodS256,
+		}, nil)
+
+	_, err := authService.ExchangeCodeForToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			Code:         "auth-code",
+			CodeVerifier: strings.Repeat("a", 43),
+		},
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "pkce") {
+		t.Errorf("expected pkce error, got %v", err)
+	}
+}
+
+/**
+ * TestExchangeCodeForToken_ConfidentialRequiresSecret verifies that a
+ * confidential client cannot swap its secret for a code_verifier.
+ */
+func TestExchangeCodeForToken_ConfidentialRequiresSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+
+	_, err := authService.ExchangeCodeForToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			Code:         "auth-code",
+			CodeVerifier: strings.Repeat("a", 43),
+		},
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "client verification") {
+		t.Errorf("expected client verification error, got %v", err)
+	}
+}
+
+/**
+ * TestExchangeCodeForToken_UserNotAllowed verifies that a code is not
+ * redeemed once the user has lost access to the client.
+ */
+func TestExchangeCodeForToken_UserNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	verifier := strings.Repeat("a", 43)
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:], PublicClient: true}, nil)
+
+	mockAuthRepo.EXPECT().
+		ExchangeCode(gomock.Any(), "auth-code").
+		Return(&models.AuthorizationCode{
+			UserId:              []byte("user"),
+			ClientId:            clientID[:],
+			CodeChallenge:       verifier,
+			CodeChallengeMethod: models.PKCEMethodPlain,
+		}, nil)
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), []byte("user"), clientID[:]).
+		Return(false, nil)
+
+	_, err := authService.ExchangeCodeForToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			Code:         "auth-code",
+			CodeVerifier: verifier,
+		},
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("expected access denied, got %v", err)
+	}
+}
+
+/**
+ * TestExchangeCodeForToken_IssuesIDToken verifies that an "openid" code
+ * yields a signed ID token carrying the nonce, auth context and the
+ * claims released by the granted scopes.
+ */
+func TestExchangeCodeForToken_IssuesIDToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+	authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), clientID[:], "secret").
+		Return(true, nil)
+
+	mockAuthRepo.EXPECT().
+		ExchangeCode(gomock.Any(), "auth-code").
+		Return(&models.AuthorizationCode{
+			ClientId: clientID[:],
+			UserId:   userID[:],
+			Scope:    "openid email",
+			Nonce:    "n-0S6_WzA2Mj",
+			AuthTime: sql.NullTime{Time: authTime, Valid: true},
+			AMR:      "pwd mfa",
+			ACR:      models.ACRMultiFactor,
+		}, nil)
+
+	mockAuthRepo.EXPECT().
+		GetClaimsByID(gomock.Any(), userID[:]).
+		Return(&models.UserClaims{UserID: userID.String()}, nil)
+
+	mockAuthRepo.EXPECT().
+		GetIdentityByID(gomock.Any(), userID[:]).
+		Return(&models.User{
+			ID:        userID[:],
+			FirstName: "Juan",
+			LastName:  "Dela Cruz",
+			Email:     "juan@example.com",
+		}, nil)
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+
+	mockClientRepo.EXPECT().
+		GetGrantTypes(gomock.Any(), clientID[:]).
+		Return([]string{"authorization_code"}, nil)
+
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil)
+
+	res, err := authService.ExchangeCodeForToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			ClientSecret: "secret",
+			Code:         "auth-code",
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	claims := &models.IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(res.IDToken, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return &privKey.PublicKey, nil
+		})
+	if err != nil {
+		t.Fatalf("expected valid id_token, got %v", err)
+	}
+
+	if claims.Subject != userID.String() {
+		t.Errorf("expected sub %s, got %s", userID, claims.Subject)
+	}
+	if claims.Nonce != "n-0S6_WzA2Mj" {
+		t.Errorf("expected nonce to be echoed, got %q", claims.Nonce)
+	}
+	if claims.AuthTime != authTime.Unix() {
+		t.Errorf("expected auth_time %d, got %d",
+			authTime.Unix(), claims.AuthTime)
+	}
+	if claims.Email != "juan@example.com" || claims.Name != "" {
+		t.Errorf("expected only email claims, got %+v", claims)
+	}
+	if claims.AccessTokenHash == "" || res.Scope != "openid email" {
+		t.Errorf("expected at_hash and scope, got %+v", res)
+	}
+}
+
+/**
+ * TestNormalizeScope verifies unsupported and duplicate scopes are dropped.
+ */
+func TestNormalizeScope(t *testing.T) {
+	got := service.NormalizeScope("openid  admin email openid")
+	if got != "openid email" {
+		t.Errorf("expected %q, got %q", "openid email", got)
+	}
+}
+
+/**
+ * TestAuthorize_RedirectURIAndState verifies that a registered secondary
+ * redirect URI is honoured, stored on the code and that state is echoed.
+ */
+func TestAuthorize_RedirectURIAndState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	requested := "https://app.example.com/silent?mode=popup"
+
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			UserId:    []byte("user"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:           clientID[:],
+			RedirectUri:  "https://app.example.com/callback",
+			RedirectUris: []string{requested},
+			Grants:       []string{string(models.GrantAuthCode)},
+		}, nil)
+
+	mockAuthRepo.EXPECT().
+		StoreCode(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			code *models.AuthorizationCode,
+		) error {
+			if code.RedirectURI != requested {
+				t.Errorf("expected stored redirect %q, got %q",
+					requested, code.RedirectURI)
+			}
+			if code.Sid == "" || code.Sid == "session" {
+				t.Errorf("expected a sid derived from the session, got %q",
+					code.Sid)
+			}
+			return nil
+		})
+	mockSessionRepo.EXPECT().
+		AddClient(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil)
+
+	redirectURL, err := authService.Authorize(
+		context.Background(),
+		dto.AuthorizeRequest{
+			ClientID:    clientID.String(),
+			RedirectURI: requested,
+			State:       "xyz 123",
+		},
+		"session",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	parsed, _ := url.Parse(redirectURL)
+	if parsed.Path != "/silent" || parsed.Query().Get("mode") != "popup" {
+		t.Errorf("expected registered redirect, got %s", redirectURL)
+	}
+	if parsed.Query().Get("state") != "xyz 123" {
+		t.Errorf("expected state to be echoed, got %s", redirectURL)
+	}
+	if parsed.Query().Get("code") == "" {
+		t.Errorf("expected code in redirect, got %s", redirectURL)
+	}
+}
+
+/**
+ * TestAuthorize_RequireConsent verifies that clients requiring consent
+ * only receive a code once the stored consent covers every requested
+ * scope.
+ */
+func TestAuthorize_RequireConsent(t *testing.T) {
+	tests := []struct {
+		name    string
+		consent *models.UserConsent
+		wantErr bool
+	}{
+		{name: "no consent", consent: nil, wantErr: true},
+		{
+			name:    "missing scope",
+			consent: &models.UserConsent{Scope: "openid"},
+			wantErr: true,
+		},
+		{
+			name:    "covered",
+			consent: &models.UserConsent{Scope: "openid profile email"},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+			mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+			mockClientRepo := mocks.NewMockClientRepository(ctrl)
+			mockConsentRepo := mocks.NewMockConsentRepository(ctrl)
+
+			authService := service.NewAuthService(
+				mockAuthRepo,
+				mockSessionRepo,
+				mockClientRepo,
+				mockConsentRepo,
+				nil,
+				service.NewTokenDenylist(cache.NewNoopCache()),
+			)
+
+			clientID := uuid.New()
+			mockSessionRepo.EXPECT().
+				GetByID(gomock.Any(), "session").
+				Return(&models.IdPSession{
+					UserId:    []byte("user"),
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil)
+			mockClientRepo.EXPECT().
+				GetByID(gomock.Any(), clientID[:]).
+				Return(&models.Client{
+					ID:             clientID[:],
+					RedirectUri:    "https://app.example.com/callback",
+					Grants:         []string{string(models.GrantAuthCode)},
+					RequireConsent: true,
+				}, nil)
+			mockConsentRepo.EXPECT().
+				GetConsent(gomock.Any(), []byte("user"), clientID[:]).
+				Return(tt.consent, nil)
+			if !tt.wantErr {
+				mockAuthRepo.EXPECT().
+					StoreCode(gomock.Any(), gomock.Any()).
+					Return(nil)
+				mockSessionRepo.EXPECT().
+					AddClient(gomock.Any(), gomock.Any()).
+					Return(nil)
+			}
+
+			mockClientRepo.EXPECT().
+				IsUserAllowed(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(true, nil)
+
+			_, err := authService.Authorize(
+				context.Background(),
+				dto.AuthorizeRequest{
+					ClientID: clientID.String(),
+					Scope:    "openid profile",
+				},
+				"session",
+			)
+			if tt.wantErr {
+				if err == nil ||
+					!strings.Contains(err.Error(), "consent required") {
+					t.Fatalf("expected consent required, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+/**
+ * TestAuthorize_UserNotAllowed verifies that users who are neither
+ * assigned to the client nor hold one of its roles get no code.
+ */
+func TestAuthorize_UserNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			UserId:    []byte("user"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:          clientID[:],
+			RedirectUri: "https://app.example.com/callback",
+			Grants:      []string{string(models.GrantAuthCode)},
+		}, nil)
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), []byte("user"), clientID[:]).
+		Return(false, nil)
+
+	_, err := authService.Authorize(
+		context.Background(),
+		dto.AuthorizeRequest{ClientID: clientID.String()},
+		"session",
+	)
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("expected access denied, got %v", err)
+	}
+}
+
+/**
+ * TestAuthorize_ReauthenticationRequired verifies that prompt=login,
+ * a max_age the session has outlived and a remembered auth_after the
+ * session predates require a fresh login, and that prompt=none cannot be
+ * combined with other values.
+ */
+func TestAuthorize_ReauthenticationRequired(t *testing.T) {
+	tests := []struct {
+		name      string
+		prompt    string
+		maxAge    string
+		authAfter string
+		wantErr   string
+	}{
+		{name: "prompt login", prompt: "login", wantErr: "login required"},
+		{
+			name:    "select account",
+			prompt:  "select_account",
+			wantErr: "login required",
+		},
+		{name: "max age exceeded", maxAge: "60", wantErr: "login required"},
+		{name: "max age invalid", maxAge: "-1", wantErr: "invalid request"},
+		{
+			name:      "session predates auth after",
+			authAfter: strconv.FormatInt(time.Now().Unix(), 10),
+			wantErr:   "login required",
+		},
+		{
+			name:      "auth after invalid",
+			authAfter: "later",
+			wantErr:   "login required",
+		},
+		{
+			name:    "none combined",
+			prompt:  "none login",
+			wantErr: "invalid request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+			authService := service.NewAuthService(
+				mocks.NewMockAuthCodeRepository(ctrl),
+				mockSessionRepo,
+				mocks.NewMockClientRepository(ctrl),
+				nil,
+				nil,
+				service.NewTokenDenylist(cache.NewNoopCache()),
+			)
+
+			mockSessionRepo.EXPECT().
+				GetByID(gomock.Any(), "session").
+				Return(&models.IdPSession{
+					UserId:    []byte("user"),
+					CreatedAt: time.Now().Add(-2 * time.Minute),
+					ExpiresAt: time.Now().Add(time.Hour),
+				}, nil).
+				AnyTimes()
+
+			_, err := authService.Authorize(
+				context.Background(),
+				dto.AuthorizeRequest{
+					ClientID:  uuid.NewString(),
+					Prompt:    tt.prompt,
+					MaxAge:    tt.maxAge,
+					AuthAfter: tt.authAfter,
+				},
+				"session",
+			)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %q error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+/**
+ * TestAuthorize_StepUpRequired verifies that a session whose second
+ * factor has aged out, or that never had one, cannot satisfy a client
+ * minimum or an acr_values request for 2fa.
+ */
+func TestAuthorize_StepUpRequired(t *testing.T) {
+	stale := time.Now().Add(-2 * service.MFA_ASSURANCE_TTL * time.Minute)
+	tests := []struct {
+		name      string
+		minACR    string
+		acrValues string
+		session   models.IdPSession
+	}{
+		{
+			name:   "client minimum",
+			minACR: models.ACRMultiFactor,
+			session: models.IdPSession{
+				AMR:     "pwd totp",
+				MFATime: sql.NullTime{Time: stale, Valid: true},
+			},
+		},
+		{
+			name:      "requested acr values",
+			acrValues: "2fa",
+			session:   models.IdPSession{AMR: "pwd"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+			mockClientRepo := mocks.NewMockClientRepository(ctrl)
+			authService := service.NewAuthService(
+				mocks.NewMockAuthCodeRepository(ctrl),
+				mockSessionRepo,
+				mockClientRepo,
+				nil,
+				nil,
+				service.NewTokenDenylist(cache.NewNoopCache()),
+			)
+
+			clientID := uuid.New()
+			session := tt.session
+			session.UserId = []byte("user")
+			session.ExpiresAt = time.Now().Add(time.Hour)
+			mockSessionRepo.EXPECT().
+				GetByID(gomock.Any(), "session").
+				Return(&session, nil)
+			mockClientRepo.EXPECT().
+				GetByID(gomock.Any(), clientID[:]).
+				Return(&models.Client{
+					ID:          clientID[:],
+					RedirectUri: "https://app.example.com/callback",
+					Grants:      []string{string(models.GrantAuthCode)},
+					MinACR:      tt.minACR,
+				}, nil)
+			mockClientRepo.EXPECT().
+				IsUserAllowed(gomock.Any(), []byte("user"), clientID[:]).
+				Return(true, nil)
+
+			_, err := authService.Authorize(
+				context.Background(),
+				dto.AuthorizeRequest{
+					ClientID:  clientID.String(),
+					ACRValues: tt.acrValues,
+				},
+				"session",
+			)
+			if err == nil || !strings.Contains(err.Error(), "step-up") {
+				t.Fatalf("expected step-up error, got %v", err)
+			}
+		})
+	}
+}
+
+/**
+ * TestValidateRedirectURI_Unregistered verifies that only exact matches
+ * against the registered redirect URIs are accepted.
+ */
+func TestValidateRedirectURI_Unregistered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:          clientID[:],
+			RedirectUri: "https://app.example.com/callback",
+		}, nil).
+		Times(2)
+
+	_, err := authService.ValidateRedirectURI(
+		context.Background(),
+		clientID.String(),
+		"https://app.example.com/callback/../evil",
+	)
+	if err == nil || !strings.Contains(err.Error(), "redirect validation") {
+		t.Errorf("expected redirect validation error, got %v", err)
+	}
+
+	got, err := authService.ValidateRedirectURI(
+		context.Background(),
+		clientID.String(),
+		"",
+	)
+	if err != nil || got != "https://app.example.com/callback" {
+		t.Errorf("expected primary redirect, got %q (%v)", got, err)
+	}
+}
+
+/**
+ * TestExchangeCodeForToken_RedirectURIMismatch verifies that a code bound
+ * to a redirect URI cannot be redeemed with a different one.
+ */
+func TestExchangeCodeForToken_RedirectURIMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), clientID[:], "secret").
+		Return(true, nil)
+
+	mockAuthRepo.EXPECT().
+		ExchangeCode(gomock.Any(), "auth-code").
+		Return(&models.AuthorizationCode{
+			ClientId:    clientID[:],
+			RedirectURI: "https://app.example.com/callback",
+		}, nil)
+
+	_, err := authService.ExchangeCodeForToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			ClientSecret: "secret",
+			Code:         "auth-code",
+			RedirectURI:  "https://app.example.com/other",
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), "redirect validation") {
+		t.Errorf("expected redirect validation error, got %v", err)
+	}
+}
+
+/**
+ * TestIssueClientCredentialsToken verifies machine tokens name the client
+ * as subject and only carry scopes registered on the client.
+ */
+func TestIssueClientCredentialsToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), clientID[:], "secret").
+		Return(true, nil)
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:            clientID[:],
+			Grants:        []string{"client_credentials"},
+			AllowedScopes: "jobs:read jobs:write",
+		}, nil)
+
+	res, err := authService.IssueClientCredentialsToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			GrantType:    "client_credentials",
+			ClientID:     clientID.String(),
+			ClientSecret: "secret",
+			Scope:        "jobs:read users:delete",
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	claims := &models.UserClaims{}
+	_, err = jwt.ParseWithClaims(res.AccessToken, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return &privKey.PublicKey, nil
+		})
+	if err != nil {
+		t.Fatalf("expected valid access token, got %v", err)
+	}
+
+	if claims.Subject != clientID.String() ||
+		claims.AuthorizedParty != clientID.String() {
+		t.Errorf("expected client as sub and azp, got %+v", claims)
+	}
+	if claims.UserID != "" {
+		t.Errorf("expected no user, got %q", claims.UserID)
+	}
+	if claims.Scope != "jobs:read" || res.Scope != "jobs:read" {
+		t.Errorf("expected scope jobs:read, got %q", claims.Scope)
+	}
+	if res.RefreshToken != "" {
+		t.Errorf("expected no refresh token")
+	}
+}
+
+/**
+ * TestIssueClientCredentialsToken_InvalidScope verifies that a request
+ * for scopes the client was never granted is rejected.
+ */
+func TestIssueClientCredentialsToken_InvalidScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), clientID[:], "secret").
+		Return(true, nil)
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:            clientID[:],
+			Grants:        []string{"client_credentials"},
+			AllowedScopes: "jobs:read",
+		}, nil)
+
+	_, err := authService.IssueClientCredentialsToken(
+		context.Background(),
+		dto.TokenExchangeRequest{
+			ClientID:     clientID.String(),
+			ClientSecret: "secret",
+			Scope:        "users:delete",
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), "scope validation") {
+		t.Errorf("expected scope validation error, got %v", err)
+	}
+}
+
+/**
+ * TestIntrospectToken_AccessToken verifies that access tokens are active
+ * until their jti lands on the revocation list.
+ */
+func TestIntrospectToken_AccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	store := &mockCache{store: make(map[string]string)}
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		keys,
+		service.NewTokenDenylist(store),
+	)
+
+	callerID := uuid.New()
+	clientID := uuid.New()
+	userID := uuid.New()
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), callerID[:], "secret").
+		Return(true, nil).
+		Times(2)
+
+	accessToken, _ := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		&models.Client{ID: clientID[:]},
+		models.UserClaims{UserID: userID.String(), Scope: "openid"},
+	)
+	req := dto.IntrospectionRequest{
+		Token:        accessToken,
+		ClientID:     callerID.String(),
+		ClientSecret: "secret",
+	}
+
+	res, err := authService.IntrospectToken(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !res.Active || res.Subject != userID.String() ||
+		res.ClientID != clientID.String() || res.Scope != "openid" {
+		t.Errorf("expected active token details, got %+v", res)
+	}
+	if res.ExpiresAt == 0 || res.IssuedAt == 0 {
+		t.Errorf("expected exp and iat, got %+v", res)
+	}
+
+	claims := &models.UserClaims{}
+	_, _ = jwt.ParseWithClaims(accessToken, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return &privKey.PublicKey, nil
+		})
+	store.store["auth:revoked_jti:"+claims.ID] = "1"
+
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), accessToken).
+		Return(nil, sql.ErrNoRows)
+
+	res, err = authService.IntrospectToken(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Active || res.Subject != "" {
+		t.Errorf("expected revoked token to be inactive, got %+v", res)
+	}
+}
+
+/**
+ * TestIntrospectToken_IDToken verifies that an ID token is never reported
+ * as an active access token even though it carries azp.
+ */
+func TestIntrospectToken_IDToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	callerID := uuid.New()
+	clientID := uuid.New()
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), callerID[:], "secret").
+		Return(true, nil)
+
+	idToken, _ := service.GenerateIDToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		&models.Client{ID: clientID[:]},
+		models.IDTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: uuid.NewString(),
+			},
+		},
+		"",
+	)
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), idToken).
+		Return(nil, sql.ErrNoRows)
+
+	res, err := authService.IntrospectToken(
+		context.Background(),
+		dto.IntrospectionRequest{
+			Token:        idToken,
+			ClientID:     callerID.String(),
+			ClientSecret: "secret",
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Active {
+		t.Errorf("expected ID token to be inactive, got %+v", res)
+	}
+}
+
+/**
+ * TestIntrospectToken_RefreshToken verifies stored refresh tokens are
+ * reported with their owner, client and scope.
+ */
+func TestIntrospectToken_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	callerID := uuid.New()
+	clientID := uuid.New()
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), callerID[:], "secret").
+		Return(true, nil)
+
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), "opaque").
+		Return(&models.RefreshToken{
+			Token:     "opaque",
+			ClientId:  clientID[:],
+			UserId:    userID[:],
+			ExpiresAt: expiresAt,
+			Scope:     "openid profile",
+			CreatedAt: time.Now(),
+		}, nil)
+
+	res, err := authService.IntrospectToken(
+		context.Background(),
+		dto.IntrospectionRequest{
+			Token:         "opaque",
+			TokenTypeHint: "refresh_token",
+			ClientID:      callerID.String(),
+			ClientSecret:  "secret",
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !res.Active || res.TokenType != "refresh_token" ||
+		res.Subject != userID.String() || res.Scope != "openid profile" {
+		t.Errorf("expected active refresh token, got %+v", res)
+	}
+	if res.ExpiresAt != expiresAt.Unix() {
+		t.Errorf("expected exp %d, got %d", expiresAt.Unix(), res.ExpiresAt)
+	}
+}
+
+/**
+ * TestRevokeToken_RefreshToken verifies a client can revoke one refresh
+ * token, or its whole family, without touching other clients.
+ */
+func TestRevokeToken_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+	familyID := uuid.New()
+	stored := &models.RefreshToken{
+		Token:     "opaque",
+		ClientId:  clientID[:],
+		UserId:    userID[:],
+		FamilyID:  familyID[:],
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), clientID[:], "secret").
+		Return(true, nil).
+		Times(2)
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), "opaque").
+		Return(stored, nil).
+		Times(2)
+	mockAuthRepo.EXPECT().
+		RevokeRefreshToken(gomock.Any(), "opaque").
+		Return(nil)
+	mockAuthRepo.EXPECT().
+		RevokeRefreshTokenFamily(gomock.Any(), familyID[:]).
+		Return(nil)
+
+	req := dto.RevocationRequest{
+		Token:        "opaque",
+		ClientID:     clientID.String(),
+		ClientSecret: "secret",
+	}
+	if err := authService.RevokeToken(context.Background(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	req.RevokeFamily = true
+	if err := authService.RevokeToken(context.Background(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+/**
+ * TestRevokeToken_AccessToken verifies that a revoked access token is no
+ * longer reported active and that other clients cannot revoke it.
+ */
+func TestRevokeToken_AccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	store := &mockCache{store: make(map[string]string)}
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keys := testKeyStore(privKey)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		keys,
+		service.NewTokenDenylist(store),
+	)
+
+	clientID := uuid.New()
+	otherID := uuid.New()
+	accessToken, _ := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		&models.Client{ID: clientID[:]},
+		models.UserClaims{UserID: uuid.NewString()},
+	)
+
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), gomock.Any(), "secret").
+		Return(true, nil).
+		AnyTimes()
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), accessToken).
+		Return(nil, sql.ErrNoRows).
+		AnyTimes()
+
+	err := authService.RevokeToken(
+		context.Background(),
+		dto.RevocationRequest{
+			Token:         accessToken,
+			TokenTypeHint: "access_token",
+			ClientID:      otherID.String(),
+			ClientSecret:  "secret",
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), "grant validation") {
+		t.Fatalf("expected grant validation error, got %v", err)
+	}
+
+	err = authService.RevokeToken(
+		context.Background(),
+		dto.RevocationRequest{
+			Token:         accessToken,
+			TokenTypeHint: "access_token",
+			ClientID:      clientID.String(),
+			ClientSecret:  "secret",
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	res, _ := authService.IntrospectToken(
+		context.Background(),
+		dto.IntrospectionRequest{
+			Token:        accessToken,
+			ClientID:     clientID.String(),
+			ClientSecret: "secret",
+		},
+	)
+	if res == nil || res.Active {
+		t.Errorf("expected revoked token to be inactive, got %+v", res)
+	}
+}
+
+/**
+ * TestRotateRefreshToken_ReuseRevokesFamily verifies that replaying an
+ * already rotated refresh token revokes its family instead of rotating.
+ */
+func TestRotateRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	familyID := uuid.New()
+	clientID := uuid.New()
+	userID := uuid.New()
+
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), "replayed").
+		Return(&models.RefreshToken{
+			Token:    "replayed",
+			UserId:   userID[:],
+			ClientId: clientID[:],
+			FamilyID: familyID[:],
+			Revoked:  true,
+			Rotated:  true,
+		}, nil)
+	mockAuthRepo.EXPECT().
+		RevokeRefreshTokenFamily(gomock.Any(), familyID[:]).
+		Return(nil)
+	mockAuthRepo.EXPECT().
+		RotateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).
+		Times(0)
+
+	_, err := authService.RotateRefreshToken(context.Background(), "replayed")
+	if err == nil || !strings.Contains(err.Error(), "token reuse") {
+		t.Fatalf("expected token reuse error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), userID.String()) {
+		t.Errorf("expected error to name the user, got %v", err)
+	}
+}
+
+/**
+ * TestRotateRefreshToken_UserNotAllowed verifies that a refresh token stops
+ * working once the user's access to the client is withdrawn.
+ */
+func TestRotateRefreshToken_UserNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+
+	mockAuthRepo.EXPECT().
+		GetRefreshToken(gomock.Any(), "current").
+		Return(&models.RefreshToken{
+			Token:    "current",
+			UserId:   userID[:],
+			ClientId: clientID[:],
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetGrantTypes(gomock.Any(), clientID[:]).
+		Return([]string{"refresh_token"}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), userID[:], clientID[:]).
+		Return(false, nil)
+	mockAuthRepo.EXPECT().
+		RotateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).
+		Times(0)
+
+	_, err := authService.RotateRefreshToken(context.Background(), "current")
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("expected access denied error, got %v", err)
+	}
+}
+
+/**
+ * TestRefreshBySession_UserNotAllowed verifies that a session cannot mint
+ * access tokens for a client the user is no longer allowed to use.
+ */
+func TestRefreshBySession_UserNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	authService := service.NewAuthService(
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			UserId:    userID[:],
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), userID[:], clientID[:]).
+		Return(false, nil)
+
+	_, err := authService.RefreshBySession(
+		context.Background(),
+		"session",
+		clientID.String(),
+	)
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("expected access denied error, got %v", err)
+	}
+}
+
+/**
+ * TestRefreshBySession_DecayedMFA verifies that once the second factor
+ * ages out the access token reports 1fa and no longer claims "mfa".
+ */
+func TestRefreshBySession_DecayedMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+
+	privKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+		testKeyStore(privKey),
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+	stale := time.Now().Add(-2 * service.MFA_ASSURANCE_TTL * time.Minute)
+
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			UserId:    userID[:],
+			AMR:       "pwd otp",
+			MFATime:   sql.NullTime{Time: stale, Valid: true},
+			CreatedAt: stale,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), userID[:], clientID[:]).
+		Return(true, nil)
+	mockAuthRepo.EXPECT().
+		GetClaimsByID(gomock.Any(), userID[:]).
+		Return(&models.UserClaims{}, nil)
+
+	res, err := authService.RefreshBySession(
+		context.Background(),
+		"session",
+		clientID.String(),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	claims := &models.UserClaims{}
+	_, err = jwt.ParseWithClaims(res.AccessToken, claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return &privKey.PublicKey, nil
+		})
+	if err != nil {
+		t.Fatalf("expected valid access token, got %v", err)
+	}
+
+	if claims.ACR != models.ACRSingleFactor {
+		t.Errorf("expected acr %q, got %q", models.ACRSingleFactor, claims.ACR)
+	}
+	if slices.Contains(claims.AMR, models.AMRMultiFactor) {
+		t.Errorf("expected no mfa in amr, got %v", claims.AMR)
+	}
+}
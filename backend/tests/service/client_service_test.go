@@ -2,9 +2,11 @@ package service_test
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
@@ -136,3 +138,66 @@ func TestGetAllowedClients(t *testing.T) {
 		t.Errorf("expected 1 client, got %d", len(resp.Clients))
 	}
 }
+
+/**
+ * TestUpdateClient_KeepsRedirectURIs verifies that an update without a
+ * redirect_uris list keeps the additional URIs and swaps the primary.
+ */
+func TestUpdateClient_KeepsRedirectURIs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockClientRepository(ctrl)
+	clientService := service.NewClientService(
+		mockRepo,
+		nil,
+		cache.NewNoopCache(),
+	)
+
+	clientID := uuid.New()
+	mockRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:          clientID[:],
+			RedirectUri: "https://app.example.com/old",
+			RedirectUris: []string{
+				"https://app.example.com/old",
+				"https://app.example.com/mobile",
+			},
+		}, nil)
+
+	var saved *models.Client
+	mockRepo.EXPECT().
+		UpdateClient(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c *models.Client,
+			_ []string,
+		) error {
+			saved = c
+			return nil
+		})
+
+	err := clientService.UpdateClient(
+		context.Background(),
+		clientID,
+		dto.CreateClientRequest{
+			Name:        "Test Client",
+			RedirectURI: "https://app.example.com/new",
+		},
+		nil,
+		nil,
+		uuid.New(),
+		[]string{"View all appclients"},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		"https://app.example.com/new",
+		"https://app.example.com/mobile",
+	}
+	if !slices.Equal(saved.RedirectUris, want) {
+		t.Errorf("expected redirect URIs %v, got %v",
+			want, saved.RedirectUris)
+	}
+}
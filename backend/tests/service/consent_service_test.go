@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestGrantConsent_MergesScopes verifies that a new grant keeps the
+ * scopes the user approved before and drops unsupported ones.
+ */
+func TestGrantConsent_MergesScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockConsentRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	consentService := service.NewConsentService(
+		mockRepo,
+		mockSessionRepo,
+		mockClientRepo,
+	)
+
+	clientID := uuid.New()
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			UserId:    []byte("user"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:]}, nil)
+	mockRepo.EXPECT().
+		GetConsent(gomock.Any(), []byte("user"), clientID[:]).
+		Return(&models.UserConsent{Scope: "openid email"}, nil)
+	mockRepo.EXPECT().
+		SaveConsent(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c *models.UserConsent) error {
+			if c.Scope != "openid email profile" {
+				t.Errorf("expected merged scopes, got %q", c.Scope)
+			}
+			return nil
+		})
+
+	err := consentService.GrantConsent(
+		context.Background(),
+		"session",
+		clientID.String(),
+		"openid profile admin",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+/**
+ * TestGrantConsent_ExpiredSession verifies that consent cannot be granted
+ * without an active session.
+ */
+func TestGrantConsent_ExpiredSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	consentService := service.NewConsentService(
+		mocks.NewMockConsentRepository(ctrl),
+		mockSessionRepo,
+		mocks.NewMockClientRepository(ctrl),
+	)
+
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			UserId:    []byte("user"),
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}, nil)
+
+	err := consentService.GrantConsent(
+		context.Background(),
+		"session",
+		uuid.NewString(),
+		"openid",
+	)
+	if err == nil || !strings.Contains(err.Error(), "session") {
+		t.Fatalf("expected session error, got %v", err)
+	}
+}
+
+/**
+ * TestRevokeConsent_NotFound verifies that revoking a consent that does
+ * not exist is reported.
+ */
+func TestRevokeConsent_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockConsentRepository(ctrl)
+	consentService := service.NewConsentService(
+		mockRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+	)
+
+	userID, clientID := uuid.New(), uuid.New()
+	mockRepo.EXPECT().
+		DeleteConsent(gomock.Any(), userID[:], clientID[:]).
+		Return(false, nil)
+
+	err := consentService.RevokeConsent(
+		context.Background(),
+		userID,
+		clientID,
+	)
+	if err == nil || !strings.Contains(err.Error(), "consent not found") {
+		t.Fatalf("expected consent not found, got %v", err)
+	}
+}
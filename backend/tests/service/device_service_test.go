@@ -0,0 +1,338 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestStartDeviceAuthorization verifies that a client allowed to use the
+ * device grant receives a device code, a user code and the verification
+ * URI, that other clients are rejected and that only public clients may
+ * leave out the secret.
+ */
+func TestStartDeviceAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeviceRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	deviceService := service.NewDeviceService(
+		mockRepo,
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+	)
+
+	os.Setenv("CLIENT_BASE_URL", "https://idp.example.com")
+	kioskID := uuid.New()
+	webID := uuid.New()
+	tvID := uuid.New()
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), kioskID[:]).
+		Return(&models.Client{
+			ID:           kioskID[:],
+			Grants:       []string{string(models.GrantDeviceCode)},
+			PublicClient: true,
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), webID[:]).
+		Return(&models.Client{
+			ID:           webID[:],
+			Grants:       []string{string(models.GrantAuthCode)},
+			PublicClient: true,
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), tvID[:]).
+		Return(&models.Client{
+			ID:     tvID[:],
+			Grants: []string{string(models.GrantDeviceCode)},
+		}, nil)
+
+	var stored *models.DeviceAuthorization
+	mockRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			d *models.DeviceAuthorization,
+		) error {
+			stored = d
+			return nil
+		})
+
+	resp, err := deviceService.StartDeviceAuthorization(
+		context.Background(),
+		dto.DeviceAuthorizationRequest{
+			ClientID: kioskID.String(),
+			Scope:    "openid profile admin",
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.DeviceCode != stored.DeviceCode ||
+		resp.UserCode != stored.UserCode {
+		t.Errorf("response does not match the stored request")
+	}
+	if utils.NormalizeUserCode(resp.UserCode) != resp.UserCode {
+		t.Errorf("user code %q is not in XXXX-XXXX form", resp.UserCode)
+	}
+	if stored.Scope != "openid profile" {
+		t.Errorf("expected unsupported scopes dropped, got %q", stored.Scope)
+	}
+	if resp.VerificationURI != "https://idp.example.com/device" ||
+		!strings.Contains(resp.VerificationURIComplete, "user_code=") {
+		t.Errorf("unexpected verification URIs %q, %q",
+			resp.VerificationURI, resp.VerificationURIComplete)
+	}
+	if resp.Interval != service.DEVICE_POLL_INTERVAL {
+		t.Errorf("expected interval %d, got %d",
+			service.DEVICE_POLL_INTERVAL, resp.Interval)
+	}
+
+	_, err = deviceService.StartDeviceAuthorization(
+		context.Background(),
+		dto.DeviceAuthorizationRequest{ClientID: webID.String()},
+	)
+	if err == nil || !strings.Contains(err.Error(), "unauthorized client") {
+		t.Errorf("expected unauthorized client, got %v", err)
+	}
+
+	_, err = deviceService.StartDeviceAuthorization(
+		context.Background(),
+		dto.DeviceAuthorizationRequest{ClientID: tvID.String()},
+	)
+	if err == nil || !strings.Contains(err.Error(), "client verification") {
+		t.Errorf("expected client verification error, got %v", err)
+	}
+}
+
+/**
+ * TestAnswerDeviceVerification_Approve verifies that approving a device
+ * binds the user and the session's authentication context to it.
+ */
+func TestAnswerDeviceVerification_Approve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDeviceRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	deviceService := service.NewDeviceService(
+		mockRepo,
+		mocks.NewMockAuthCodeRepository(ctrl),
+		mockSessionRepo,
+		mockClientRepo,
+	)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			SessionId: "session",
+			UserId:    userID[:],
+			ExpiresAt: now.Add(time.Hour),
+			AMR:       "pwd otp",
+			AuthTime:  sql.NullTime{Time: now, Valid: true},
+			MFATime:   sql.NullTime{Time: now, Valid: true},
+		}, nil)
+	mockRepo.EXPECT().
+		GetByUserCode(gomock.Any(), "BCDF-GHJK").
+		Return(&models.DeviceAuthorization{
+			UserCode:  "BCDF-GHJK",
+			ClientId:  clientID[:],
+			Status:    models.DeviceStatusPending,
+			ExpiresAt: now.Add(time.Minute),
+		}, nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{ID: clientID[:], MinACR: "2fa"}, nil)
+	mockClientRepo.EXPECT().
+		IsUserAllowed(gomock.Any(), userID[:], clientID[:]).
+		Return(true, nil)
+	mockRepo.EXPECT().
+		Answer(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			d *models.DeviceAuthorization,
+		) (bool, error) {
+			if d.Status != models.DeviceStatusApproved ||
+				d.ACR != models.ACRMultiFactor ||
+				d.Sid != service.SessionSID("session") ||
+				string(d.UserId) != string(userID[:]) {
+				t.Errorf("unexpected answer %+v", d)
+			}
+			return true, nil
+		})
+	mockSessionRepo.EXPECT().AddClient(gomock.Any(), gomock.Any()).Return(nil)
+
+	// The code is accepted as typed on a phone keyboard.
+	err := deviceService.AnswerDeviceVerification(
+		context.Background(),
+		"session",
+		dto.DeviceVerificationRequest{UserCode: "bcdf ghjk", Approve: true},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+/**
+ * TestPollDeviceCode verifies the answers a polling device receives
+ * while the request is pending, after polling too fast, once expired and
+ * once approved.
+ */
+func TestPollDeviceCode(t *testing.T) {
+	clientID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		device  *models.DeviceAuthorization
+		wantErr string
+	}{
+		{
+			name: "pending",
+			device: &models.DeviceAuthorization{
+				Status:    models.DeviceStatusPending,
+				Interval:  5,
+				ExpiresAt: now.Add(time.Minute),
+			},
+			wantErr: "authorization pending",
+		},
+		{
+			name: "too fast",
+			device: &models.DeviceAuthorization{
+				Status:   models.DeviceStatusPending,
+				Interval: 5,
+				LastPolledAt: sql.NullTime{
+					Time:  now.Add(-time.Second),
+					Valid: true,
+				},
+				ExpiresAt: now.Add(time.Minute),
+			},
+			wantErr: "slow down",
+		},
+		{
+			name: "expired",
+			device: &models.DeviceAuthorization{
+				Status:    models.DeviceStatusPending,
+				Interval:  5,
+				ExpiresAt: now.Add(-time.Second),
+			},
+			wantErr: "expired token",
+		},
+		{
+			name: "denied",
+			device: &models.DeviceAuthorization{
+				Status:    models.DeviceStatusDenied,
+				Interval:  5,
+				ExpiresAt: now.Add(time.Minute),
+			},
+			wantErr: "device denied",
+		},
+		{
+			name: "approved",
+			device: &models.DeviceAuthorization{
+				Status:    models.DeviceStatusApproved,
+				UserId:    userID[:],
+				Scope:     "openid",
+				ACR:       models.ACRSingleFactor,
+				Interval:  5,
+				ExpiresAt: now.Add(time.Minute),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockDeviceRepository(ctrl)
+			mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+			deviceService := service.NewDeviceService(
+				mockRepo,
+				mockAuthRepo,
+				mocks.NewMockSessionRepository(ctrl),
+				mocks.NewMockClientRepository(ctrl),
+			)
+
+			deviceCode := strings.Repeat("d", 43)
+			tt.device.DeviceCode = deviceCode
+			tt.device.ClientId = clientID[:]
+			mockRepo.EXPECT().
+				GetByDeviceCode(gomock.Any(), deviceCode).
+				Return(tt.device, nil)
+
+			wantInterval := 5
+			if tt.name == "too fast" {
+				wantInterval = 10
+			}
+			if tt.name == "expired" {
+				mockRepo.EXPECT().Delete(gomock.Any(), deviceCode).
+					Return(true, nil)
+			} else {
+				mockRepo.EXPECT().
+					RecordPoll(gomock.Any(), deviceCode, gomock.Any(),
+						wantInterval).
+					Return(nil)
+			}
+			if tt.name == "denied" || tt.name == "approved" {
+				mockRepo.EXPECT().Delete(gomock.Any(), deviceCode).
+					Return(true, nil)
+			}
+			if tt.name == "approved" {
+				mockAuthRepo.EXPECT().
+					StoreCode(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(
+						_ context.Context,
+						code *models.AuthorizationCode,
+					) error {
+						if !utils.VerifyCodeChallenge(deviceCode,
+							code.CodeChallenge,
+							code.CodeChallengeMethod) {
+							t.Errorf("code is not bound to the device code")
+						}
+						if string(code.UserId) != string(userID[:]) ||
+							code.Scope != "openid" {
+							t.Errorf("unexpected code %+v", code)
+						}
+						return nil
+					})
+			}
+
+			code, err := deviceService.PollDeviceCode(
+				context.Background(),
+				dto.TokenExchangeRequest{
+					ClientID:   clientID.String(),
+					DeviceCode: deviceCode,
+				},
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || code == "" {
+				t.Errorf("expected a code, got %q, %v", code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// opaqueSigner hides the concrete key type, like a PKCS#11 key handle.
+type opaqueSigner struct {
+	key crypto.Signer
+}
+
+func (s *opaqueSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *opaqueSigner) Sign(
+	r io.Reader, digest []byte, opts crypto.SignerOpts,
+) ([]byte, error) {
+	return s.key.Sign(r, digest, opts)
+}
+
+// fakePKCS11Module keeps generated keys in memory by label.
+type fakePKCS11Module struct {
+	keys map[string]crypto.Signer
+}
+
+func (m *fakePKCS11Module) GenerateKeyPair(
+	ctx context.Context, label, alg string,
+) (crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	m.keys[label] = &opaqueSigner{key: key}
+	return m.keys[label], nil
+}
+
+func (m *fakePKCS11Module) FindKeyPair(
+	ctx context.Context, label string,
+) (crypto.Signer, error) {
+	return m.keys[label], nil
+}
+
+func (m *fakePKCS11Module) DestroyKeyPair(
+	ctx context.Context, label string,
+) error {
+	delete(m.keys, label)
+	return nil
+}
+
+/**
+ * TestPKCS11KeyProvider verifies that tokens signed by a key that never
+ * leaves its module validate.
+ */
+func TestPKCS11KeyProvider(t *testing.T) {
+	ctx := context.Background()
+	provider := service.NewPKCS11KeyProvider(
+		&fakePKCS11Module{keys: map[string]crypto.Signer{}},
+	)
+
+	kid := uuid.NewString()
+	signer, ref, err := provider.GenerateKey(ctx, kid,
+		models.SigningAlgES256)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if _, err := provider.ImportKey(ctx, kid, signer); err == nil {
+		t.Errorf("expected import to be unsupported")
+	}
+	loaded, err := provider.LoadKey(ctx, kid, ref)
+	if err != nil || loaded != signer {
+		t.Fatalf("expected the generated key back, got %v", err)
+	}
+
+	keys := service.NewKeyStore(nil, nil, &service.SigningKey{
+		ID:        kid,
+		Algorithm: models.SigningAlgES256,
+		Signer:    loaded,
+		PublicKey: loaded.Public(),
+	})
+	clientID := uuid.New()
+	client := &models.Client{
+		ID:              clientID[:],
+		BaseUrl:         "http://client.com",
+		TokenSigningAlg: models.SigningAlgES256,
+	}
+	token, err := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgES256),
+		client,
+		models.UserClaims{UserID: "user-123"},
+	)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	if _, err := service.GetParsedToken(token, keys); err != nil {
+		t.Fatalf("expected token to validate: %v", err)
+	}
+}
+
+/**
+ * TestFileKeyringProvider verifies that keys are written as protected
+ * files and load back with the passphrase.
+ */
+func TestFileKeyringProvider(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider := service.NewFileKeyringProvider(dir, "keyring-passphrase")
+
+	kid := uuid.NewString()
+	signer, ref, err := provider.GenerateKey(ctx, kid,
+		models.SigningAlgEdDSA)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, kid+".pem"))
+	if err != nil {
+		t.Fatalf("expected key file: %v", err)
+	}
+	if !strings.Contains(string(data), "ENCRYPTED PRIVATE KEY") {
+		t.Errorf("expected an encrypted PKCS#8 file")
+	}
+	if strings.Contains(ref, "PRIVATE KEY") {
+		t.Errorf("expected only a reference to be stored, got %q", ref)
+	}
+
+	loaded, err := provider.LoadKey(ctx, kid, ref)
+	if err != nil {
+		t.Fatalf("failed to load key: %v", err)
+	}
+	type equaler interface{ Equal(crypto.PublicKey) bool }
+	if !signer.Public().(equaler).Equal(loaded.Public()) {
+		t.Errorf("loaded key does not match the generated key")
+	}
+
+	if _, err := provider.LoadKey(ctx, kid, "keyring:../escape"); err == nil {
+		t.Errorf("expected a key id outside the keyring to be rejected")
+	}
+}
+
+/**
+ * TestKeyStoreRotate_LostRace verifies that a rotation that lost to another
+ * instance deletes the key it generated and reports the conflict.
+ */
+func TestKeyStoreRotate_LostRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+
+	mockRepo := mocks.NewMockSigningKeyRepository(ctrl)
+	keys := service.NewKeyStore(
+		mockRepo,
+		service.NewFileKeyringProvider(dir, "keyring-passphrase"),
+		&service.SigningKey{
+			ID:        "initial",
+			Signer:    privateKey,
+			PublicKey: &privateKey.PublicKey,
+		},
+	)
+
+	mockRepo.EXPECT().
+		RotateSigningKey(gomock.Any(), "initial", gomock.Any(), gomock.Any()).
+		Return(false, nil)
+	mockRepo.EXPECT().
+		GetSigningKeys(gomock.Any()).
+		Return(nil, errors.New("unavailable"))
+
+	_, err = keys.Rotate(ctx, models.SigningAlgRS256)
+	if !errors.Is(err, service.ErrKeyRotationConflict) {
+		t.Fatalf("expected a rotation conflict, got %v", err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 0 {
+		t.Errorf("expected the generated key file to be deleted, got %d",
+			len(files))
+	}
+}
+
+/**
+ * TestDatabaseKeyProviderRewrap verifies that stored keys are sealed and
+ * follow the encryption key version.
+ */
+func TestDatabaseKeyProviderRewrap(t *testing.T) {
+	ctx := context.Background()
+	v1, _ := hex.DecodeString(testLegacyKeyHex)
+	v2 := bytes.Repeat([]byte{0x42}, 32)
+	oldCipher, _ := utils.NewEnvelopeCipher(map[uint32][]byte{1: v1}, 1, nil)
+	newCipher, _ := utils.NewEnvelopeCipher(
+		map[uint32][]byte{1: v1, 2: v2}, 2, nil,
+	)
+	rotatedCipher, _ := utils.NewEnvelopeCipher(
+		map[uint32][]byte{2: v2}, 2, nil,
+	)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	ref, err := service.NewDatabaseKeyProvider(oldCipher).
+		ImportKey(ctx, "kid", key)
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	if strings.Contains(ref, "PRIVATE KEY") {
+		t.Fatalf("expected the stored key to be sealed")
+	}
+
+	provider := service.NewDatabaseKeyProvider(newCipher)
+	rewrapper, ok := provider.(interface {
+		RewrapKey(ref string) (string, bool, error)
+	})
+	if !ok {
+		t.Fatalf("expected the database provider to support rewrapping")
+	}
+	rewrapped, changed, err := rewrapper.RewrapKey(ref)
+	if err != nil || !changed {
+		t.Fatalf("expected the key to be rewrapped: %v", err)
+	}
+	if _, changed, _ := rewrapper.RewrapKey(rewrapped); changed {
+		t.Errorf("expected a key on the active version to stay as is")
+	}
+
+	loaded, err := service.NewDatabaseKeyProvider(rotatedCipher).
+		LoadKey(ctx, "kid", rewrapped)
+	if err != nil {
+		t.Fatalf("failed to load key after dropping version 1: %v", err)
+	}
+	if !key.PublicKey.Equal(loaded.Public()) {
+		t.Errorf("loaded key does not match the imported key")
+	}
+}
@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+var testLockoutPolicy = &models.LockoutPolicy{
+	MaxFailures:          3,
+	LockoutMinutes:       15,
+	BackoffBaseSeconds:   2,
+	BackoffMaxSeconds:    30,
+	FailureWindowMinutes: 15,
+}
+
+/**
+ * TestCheckLogin verifies that a locked account and an account inside its
+ * progressive delay are refused, and that others may log in.
+ */
+func TestCheckLogin(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		failure    *models.LoginFailure
+		wantStatus bool
+		wantLocked bool
+	}{
+		{name: "no failures"},
+		{
+			name: "locked",
+			failure: &models.LoginFailure{
+				FailedCount:  3,
+				LastFailedAt: now,
+				LockedUntil: sql.NullTime{
+					Time:  now.Add(10 * time.Minute),
+					Valid: true,
+				},
+			},
+			wantStatus: true,
+			wantLocked: true,
+		},
+		{
+			name: "lock ran out",
+			failure: &models.LoginFailure{
+				FailedCount:  3,
+				LastFailedAt: now.Add(-20 * time.Minute),
+				LockedUntil: sql.NullTime{
+					Time:  now.Add(-time.Minute),
+					Valid: true,
+				},
+			},
+		},
+		{
+			// The second failure requires a wait of the 2s base.
+			name: "within delay",
+			failure: &models.LoginFailure{
+				FailedCount:  2,
+				LastFailedAt: now,
+			},
+			wantStatus: true,
+		},
+		{
+			name: "delay passed",
+			failure: &models.LoginFailure{
+				FailedCount:  2,
+				LastFailedAt: now.Add(-3 * time.Second),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockLockoutRepository(ctrl)
+			lockoutService := service.NewLockoutService(
+				mockRepo,
+				mocks.NewMockUserRepository(ctrl),
+				cache.NewNoopCache(),
+			)
+
+			if tt.failure != nil {
+				tt.failure.Email = "user@example.com"
+				tt.failure.ExpiresAt = now.Add(time.Hour)
+			}
+			mockRepo.EXPECT().
+				GetFailure(gomock.Any(), "user@example.com").
+				Return(tt.failure, nil)
+			mockRepo.EXPECT().GetPolicy(gomock.Any()).
+				Return(testLockoutPolicy, nil).AnyTimes()
+
+			status, err := lockoutService.CheckLogin(
+				context.Background(),
+				" User@Example.com",
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (status != nil) != tt.wantStatus {
+				t.Fatalf("expected status %v, got %+v", tt.wantStatus, status)
+			}
+			if status != nil && status.Locked != tt.wantLocked {
+				t.Errorf("expected locked %v, got %v",
+					tt.wantLocked, status.Locked)
+			}
+		})
+	}
+}
+
+/**
+ * TestRecordFailure_Locks verifies that the failure reaching the policy's
+ * limit locks the account for the lockout duration.
+ */
+func TestRecordFailure_Locks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockLockoutRepository(ctrl)
+	lockoutService := service.NewLockoutService(
+		mockRepo,
+		mocks.NewMockUserRepository(ctrl),
+		cache.NewNoopCache(),
+	)
+
+	now := time.Now()
+	mockRepo.EXPECT().GetPolicy(gomock.Any()).Return(testLockoutPolicy, nil)
+	mockRepo.EXPECT().
+		RecordFailure(gomock.Any(), "user@example.com", gomock.Any(),
+			gomock.Any(), gomock.Any()).
+		Return(&models.LoginFailure{
+			Email:        "user@example.com",
+			FailedCount:  3,
+			LastFailedAt: now,
+			ExpiresAt:    now.Add(15 * time.Minute),
+		}, nil)
+	mockRepo.EXPECT().
+		Lock(gomock.Any(), "user@example.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+			if until.Sub(now) < 14*time.Minute {
+				t.Errorf("expected a 15 minute lock, got until %v", until)
+			}
+			return nil
+		})
+
+	status, err := lockoutService.RecordFailure(
+		context.Background(),
+		"user@example.com",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status == nil || !status.Locked || status.RetryAfter < 14*60 {
+		t.Errorf("expected a lock of about 15 minutes, got %+v", status)
+	}
+}
@@ -0,0 +1,311 @@
+package service_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestEndSession_NotifiesSessionClients verifies that ending a session
+ * queues a back-channel logout for its clients, returns their
+ * front-channel logout URIs, revokes the session's refresh tokens and
+ * redirects to the registered URI.
+ */
+func TestEndSession_NotifiesSessionClients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockLogoutRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	logoutService := service.NewLogoutService(
+		mockRepo,
+		mockSessionRepo,
+		mockClientRepo,
+		nil,
+	)
+
+	os.Setenv("CLIENT_BASE_URL", "https://idp.example.com")
+	clientID := uuid.New()
+	userID := uuid.New()
+	sid := service.SessionSID("session")
+	client := &models.Client{
+		ID:                    clientID[:],
+		BaseUrl:               "https://app.example.com",
+		LogoutUri:             "https://app.example.com/backchannel-logout",
+		FrontchannelLogoutUri: "https://app.example.com/frontchannel-logout",
+	}
+
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(client, nil).
+		Times(2)
+	mockSessionRepo.EXPECT().
+		GetByID(gomock.Any(), "session").
+		Return(&models.IdPSession{
+			SessionId: "session",
+			UserId:    userID[:],
+		}, nil)
+	mockSessionRepo.EXPECT().
+		ListClients(gomock.Any(), sid).
+		Return([]models.SessionClient{
+			{Sid: sid, ClientId: clientID[:], UserId: userID[:]},
+		}, nil)
+	mockRepo.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			n *models.LogoutNotification,
+		) error {
+			if n.Sid != sid || string(n.ClientId) != string(clientID[:]) {
+				t.Errorf("unexpected notification %+v", n)
+			}
+			return nil
+		})
+	mockSessionRepo.EXPECT().DeleteClients(gomock.Any(), sid).Return(nil)
+	mockSessionRepo.EXPECT().
+		RevokeRefreshTokens(gomock.Any(), sid).
+		Return(nil)
+	mockSessionRepo.EXPECT().Delete(gomock.Any(), "session").Return(nil)
+
+	result, err := logoutService.EndSession(
+		context.Background(),
+		dto.EndSessionRequest{
+			ClientID:              clientID.String(),
+			PostLogoutRedirectURI: "https://app.example.com",
+			State:                 "xyz",
+			Confirmed:             true,
+		},
+		"session",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if result.RedirectURL != "https://app.example.com?state=xyz" {
+		t.Errorf("unexpected redirect %q", result.RedirectURL)
+	}
+	if len(result.FrontchannelURIs) != 1 {
+		t.Fatalf("expected one front-channel URI, got %v",
+			result.FrontchannelURIs)
+	}
+	frontchannel, _ := url.Parse(result.FrontchannelURIs[0])
+	if frontchannel.Query().Get("sid") != sid ||
+		frontchannel.Query().Get("iss") != "https://idp.example.com" {
+		t.Errorf("unexpected front-channel URI %s", frontchannel)
+	}
+}
+
+/**
+ * TestEndSession_RejectsInvalidRequests verifies that unregistered
+ * redirects, ID token hints of another user and unconfirmed logouts
+ * without a hint do not end the session.
+ */
+func TestEndSession_RejectsInvalidRequests(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	keys := testKeyStore(privateKey)
+	os.Setenv("CLIENT_BASE_URL", "https://idp.example.com")
+
+	clientID := uuid.New()
+	client := &models.Client{
+		ID:          clientID[:],
+		BaseUrl:     "https://app.example.com",
+		RedirectUri: "https://app.example.com/callback",
+	}
+
+	claims := models.IDTokenClaims{}
+	claims.Subject = uuid.NewString()
+	hint, err := service.GenerateIDToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		client,
+		claims,
+		"",
+	)
+	if err != nil {
+		t.Fatalf("failed to generate id token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     dto.EndSessionRequest
+		wantErr string
+	}{
+		{
+			name: "unregistered redirect",
+			req: dto.EndSessionRequest{
+				ClientID:              clientID.String(),
+				PostLogoutRedirectURI: "https://evil.example.com",
+			},
+			wantErr: "not registered",
+		},
+		{
+			name:    "hint for another user",
+			req:     dto.EndSessionRequest{IDTokenHint: hint},
+			wantErr: "does not match the session",
+		},
+		{
+			name: "client mismatch",
+			req: dto.EndSessionRequest{
+				IDTokenHint: hint,
+				ClientID:    uuid.NewString(),
+			},
+			wantErr: "client_id does not match",
+		},
+		{
+			name:    "unconfirmed without hint",
+			req:     dto.EndSessionRequest{},
+			wantErr: "not confirmed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+			mockClientRepo := mocks.NewMockClientRepository(ctrl)
+			logoutService := service.NewLogoutService(
+				mocks.NewMockLogoutRepository(ctrl),
+				mockSessionRepo,
+				mockClientRepo,
+				keys,
+			)
+
+			mockClientRepo.EXPECT().
+				GetByID(gomock.Any(), clientID[:]).
+				Return(client, nil).
+				AnyTimes()
+			userID := uuid.New()
+			mockSessionRepo.EXPECT().
+				GetByID(gomock.Any(), "session").
+				Return(&models.IdPSession{
+					SessionId: "session",
+					UserId:    userID[:],
+				}, nil).
+				AnyTimes()
+
+			_, err := logoutService.EndSession(
+				context.Background(),
+				tt.req,
+				"session",
+			)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %q error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+/**
+ * TestDeliverPending_RetriesFailures verifies that delivered logout
+ * tokens are removed from the queue and failed ones are rescheduled.
+ */
+func TestDeliverPending_RetriesFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	keys := testKeyStore(privateKey)
+	os.Setenv("CLIENT_BASE_URL", "https://idp.example.com")
+
+	accepting := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			claims := &models.LogoutTokenClaims{}
+			_, err := jwt.ParseWithClaims(
+				r.PostFormValue("logout_token"),
+				claims,
+				func(*jwt.Token) (interface{}, error) {
+					return &privateKey.PublicKey, nil
+				},
+			)
+			_, hasEvent := claims.Events[models.BackchannelLogoutEvent]
+			if err != nil || !hasEvent || claims.SessionID != "sid-1" {
+				t.Errorf("invalid logout token: %v %+v", err, claims)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer accepting.Close()
+	failing := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer failing.Close()
+
+	mockRepo := mocks.NewMockLogoutRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	logoutService := service.NewLogoutService(
+		mockRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		keys,
+	)
+
+	okClient, failClient := uuid.New(), uuid.New()
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	mockRepo.EXPECT().
+		ListDue(gomock.Any(), gomock.Any()).
+		Return([]models.LogoutNotification{
+			{
+				ID:        1,
+				ClientId:  okClient[:],
+				UserId:    userID[:],
+				Sid:       "sid-1",
+				ExpiresAt: expiresAt,
+			},
+			{
+				ID:        2,
+				ClientId:  failClient[:],
+				UserId:    userID[:],
+				Sid:       "sid-2",
+				ExpiresAt: expiresAt,
+			},
+		}, nil)
+	mockRepo.EXPECT().
+		Claim(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(true, nil).
+		Times(2)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), okClient[:]).
+		Return(&models.Client{ID: okClient[:], LogoutUri: accepting.URL},
+			nil)
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), failClient[:]).
+		Return(&models.Client{ID: failClient[:], LogoutUri: failing.URL},
+			nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+	mockRepo.EXPECT().
+		Reschedule(gomock.Any(), int64(2), 1, gomock.Any()).
+		Return(nil)
+
+	delivered, err := logoutService.DeliverPending(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if delivered != 1 {
+		t.Errorf("expected 1 delivery, got %d", delivered)
+	}
+}
@@ -0,0 +1,209 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestRequestLink verifies that a link is only stored for a known user of
+ * a client that allows magic links, and that unknown emails get the same
+ * answer.
+ */
+func TestRequestLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockMagicLinkRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	svc := service.NewMagicLinkService(mockRepo, mockUserRepo, mockClientRepo)
+
+	clientID := uuid.New()
+	client := &models.Client{
+		ID:             clientID[:],
+		ClientName:     "Portal",
+		RedirectUri:    "https://app.example.com/callback",
+		AllowMagicLink: true,
+	}
+	ctx := context.Background()
+
+	t.Run("client does not allow magic links", func(t *testing.T) {
+		mockClientRepo.EXPECT().
+			GetByID(gomock.Any(), clientID[:]).
+			Return(&models.Client{ID: clientID[:]}, nil)
+
+		err := svc.RequestLink(ctx, "user@example.com",
+			clientID.String(), "", "")
+		if err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("expected not allowed error, got %v", err)
+		}
+	})
+
+	t.Run("unregistered redirect URI", func(t *testing.T) {
+		mockClientRepo.EXPECT().
+			GetByID(gomock.Any(), clientID[:]).
+			Return(client, nil)
+
+		err := svc.RequestLink(ctx, "user@example.com",
+			clientID.String(), "https://evil.example.com", "")
+		if err == nil ||
+			!strings.Contains(err.Error(), "redirect validation") {
+			t.Errorf("expected redirect validation error, got %v", err)
+		}
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		mockClientRepo.EXPECT().
+			GetByID(gomock.Any(), clientID[:]).
+			Return(client, nil)
+		mockUserRepo.EXPECT().
+			GetUserByEmail(gomock.Any(), "nobody@example.com").
+			Return(nil, nil)
+
+		err := svc.RequestLink(ctx, " Nobody@Example.com ",
+			clientID.String(), "", "")
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("known user", func(t *testing.T) {
+		userID := uuid.New()
+		mockClientRepo.EXPECT().
+			GetByID(gomock.Any(), clientID[:]).
+			Return(client, nil)
+		mockUserRepo.EXPECT().
+			GetUserByEmail(gomock.Any(), "user@example.com").
+			Return(&models.User{
+				ID:     userID[:],
+				Email:  "user@example.com",
+				Status: models.StatusActive,
+			}, nil)
+
+		var stored *models.MagicLinkToken
+		mockRepo.EXPECT().
+			CreateToken(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ context.Context,
+				token *models.MagicLinkToken,
+			) error {
+				stored = token
+				return nil
+			})
+
+		pending := "client_id=" + clientID.String() +
+			"&redirect_uri=" + url.QueryEscape(client.RedirectUri) +
+			"&state=xyz"
+		err := svc.RequestLink(ctx, "user@example.com",
+			clientID.String(), client.RedirectUri, pending)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(stored.TokenHash) != 64 ||
+			uuid.UUID(stored.ClientID) != clientID ||
+			stored.RedirectURI != client.RedirectUri ||
+			!strings.Contains(stored.AuthorizeQuery, "state=xyz") {
+			t.Errorf("unexpected token %+v", stored)
+		}
+		ttl := time.Until(stored.ExpiresAt)
+		if ttl <= 0 || ttl > service.MAGIC_LINK_TTL*time.Second {
+			t.Errorf("unexpected expiry in %v", ttl)
+		}
+	})
+}
+
+/**
+ * TestConsumeLink verifies that a redeemed link resolves the user and the
+ * authorization request, and that suspended users are refused.
+ */
+func TestConsumeLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockMagicLinkRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	svc := service.NewMagicLinkService(mockRepo, mockUserRepo, mockClientRepo)
+
+	clientID := uuid.New()
+	userID := uuid.New()
+	token := "link-token"
+	link := &models.MagicLinkToken{
+		TokenHash:   utils.HashToken(token),
+		Email:       "user@example.com",
+		ClientID:    clientID[:],
+		RedirectURI: "https://app.example.com/callback",
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	ctx := context.Background()
+
+	t.Run("unknown or used token", func(t *testing.T) {
+		mockRepo.EXPECT().
+			ConsumeToken(gomock.Any(), utils.HashToken(token)).
+			Return(nil, nil)
+
+		_, err := svc.ConsumeLink(ctx, token)
+		if err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Errorf("expected invalid link error, got %v", err)
+		}
+	})
+
+	for _, tc := range []struct {
+		name    string
+		status  models.UserStatus
+		wantErr string
+	}{
+		{name: "active user", status: models.StatusActive},
+		{
+			name:    "suspended user",
+			status:  models.StatusSuspended,
+			wantErr: "suspended",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo.EXPECT().
+				ConsumeToken(gomock.Any(), utils.HashToken(token)).
+				Return(link, nil)
+			mockClientRepo.EXPECT().
+				GetByID(gomock.Any(), clientID[:]).
+				Return(&models.Client{
+					ID:             clientID[:],
+					AllowMagicLink: true,
+				}, nil)
+			mockUserRepo.EXPECT().
+				GetUserByEmail(gomock.Any(), link.Email).
+				Return(&models.User{
+					ID:     userID[:],
+					Email:  link.Email,
+					Status: tc.status,
+				}, nil)
+
+			login, err := svc.ConsumeLink(ctx, token)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("expected %q error, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if login.UserID != userID ||
+				login.ClientID != clientID.String() ||
+				login.RedirectURI != link.RedirectURI {
+				t.Errorf("unexpected login %+v", login)
+			}
+		})
+	}
+}
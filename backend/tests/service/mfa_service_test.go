@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
-	"os"
+	"encoding/hex"
 	"testing"
 
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
@@ -17,7 +19,7 @@ func TestGenerateTOTPSetup(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockMFARepository(ctrl)
-	mfaService := service.NewMFAService(mockRepo)
+	mfaService := service.NewMFAService(mockRepo, testEnvelopeCipher(t))
 
 	secret, uri, err := mfaService.GenerateTOTPSetup(context.Background(),
 		"test@example.com")
@@ -34,11 +36,8 @@ func TestFinalizeTOTP(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	os.Setenv("MFA_ENCRYPTION_KEY",
-		"94f9aae6af350c1f2ccb6f02702d66262a12ccb5aad6ef97f5bb283c4c5927ef")
-
 	mockRepo := mocks.NewMockMFARepository(ctrl)
-	mfaService := service.NewMFAService(mockRepo)
+	mfaService := service.NewMFAService(mockRepo, testEnvelopeCipher(t))
 
 	userID := uuid.New()
 	secret := "JBSWY3DPEHPK3PXP"
@@ -58,3 +57,88 @@ func TestFinalizeTOTP(t *testing.T) {
 		t.Errorf("Expected 10 backup codes, got %d", len(backupCodes))
 	}
 }
+
+/**
+ * TestRewrapSecrets verifies that legacy and old-version TOTP secrets are
+ * moved onto the active encryption key version.
+ */
+func TestRewrapSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	legacyKey, _ := hex.DecodeString(testLegacyKeyHex)
+	oldCipher, err := utils.NewEnvelopeCipher(
+		map[uint32][]byte{1: legacyKey}, 1, legacyKey,
+	)
+	if err != nil {
+		t.Fatalf("failed to build cipher: %v", err)
+	}
+	cipher := testEnvelopeCipher(t)
+
+	t.Setenv("MFA_ENCRYPTION_KEY", testLegacyKeyHex)
+	legacySecret, err := utils.Encrypt([]byte("LEGACYSECRET"))
+	if err != nil {
+		t.Fatalf("failed to encrypt legacy secret: %v", err)
+	}
+	oldSecret, err := oldCipher.Seal([]byte("OLDSECRET"))
+	if err != nil {
+		t.Fatalf("failed to seal secret: %v", err)
+	}
+	version := 1
+
+	mockRepo := mocks.NewMockMFARepository(ctrl)
+	mockRepo.EXPECT().
+		GetSecretsToRewrap(gomock.Any(), 2, gomock.Any(), gomock.Any()).
+		Return([]models.UserAuthenticator{
+			{ID: []byte{1}, SecretEncrypted: legacySecret},
+			{ID: []byte{2}, SecretEncrypted: oldSecret, KeyVersion: &version},
+		}, nil)
+
+	rewrapped := map[byte][]byte{}
+	mockRepo.EXPECT().
+		UpdateSecret(gomock.Any(), gomock.Any(), gomock.Any(), 2).
+		DoAndReturn(func(_ context.Context, id, secret []byte, _ int) error {
+			rewrapped[id[0]] = secret
+			return nil
+		}).Times(2)
+
+	mfaService := service.NewMFAService(mockRepo, cipher)
+	count, err := mfaService.RewrapSecrets(context.Background())
+	if err != nil {
+		t.Fatalf("failed to rewrap: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 rewrapped secrets, got %d", count)
+	}
+
+	expected := map[byte]string{1: "LEGACYSECRET", 2: "OLDSECRET"}
+	for id, plain := range expected {
+		if v, _ := utils.EnvelopeVersion(rewrapped[id]); v != 2 {
+			t.Errorf("secret %d: expected key version 2, got %d", id, v)
+		}
+		opened, err := cipher.Open(rewrapped[id])
+		if err != nil || !bytes.Equal(opened, []byte(plain)) {
+			t.Errorf("secret %d: expected %q, got %q (%v)",
+				id, plain, opened, err)
+		}
+	}
+}
+
+const testLegacyKeyHex = "94f9aae6af350c1f2ccb6f02702d6626" +
+	"2a12ccb5aad6ef97f5bb283c4c5927ef"
+
+// testEnvelopeCipher builds a cipher sealing with version 2 that still
+// knows version 1 and the legacy key.
+func testEnvelopeCipher(t *testing.T) *utils.EnvelopeCipher {
+	t.Helper()
+
+	legacyKey, _ := hex.DecodeString(testLegacyKeyHex)
+	cipher, err := utils.NewEnvelopeCipher(map[uint32][]byte{
+		1: legacyKey,
+		2: bytes.Repeat([]byte{0x42}, 32),
+	}, 2, legacyKey)
+	if err != nil {
+		t.Fatalf("failed to build cipher: %v", err)
+	}
+	return cipher
+}
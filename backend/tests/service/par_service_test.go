@@ -0,0 +1,215 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/dto"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+/**
+ * TestPushAuthorizationRequest verifies that a pushed request is stored
+ * under the returned request_uri, and that a confidential client has to
+ * authenticate even when it sends a code challenge.
+ */
+func TestPushAuthorizationRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	mockClientRepo := mocks.NewMockClientRepository(ctrl)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mockClientRepo,
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	mockClientRepo.EXPECT().
+		GetByID(gomock.Any(), clientID[:]).
+		Return(&models.Client{
+			ID:          clientID[:],
+			RedirectUri: "https://app.example.com/callback",
+			Grants:      []string{string(models.GrantAuthCode)},
+		}, nil).
+		Times(2)
+	mockAuthRepo.EXPECT().
+		VerifyClient(gomock.Any(), clientID[:], "secret").
+		Return(true, nil)
+
+	var stored *models.PushedAuthorizationRequest
+	mockAuthRepo.EXPECT().
+		StorePushedRequest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			par *models.PushedAuthorizationRequest,
+		) error {
+			stored = par
+			return nil
+		})
+
+	verifier := strings.Repeat("v", 43)
+	resp, err := authService.PushAuthorizationRequest(
+		context.Background(),
+		dto.PushedAuthorizationRequest{
+			AuthorizeRequest: dto.AuthorizeRequest{
+				ClientID:            clientID.String(),
+				State:               "xyz",
+				CodeChallenge:       utils.ComputeS256Challenge(verifier),
+				CodeChallengeMethod: models.PKCEMethodS256,
+			},
+			ClientSecret: "secret",
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.RequestURI != service.RequestURIPrefix+stored.RequestID {
+		t.Errorf("request_uri %q does not match the stored request",
+			resp.RequestURI)
+	}
+	if resp.ExpiresIn != service.PAR_REQUEST_TTL {
+		t.Errorf("expected expires_in %d, got %d",
+			service.PAR_REQUEST_TTL, resp.ExpiresIn)
+	}
+	if !strings.Contains(stored.Params, `"xyz"`) {
+		t.Errorf("expected the state to be stored, got %s", stored.Params)
+	}
+
+	// A code challenge does not stand in for a confidential client's
+	// secret.
+	_, err = authService.PushAuthorizationRequest(
+		context.Background(),
+		dto.PushedAuthorizationRequest{
+			AuthorizeRequest: dto.AuthorizeRequest{
+				ClientID:            clientID.String(),
+				CodeChallenge:       utils.ComputeS256Challenge(verifier),
+				CodeChallengeMethod: models.PKCEMethodS256,
+			},
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), "client verification") {
+		t.Errorf("expected client verification error, got %v", err)
+	}
+}
+
+/**
+ * TestResolveAuthorizeRequest verifies that a request_uri is replaced by
+ * the pushed parameters only for the client that pushed them, and that
+ * the first resolve claims it for the browser that made it.
+ */
+func TestResolveAuthorizeRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthRepo := mocks.NewMockAuthCodeRepository(ctrl)
+	authService := service.NewAuthService(
+		mockAuthRepo,
+		mocks.NewMockSessionRepository(ctrl),
+		mocks.NewMockClientRepository(ctrl),
+		nil,
+		nil,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	clientID := uuid.New()
+	otherID := uuid.New()
+	requestURI := service.RequestURIPrefix + "abc"
+	pushed := &models.PushedAuthorizationRequest{
+		RequestID: "abc",
+		ClientId:  clientID[:],
+		Params:    `{"RedirectURI":"https://app.example.com/cb"}`,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	mockAuthRepo.EXPECT().
+		GetPushedRequest(gomock.Any(), "abc").
+		Return(pushed, nil).
+		Times(2)
+	mockAuthRepo.EXPECT().
+		ClaimPushedRequest(gomock.Any(), "abc", "browser").
+		Return(true, nil)
+
+	// Parameters sent next to request_uri are ignored.
+	req, err := authService.ResolveAuthorizeRequest(
+		context.Background(),
+		dto.AuthorizeRequest{
+			ClientID:    clientID.String(),
+			RequestURI:  requestURI,
+			RedirectURI: "https://evil.example.com",
+		},
+		"browser",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.RedirectURI != "https://app.example.com/cb" ||
+		req.RequestURI != requestURI {
+		t.Errorf("unexpected resolved request %+v", req)
+	}
+
+	_, err = authService.ResolveAuthorizeRequest(
+		context.Background(),
+		dto.AuthorizeRequest{
+			ClientID:   otherID.String(),
+			RequestURI: requestURI,
+		},
+		"browser",
+	)
+	if err == nil || !strings.Contains(err.Error(), "another client") {
+		t.Errorf("expected another client error, got %v", err)
+	}
+
+	// Once claimed, the request resolves only for the same browser.
+	pushed.UsedBy = sql.NullString{String: "browser", Valid: true}
+	mockAuthRepo.EXPECT().
+		GetPushedRequest(gomock.Any(), "abc").
+		Return(pushed, nil).
+		Times(2)
+	_, err = authService.ResolveAuthorizeRequest(
+		context.Background(),
+		dto.AuthorizeRequest{
+			ClientID:   clientID.String(),
+			RequestURI: requestURI,
+		},
+		"browser",
+	)
+	if err != nil {
+		t.Errorf("unexpected error for the claiming browser: %v", err)
+	}
+	_, err = authService.ResolveAuthorizeRequest(
+		context.Background(),
+		dto.AuthorizeRequest{
+			ClientID:   clientID.String(),
+			RequestURI: requestURI,
+		},
+		"attacker",
+	)
+	if err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("expected already used error, got %v", err)
+	}
+
+	_, err = authService.ResolveAuthorizeRequest(
+		context.Background(),
+		dto.AuthorizeRequest{
+			ClientID:   clientID.String(),
+			RequestURI: "https://app.example.com/request",
+		},
+		"browser",
+	)
+	if err == nil || !strings.Contains(err.Error(), "malformed") {
+		t.Errorf("expected malformed request_uri error, got %v", err)
+	}
+}
@@ -1,14 +1,21 @@
 package service_test
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/cache"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/models"
 	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/service"
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/tests/mocks"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
 )
 
 /**
@@ -20,10 +27,9 @@ func TestGenerateAndValidateToken(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to generate rsa key: %v", err)
 	}
-	publicKey := &privateKey.PublicKey
+	keys := testKeyStore(privateKey)
 
 	os.Setenv("CLIENT_BASE_URL", "http://localhost:8080")
-	os.Setenv("KEY_ID", "test-key-id")
 
 	clientID := uuid.New()
 	client := &models.Client{
@@ -37,7 +43,11 @@ func TestGenerateAndValidateToken(t *testing.T) {
 	}
 
 	// 2. Issuance
-	token, err := service.GenerateToken(privateKey, client, claims)
+	token, err := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		client,
+		claims,
+	)
 	if err != nil {
 		t.Fatalf("failed to generate token: %v", err)
 	}
@@ -47,7 +57,7 @@ func TestGenerateAndValidateToken(t *testing.T) {
 	}
 
 	// 3. Validation
-	valid, err := service.ValidateToken(token, publicKey)
+	valid, err := service.ValidateToken(token, keys)
 	if err != nil {
 		t.Fatalf("failed to validate token: %v", err)
 	}
@@ -56,3 +66,317 @@ func TestGenerateAndValidateToken(t *testing.T) {
 		t.Error("expected token to be valid")
 	}
 }
+
+/**
+ * TestValidateToken_RejectsOtherTokenTypes verifies that ID and logout
+ * tokens, though signed with the same keys, are not accepted as bearer
+ * access tokens.
+ */
+func TestValidateToken_RejectsOtherTokenTypes(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	keys := testKeyStore(privateKey)
+	key := keys.ActiveKey(models.SigningAlgRS256)
+
+	clientID := uuid.New()
+	client := &models.Client{ID: clientID[:], AccessTokenTTL: 30}
+
+	idToken, err := service.GenerateIDToken(
+		key,
+		client,
+		models.IDTokenClaims{RegisteredClaims: jwt.RegisteredClaims{
+			Subject: "user-123",
+		}},
+		"",
+	)
+	if err != nil {
+		t.Fatalf("failed to generate id token: %v", err)
+	}
+	logoutToken, err := service.GenerateLogoutToken(
+		key, client, "user-123", "sid-1",
+	)
+	if err != nil {
+		t.Fatalf("failed to generate logout token: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"id token":     idToken,
+		"logout token": logoutToken,
+	} {
+		if valid, err := service.ValidateToken(token, keys); err == nil ||
+			valid {
+			t.Errorf("expected %s to be rejected", name)
+		}
+	}
+}
+
+/**
+ * TestKeyStoreRotation verifies that tokens signed before a rotation still
+ * validate by kid while new tokens use the new key.
+ */
+func TestKeyStoreRotation(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	keys := testKeyStore(privateKey)
+
+	clientID := uuid.New()
+	client := &models.Client{ID: clientID[:], BaseUrl: "http://client.com"}
+	claims := models.UserClaims{UserID: "user-123"}
+
+	oldToken, err := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		client,
+		claims,
+	)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	rotated, err := keys.Rotate(
+		context.Background(),
+		models.SigningAlgRS256,
+	)
+	if err != nil {
+		t.Fatalf("failed to rotate key: %v", err)
+	}
+	active := keys.ActiveKey(models.SigningAlgRS256)
+	if rotated.ID == "test-key-id" || active.ID != rotated.ID {
+		t.Fatalf("expected a new active key, got %s", rotated.ID)
+	}
+	if len(keys.Keys()) != 2 {
+		t.Fatalf("expected 2 published keys, got %d", len(keys.Keys()))
+	}
+
+	newToken, err := service.GenerateToken(
+		keys.ActiveKey(models.SigningAlgRS256),
+		client,
+		claims,
+	)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"old": oldToken,
+		"new": newToken,
+	} {
+		parsed, err := service.GetParsedToken(token, keys)
+		if err != nil || !parsed.Valid {
+			t.Errorf("expected %s token to validate: %v", name, err)
+		}
+	}
+	parsed, _ := service.GetParsedToken(newToken, keys)
+	if parsed.Header["kid"] != rotated.ID {
+		t.Errorf("expected kid %s, got %v", rotated.ID, parsed.Header["kid"])
+	}
+
+	if _, err := keys.VerificationKey("unknown-kid"); err == nil {
+		t.Error("expected unknown kid to be rejected")
+	}
+}
+
+/**
+ * TestGenerateToken_ClientSigningAlgorithms verifies that every supported
+ * algorithm signs with its own key, verifies by kid and is published in
+ * JWKS with the matching key type.
+ */
+func TestGenerateToken_ClientSigningAlgorithms(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	keys := testKeyStore(privateKey)
+	if err := keys.Refresh(context.Background()); err != nil {
+		t.Fatalf("failed to provision keys: %v", err)
+	}
+
+	clientID := uuid.New()
+	for _, alg := range models.SigningAlgorithms {
+		client := &models.Client{
+			ID:              clientID[:],
+			BaseUrl:         "http://client.com",
+			TokenSigningAlg: alg,
+		}
+		token, err := service.GenerateToken(
+			keys.ActiveKey(client.TokenSigningAlg),
+			client,
+			models.UserClaims{UserID: "user-123"},
+		)
+		if err != nil {
+			t.Fatalf("%s: failed to generate token: %v", alg, err)
+		}
+
+		parsed, err := service.GetParsedToken(token, keys)
+		if err != nil || !parsed.Valid {
+			t.Fatalf("%s: expected token to validate: %v", alg, err)
+		}
+		if parsed.Method.Alg() != alg {
+			t.Errorf("expected alg %s, got %s", alg, parsed.Method.Alg())
+		}
+	}
+
+	authService := service.NewAuthService(
+		nil, nil, nil,
+		nil,
+		keys,
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+	jwks, err := authService.GetJWKS(context.Background())
+	if err != nil {
+		t.Fatalf("failed to build jwks: %v", err)
+	}
+
+	keyTypes := map[string]string{}
+	for _, jwk := range jwks.Keys {
+		keyTypes[jwk.Algorithm] = jwk.KeyType
+	}
+	expected := map[string]string{
+		models.SigningAlgRS256: "RSA",
+		models.SigningAlgPS256: "RSA",
+		models.SigningAlgES256: "EC",
+		models.SigningAlgEdDSA: "OKP",
+	}
+	for alg, kty := range expected {
+		if keyTypes[alg] != kty {
+			t.Errorf("expected %s key of type %s, got %q",
+				alg, kty, keyTypes[alg])
+		}
+	}
+}
+
+/**
+ * TestTokenDenylist verifies jti revocation and the per-user watermark.
+ */
+func TestTokenDenylist(t *testing.T) {
+	ctx := context.Background()
+	denylist := service.NewTokenDenylist(
+		&mockCache{store: make(map[string]string)},
+	)
+
+	userID := uuid.NewString()
+	issuedAt := time.Now().Add(-time.Minute)
+	older := &models.UserClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       "old-jti",
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+	other := &models.UserClaims{
+		UserID: uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       "other-jti",
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+
+	if denylist.IsRevoked(ctx, older) {
+		t.Fatalf("expected token to be accepted before revocation")
+	}
+
+	if err := denylist.RevokeUserTokens(ctx, userID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !denylist.IsRevoked(ctx, older) {
+		t.Errorf("expected token issued before watermark to be revoked")
+	}
+	if denylist.IsRevoked(ctx, other) {
+		t.Errorf("expected other users to be unaffected")
+	}
+
+	newer := &models.UserClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       "new-jti",
+			IssuedAt: jwt.NewNumericDate(time.Now().Add(time.Second)),
+		},
+	}
+	if denylist.IsRevoked(ctx, newer) {
+		t.Errorf("expected token issued after watermark to be accepted")
+	}
+
+	sameSecond := &models.UserClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       "same-second-jti",
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	}
+	if !denylist.IsRevoked(ctx, sameSecond) {
+		t.Errorf("expected token issued in the watermark second to be revoked")
+	}
+
+	err := denylist.RevokeJTI(ctx, "other-jti", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !denylist.IsRevoked(ctx, other) {
+		t.Errorf("expected denylisted jti to be revoked")
+	}
+}
+
+/**
+ * TestDBTokenDenylist verifies the database-backed revocation list and
+ * that a failed lookup rejects the token.
+ */
+func TestDBTokenDenylist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockTokenDenylistRepository(ctrl)
+	denylist := service.NewDBTokenDenylist(mockRepo)
+
+	userID := uuid.NewString()
+	issuedAt := time.Now().Add(-time.Minute)
+	claims := &models.UserClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       "jti",
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+
+	mockRepo.EXPECT().
+		SetWatermark(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+		Return(nil)
+	if err := denylist.RevokeUserTokens(ctx, userID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	mockRepo.EXPECT().IsJTIRevoked(gomock.Any(), "jti").Return(false, nil)
+	mockRepo.EXPECT().
+		GetWatermark(gomock.Any(), userID).
+		Return(issuedAt.Unix()-1, true, nil)
+	if denylist.IsRevoked(ctx, claims) {
+		t.Errorf("expected token issued after watermark to be accepted")
+	}
+
+	mockRepo.EXPECT().IsJTIRevoked(gomock.Any(), "jti").Return(false, nil)
+	mockRepo.EXPECT().
+		GetWatermark(gomock.Any(), userID).
+		Return(issuedAt.Unix(), true, nil)
+	if !denylist.IsRevoked(ctx, claims) {
+		t.Errorf("expected token issued before watermark to be revoked")
+	}
+
+	mockRepo.EXPECT().
+		IsJTIRevoked(gomock.Any(), "jti").
+		Return(false, errors.New("connection refused"))
+	if !denylist.IsRevoked(ctx, claims) {
+		t.Errorf("expected lookup failure to reject the token")
+	}
+}
+
+// testKeyStore builds an in-memory key store around a single RSA key.
+func testKeyStore(privateKey *rsa.PrivateKey) service.KeyStore {
+	return service.NewKeyStore(nil, nil, &service.SigningKey{
+		ID:        "test-key-id",
+		Signer:    privateKey,
+		PublicKey: &privateKey.PublicKey,
+	})
+}
@@ -31,6 +31,7 @@ func TestCreateUser_ReRegistration(t *testing.T) {
 		mockRegRepo,
 		mockCauRepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	ctx := context.Background()
@@ -32,6 +32,7 @@ func TestGetUserByID(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -94,6 +95,7 @@ func TestSyncAdminClientAccess(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -126,6 +128,7 @@ func TestUpdateUserAccountAndRole(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -185,6 +188,7 @@ func TestUpdateUserAccountAndRole_RevokeAdmin(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -253,6 +257,7 @@ func TestGetUserByID_NullAccountType(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -307,6 +312,7 @@ func TestGetUserList_NullAccountType(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -368,6 +374,7 @@ func TestGetDeletedUserList(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -426,6 +433,7 @@ func TestUnarchiveUser(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -456,6 +464,7 @@ func TestHardDeleteUser(t *testing.T) {
 		mockRegRepo,
 		mockCAURepo,
 		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
 	)
 
 	userID := uuid.New()
@@ -470,3 +479,70 @@ func TestHardDeleteUser(t *testing.T) {
 		t.Errorf("expected no error, got %v", err)
 	}
 }
+
+/**
+ * TestGetUserInfo_ScopeFiltering verifies that UserInfo only releases
+ * the claims covered by the access token's scopes.
+ */
+func TestGetUserInfo_ScopeFiltering(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+
+	userService := service.NewUserService(
+		mockRepo,
+		mocks.NewMockClientRepository(ctrl),
+		mocks.NewMockRegistrationRepository(ctrl),
+		mocks.NewMockClientAllowedUserRepository(ctrl),
+		cache.NewNoopCache(),
+		service.NewTokenDenylist(cache.NewNoopCache()),
+	)
+
+	userID := uuid.New()
+	user := &models.User{
+		ID:          userID[:],
+		FirstName:   "Juan",
+		LastName:    "Dela Cruz",
+		Email:       "juan@example.com",
+		Status:      models.StatusActive,
+		Role:        models.Role{RoleName: "student"},
+		AccountType: "faculty",
+	}
+
+	mockRepo.EXPECT().
+		GetUserById(gomock.Any(), userID[:], gomock.Any(), true).
+		Return(user, nil).
+		Times(2)
+
+	// 1. openid + profile releases the name but not the email
+	resp, err := userService.GetUserInfo(
+		context.Background(), userID, "openid profile",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Subject != userID.String() || resp.Name != "Juan Dela Cruz" {
+		t.Errorf("unexpected profile claims: %+v", resp)
+	}
+	if resp.Role != "student" || resp.AccountType != "faculty" {
+		t.Errorf("expected custom claims, got %+v", resp)
+	}
+	if resp.Email != "" || resp.EmailVerified != nil {
+		t.Errorf("expected no email claims, got %+v", resp)
+	}
+
+	// 2. openid + email releases only the email claims
+	resp, err = userService.GetUserInfo(
+		context.Background(), userID, "openid email",
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Email != user.Email || resp.EmailVerified == nil {
+		t.Errorf("expected email claims, got %+v", resp)
+	}
+	if resp.GivenName != "" {
+		t.Errorf("expected no profile claims, got %+v", resp)
+	}
+}
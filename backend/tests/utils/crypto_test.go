@@ -36,3 +36,25 @@ func TestInvalidKey(t *testing.T) {
 		t.Error("Expected error for invalid key, got nil")
 	}
 }
+
+func TestUserCode(t *testing.T) {
+	code, err := utils.GenerateUserCode()
+	if err != nil {
+		t.Fatalf("Failed to generate user code: %v", err)
+	}
+	if len(code) != 9 || code[4] != '-' {
+		t.Errorf("Expected XXXX-XXXX, got %s", code)
+	}
+
+	tests := map[string]string{
+		"bcdf-ghjk":  "BCDF-GHJK",
+		"BCDF GHJK":  "BCDF-GHJK",
+		" bcdfghjk ": "BCDF-GHJK",
+		"bcd":        "BCD",
+	}
+	for input, want := range tests {
+		if got := utils.NormalizeUserCode(input); got != want {
+			t.Errorf("NormalizeUserCode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
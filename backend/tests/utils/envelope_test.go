@@ -0,0 +1,93 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+)
+
+const (
+	testKeyV1 = "94f9aae6af350c1f2ccb6f02702d66262a12ccb5aad6ef97f5bb283c4c5927ef"
+	testKeyV2 = "1d6e3c0f7a58b9e2d4c1a0f3b6e9d2c5a8f1b4e7d0c3a6f9b2e5d8c1a4f7b0e3"
+)
+
+func TestEnvelopeRotation(t *testing.T) {
+	t.Setenv("MFA_ENCRYPTION_KEY", testKeyV1)
+	t.Setenv("MFA_ENCRYPTION_KEYS", "")
+	t.Setenv("MFA_ENCRYPTION_KEY_VERSION", "")
+
+	// Without versioned keys the legacy key is version 1.
+	oldCipher, err := utils.LoadEnvelopeCipher()
+	if err != nil {
+		t.Fatalf("Failed to load cipher: %v", err)
+	}
+	if oldCipher.ActiveVersion() != 1 {
+		t.Fatalf("Expected version 1, got %d", oldCipher.ActiveVersion())
+	}
+	sealed, err := oldCipher.Seal([]byte("this is a secret message"))
+	if err != nil {
+		t.Fatalf("Failed to seal: %v", err)
+	}
+
+	t.Setenv("MFA_ENCRYPTION_KEYS", "1:"+testKeyV1+",2:"+testKeyV2)
+	cipher, err := utils.LoadEnvelopeCipher()
+	if err != nil {
+		t.Fatalf("Failed to load cipher: %v", err)
+	}
+	if cipher.ActiveVersion() != 2 {
+		t.Fatalf("Expected version 2, got %d", cipher.ActiveVersion())
+	}
+
+	rewrapped, changed, err := cipher.Rewrap(sealed)
+	if err != nil || !changed {
+		t.Fatalf("Failed to rewrap: %v", err)
+	}
+	if v, _ := utils.EnvelopeVersion(rewrapped); v != 2 {
+		t.Errorf("Expected version 2 after rewrap, got %d", v)
+	}
+
+	t.Setenv("MFA_ENCRYPTION_KEYS", "2:"+testKeyV2)
+	rotated, err := utils.LoadEnvelopeCipher()
+	if err != nil {
+		t.Fatalf("Failed to load cipher: %v", err)
+	}
+	opened, err := rotated.Open(rewrapped)
+	if err != nil {
+		t.Fatalf("Failed to open after dropping version 1: %v", err)
+	}
+	if string(opened) != "this is a secret message" {
+		t.Errorf("Expected the original message, got %s", opened)
+	}
+	if _, err := rotated.Open(sealed); err == nil {
+		t.Error("Expected the version 1 envelope to be unreadable")
+	}
+}
+
+func TestEnvelopeOpenLegacy(t *testing.T) {
+	t.Setenv("MFA_ENCRYPTION_KEY", testKeyV1)
+	t.Setenv("MFA_ENCRYPTION_KEYS", "2:"+testKeyV2)
+	t.Setenv("MFA_ENCRYPTION_KEY_VERSION", "")
+
+	legacy, err := utils.Encrypt([]byte("legacy secret"))
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	cipher, err := utils.LoadEnvelopeCipher()
+	if err != nil {
+		t.Fatalf("Failed to load cipher: %v", err)
+	}
+	opened, err := cipher.OpenLegacy(legacy)
+	if err != nil || string(opened) != "legacy secret" {
+		t.Errorf("Expected legacy secret, got %q (%v)", opened, err)
+	}
+}
+
+func TestEnvelopeInvalidKeyVersion(t *testing.T) {
+	t.Setenv("MFA_ENCRYPTION_KEY", "")
+	t.Setenv("MFA_ENCRYPTION_KEYS", "1:"+testKeyV1)
+	t.Setenv("MFA_ENCRYPTION_KEY_VERSION", "3")
+
+	if _, err := utils.LoadEnvelopeCipher(); err == nil {
+		t.Error("Expected error for unknown active version, got nil")
+	}
+}
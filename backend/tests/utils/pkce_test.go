@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Iskolutions-Capstone-Dev-Team/Identity-Provider/internal/utils"
+)
+
+func TestVerifyCodeChallengeS256(t *testing.T) {
+	// Test vector from RFC 7636 Appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if got := utils.ComputeS256Challenge(verifier); got != challenge {
+		t.Errorf("Expected challenge %s, got %s", challenge, got)
+	}
+
+	if !utils.VerifyCodeChallenge(verifier, challenge, "S256") {
+		t.Error("Expected S256 verifier to match")
+	}
+
+	if utils.VerifyCodeChallenge(verifier+"x", challenge, "S256") {
+		t.Error("Expected modified verifier to be rejected")
+	}
+}
+
+func TestVerifyCodeChallengePlain(t *testing.T) {
+	verifier := strings.Repeat("a", 43)
+
+	if !utils.VerifyCodeChallenge(verifier, verifier, "plain") {
+		t.Error("Expected plain verifier to match")
+	}
+
+	if utils.VerifyCodeChallenge(verifier, verifier, "unknown") {
+		t.Error("Expected unknown method to be rejected")
+	}
+}
+
+func TestIsValidPKCEValue(t *testing.T) {
+	if utils.IsValidPKCEValue(strings.Repeat("a", 42)) {
+		t.Error("Expected short value to be rejected")
+	}
+
+	if utils.IsValidPKCEValue(strings.Repeat("a", 129)) {
+		t.Error("Expected long value to be rejected")
+	}
+
+	if utils.IsValidPKCEValue(strings.Repeat("a", 42) + "+") {
+		t.Error("Expected reserved character to be rejected")
+	}
+
+	if !utils.IsValidPKCEValue(strings.Repeat("a", 40) + "-._~") {
+		t.Error("Expected unreserved characters to be accepted")
+	}
+}
@@ -15,6 +15,10 @@ const Register = lazy(() => import("../auth/pages/Register"));
 const RegisterPasswordSetup = lazy(() => import("../auth/pages/RegisterPasswordSetup"));
 const Logout = lazy(() => import("../auth/pages/Logout"));
 const Callback = lazy(() => import("../auth/pages/Callback"));
+const MagicLink = lazy(() => import("../auth/pages/MagicLink"));
+const Consent = lazy(() => import("../auth/pages/Consent"));
+const StepUp = lazy(() => import("../auth/pages/StepUp"));
+const Device = lazy(() => import("../auth/pages/Device"));
 const AuthorizeRedirect = lazy(() => import("../auth/pages/AuthorizeRedirect"));
 const AccessDenied = lazy(() => import("../auth/pages/AccessDenied"));
 const Dashboard = lazy(() => import("../features/dashboard/pages/Dashboard"));
@@ -64,6 +68,10 @@ export default function App() {
           <Route path={ROUTE_PATHS.REGISTER} element={<Register />} />
           <Route path={ROUTE_PATHS.REGISTER_SET_PASSWORD} element={<RegisterPasswordSetup />} />
           <Route path={ROUTE_PATHS.CALLBACK} element={<Callback />} />
+          <Route path={ROUTE_PATHS.MAGIC_LINK} element={<MagicLink />} />
+          <Route path={ROUTE_PATHS.CONSENT} element={<Consent />} />
+          <Route path={ROUTE_PATHS.STEP_UP} element={<StepUp />} />
+          <Route path={ROUTE_PATHS.DEVICE} element={<Device />} />
           <Route path={ROUTE_PATHS.LOGOUT} element={<Logout />} />
           <Route path={ACCESS_DENIED_PATH} element={<AccessDenied />} />
           <Route path={LEGACY_UNAUTHORIZED_PATH} element={<Navigate to={buildAccessDeniedPath()} replace />} />
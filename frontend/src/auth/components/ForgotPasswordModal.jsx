@@ -44,6 +44,7 @@ export default function ForgotPasswordModal({ isOpen, onClose, emailAddress = ""
   const [otpError, setOtpError] = useState("");
   const [form, setForm] = useState(EMPTY_PASSWORD_FORM);
   const [passwordError, setPasswordError] = useState("");
+  const [resetToken, setResetToken] = useState("");
   const [timer, setTimer] = useState(OTP_TIMER_SECONDS);
   const [canResend, setCanResend] = useState(false);
   const [otpTimerKey, setOtpTimerKey] = useState(0);
@@ -188,10 +189,12 @@ export default function ForgotPasswordModal({ isOpen, onClose, emailAddress = ""
     setIsVerifyingOtp(true);
 
     try {
-      await passwordResetService.verifyOtp({
+      const res = await passwordResetService.verifyOtp({
         email: trimmedRecoveryEmail,
         otp: code,
+        purpose: "password_reset",
       });
+      setResetToken(res?.reset_token ?? "");
       setPasswordError("");
       setStep("password");
     } catch (error) {
@@ -218,7 +221,9 @@ export default function ForgotPasswordModal({ isOpen, onClose, emailAddress = ""
       await passwordResetService.updateForgotPassword({
         email: trimmedRecoveryEmail,
         newPassword: form.newPassword,
+        resetToken,
       });
+      setResetToken("");
       setStep("success");
     } catch (error) {
       setPasswordError(getRequestErrorMessage(error, "Unable to change the password right now."));
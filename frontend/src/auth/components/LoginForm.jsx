@@ -1,23 +1,32 @@
 import { useEffect, useState } from "react";
 import { Link, useNavigate } from "react-router-dom";
-import { Mail, Lock, Eye, EyeOff } from "lucide-react";
+import { Mail, Lock, Eye, EyeOff, KeyRound, Send } from "lucide-react";
 import { authService } from "../services/authService";
+import { passwordResetService } from "../../services/passwordResetService";
 import ErrorAlert from "../../components/ErrorAlert";
+import InfoAlert from "../../components/InfoAlert";
 import ForgotPasswordModal from "./ForgotPasswordModal";
 import { buildAccessDeniedPath } from "../utils/loginRoute";
 import { beginPendingMfaSession } from "../utils/authCookies";
+import { cancelPasskeyCeremony, getPasskeyCredential, supportsPasskeyAutofill } from "../utils/webAuthn";
 import { Input } from "../../components/ui/input";
 import { Button } from "../../components/ui/button";
 import { Separator } from "../../components/ui/separator";
 import { Card, CardContent } from "../../components/ui/card";
 
-export default function LoginForm({ clientId, redirectUri = "", initialError = "", onLoginSuccess }) {
+export default function LoginForm({ clientId, redirectUri = "", loginHint = "", initialError = "", onLoginSuccess }) {
   const navigate = useNavigate();
-  const [email, setEmail] = useState("");
+  const [email, setEmail] = useState(loginHint);
   const [password, setPassword] = useState("");
   const [showPassword, setShowPassword] = useState(false);
   const [isForgotOpen, setForgotOpen] = useState(false);
   const [error, setError] = useState(initialError);
+  const [info, setInfo] = useState("");
+  const [isSendingLink, setSendingLink] = useState(false);
+  const [lockedEmail, setLockedEmail] = useState("");
+  const [unlockCode, setUnlockCode] = useState("");
+  const [hasSentUnlockCode, setSentUnlockCode] = useState(false);
+  const [isUnlocking, setUnlocking] = useState(false);
   const [fieldErrors, setFieldErrors] = useState({
     email: "",
     password: "",
@@ -41,6 +50,168 @@ export default function LoginForm({ clientId, redirectUri = "", initialError = "
     setError(initialError);
   }, [initialError]);
 
+  const getPasskeyErrorMessage = (err) => {
+    const status = err.response?.status;
+    if (status === 403) {
+      return "Your account has been suspended.";
+    }
+    if (status === 429) {
+      return "Too many attempts. Please wait and try again.";
+    }
+    return "Passkey sign-in failed. Try again or use your password.";
+  };
+
+  const signInWithPasskey = async ({ conditional = false } = {}) => {
+    const options = await authService.beginPasskeyLogin({ conditional });
+    const credential = await getPasskeyCredential(options, {
+      useBrowserAutofill: conditional,
+    });
+    const redirectUrl = await authService.finishPasskeyLogin(credential, clientId, redirectUri);
+
+    if (!redirectUrl) {
+      setError("Invalid server response. Please contact support.");
+      return;
+    }
+
+    window.location.href = redirectUrl;
+  };
+
+  // Offer saved passkeys in the autofill of the email field, so users can
+  // skip the password entirely.
+  useEffect(() => {
+    if (!clientId) {
+      return undefined;
+    }
+
+    let active = true;
+    supportsPasskeyAutofill().then((supported) => {
+      if (!supported || !active) {
+        return;
+      }
+
+      signInWithPasskey({ conditional: true }).catch((err) => {
+        // Aborted or dismissed prompts are not errors.
+        if (active && err.response) {
+          setError(getPasskeyErrorMessage(err));
+        }
+      });
+    });
+
+    return () => {
+      active = false;
+      cancelPasskeyCeremony();
+    };
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [clientId, redirectUri]);
+
+  const handlePasskeySignIn = async () => {
+    setError("");
+
+    if (!clientId) {
+      setError("Login client is missing.");
+      return;
+    }
+
+    try {
+      await signInWithPasskey();
+    } catch (err) {
+      if (err.name === "NotAllowedError" || err.name === "AbortError") {
+        return;
+      }
+      setError(getPasskeyErrorMessage(err));
+    }
+  };
+
+  const handleMagicLinkRequest = async () => {
+    setError("");
+    setInfo("");
+
+    const emailError = getEmailError(email);
+    setFieldErrors((prev) => ({
+      ...prev,
+      email: emailError,
+    }));
+    if (emailError) {
+      setError(emailError);
+      return;
+    }
+
+    if (!clientId) {
+      setError("Login client is missing.");
+      return;
+    }
+
+    setSendingLink(true);
+    try {
+      await authService.requestMagicLink(email, clientId, redirectUri);
+      setInfo("If this email belongs to an account, a sign-in link is on its way. Check your inbox.");
+    } catch (err) {
+      const status = err.response?.status;
+      if (status === 403) {
+        setError("Email sign-in links are not enabled for this app.");
+      } else if (status === 429) {
+        setError("Too many requests. Please wait before asking for another link.");
+      } else {
+        setError("Failed to send the sign-in link. Please try again.");
+      }
+    } finally {
+      setSendingLink(false);
+    }
+  };
+
+  // A locked account can be unlocked with a code sent to its email, which
+  // proves the owner is the one signing in.
+  const handleSendUnlockCode = async () => {
+    setError("");
+    setInfo("");
+    setUnlocking(true);
+    try {
+      await passwordResetService.sendOtp({ email: lockedEmail });
+      setSentUnlockCode(true);
+      setInfo("We sent an unlock code to your email.");
+    } catch (err) {
+      if (err.response?.status === 429) {
+        setError("Too many requests. Please wait before asking for another code.");
+      } else {
+        setError("Failed to send the unlock code. Please try again.");
+      }
+    } finally {
+      setUnlocking(false);
+    }
+  };
+
+  const handleUnlock = async () => {
+    setError("");
+    setInfo("");
+
+    if (!/^\d{6}$/.test(unlockCode)) {
+      setError("Enter the 6-digit unlock code.");
+      return;
+    }
+
+    setUnlocking(true);
+    try {
+      await passwordResetService.verifyOtp({
+        email: lockedEmail,
+        otp: unlockCode,
+        purpose: "account_unlock",
+      });
+      setLockedEmail("");
+      setUnlockCode("");
+      setSentUnlockCode(false);
+      setPassword("");
+      setInfo("Your account is unlocked. Sign in again.");
+    } catch (err) {
+      if (err.response?.status === 401) {
+        setError("The unlock code is incorrect or has expired.");
+      } else {
+        setError("Failed to unlock your account. Please try again.");
+      }
+    } finally {
+      setUnlocking(false);
+    }
+  };
+
   const toggleShowPassword = () => {
     setShowPassword((prev) => !prev);
   };
@@ -154,6 +325,20 @@ export default function LoginForm({ clientId, redirectUri = "", initialError = "
             replace: true,
           });
         }
+      } else if (status === 423) {
+        setLockedEmail(email);
+        setUnlockCode("");
+        setSentUnlockCode(false);
+        setError(
+          "Your account is temporarily locked after too many failed attempts. Unlock it with a code sent to your email, or try again later.",
+        );
+      } else if (status === 429) {
+        const retryAfter = Number(err.response?.headers?.["retry-after"]);
+        setError(
+          retryAfter > 0
+            ? `Too many attempts. Please wait ${retryAfter} seconds and try again.`
+            : "Too many attempts. Please wait and try again.",
+        );
       } else if (status === 500) {
         setError("Server error. Please try again later.");
       } else {
@@ -185,8 +370,36 @@ export default function LoginForm({ clientId, redirectUri = "", initialError = "
                   message={error}
                   onClose={() => setError("")}
                 />
+                <InfoAlert
+                  message={info}
+                  onClose={() => setInfo("")}
+                  autoHideDuration={8000}
+                />
               </div>
 
+              {lockedEmail ? (
+                <div className="space-y-3 rounded-xl border border-white/20 bg-white/5 p-4">
+                  <p className="text-sm text-white/85">
+                    Unlock <span className="font-semibold">{lockedEmail}</span> with a one-time code.
+                  </p>
+                  {hasSentUnlockCode ? (
+                    <>
+                      <Input inputMode="numeric" autoComplete="one-time-code" maxLength={6} value={unlockCode} onChange={(e) => setUnlockCode(e.target.value.replace(/\D/g, "").slice(0, 6))} placeholder="Enter the 6-digit code" className="h-12 w-full rounded-xl border-input bg-background px-4 text-base shadow-sm focus-visible:ring-[#ffd700]"/>
+                      <Button type="button" onClick={handleUnlock} disabled={isUnlocking} className="h-12 w-full rounded-xl bg-[#ffd700] text-sm font-bold text-[#6f0f15] hover:bg-[#991b1b] hover:text-white transition duration-300">
+                        {isUnlocking ? "Unlocking..." : "Unlock account"}
+                      </Button>
+                      <button type="button" onClick={handleSendUnlockCode} disabled={isUnlocking} className="block w-full text-center text-xs font-medium text-white/70 transition duration-300 hover:text-[#ffd700]">
+                        Send a new code
+                      </button>
+                    </>
+                  ) : (
+                    <Button type="button" onClick={handleSendUnlockCode} disabled={isUnlocking} className="h-12 w-full rounded-xl bg-[#ffd700] text-sm font-bold text-[#6f0f15] hover:bg-[#991b1b] hover:text-white transition duration-300">
+                      {isUnlocking ? "Sending code..." : "Send unlock code"}
+                    </Button>
+                  )}
+                </div>
+              ) : null}
+
               <form onSubmit={handleSubmit} noValidate className="space-y-4">
                 <div>
                   <label className="mb-2 block text-sm font-medium text-white/90">
@@ -196,7 +409,7 @@ export default function LoginForm({ clientId, redirectUri = "", initialError = "
                     <span className="pointer-events-none absolute left-3 top-1/2 -translate-y-1/2 text-[#7b0d15]/60 z-10">
                       <Mail className="size-5" />
                     </span>
-                    <Input type="email" value={email} onChange={handleEmailChange} required placeholder="Enter your email"
+                    <Input type="email" autoComplete="username webauthn" value={email} onChange={handleEmailChange} required placeholder="Enter your email"
                       className={`h-12 w-full rounded-xl bg-background pl-10 pr-4 text-base shadow-sm ${
                         fieldErrors.email
                           ? "border-destructive focus-visible:ring-destructive"
@@ -256,6 +469,16 @@ export default function LoginForm({ clientId, redirectUri = "", initialError = "
                   SIGN IN
                 </Button>
 
+                <Button type="button" variant="outline" onClick={handlePasskeySignIn} className="h-12 w-full rounded-xl border-white/25 bg-transparent text-sm font-semibold text-white hover:bg-white/10 hover:text-[#ffd700] transition duration-300">
+                  <KeyRound className="size-5" />
+                  Sign in with a passkey
+                </Button>
+
+                <Button type="button" variant="outline" onClick={handleMagicLinkRequest} disabled={isSendingLink} className="h-12 w-full rounded-xl border-white/25 bg-transparent text-sm font-semibold text-white hover:bg-white/10 hover:text-[#ffd700] transition duration-300">
+                  <Send className="size-5" />
+                  {isSendingLink ? "Sending link..." : "Email me a sign-in link"}
+                </Button>
+
                 <div className="flex items-center gap-4 text-xs text-white/55">
                   <Separator className="flex-1 bg-white/15" />
                   <span>or</span>
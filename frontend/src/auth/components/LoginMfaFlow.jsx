@@ -48,13 +48,15 @@ function getPasskeyErrorMessage(error) {
   return message;
 }
 
-export default function LoginMfaFlow({ callbackRedirectUrl = "", initialEmail = "", isReturningToLogin = false, onBackToLogin }) {
+// allowEmailOtp is false after a magic link: an emailed code proves the same
+// inbox as the link and is not accepted as the second factor.
+export default function LoginMfaFlow({ allowEmailOtp = true, callbackRedirectUrl = "", initialEmail = "", isReturningToLogin = false, onBackToLogin }) {
   const navigate = useNavigate();
   const [step, setStep] = useState(MFA_STEPS.CHOOSE);
   const [email, setEmail] = useState(initialEmail);
   const [code, setCode] = useState("");
   const [backupCode, setBackupCode] = useState("");
-  const [mode, setMode] = useState("email");
+  const [mode, setMode] = useState(allowEmailOtp ? "email" : "");
   const [error, setError] = useState("");
   const [info, setInfo] = useState("");
   const [isLoading, setIsLoading] = useState(true);
@@ -447,6 +449,7 @@ export default function LoginMfaFlow({ callbackRedirectUrl = "", initialEmail =
 
     return (
       <MfaVerifyStep
+        allowEmail={allowEmailOtp}
         email={email}
         code={code}
         mode={mode}
@@ -1,11 +1,53 @@
-import { describe, it, expect } from 'vitest';
-import { render } from '@testing-library/react';
+import { describe, it, expect, vi } from 'vitest';
+import { render, screen, fireEvent, waitFor } from '@testing-library/react';
 import LoginForm from '../LoginForm';
 import { BrowserRouter } from 'react-router-dom';
+import { authService } from '../../services/authService';
+import { passwordResetService } from '../../../services/passwordResetService';
+
+vi.mock('../../services/authService', () => ({
+  authService: {
+    login: vi.fn()
+  }
+}));
+
+vi.mock('../../../services/passwordResetService', () => ({
+  passwordResetService: {
+    sendOtp: vi.fn(),
+    verifyOtp: vi.fn()
+  }
+}));
 
 describe('LoginForm Component', () => {
   it('renders without crashing', () => {
     const { container } = render(<BrowserRouter><LoginForm /></BrowserRouter>);
     expect(container).toBeInTheDocument();
   });
+
+  it('pre-fills the email from the login hint', () => {
+    render(<BrowserRouter><LoginForm loginHint="jane@example.com" /></BrowserRouter>);
+    expect(screen.getByPlaceholderText('Enter your email')).toHaveValue('jane@example.com');
+  });
+
+  it('unlocks a locked account with an emailed code', async () => {
+    authService.login.mockRejectedValue({ response: { status: 423 } });
+    passwordResetService.sendOtp.mockResolvedValue({});
+    passwordResetService.verifyOtp.mockResolvedValue({});
+    render(<BrowserRouter><LoginForm clientId="client_1" loginHint="jane@example.com" /></BrowserRouter>);
+
+    fireEvent.change(screen.getByPlaceholderText('Enter your password'), { target: { value: 'secret' } });
+    fireEvent.click(screen.getByText('SIGN IN'));
+    fireEvent.click(await screen.findByText('Send unlock code'));
+    fireEvent.change(await screen.findByPlaceholderText('Enter the 6-digit code'), { target: { value: '123456' } });
+    fireEvent.click(screen.getByText('Unlock account'));
+
+    await waitFor(() => {
+      expect(passwordResetService.verifyOtp).toHaveBeenCalledWith({
+        email: 'jane@example.com',
+        otp: '123456',
+        purpose: 'account_unlock'
+      });
+    });
+    expect(passwordResetService.sendOtp).toHaveBeenCalledWith({ email: 'jane@example.com' });
+  });
 });
@@ -12,7 +12,7 @@ function MfaMethodButton({ label, icon, isActive, isLoading = false, loadingText
   );
 }
 
-export default function MfaVerifyStep({ email, code, mode, hasSentOtp, isSendingOtp, isVerifying, isCheckingAuthenticators, isCheckingPasskey, isCancelling = false, onSelectEmail, onSelectAuthenticator, onSelectPasskey, onCodeChange, onSendOtp, onVerify, onCancel }) {
+export default function MfaVerifyStep({ allowEmail = true, email, code, mode, hasSentOtp, isSendingOtp, isVerifying, isCheckingAuthenticators, isCheckingPasskey, isCancelling = false, onSelectEmail, onSelectAuthenticator, onSelectPasskey, onCodeChange, onSendOtp, onVerify, onCancel }) {
   const isEmailMode = mode === "email";
   const isAuthenticatorMode = mode === "authenticator";
   const isPasskeyMode = mode === "passkey";
@@ -36,12 +36,14 @@ export default function MfaVerifyStep({ email, code, mode, hasSentOtp, isSending
       </div>
 
       <form onSubmit={onVerify} className="space-y-4">
-        <MfaMethodButton
-          label="Email"
-          icon={<Mail className="size-4" />}
-          isActive={isEmailMode}
-          onClick={onSelectEmail}
-        />
+        {allowEmail ? (
+          <MfaMethodButton
+            label="Email"
+            icon={<Mail className="size-4" />}
+            isActive={isEmailMode}
+            onClick={onSelectEmail}
+          />
+        ) : null}
 
         {isEmailMode && !hasSentOtp ? (
           <Button type="button" onClick={onSendOtp} disabled={isSendingOtp || !email} className="h-11 w-full bg-[#ffd700] text-[#991b1b] hover:bg-[#991b1b] hover:text-white font-bold transition duration-300">
@@ -34,6 +34,12 @@ describe('MfaVerifyStep Component', () => {
     expect(screen.getByText('Passkey')).toBeInTheDocument();
   });
 
+  it('hides the email option when it is not allowed', () => {
+    render(<MfaVerifyStep {...defaultProps} allowEmail={false} />);
+    expect(screen.queryByText('Email')).not.toBeInTheDocument();
+    expect(screen.getByText('Passkey')).toBeInTheDocument();
+  });
+
   it('handles cancel click', () => {
     render(<MfaVerifyStep {...defaultProps} />);
     fireEvent.click(screen.getByText('Back to login'));
@@ -0,0 +1,149 @@
+import { useEffect, useState } from "react";
+import { useSearchParams } from "react-router-dom";
+import { authService } from "../services/authService";
+import { authPageBackground } from "../utils/authBackground";
+import DotField from "@/components/ui/DotField";
+import { buildLoginPath, getLoginClientId } from "../utils/loginRoute";
+import { Button } from "../../components/ui/button";
+
+function isUnauthorized(error) {
+  return error?.response?.status === 401;
+}
+
+function getErrorMessage(error) {
+  return error?.response?.data?.error_description
+    || error?.response?.data?.message
+    || "Unable to load the consent request.";
+}
+
+export default function Consent() {
+  const [searchParams] = useSearchParams();
+  const clientId = getLoginClientId(searchParams);
+  const scope = searchParams.get("scope") ?? "";
+  const [prompt, setPrompt] = useState(null);
+  const [errorMessage, setErrorMessage] = useState("");
+  const [isSubmitting, setIsSubmitting] = useState(false);
+
+  useEffect(() => {
+    let isActive = true;
+
+    authService.getConsent(clientId, scope)
+      .then((data) => {
+        if (isActive) {
+          setPrompt(data);
+        }
+      })
+      .catch((error) => {
+        if (!isActive) {
+          return;
+        }
+
+        if (isUnauthorized(error)) {
+          window.location.replace(buildLoginPath(clientId));
+          return;
+        }
+
+        setErrorMessage(getErrorMessage(error));
+      });
+
+    return () => {
+      isActive = false;
+    };
+  }, [clientId, scope]);
+
+  const handleDecision = async (approve) => {
+    if (isSubmitting) {
+      return;
+    }
+
+    setIsSubmitting(true);
+
+    try {
+      const redirectUrl = await authService.submitConsent({ clientId, scope, approve });
+
+      if (redirectUrl) {
+        window.location.replace(redirectUrl);
+        return;
+      }
+
+      setErrorMessage("Unable to continue the sign-in.");
+    } catch (error) {
+      if (isUnauthorized(error)) {
+        window.location.replace(buildLoginPath(clientId));
+        return;
+      }
+
+      setErrorMessage(getErrorMessage(error));
+    }
+
+    setIsSubmitting(false);
+  };
+
+  const scopes = prompt?.scopes ?? [];
+  const grantedScopes = new Set(prompt?.granted_scopes ?? []);
+
+  return (
+    <main className="relative min-h-screen overflow-hidden font-[Poppins] text-white" style={{ background: authPageBackground }}>
+      <div className="absolute inset-0 overflow-hidden" aria-hidden="true">
+        <DotField
+          dotRadius={1.5}
+          dotSpacing={14}
+          bulgeStrength={67}
+          glowRadius={160}
+          sparkle={false}
+          waveAmplitude={0}
+          cursorRadius={500}
+          cursorForce={0.1}
+          bulgeOnly
+          gradientFrom="rgba(255, 255, 255, 0.22)"
+          gradientTo="rgba(255, 255, 255, 0.08)"
+          glowColor="rgba(0, 0, 0, 0.2)"
+        />
+      </div>
+
+      <section className="relative flex min-h-screen flex-col items-center justify-center px-4 text-center">
+        <div className="relative flex h-44 w-44 items-center justify-center sm:h-48 sm:w-48">
+          <img src="/assets/images/IDP_Logo.png" alt="IDP Logo" className="relative z-10 w-24 sm:w-28"/>
+        </div>
+
+        {errorMessage ? (
+          <p role="alert" className="mt-7 max-w-2xl text-sm font-medium leading-7 text-white/85">
+            {errorMessage}
+          </p>
+        ) : !prompt ? (
+          <p className="mt-7 text-sm font-medium uppercase tracking-widest text-white/85">
+            Loading...
+          </p>
+        ) : (
+          <>
+            <div className="mt-7 max-w-2xl">
+              <p className="text-sm font-medium uppercase leading-7 tracking-widest text-white/85">
+                {prompt.client_name || prompt.client_id} is requesting access to your account.
+              </p>
+            </div>
+
+            <ul className="mt-6 w-full max-w-lg space-y-2 text-left text-sm">
+              {scopes.map((item) => (
+                <li key={item} className="rounded-lg border border-white/20 bg-white/10 px-4 py-3">
+                  {item}
+                  {grantedScopes.has(item) && (
+                    <span className="ml-2 text-xs text-white/60">(previously granted)</span>
+                  )}
+                </li>
+              ))}
+            </ul>
+
+            <div className="mt-8 flex w-full max-w-lg flex-col gap-3 sm:flex-row sm:justify-center">
+              <Button type="button" onClick={() => handleDecision(false)} disabled={isSubmitting} className="h-12 w-full rounded-lg border border-[#ffd700] bg-white/10 px-6 text-[#ffd700] shadow-[0_18px_40px_-24px_rgba(0,0,0,0.9)] transition duration-300 hover:border-[#7b0d15] hover:bg-[#7b0d15] hover:text-white sm:w-auto sm:min-w-40">
+                Deny
+              </Button>
+              <Button type="button" onClick={() => handleDecision(true)} disabled={isSubmitting} className="h-12 w-full rounded-lg border border-[#ffd700] bg-[#ffd700] px-6 text-[#7b0d15] shadow-[0_18px_40px_-22px_rgba(248,210,78,0.65)] transition duration-300 hover:border-[#7b0d15] hover:bg-[#7b0d15] hover:text-white sm:w-auto sm:min-w-44">
+                {isSubmitting ? "Continuing..." : "Allow"}
+              </Button>
+            </div>
+          </>
+        )}
+      </section>
+    </main>
+  );
+}
@@ -0,0 +1,192 @@
+import { useEffect, useState } from "react";
+import { useSearchParams } from "react-router-dom";
+import { authService } from "../services/authService";
+import { authPageBackground } from "../utils/authBackground";
+import DotField from "@/components/ui/DotField";
+import { getCurrentReturnPath, redirectToAuthorize } from "../utils/authorizeFlow";
+import { buildLoginPath } from "../utils/loginRoute";
+import { Button } from "../../components/ui/button";
+
+function isUnauthorized(error) {
+  return error?.response?.status === 401;
+}
+
+function getErrorMessage(error, fallbackMessage) {
+  return error?.response?.data?.message
+    || error?.response?.data?.error_description
+    || fallbackMessage;
+}
+
+function normalizeUserCode(value) {
+  return value.toUpperCase().replace(/[^A-Z0-9-]/g, "");
+}
+
+// signIn sends the user through the portal sign-in and brings them back to
+// this page, user code included.
+function signIn() {
+  if (!redirectToAuthorize(undefined, getCurrentReturnPath())) {
+    window.location.replace(buildLoginPath());
+  }
+}
+
+// Device is the verification page of the device authorization grant: the
+// signed-in user enters the code shown on a device, checks which
+// application asks for access and approves or denies it.
+export default function Device() {
+  const [searchParams] = useSearchParams();
+  const [userCode, setUserCode] = useState(
+    normalizeUserCode(searchParams.get("user_code") ?? ""),
+  );
+  const [request, setRequest] = useState(null);
+  const [result, setResult] = useState("");
+  const [errorMessage, setErrorMessage] = useState("");
+  const [isLoading, setIsLoading] = useState(false);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+
+  const loadRequest = async (code) => {
+    setErrorMessage("");
+    setIsLoading(true);
+
+    try {
+      setRequest(await authService.getDevice(code));
+    } catch (error) {
+      if (isUnauthorized(error)) {
+        signIn();
+        return;
+      }
+
+      setErrorMessage(getErrorMessage(error, "Unable to find this code."));
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  useEffect(() => {
+    if (userCode) {
+      loadRequest(userCode);
+    }
+  }, []);
+
+  const handleSubmitCode = (event) => {
+    event.preventDefault();
+
+    if (!userCode) {
+      setErrorMessage("Enter the code shown on your device.");
+      return;
+    }
+
+    loadRequest(userCode);
+  };
+
+  const handleDecision = async (approve) => {
+    if (isSubmitting) {
+      return;
+    }
+
+    setErrorMessage("");
+    setIsSubmitting(true);
+
+    try {
+      await authService.answerDevice({ userCode: request.user_code, approve });
+      setResult(approve
+        ? "Device approved. You can return to your device."
+        : "Device denied. You can close this page.");
+    } catch (error) {
+      if (isUnauthorized(error)) {
+        signIn();
+        return;
+      }
+
+      setErrorMessage(getErrorMessage(error, "Unable to answer this request."));
+    }
+
+    setIsSubmitting(false);
+  };
+
+  const scopes = request?.scopes ?? [];
+
+  return (
+    <main className="relative min-h-screen overflow-hidden font-[Poppins] text-white" style={{ background: authPageBackground }}>
+      <div className="absolute inset-0 overflow-hidden" aria-hidden="true">
+        <DotField
+          dotRadius={1.5}
+          dotSpacing={14}
+          bulgeStrength={67}
+          glowRadius={160}
+          sparkle={false}
+          waveAmplitude={0}
+          cursorRadius={500}
+          cursorForce={0.1}
+          bulgeOnly
+          gradientFrom="rgba(255, 255, 255, 0.22)"
+          gradientTo="rgba(255, 255, 255, 0.08)"
+          glowColor="rgba(0, 0, 0, 0.2)"
+        />
+      </div>
+
+      <section className="relative flex min-h-screen flex-col items-center justify-center px-4 text-center">
+        <div className="relative flex h-44 w-44 items-center justify-center sm:h-48 sm:w-48">
+          <img src="/assets/images/IDP_Logo.png" alt="IDP Logo" className="relative z-10 w-24 sm:w-28"/>
+        </div>
+
+        {errorMessage && (
+          <p role="alert" className="mt-7 max-w-2xl text-sm font-medium leading-7 text-white/85">
+            {errorMessage}
+          </p>
+        )}
+
+        {result ? (
+          <p className="mt-7 max-w-2xl text-sm font-medium uppercase leading-7 tracking-widest text-white/85">
+            {result}
+          </p>
+        ) : isLoading ? (
+          <p className="mt-7 text-sm font-medium uppercase tracking-widest text-white/85">
+            Loading...
+          </p>
+        ) : !request ? (
+          <form onSubmit={handleSubmitCode} className="mt-7 flex w-full max-w-lg flex-col gap-3">
+            <label htmlFor="user-code" className="text-sm font-medium uppercase tracking-widest text-white/85">
+              Enter the code shown on your device
+            </label>
+            <input
+              id="user-code"
+              value={userCode}
+              onChange={(event) => setUserCode(normalizeUserCode(event.target.value))}
+              autoComplete="off"
+              className="h-12 rounded-lg border border-white/20 bg-white/10 px-4 text-center text-lg tracking-widest text-white outline-none focus:border-[#ffd700]"
+            />
+            <Button type="submit" className="h-12 w-full rounded-lg border border-[#ffd700] bg-[#ffd700] px-6 text-[#7b0d15] transition duration-300 hover:border-[#7b0d15] hover:bg-[#7b0d15] hover:text-white">
+              Continue
+            </Button>
+          </form>
+        ) : (
+          <>
+            <div className="mt-7 max-w-2xl">
+              <p className="text-sm font-medium uppercase leading-7 tracking-widest text-white/85">
+                {request.client_name || request.client_id} is requesting access to your account on a device.
+              </p>
+              <p className="mt-2 text-xs text-white/60">Code: {request.user_code}</p>
+            </div>
+
+            <ul className="mt-6 w-full max-w-lg space-y-2 text-left text-sm">
+              {scopes.map((item) => (
+                <li key={item} className="rounded-lg border border-white/20 bg-white/10 px-4 py-3">
+                  {item}
+                </li>
+              ))}
+            </ul>
+
+            <div className="mt-8 flex w-full max-w-lg flex-col gap-3 sm:flex-row sm:justify-center">
+              <Button type="button" onClick={() => handleDecision(false)} disabled={isSubmitting} className="h-12 w-full rounded-lg border border-[#ffd700] bg-white/10 px-6 text-[#ffd700] shadow-[0_18px_40px_-24px_rgba(0,0,0,0.9)] transition duration-300 hover:border-[#7b0d15] hover:bg-[#7b0d15] hover:text-white sm:w-auto sm:min-w-40">
+                Deny
+              </Button>
+              <Button type="button" onClick={() => handleDecision(true)} disabled={isSubmitting} className="h-12 w-full rounded-lg border border-[#ffd700] bg-[#ffd700] px-6 text-[#7b0d15] shadow-[0_18px_40px_-22px_rgba(248,210,78,0.65)] transition duration-300 hover:border-[#7b0d15] hover:bg-[#7b0d15] hover:text-white sm:w-auto sm:min-w-44">
+                {isSubmitting ? "Continuing..." : "Approve"}
+              </Button>
+            </div>
+          </>
+        )}
+      </section>
+    </main>
+  );
+}
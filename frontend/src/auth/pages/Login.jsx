@@ -5,7 +5,7 @@ import AuthLoadingScreen from "../components/AuthLoadingScreen";
 import LoginForm from "../components/LoginForm";
 import LoginMfaFlow from "../components/LoginMfaFlow";
 import AccessDenied from "./AccessDenied";
-import { buildLoginPath, getLoginClientId, getLoginErrorCode, getLoginErrorMessage, getLoginRedirectUri, isLoginMfaRequested, LOGIN_ERROR_CODES } from "../utils/loginRoute";
+import { buildLoginPath, getLoginClientId, getLoginErrorCode, getLoginErrorMessage, getLoginHint, getLoginRedirectUri, isLoginMfaRequested, LOGIN_ERROR_CODES } from "../utils/loginRoute";
 import { DEFAULT_AUTHENTICATED_PATH } from "../utils/authAccess";
 import { hasStoredAccessToken } from "../utils/authRecovery";
 import { clearAuthState } from "../utils/authCookies";
@@ -24,6 +24,7 @@ export default function Login() {
   const [searchParams] = useSearchParams();
   const clientId = getLoginClientId(searchParams);
   const redirectUri = getLoginRedirectUri(searchParams);
+  const loginHint = getLoginHint(searchParams);
   const isClientLoginFlow =
     Boolean(clientId) &&
     (clientId !== authClientId || Boolean(redirectUri));
@@ -209,6 +210,7 @@ export default function Login() {
         <LoginForm
           clientId={clientId}
           redirectUri={redirectUri}
+          loginHint={loginHint}
           initialError={loginErrorMessage}
           onLoginSuccess={handleLoginSuccess}
         />
@@ -0,0 +1,87 @@
+import { useEffect, useRef, useState } from "react";
+import { useNavigate, useSearchParams } from "react-router-dom";
+import AuthLayout from "../layouts/AuthLayout";
+import AuthLoadingScreen from "../components/AuthLoadingScreen";
+import LoginMfaFlow from "../components/LoginMfaFlow";
+import { authService } from "../services/authService";
+import { beginPendingMfaSession, clearAuthState } from "../utils/authCookies";
+import { buildAccessDeniedPath, buildLoginPath, LOGIN_ERROR_CODES } from "../utils/loginRoute";
+
+function getRedirectClientId(redirectUrl) {
+  try {
+    return new URL(redirectUrl).searchParams.get("client_id") || undefined;
+  } catch {
+    return undefined;
+  }
+}
+
+// The emailed link opens this page rather than the API, so that mail
+// scanners that prefetch links cannot spend the single-use token. The link
+// is the first factor; the user then verifies a second one, as after a
+// password, other than an emailed code.
+export default function MagicLink() {
+  const [searchParams] = useSearchParams();
+  const navigate = useNavigate();
+  const hasRun = useRef(false);
+  const [mfaContext, setMfaContext] = useState(null);
+
+  useEffect(() => {
+    if (hasRun.current) return;
+    hasRun.current = true;
+
+    const invalidLinkPath = buildLoginPath(undefined, {
+      authError: LOGIN_ERROR_CODES.MAGIC_LINK_INVALID,
+    });
+
+    const handleLink = async () => {
+      const token = searchParams.get("token");
+
+      if (!token) {
+        navigate(invalidLinkPath, { replace: true });
+        return;
+      }
+
+      try {
+        const { redirectUrl, email } = await authService.verifyMagicLink(token);
+
+        if (!redirectUrl) {
+          throw new Error("Magic link verification did not return a redirect.");
+        }
+
+        beginPendingMfaSession(email);
+        setMfaContext({ email, redirectUrl });
+      } catch (err) {
+        console.error(err);
+        if (err.response?.data?.code === 1030) {
+          navigate(buildAccessDeniedPath(undefined, { reason: "suspended" }), { replace: true });
+          return;
+        }
+        navigate(invalidLinkPath, { replace: true });
+      }
+    };
+
+    handleLink();
+  }, [searchParams, navigate]);
+
+  const handleBackToLogin = () => {
+    clearAuthState();
+    navigate(buildLoginPath(getRedirectClientId(mfaContext?.redirectUrl)), {
+      replace: true,
+    });
+  };
+
+  if (!mfaContext) {
+    return <AuthLoadingScreen message="Signing You In" />;
+  }
+
+  return (
+    <AuthLayout>
+      <LoginMfaFlow
+        allowEmailOtp={false}
+        callbackRedirectUrl={mfaContext.redirectUrl}
+        initialEmail={mfaContext.email}
+        onBackToLogin={handleBackToLogin}
+      />
+    </AuthLayout>
+  );
+}
@@ -0,0 +1,241 @@
+import { useEffect, useState } from "react";
+import { useSearchParams } from "react-router-dom";
+import { authService } from "../services/authService";
+import { mfaService } from "../../services/mfaService";
+import { passwordResetService } from "../../services/passwordResetService";
+import { buildAuthorizeUrl } from "../utils/authorizeFlow";
+import { buildLoginPath, getLoginClientId } from "../utils/loginRoute";
+import { getPasskeyCredential } from "../utils/webAuthn";
+import ErrorAlert from "../../components/ErrorAlert";
+import MfaLoadingStep from "../components/mfa/MfaLoadingStep";
+import MfaShell from "../components/mfa/MfaShell";
+import MfaVerifyStep from "../components/mfa/MfaVerifyStep";
+import { getDigits } from "../components/mfa/mfaInputUtils";
+
+function getRequestErrorMessage(error, fallbackMessage) {
+  return (
+    error?.response?.data?.error ||
+    error?.response?.data?.message ||
+    error?.message ||
+    fallbackMessage
+  );
+}
+
+// StepUp asks a signed-in user for a second factor again when a client
+// requires a stronger authentication context, then resumes the remembered
+// authorization request.
+export default function StepUp() {
+  const [searchParams] = useSearchParams();
+  const clientId = getLoginClientId(searchParams);
+  const [email, setEmail] = useState("");
+  const [code, setCode] = useState("");
+  const [mode, setMode] = useState("email");
+  const [error, setError] = useState("");
+  const [isLoading, setIsLoading] = useState(true);
+  const [hasSentOtp, setHasSentOtp] = useState(false);
+  const [isSendingOtp, setIsSendingOtp] = useState(false);
+  const [isVerifying, setIsVerifying] = useState(false);
+  const [isCheckingAuthenticators, setIsCheckingAuthenticators] =
+    useState(false);
+  const [isCheckingPasskey, setIsCheckingPasskey] = useState(false);
+
+  const returnToLogin = () => {
+    window.location.replace(buildLoginPath(clientId));
+  };
+
+  const finishStepUp = () => {
+    window.location.replace(buildAuthorizeUrl(clientId));
+  };
+
+  useEffect(() => {
+    let isMounted = true;
+
+    async function loadSession() {
+      try {
+        const session = await authService.checkSession();
+
+        if (isMounted) {
+          setEmail(session?.email || "");
+        }
+      } catch (loadError) {
+        if (!isMounted) {
+          return;
+        }
+
+        if (loadError?.response?.status === 401) {
+          returnToLogin();
+          return;
+        }
+
+        setError(
+          getRequestErrorMessage(
+            loadError,
+            "Unable to prepare verification. Please sign in again.",
+          ),
+        );
+      } finally {
+        if (isMounted) {
+          setIsLoading(false);
+        }
+      }
+    }
+
+    loadSession();
+
+    return () => {
+      isMounted = false;
+    };
+  }, []);
+
+  const handleSendOtp = async () => {
+    setError("");
+
+    if (!email) {
+      setError("Your email address is unavailable.");
+      return;
+    }
+
+    try {
+      setIsSendingOtp(true);
+      await passwordResetService.sendOtp({ email });
+      setHasSentOtp(true);
+    } catch (otpError) {
+      setError(
+        getRequestErrorMessage(otpError, "Unable to send an OTP right now."),
+      );
+    } finally {
+      setIsSendingOtp(false);
+    }
+  };
+
+  const handleSelectEmail = () => {
+    setMode("email");
+    setCode("");
+    setError("");
+  };
+
+  const handleSelectAuthenticator = async () => {
+    setError("");
+    setCode("");
+
+    try {
+      setIsCheckingAuthenticators(true);
+      const hasAuthenticator = await mfaService.hasTotpAuthenticator(email);
+
+      if (!hasAuthenticator) {
+        setError("No authenticator app is set up for this account.");
+        return;
+      }
+
+      setMode("authenticator");
+    } catch (authenticatorError) {
+      setError(
+        getRequestErrorMessage(
+          authenticatorError,
+          "Unable to check your authenticator apps.",
+        ),
+      );
+    } finally {
+      setIsCheckingAuthenticators(false);
+    }
+  };
+
+  const handleSelectPasskey = async () => {
+    setError("");
+    setCode("");
+    setMode("passkey");
+
+    try {
+      setIsCheckingPasskey(true);
+      const hasPasskey = await mfaService.hasPasskey(email);
+
+      if (!hasPasskey) {
+        setError("No passkey is registered for this account.");
+        return;
+      }
+
+      let platformAvailable = false;
+      if (window.PublicKeyCredential &&
+          typeof window.PublicKeyCredential
+            .isUserVerifyingPlatformAuthenticatorAvailable === "function") {
+        platformAvailable = await window.PublicKeyCredential
+          .isUserVerifyingPlatformAuthenticatorAvailable();
+      }
+
+      const options = await mfaService.beginPasskeyVerification(
+        email,
+        platformAvailable,
+      );
+      const credential = await getPasskeyCredential(options);
+
+      await mfaService.finishPasskeyVerification(email, credential);
+      finishStepUp();
+    } catch (passkeyError) {
+      setError(
+        passkeyError?.response?.status === 401
+          ? "Passkey verification failed. Try another method."
+          : getRequestErrorMessage(passkeyError, "Unable to verify your passkey."),
+      );
+    } finally {
+      setIsCheckingPasskey(false);
+    }
+  };
+
+  const handleVerify = async (event) => {
+    event.preventDefault();
+    setError("");
+
+    if (code.length !== 6) {
+      setError("Enter the 6-digit verification code.");
+      return;
+    }
+
+    try {
+      setIsVerifying(true);
+
+      if (mode === "authenticator") {
+        await mfaService.verifyCode({ email, code });
+      } else {
+        await passwordResetService.verifyOtp({ email, otp: code });
+      }
+
+      finishStepUp();
+    } catch (verifyError) {
+      setError(
+        getRequestErrorMessage(verifyError, "Unable to verify this code."),
+      );
+    } finally {
+      setIsVerifying(false);
+    }
+  };
+
+  return (
+    <MfaShell>
+      <div className="mb-5">
+        <ErrorAlert message={error} onClose={() => setError("")} />
+      </div>
+
+      {isLoading ? (
+        <MfaLoadingStep />
+      ) : (
+        <MfaVerifyStep
+          email={email}
+          code={code}
+          mode={mode}
+          hasSentOtp={hasSentOtp}
+          isSendingOtp={isSendingOtp}
+          isVerifying={isVerifying}
+          isCheckingAuthenticators={isCheckingAuthenticators}
+          isCheckingPasskey={isCheckingPasskey}
+          onSelectEmail={handleSelectEmail}
+          onSelectAuthenticator={handleSelectAuthenticator}
+          onSelectPasskey={handleSelectPasskey}
+          onCodeChange={(value) => setCode(getDigits(value))}
+          onSendOtp={handleSendOtp}
+          onVerify={handleVerify}
+          onCancel={returnToLogin}
+        />
+      )}
+    </MfaShell>
+  );
+}
@@ -0,0 +1,53 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { render, screen, waitFor, fireEvent } from '@testing-library/react';
+import Consent from '../Consent';
+import { authService } from '../../services/authService';
+
+const mockSearchParams = new URLSearchParams({ client_id: 'client_1', scope: 'openid email' });
+
+vi.mock('react-router-dom', () => ({
+  useSearchParams: () => [mockSearchParams]
+}));
+
+vi.mock('../../services/authService', () => ({
+  authService: {
+    getConsent: vi.fn(),
+    submitConsent: vi.fn()
+  }
+}));
+
+describe('Consent Page', () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+  });
+
+  it('lists the requested scopes', async () => {
+    authService.getConsent.mockResolvedValue({
+      client_id: 'client_1',
+      client_name: 'Portal',
+      scopes: ['openid', 'email'],
+      granted_scopes: ['openid']
+    });
+    render(<Consent />);
+
+    expect(await screen.findByText(/Portal is requesting access/i)).toBeInTheDocument();
+    expect(screen.getByText('email')).toBeInTheDocument();
+    expect(authService.getConsent).toHaveBeenCalledWith('client_1', 'openid email');
+  });
+
+  it('submits the decision', async () => {
+    authService.getConsent.mockResolvedValue({ client_id: 'client_1', scopes: ['openid'] });
+    authService.submitConsent.mockReturnValue(new Promise(() => {}));
+    render(<Consent />);
+
+    fireEvent.click(await screen.findByText('Deny'));
+
+    await waitFor(() => {
+      expect(authService.submitConsent).toHaveBeenCalledWith({
+        clientId: 'client_1',
+        scope: 'openid email',
+        approve: false
+      });
+    });
+  });
+});
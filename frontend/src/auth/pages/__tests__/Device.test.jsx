@@ -0,0 +1,49 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { render, screen, waitFor, fireEvent } from '@testing-library/react';
+import Device from '../Device';
+import { authService } from '../../services/authService';
+
+const mockSearchParams = new URLSearchParams({ user_code: 'ABCD-EFGH' });
+
+vi.mock('react-router-dom', () => ({
+  useSearchParams: () => [mockSearchParams]
+}));
+
+vi.mock('../../services/authService', () => ({
+  authService: {
+    getDevice: vi.fn(),
+    answerDevice: vi.fn()
+  }
+}));
+
+describe('Device Page', () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+  });
+
+  it('shows the device request of the code', async () => {
+    authService.getDevice.mockResolvedValue({
+      user_code: 'ABCD-EFGH',
+      client_id: 'client_1',
+      client_name: 'Kiosk',
+      scopes: ['openid']
+    });
+    render(<Device />);
+
+    expect(await screen.findByText(/Kiosk is requesting access/i)).toBeInTheDocument();
+    expect(authService.getDevice).toHaveBeenCalledWith('ABCD-EFGH');
+  });
+
+  it('approves the device', async () => {
+    authService.getDevice.mockResolvedValue({ user_code: 'ABCD-EFGH', client_id: 'client_1', scopes: [] });
+    authService.answerDevice.mockResolvedValue({ message: 'Device approved.' });
+    render(<Device />);
+
+    fireEvent.click(await screen.findByText('Approve'));
+
+    await waitFor(() => {
+      expect(authService.answerDevice).toHaveBeenCalledWith({ userCode: 'ABCD-EFGH', approve: true });
+    });
+    expect(await screen.findByText(/Device approved/i)).toBeInTheDocument();
+  });
+});
@@ -0,0 +1,79 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { render, screen, waitFor } from '@testing-library/react';
+import MagicLink from '../MagicLink';
+import { authService } from '../../services/authService';
+
+const mockNavigate = vi.fn();
+let mockSearchParams = new URLSearchParams({ token: 'link_token' });
+
+vi.mock('react-router-dom', () => ({
+  useSearchParams: () => [mockSearchParams],
+  useNavigate: () => mockNavigate
+}));
+
+vi.mock('../../services/authService', () => ({
+  authService: {
+    verifyMagicLink: vi.fn()
+  }
+}));
+
+vi.mock('../../layouts/AuthLayout', () => ({
+  default: ({ children }) => <div>{children}</div>
+}));
+
+vi.mock('../../components/LoginMfaFlow', () => ({
+  default: ({ allowEmailOtp, callbackRedirectUrl, initialEmail }) => (
+    <div>
+      MFA for {initialEmail} to {callbackRedirectUrl}
+      {allowEmailOtp ? null : <span>No email code</span>}
+    </div>
+  )
+}));
+
+describe('MagicLink Page', () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    mockSearchParams = new URLSearchParams({ token: 'link_token' });
+  });
+
+  it('renders loading message', () => {
+    authService.verifyMagicLink.mockReturnValue(new Promise(() => {}));
+    render(<MagicLink />);
+    expect(screen.getByText(/Signing You In/i)).toBeInTheDocument();
+  });
+
+  it('sends an invalid link back to the login page', async () => {
+    authService.verifyMagicLink.mockRejectedValue({ response: { status: 401 } });
+    render(<MagicLink />);
+
+    await waitFor(() => {
+      expect(mockNavigate).toHaveBeenCalledWith(
+        expect.stringContaining('auth_error=magic_link_invalid'),
+        { replace: true }
+      );
+    });
+    expect(authService.verifyMagicLink).toHaveBeenCalledWith('link_token');
+  });
+
+  it('asks for a second factor before continuing', async () => {
+    authService.verifyMagicLink.mockResolvedValue({
+      redirectUrl: 'https://api.example.com/api/v1/auth/authorize?client_id=client_1&state=xyz',
+      email: 'user@example.com'
+    });
+    render(<MagicLink />);
+
+    expect(await screen.findByText(/MFA for user@example.com/i)).toBeInTheDocument();
+    expect(screen.getByText(/state=xyz/)).toBeInTheDocument();
+    expect(screen.getByText('No email code')).toBeInTheDocument();
+  });
+
+  it('does not call the API without a token', async () => {
+    mockSearchParams = new URLSearchParams();
+    render(<MagicLink />);
+
+    await waitFor(() => {
+      expect(mockNavigate).toHaveBeenCalled();
+    });
+    expect(authService.verifyMagicLink).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,20 @@
+import { describe, it, expect, vi } from 'vitest';
+import { render, screen } from '@testing-library/react';
+import StepUp from '../StepUp';
+
+vi.mock('react-router-dom', () => ({
+  useSearchParams: () => [new URLSearchParams({ client_id: 'client_1', acr_values: '2fa' })]
+}));
+
+vi.mock('../../services/authService', () => ({
+  authService: {
+    checkSession: vi.fn().mockResolvedValue({ authenticated: true, email: 'test@example.com' })
+  }
+}));
+
+describe('StepUp Page', () => {
+  it('shows the signed-in email once the session is loaded', async () => {
+    render(<StepUp />);
+    expect(await screen.findByText('test@example.com')).toBeInTheDocument();
+  });
+});
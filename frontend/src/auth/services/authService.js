@@ -40,6 +40,116 @@ export const authService = {
     return redirectUrl;
   },
 
+  async beginPasskeyLogin({ conditional = false } = {}) {
+    const response = await axiosInstance.post("/auth/passkey/login/begin", {
+      conditional,
+    }, {
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+    });
+
+    return response.data;
+  },
+
+  async finishPasskeyLogin(credential, clientId, redirectUri = "") {
+    const params = { client_id: clientId };
+    if (redirectUri) {
+      params.redirect_uri = redirectUri;
+    }
+
+    const response = await axiosInstance.post("/auth/passkey/login/finish", credential, {
+      params,
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    return getLoginRedirectUrl(response.data);
+  },
+
+  async requestMagicLink(email, clientId, redirectUri = "") {
+    const response = await axiosInstance.post("/auth/magic-link", {
+      email,
+      client_id: clientId,
+      redirect_uri: redirectUri,
+    }, {
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    return response.data;
+  },
+
+  async verifyMagicLink(token) {
+    const response = await axiosInstance.post("/auth/magic-link/verify", {
+      token,
+    }, {
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    const pendingToken = response.data?.mfa_pending_token;
+    if (pendingToken) {
+      storePendingMfaTokenResponse({ access_token: pendingToken });
+    }
+
+    return {
+      redirectUrl: getLoginRedirectUrl(response.data),
+      email: response.data?.email ?? "",
+    };
+  },
+
+  async getConsent(clientId, scope = "") {
+    const response = await axiosInstance.get("/auth/consent", {
+      params: { client_id: clientId, scope },
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    return response.data;
+  },
+
+  async submitConsent({ clientId, scope = "", approve }) {
+    const response = await axiosInstance.post("/auth/consent", {
+      client_id: clientId,
+      scope,
+      approve: Boolean(approve),
+    }, {
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    return getLoginRedirectUrl(response.data);
+  },
+
+  async getDevice(userCode) {
+    const response = await axiosInstance.get("/auth/device", {
+      params: { user_code: userCode },
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    return response.data;
+  },
+
+  async answerDevice({ userCode, approve }) {
+    const response = await axiosInstance.post("/auth/device", {
+      user_code: userCode,
+      approve: Boolean(approve),
+    }, {
+      skipAuthHeader: true,
+      skipAuthRefresh: true,
+      skipUnauthorizedRedirect: true,
+    });
+
+    return response.data;
+  },
+
   async exchangeCode(code) {
     const response = await axiosInstance.post("/auth/token", {
       code,
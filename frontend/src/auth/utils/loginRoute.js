@@ -2,17 +2,21 @@ const defaultClientId = import.meta.env.VITE_CLIENT_ID ?? "";
 const LOGIN_ERROR_QUERY_PARAM = "auth_error";
 const LOGIN_MFA_QUERY_PARAM = "mfa";
 const REDIRECT_URI_QUERY_PARAM = "redirect_uri";
+const LOGIN_HINT_QUERY_PARAM = "login_hint";
 export const LOGIN_PATH = "/login";
 export const ACCESS_DENIED_PATH = "/access-denied";
 export const LEGACY_UNAUTHORIZED_PATH = "/unauthorized";
 
 export const LOGIN_ERROR_CODES = {
   UNAUTHORIZED: "unauthorized",
+  MAGIC_LINK_INVALID: "magic_link_invalid",
 };
 
 const LOGIN_ERROR_MESSAGES = {
   [LOGIN_ERROR_CODES.UNAUTHORIZED]:
     "Unauthorized to access this service.",
+  [LOGIN_ERROR_CODES.MAGIC_LINK_INVALID]:
+    "This sign-in link is invalid or has expired. Please request a new one.",
 };
 
 export function buildLoginPath(clientId = defaultClientId, options = {}) {
@@ -98,6 +102,10 @@ export function getLoginRedirectUri(searchParams) {
   return searchParams.get(REDIRECT_URI_QUERY_PARAM)?.trim() ?? "";
 }
 
+export function getLoginHint(searchParams) {
+  return searchParams.get(LOGIN_HINT_QUERY_PARAM)?.trim() ?? "";
+}
+
 export function getLoginErrorCode(searchParams) {
   return searchParams.get(LOGIN_ERROR_QUERY_PARAM)?.trim() ?? "";
 }
@@ -1,4 +1,9 @@
-import { startAuthentication, startRegistration } from "@simplewebauthn/browser";
+import {
+  browserSupportsWebAuthnAutofill,
+  startAuthentication,
+  startRegistration,
+  WebAuthnAbortService,
+} from "@simplewebauthn/browser";
 
 function getPublicKeyOptions(options = {}) {
   return options.publicKey || options;
@@ -10,8 +15,21 @@ export async function createPasskeyCredential(options) {
   });
 }
 
-export async function getPasskeyCredential(options) {
+export async function getPasskeyCredential(options, { useBrowserAutofill = false } = {}) {
   return startAuthentication({
     optionsJSON: getPublicKeyOptions(options),
+    useBrowserAutofill,
   });
-}
\ No newline at end of file
+}
+
+export async function supportsPasskeyAutofill() {
+  try {
+    return await browserSupportsWebAuthnAutofill();
+  } catch {
+    return false;
+  }
+}
+
+export function cancelPasskeyCeremony() {
+  WebAuthnAbortService.cancelCeremony();
+}
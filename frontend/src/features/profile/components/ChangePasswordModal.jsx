@@ -79,6 +79,7 @@ export default function ChangePasswordModal({ isOpen, onClose, showCurrentPasswo
   const [otp, setOtp] = useState(EMPTY_OTP);
   const [otpError, setOtpError] = useState("");
   const [passwordError, setPasswordError] = useState("");
+  const [resetToken, setResetToken] = useState("");
   const [timer, setTimer] = useState(OTP_TIMER_SECONDS);
   const [canResend, setCanResend] = useState(false);
   const [successMessage, setSuccessMessage] = useState("");
@@ -255,7 +256,9 @@ export default function ChangePasswordModal({ isOpen, onClose, showCurrentPasswo
         await passwordResetService.updateForgotPassword({
           email: trimmedRecoveryEmail,
           newPassword: form.newPassword,
+          resetToken,
         });
+        setResetToken("");
         logPasswordChange();
         setStep("success");
       } catch (error) {
@@ -365,12 +368,14 @@ export default function ChangePasswordModal({ isOpen, onClose, showCurrentPasswo
     setIsVerifyingOtp(true);
 
     try {
-      await passwordResetService.verifyOtp({
+      const res = await passwordResetService.verifyOtp({
         email: otpTargetEmail,
         otp: code,
+        ...(isForgotPasswordFlow ? { purpose: "password_reset" } : {}),
       });
 
       if (isForgotPasswordFlow) {
+        setResetToken(res?.reset_token ?? "");
         setPasswordError("");
         setStep("password");
         return;
@@ -4,6 +4,10 @@ export const ROUTE_PATHS = Object.freeze({
   REGISTER: "/register",
   REGISTER_SET_PASSWORD: "/register/set-password",
   CALLBACK: "/callback",
+  MAGIC_LINK: "/magic-link",
+  CONSENT: "/consent",
+  STEP_UP: "/step-up",
+  DEVICE: "/device",
   LOGOUT: "/logout",
   ONE_PORTAL: "/one-portal",
   DASHBOARD: "/dashboard",
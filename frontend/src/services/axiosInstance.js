@@ -38,6 +38,9 @@ function redirectAfterUnauthorized() {
     "/register/set-password",
     "/callback",
     "/logout",
+    "/consent",
+    "/step-up",
+    "/device",
   ]);
 
   if (publicPaths.has(window.location.pathname)) {
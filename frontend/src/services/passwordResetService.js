@@ -67,7 +67,7 @@ export const passwordResetService = {
     return response.data;
   },
 
-  async verifyOtp({ email, otp } = {}) {
+  async verifyOtp({ email, otp, purpose } = {}) {
     const headers = {
       "Content-Type": "application/json",
     };
@@ -80,6 +80,7 @@ export const passwordResetService = {
       {
         email: getRequiredTextValue(email, "Email address"),
         otp: getRequiredTextValue(otp, "OTP"),
+        ...(purpose ? { purpose } : {}),
       },
       { headers },
     );
@@ -87,12 +88,13 @@ export const passwordResetService = {
     return response.data;
   },
 
-  async updateForgotPassword({ email, newPassword } = {}) {
+  async updateForgotPassword({ email, newPassword, resetToken } = {}) {
     const response = await passwordResetApi.patch(
       "/internal/user/password/forgot",
       {
         email: getRequiredTextValue(email, "Email address"),
         new_password: getRequiredTextValue(newPassword, "New password"),
+        reset_token: getRequiredTextValue(resetToken, "Reset token"),
       },
       getJsonRequestConfig(),
     );